@@ -77,6 +77,7 @@ describe('categories API', { concurrent: false }, () => {
           method: 'GET',
           headers: {
             Accept: 'application/json',
+            'X-API-Version': '1',
           },
           body: undefined,
           signal: undefined,
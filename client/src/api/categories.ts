@@ -1,5 +1,5 @@
 import { z } from 'zod';
-import { requestJson } from './http';
+import { LEGACY_LIST_HEADERS, requestJson } from './http';
 import { type Category, CategorySchema, CategoryListSchema } from '../types';
 
 export interface CreateCategoryInput {
@@ -11,7 +11,7 @@ export interface CreateCategoryInput {
 export async function listCategories(projectId: string): Promise<Category[]> {
   return requestJson(
     `/api/projects/${projectId}/categories`,
-    { method: 'GET' },
+    { method: 'GET', headers: LEGACY_LIST_HEADERS },
     CategoryListSchema,
   );
 }
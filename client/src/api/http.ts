@@ -9,6 +9,9 @@ export interface RequestOptions {
   signal?: AbortSignal;
 }
 
+/** Requests the unpaginated array shape of list endpoints instead of a page envelope. */
+export const LEGACY_LIST_HEADERS: Record<string, string> = { 'X-API-Version': '1' };
+
 export class ApiError extends Error {
   public readonly status: number;
   public readonly code?: string;
@@ -65,6 +65,7 @@ describe('projects API', { concurrent: false }, () => {
         method: 'GET',
         headers: {
           Accept: 'application/json',
+          'X-API-Version': '1',
         },
         body: undefined,
         signal: undefined,
@@ -1,9 +1,13 @@
 import { z } from 'zod';
-import { requestJson } from './http';
+import { LEGACY_LIST_HEADERS, requestJson } from './http';
 import { type Project, ProjectSchema, ProjectListSchema } from '../types';
 
 export async function listProjects(): Promise<Project[]> {
-  return requestJson('/api/projects', { method: 'GET' }, ProjectListSchema);
+  return requestJson(
+    '/api/projects',
+    { method: 'GET', headers: LEGACY_LIST_HEADERS },
+    ProjectListSchema,
+  );
 }
 
 export interface CreateProjectInput {
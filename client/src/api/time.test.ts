@@ -212,6 +212,7 @@ describe('time API', { concurrent: false }, () => {
           method: 'GET',
           headers: {
             Accept: 'application/json',
+            'X-API-Version': '1',
           },
           body: undefined,
           signal: undefined,
@@ -247,6 +248,7 @@ describe('time API', { concurrent: false }, () => {
         method: 'GET',
         headers: {
           Accept: 'application/json',
+          'X-API-Version': '1',
         },
         body: undefined,
         signal: undefined,
@@ -1,5 +1,5 @@
 import { z } from 'zod';
-import { requestJson } from './http';
+import { LEGACY_LIST_HEADERS, requestJson } from './http';
 import { type TimeEntry, TimeEntrySchema, TimeEntryListSchema } from '../types';
 
 export async function startTimer(categoryId: string): Promise<TimeEntry> {
@@ -60,6 +60,7 @@ export async function listEntries(params: ListEntriesParams): Promise<TimeEntry[
     path,
     {
       method: 'GET',
+      headers: LEGACY_LIST_HEADERS,
     },
     TimeEntryListSchema,
   );
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -15,6 +16,8 @@ import (
 	"github.com/Gargair/clockwork/server/internal/clock"
 	"github.com/Gargair/clockwork/server/internal/config"
 	"github.com/Gargair/clockwork/server/internal/db"
+	repo_pg "github.com/Gargair/clockwork/server/internal/repository/postgres"
+	"github.com/Gargair/clockwork/server/internal/service"
 )
 
 func main() {
@@ -46,7 +49,8 @@ func main() {
 	}
 	defer func() { _ = dbConn.Close() }()
 
-	handler := apphttp.NewRouter(cfg, dbConn, clock.NewSystemClock(), logger)
+	clk := clock.NewSystemClock()
+	handler := apphttp.NewRouter(cfg, dbConn, clk, logger)
 
 	srv := &http.Server{
 		Addr:              fmt.Sprintf(":%d", cfg.Port),
@@ -65,7 +69,30 @@ func main() {
 		slog.String("static_dir", cfg.StaticDir),
 	)
 
-	waitForShutdown(srv, logger)
+	rp := startReaper(newReaperService(dbConn, clk), reaperPolicy(cfg), cfg.TimerReaperInterval, logger)
+	pg := startPurger(newTrashService(dbConn, clk), cfg.TrashRetention, cfg.TrashPurgeInterval, logger)
+
+	waitForShutdown(srv, rp, pg, logger)
+}
+
+func newReaperService(dbConn *sql.DB, clk clock.Clock) service.TimeTrackingService {
+	repos := repo_pg.NewRepositories(dbConn)
+	return service.NewTimeTrackingService(repos.TimeEntries, repos.Categories, repos.Tags, repos.Projects, repos.Audit, repos.UnitOfWork, clk)
+}
+
+func newTrashService(dbConn *sql.DB, clk clock.Clock) service.TrashService {
+	repos := repo_pg.NewRepositories(dbConn)
+	return service.NewTrashService(repos.Projects, repos.Categories, repos.TimeEntries, repos.UnitOfWork, clk)
+}
+
+func reaperPolicy(cfg config.Config) service.ReaperPolicy {
+	// Validated by config.Load
+	workdayEnd, loc, _ := cfg.WorkdayEndClock()
+	return service.ReaperPolicy{
+		MaxDuration: cfg.TimerMaxDuration,
+		WorkdayEnd:  workdayEnd,
+		Location:    loc,
+	}
 }
 
 func buildLogger(cfg config.Config) *slog.Logger {
@@ -81,7 +108,7 @@ func serveHTTP(srv *http.Server, logger *slog.Logger) {
 	}
 }
 
-func waitForShutdown(srv *http.Server, logger *slog.Logger) {
+func waitForShutdown(srv *http.Server, rp *reaper, pg *purger, logger *slog.Logger) {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	<-sigCh
@@ -93,4 +120,6 @@ func waitForShutdown(srv *http.Server, logger *slog.Logger) {
 	} else {
 		logger.Info("server_stopped")
 	}
+	rp.Stop()
+	pg.Stop()
 }
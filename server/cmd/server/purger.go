@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/service"
+)
+
+// purger periodically empties the trash of rows deleted longer than the retention ago.
+type purger struct {
+	svc       service.TrashService
+	retention time.Duration
+	interval  time.Duration
+	logger    *slog.Logger
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// startPurger launches the background purge. It returns nil when retention is 0, which keeps
+// the trash forever.
+func startPurger(svc service.TrashService, retention, interval time.Duration, logger *slog.Logger) *purger {
+	if retention == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	pg := &purger{
+		svc:       svc,
+		retention: retention,
+		interval:  interval,
+		logger:    logger,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	go pg.run(ctx)
+	logger.Info("trash_purger_started",
+		slog.Duration("retention", retention),
+		slog.Duration("interval", interval),
+	)
+	return pg
+}
+
+func (pg *purger) run(ctx context.Context) {
+	defer close(pg.done)
+	ticker := time.NewTicker(pg.interval)
+	defer ticker.Stop()
+	pg.purge(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pg.purge(ctx)
+		}
+	}
+}
+
+func (pg *purger) purge(ctx context.Context) {
+	result, err := pg.svc.Purge(ctx, pg.retention)
+	if err != nil {
+		if ctx.Err() == nil {
+			pg.logger.Error("trash_purge_error", slog.String("error", err.Error()))
+		}
+		return
+	}
+	if result == (domain.PurgeResult{}) {
+		return
+	}
+	pg.logger.Info("trash_purged",
+		slog.Int64("projects", result.Projects),
+		slog.Int64("categories", result.Categories),
+		slog.Int64("entries", result.Entries),
+	)
+}
+
+// Stop cancels the purge loop and waits for an in-flight purge to finish. Safe on a nil purger.
+func (pg *purger) Stop() {
+	if pg == nil {
+		return
+	}
+	pg.cancel()
+	<-pg.done
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/service"
+)
+
+// reaper periodically auto-stops timers that reached their target or have been left running too long.
+type reaper struct {
+	svc      service.TimeTrackingService
+	policy   service.ReaperPolicy
+	interval time.Duration
+	logger   *slog.Logger
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// startReaper launches the background sweep. Target timers are always stopped; runaway timers
+// only when the policy sets a maximum duration.
+func startReaper(svc service.TimeTrackingService, policy service.ReaperPolicy, interval time.Duration, logger *slog.Logger) *reaper {
+	ctx, cancel := context.WithCancel(context.Background())
+	rp := &reaper{
+		svc:      svc,
+		policy:   policy,
+		interval: interval,
+		logger:   logger,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go rp.run(ctx)
+	logger.Info("timer_reaper_started",
+		slog.Duration("max_duration", policy.MaxDuration),
+		slog.Duration("interval", interval),
+	)
+	return rp
+}
+
+func (rp *reaper) run(ctx context.Context) {
+	defer close(rp.done)
+	ticker := time.NewTicker(rp.interval)
+	defer ticker.Stop()
+	rp.sweep(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rp.sweep(ctx)
+		}
+	}
+}
+
+func (rp *reaper) sweep(ctx context.Context) {
+	reached, err := rp.svc.StopReachedTargets(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			rp.logger.Error("timer_target_error", slog.String("error", err.Error()))
+		}
+		return
+	}
+	rp.logStopped("timer_target_reached", reached)
+
+	stopped, err := rp.svc.ReapRunaway(ctx, rp.policy)
+	if err != nil {
+		if ctx.Err() == nil {
+			rp.logger.Error("timer_reaper_error", slog.String("error", err.Error()))
+		}
+		return
+	}
+	rp.logStopped("timer_auto_stopped", stopped)
+}
+
+// logStopped logs one event per stopped entry.
+func (rp *reaper) logStopped(event string, stopped []domain.TimeEntry) {
+	for _, e := range stopped {
+		attrs := []any{
+			slog.String("entry_id", e.ID.String()),
+			slog.String("category_id", e.CategoryID.String()),
+			slog.Time("started_at", e.StartedAt),
+		}
+		if e.StoppedAt != nil {
+			attrs = append(attrs, slog.Time("stopped_at", *e.StoppedAt))
+		}
+		rp.logger.Warn(event, attrs...)
+	}
+}
+
+// Stop cancels the sweep loop and waits for an in-flight sweep to finish. Safe on a nil reaper.
+func (rp *reaper) Stop() {
+	if rp == nil {
+		return
+	}
+	rp.cancel()
+	<-rp.done
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
@@ -26,6 +27,18 @@ type Config struct {
 	StaticDir string `env:"STATIC_DIR" envDefault:"client/dist"`
 	// AllowedOrigins lists origins allowed by CORS. CSV. Default depends on Env.
 	AllowedOrigins []string `env:"ALLOWED_ORIGINS" envSeparator:","`
+	// TimerMaxDuration is how long a timer may run before it is auto-stopped. 0 disables the reaper.
+	TimerMaxDuration time.Duration `env:"TIMER_MAX_DURATION" envDefault:"0s"`
+	// TimerReaperInterval is how often running timers are checked for a reached target or maximum duration.
+	TimerReaperInterval time.Duration `env:"TIMER_REAPER_INTERVAL" envDefault:"15s"`
+	// WorkdayEnd optionally caps auto-stopped timers at this local time of day (HH:MM).
+	WorkdayEnd string `env:"WORKDAY_END"`
+	// WorkdayTimezone is the IANA time zone WorkdayEnd is interpreted in.
+	WorkdayTimezone string `env:"WORKDAY_TIMEZONE" envDefault:"UTC"`
+	// TrashRetention is how long deleted rows stay restorable before they are purged. 0 keeps them forever.
+	TrashRetention time.Duration `env:"TRASH_RETENTION" envDefault:"720h"`
+	// TrashPurgeInterval is how often the trash is checked for rows past their retention.
+	TrashPurgeInterval time.Duration `env:"TRASH_PURGE_INTERVAL" envDefault:"1h"`
 }
 
 // Load reads configuration from environment (and optional .env) and validates it.
@@ -46,6 +59,21 @@ func Load() (Config, error) {
 	if cfg.Port <= 0 {
 		return Config{}, errors.New("PORT must be > 0")
 	}
+	if cfg.TimerMaxDuration < 0 {
+		return Config{}, errors.New("TIMER_MAX_DURATION must be >= 0")
+	}
+	if cfg.TimerReaperInterval <= 0 {
+		return Config{}, errors.New("TIMER_REAPER_INTERVAL must be > 0")
+	}
+	if cfg.TrashRetention < 0 {
+		return Config{}, errors.New("TRASH_RETENTION must be >= 0")
+	}
+	if cfg.TrashPurgeInterval <= 0 {
+		return Config{}, errors.New("TRASH_PURGE_INTERVAL must be > 0")
+	}
+	if _, _, err := cfg.WorkdayEndClock(); err != nil {
+		return Config{}, err
+	}
 	// Default CORS origins: '*' in development when not explicitly set.
 	if len(cfg.AllowedOrigins) == 0 && cfg.Env == "development" {
 		cfg.AllowedOrigins = []string{"*"}
@@ -81,3 +109,21 @@ func validateEnv(env string) error {
 		return fmt.Errorf("ENV must be one of 'development' or 'production', got %q", env)
 	}
 }
+
+// WorkdayEndClock returns WorkdayEnd as an offset from local midnight together with its location.
+// The offset is nil when WorkdayEnd is unset; 00:00 is a valid end of workday at midnight.
+func (c Config) WorkdayEndClock() (*time.Duration, *time.Location, error) {
+	loc, err := time.LoadLocation(c.WorkdayTimezone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid WORKDAY_TIMEZONE: %w", err)
+	}
+	if c.WorkdayEnd == "" {
+		return nil, loc, nil
+	}
+	t, err := time.Parse("15:04", c.WorkdayEnd)
+	if err != nil {
+		return nil, nil, fmt.Errorf("WORKDAY_END must be HH:MM, got %q", c.WorkdayEnd)
+	}
+	end := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	return &end, loc, nil
+}
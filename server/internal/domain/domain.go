@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,8 +11,66 @@ type Project struct {
 	ID          uuid.UUID
 	Name        string
 	Description *string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	Rate        *Rate
+	Settings    ProjectSettings
+	// Version increases with every update; it backs ETags and conditional writes.
+	Version   int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// ArchivedAt is set while the project is archived.
+	ArchivedAt *time.Time
+	// DeletedAt is set while the project is in the trash.
+	DeletedAt *time.Time
+}
+
+// ProjectSettings holds per-project behavior switches.
+type ProjectSettings struct {
+	// ConcurrentTimers allows one running entry per category instead of one overall.
+	ConcurrentTimers bool
+	Rounding         Rounding
+}
+
+// RoundingMode is the direction in which durations are rounded to the increment.
+type RoundingMode string
+
+const (
+	RoundUp      RoundingMode = "up"
+	RoundDown    RoundingMode = "down"
+	RoundNearest RoundingMode = "nearest"
+)
+
+// RoundingScope selects whether rounding applies to each entry or to each day's total.
+type RoundingScope string
+
+const (
+	RoundPerEntry RoundingScope = "entry"
+	RoundPerDay   RoundingScope = "day"
+)
+
+// Rounding configures how reported durations are rounded. Stored durations are never rounded.
+// A zero IncrementSeconds disables rounding. The units rounded are single entries or, for
+// RoundPerDay, the time of one category's billable or non-billable entries started on one
+// calendar day in UTC; billing, reports and tag totals all round these same units.
+type Rounding struct {
+	IncrementSeconds int32
+	Mode             RoundingMode
+	Scope            RoundingScope
+}
+
+// Round rounds seconds to a multiple of the increment; halves round up in RoundNearest mode.
+func (r Rounding) Round(seconds int64) int64 {
+	inc := int64(r.IncrementSeconds)
+	if inc <= 0 || seconds <= 0 {
+		return seconds
+	}
+	switch r.Mode {
+	case RoundUp:
+		return (seconds + inc - 1) / inc * inc
+	case RoundDown:
+		return seconds / inc * inc
+	default:
+		return (seconds + inc/2) / inc * inc
+	}
 }
 
 type Category struct {
@@ -20,16 +79,221 @@ type Category struct {
 	ParentCategoryID *uuid.UUID
 	Name             string
 	Description      *string
+	Rate             *Rate
+	Billable         bool
+	Version          int64
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
+	// ArchivedAt is set while the category is archived.
+	ArchivedAt *time.Time
+	// DeletedAt is set while the category is in the trash.
+	DeletedAt *time.Time
 }
 
 type TimeEntry struct {
-	ID              uuid.UUID
-	CategoryID      uuid.UUID
+	ID         uuid.UUID
+	CategoryID uuid.UUID
+	// CategoryName and CategoryPath (root first, ending with CategoryName) are only
+	// filled by project- and subtree-wide listings.
+	CategoryName    string
+	CategoryPath    []string
 	StartedAt       time.Time
 	StoppedAt       *time.Time
 	DurationSeconds *int32
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
-}
\ No newline at end of file
+	Note            *string
+	TagIDs          []uuid.UUID
+	Billable        bool
+	BillableAmount  *Money
+	AutoStopped     bool
+	// TargetSeconds is the planned worked time after which the server stops the entry.
+	TargetSeconds  *int32
+	Break          bool
+	Paused         bool
+	ElapsedSeconds *int32
+	// RemainingSeconds is the worked time left until TargetSeconds, only filled for running entries with a target.
+	RemainingSeconds *int32
+	// RoundedSeconds is DurationSeconds rounded by the project's per-entry rounding, or
+	// DurationSeconds itself when the project rounds per day or not at all.
+	RoundedSeconds *int32
+	// ClippedSeconds is the worked time inside a listing's bounds, only filled when clipping was requested.
+	ClippedSeconds *int32
+	Version        int64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	// DeletedAt is set while the entry is in the trash.
+	DeletedAt *time.Time
+}
+
+// TimeEntrySegment is an interval during which a paused/resumed entry was running.
+// A nil StoppedAt marks the currently running segment.
+type TimeEntrySegment struct {
+	ID          uuid.UUID
+	TimeEntryID uuid.UUID
+	StartedAt   time.Time
+	StoppedAt   *time.Time
+}
+
+type Tag struct {
+	ID        uuid.UUID
+	ProjectID uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TagTotal is the tracked time attributed to a tag.
+type TagTotal struct {
+	TagID   uuid.UUID
+	Name    string
+	Seconds int64
+	// RoundedSeconds is Seconds under the project's rounding.
+	RoundedSeconds int64
+}
+
+// CategoryTotal is the worked time tracked in a category itself and in its subtree, the category
+// together with all of its descendants.
+type CategoryTotal struct {
+	Category       Category
+	OwnSeconds     int64
+	SubtreeSeconds int64
+}
+
+// CategoryNode is a category of a project's tree with its totals and its child categories.
+type CategoryNode struct {
+	CategoryTotal
+	Children []CategoryNode
+}
+
+// Rate is an hourly billing rate in minor units of an ISO 4217 currency.
+type Rate struct {
+	HourlyRateCents int64
+	Currency        string
+}
+
+// Money is an amount in minor units of an ISO 4217 currency.
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+// BillingSummary aggregates billable time and amounts of a project over a range.
+type BillingSummary struct {
+	ProjectID uuid.UUID
+	Start     time.Time
+	End       time.Time
+	Lines     []BillingLine
+	Totals    []Money
+}
+
+// BillingLine is the billing breakdown of a single category.
+type BillingLine struct {
+	CategoryID         uuid.UUID
+	CategoryName       string
+	Rate               *Rate
+	BillableSeconds    int64
+	NonBillableSeconds int64
+	// RoundedBillableSeconds and RoundedNonBillableSeconds apply the project's rounding;
+	// Amount prices the rounded billable time.
+	RoundedBillableSeconds    int64
+	RoundedNonBillableSeconds int64
+	Amount                    *Money
+}
+
+// AuditAction is the kind of mutation an AuditEvent records.
+type AuditAction string
+
+const (
+	AuditCreate  AuditAction = "create"
+	AuditUpdate  AuditAction = "update"
+	AuditDelete  AuditAction = "delete"
+	AuditStop    AuditAction = "stop"
+	AuditRestore AuditAction = "restore"
+	AuditPause   AuditAction = "pause"
+	AuditResume  AuditAction = "resume"
+)
+
+// AuditEntity is the type of entity an AuditEvent describes.
+type AuditEntity string
+
+const (
+	AuditProject   AuditEntity = "project"
+	AuditCategory  AuditEntity = "category"
+	AuditTimeEntry AuditEntity = "time_entry"
+)
+
+// AuditEvent records one mutation of a project, category or time entry.
+type AuditEvent struct {
+	ID uuid.UUID
+	// Actor names who made the change; background jobs act as "system".
+	Actor string
+	// RequestID is the ID of the HTTP request that made the change, empty for background jobs.
+	RequestID  string
+	Action     AuditAction
+	EntityType AuditEntity
+	EntityID   uuid.UUID
+	// Before and After are JSON snapshots of the entity around the change. Before is nil for
+	// creations and After for deletions.
+	Before    json.RawMessage
+	After     json.RawMessage
+	CreatedAt time.Time
+}
+
+// Trash holds the deleted projects, categories and time entries that have not been purged yet.
+type Trash struct {
+	Projects   []Project
+	Categories []Category
+	Entries    []TimeEntry
+}
+
+// PurgeResult counts the rows a purge of the trash removed for good.
+type PurgeResult struct {
+	Projects   int64
+	Categories int64
+	Entries    int64
+}
+
+// ReportGroup is a dimension a summary report groups tracked time by.
+type ReportGroup string
+
+const (
+	ReportByProject  ReportGroup = "project"
+	ReportByCategory ReportGroup = "category"
+	ReportByTag      ReportGroup = "tag"
+	ReportByDay      ReportGroup = "day"
+	ReportByWeek     ReportGroup = "week"
+	ReportByMonth    ReportGroup = "month"
+)
+
+// IsPeriod reports whether g buckets time by calendar period rather than by what it was tracked on.
+func (g ReportGroup) IsPeriod() bool {
+	return g == ReportByDay || g == ReportByWeek || g == ReportByMonth
+}
+
+// SummaryReport totals the worked time inside [Start, End) per combination of the GroupBy values.
+type SummaryReport struct {
+	Start    time.Time
+	End      time.Time
+	Location *time.Location
+	GroupBy  []ReportGroup
+	Rows     []ReportRow
+	// TotalSeconds counts time tagged with several tags once, unlike the rows of a report by tag.
+	TotalSeconds int64
+	// RoundedTotalSeconds is TotalSeconds rounded per entry or per day by the projects' rounding.
+	RoundedTotalSeconds int64
+}
+
+// ReportRow is the worked time of one group of a summary report. Only the fields of the grouped
+// dimensions are set, except that TagID stays nil on the row of untagged time.
+type ReportRow struct {
+	ProjectID    *uuid.UUID
+	ProjectName  string
+	CategoryID   *uuid.UUID
+	CategoryName string
+	TagID        *uuid.UUID
+	TagName      string
+	// Period is the start of the day, week (from Monday) or month in the report's location.
+	Period  *time.Time
+	Seconds int64
+	// RoundedSeconds is Seconds rounded per entry or per day by the projects' rounding, as billing does.
+	RoundedSeconds int64
+}
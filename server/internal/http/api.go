@@ -26,24 +26,50 @@ func (h ApiHandler) mountAPI(api chi.Router) {
 		Projects    repository.ProjectRepository
 		Categories  repository.CategoryRepository
 		TimeEntries repository.TimeEntryRepository
+		Tags        repository.TagRepository
+		Audit       repository.AuditRepository
+		Reports     repository.ReportRepository
+		UnitOfWork  repository.UnitOfWork
 	}{
 		Projects:    repos.Projects,
 		Categories:  repos.Categories,
 		TimeEntries: repos.TimeEntries,
+		Tags:        repos.Tags,
+		Audit:       repos.Audit,
+		Reports:     repos.Reports,
+		UnitOfWork:  repos.UnitOfWork,
 	}, h.clk)
 
 	// Handlers
 	projH := NewProjectHandler(svcs.Projects, h.logger)
 	catH := NewCategoryHandler(svcs.Categories, h.logger)
+	tagH := NewTagHandler(svcs.Tags, h.logger)
+	billH := NewBillingHandler(svcs.Billing, h.logger)
 	timeH := NewTimeHandler(svcs.Time, h.logger)
+	auditH := NewAuditHandler(svcs.Audit, h.logger)
+	trashH := NewTrashHandler(svcs.Trash, h.logger)
+	reportH := NewReportHandler(svcs.Reports, h.logger)
 
 	// /api/projects
 	api.Route("/projects", func(rp chi.Router) {
 		projH.RegisterRoutes(rp)
 		// /api/projects/{projectId}/categories
 		rp.Route("/{projectId}/categories", catH.RegisterRoutes)
+		// /api/projects/{projectId}/tags
+		rp.Route("/{projectId}/tags", tagH.RegisterRoutes)
+		// /api/projects/{projectId}/billing
+		rp.Route("/{projectId}/billing", billH.RegisterRoutes)
 	})
 
 	// /api/time
 	api.Route("/time", timeH.RegisterRoutes)
+
+	// /api/audit
+	api.Route("/audit", auditH.RegisterRoutes)
+
+	// /api/trash
+	api.Route("/trash", trashH.RegisterRoutes)
+
+	// /api/reports
+	api.Route("/reports", reportH.RegisterRoutes)
 }
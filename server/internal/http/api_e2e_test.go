@@ -41,6 +41,20 @@ func (f *e2eProjectService) Update(_ context.Context, id uuid.UUID, name string,
 	f.items[id] = p
 	return p, nil
 }
+func (f *e2eProjectService) UpdateSettings(_ context.Context, id uuid.UUID, update service.ProjectSettingsUpdate) (domain.Project, error) {
+	p, ok := f.items[id]
+	if !ok {
+		return domain.Project{}, repository.ErrNotFound
+	}
+	if update.ConcurrentTimers != nil {
+		p.Settings.ConcurrentTimers = *update.ConcurrentTimers
+	}
+	if update.Rounding != nil {
+		p.Settings.Rounding = *update.Rounding
+	}
+	f.items[id] = p
+	return p, nil
+}
 func (f *e2eProjectService) Delete(_ context.Context, id uuid.UUID) error {
 	if _, ok := f.items[id]; !ok {
 		return repository.ErrNotFound
@@ -48,6 +62,15 @@ func (f *e2eProjectService) Delete(_ context.Context, id uuid.UUID) error {
 	delete(f.items, id)
 	return nil
 }
+func (f *e2eProjectService) Restore(context.Context, uuid.UUID) (domain.Project, error) {
+	return domain.Project{}, repository.ErrNotFound
+}
+func (f *e2eProjectService) Archive(context.Context, uuid.UUID) (domain.Project, error) {
+	return domain.Project{}, repository.ErrNotFound
+}
+func (f *e2eProjectService) Unarchive(context.Context, uuid.UUID) (domain.Project, error) {
+	return domain.Project{}, repository.ErrNotFound
+}
 func (f *e2eProjectService) GetByID(_ context.Context, id uuid.UUID) (domain.Project, error) {
 	p, ok := f.items[id]
 	if !ok {
@@ -55,7 +78,7 @@ func (f *e2eProjectService) GetByID(_ context.Context, id uuid.UUID) (domain.Pro
 	}
 	return p, nil
 }
-func (f *e2eProjectService) List(_ context.Context) ([]domain.Project, error) {
+func (f *e2eProjectService) List(_ context.Context, _ bool, _ repository.PageRequest) ([]domain.Project, error) {
 	out := make([]domain.Project, 0, len(f.items))
 	for _, p := range f.items {
 		out = append(out, p)
@@ -74,31 +97,94 @@ func (e *e2eCategoryService) Update(context.Context, uuid.UUID, string, *string,
 	return domain.Category{}, nil
 }
 func (e *e2eCategoryService) Delete(context.Context, uuid.UUID) error { return nil }
+func (e *e2eCategoryService) Restore(context.Context, uuid.UUID) (domain.Category, error) {
+	return domain.Category{}, repository.ErrNotFound
+}
+func (e *e2eCategoryService) Archive(context.Context, uuid.UUID) (domain.Category, error) {
+	return domain.Category{}, repository.ErrNotFound
+}
+func (e *e2eCategoryService) Unarchive(context.Context, uuid.UUID) (domain.Category, error) {
+	return domain.Category{}, repository.ErrNotFound
+}
 func (e *e2eCategoryService) GetByID(context.Context, uuid.UUID) (domain.Category, error) {
 	return domain.Category{}, repository.ErrNotFound
 }
-func (e *e2eCategoryService) ListByProject(context.Context, uuid.UUID) ([]domain.Category, error) {
+func (e *e2eCategoryService) ListByProject(context.Context, uuid.UUID, bool, repository.PageRequest) ([]domain.Category, error) {
 	return nil, nil
 }
 func (e *e2eCategoryService) ListChildren(context.Context, uuid.UUID) ([]domain.Category, error) {
 	return nil, nil
 }
 
+func (e *e2eCategoryService) Tree(context.Context, uuid.UUID, *time.Time, *time.Time) ([]domain.CategoryNode, error) {
+	return []domain.CategoryNode{}, nil
+}
+
 var _ service.CategoryService = (*e2eCategoryService)(nil)
 
 type e2eTimeService struct{}
 
-func (e *e2eTimeService) Start(context.Context, uuid.UUID) (domain.TimeEntry, error) {
+func (e *e2eTimeService) Start(context.Context, uuid.UUID, service.StartOptions) (domain.TimeEntry, error) {
 	return domain.TimeEntry{}, nil
 }
-func (e *e2eTimeService) StopActive(context.Context) (domain.TimeEntry, error) {
+func (e *e2eTimeService) StopActive(context.Context, service.StopOptions) (domain.TimeEntry, error) {
+	return domain.TimeEntry{}, service.ErrNoActiveTimer
+}
+func (e *e2eTimeService) Pause(context.Context, service.TimerTarget) (domain.TimeEntry, error) {
+	return domain.TimeEntry{}, service.ErrNoActiveTimer
+}
+func (e *e2eTimeService) Resume(context.Context, service.TimerTarget) (domain.TimeEntry, error) {
 	return domain.TimeEntry{}, service.ErrNoActiveTimer
 }
+func (e *e2eTimeService) CreateManual(context.Context, uuid.UUID, time.Time, time.Time) (domain.TimeEntry, error) {
+	return domain.TimeEntry{}, nil
+}
+func (e *e2eTimeService) UpdateEntry(context.Context, uuid.UUID, service.TimeEntryUpdate) (domain.TimeEntry, error) {
+	return domain.TimeEntry{}, repository.ErrNotFound
+}
+func (e *e2eTimeService) DeleteEntry(context.Context, uuid.UUID) error { return repository.ErrNotFound }
+func (e *e2eTimeService) DeleteEntries(context.Context, uuid.UUID, time.Time, time.Time, bool) ([]domain.TimeEntry, error) {
+	return nil, nil
+}
+func (e *e2eTimeService) RestoreEntry(context.Context, uuid.UUID) (domain.TimeEntry, error) {
+	return domain.TimeEntry{}, repository.ErrNotFound
+}
+func (e *e2eTimeService) GetEntry(context.Context, uuid.UUID) (domain.TimeEntry, error) {
+	return domain.TimeEntry{}, repository.ErrNotFound
+}
 func (e *e2eTimeService) GetActive(context.Context) (*domain.TimeEntry, error) { return nil, nil }
-func (e *e2eTimeService) ListByCategory(context.Context, uuid.UUID) ([]domain.TimeEntry, error) {
+func (e *e2eTimeService) StopActiveInCategory(context.Context, uuid.UUID, service.StopOptions) (domain.TimeEntry, error) {
+	return domain.TimeEntry{}, service.ErrNoActiveTimer
+}
+func (e *e2eTimeService) GetActiveInCategory(context.Context, uuid.UUID) (*domain.TimeEntry, error) {
+	return nil, nil
+}
+func (e *e2eTimeService) ListActive(context.Context) ([]domain.TimeEntry, error) { return nil, nil }
+func (e *e2eTimeService) Split(context.Context, uuid.UUID, time.Time, *uuid.UUID) (domain.TimeEntry, domain.TimeEntry, error) {
+	return domain.TimeEntry{}, domain.TimeEntry{}, repository.ErrNotFound
+}
+func (e *e2eTimeService) Merge(context.Context, []uuid.UUID, time.Duration) (domain.TimeEntry, error) {
+	return domain.TimeEntry{}, service.ErrInvalidMerge
+}
+func (e *e2eTimeService) ReapRunaway(context.Context, service.ReaperPolicy) ([]domain.TimeEntry, error) {
+	return nil, nil
+}
+func (e *e2eTimeService) Continue(context.Context, *uuid.UUID) (domain.TimeEntry, error) {
+	return domain.TimeEntry{}, nil
+}
+func (e *e2eTimeService) StopReachedTargets(context.Context) ([]domain.TimeEntry, error) {
+	return nil, nil
+}
+func (e *e2eTimeService) ListByCategory(context.Context, uuid.UUID, repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+	return nil, nil
+}
+func (e *e2eTimeService) ListByCategoryAndRange(context.Context, uuid.UUID, time.Time, time.Time, repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+	return nil, nil
+}
+func (e *e2eTimeService) ListByProject(context.Context, uuid.UUID, *time.Time, *time.Time, repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
 	return nil, nil
 }
-func (e *e2eTimeService) ListByCategoryAndRange(context.Context, uuid.UUID, time.Time, time.Time) ([]domain.TimeEntry, error) {
+func (e *e2eTimeService) ListByCategoryTree(context.Context, uuid.UUID, *time.Time, *time.Time, repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
 	return nil, nil
 }
 
@@ -159,10 +245,11 @@ func TestAPIEndToEndProjectCreateAndList(t *testing.T) {
 	if w.Code != stdhttp.StatusOK {
 		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
 	}
-	var list []ProjectResponse
-	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+	var page PageResponse[ProjectResponse]
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
 		t.Fatalf(invalidJsonErrorMessage, err)
 	}
+	list := page.Items
 	if len(list) != 1 || list[0].ID != created.ID || list[0].Name != created.Name {
 		t.Fatalf("unexpected list response: %+v", list)
 	}
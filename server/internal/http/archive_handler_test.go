@@ -0,0 +1,134 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	stdhttp "net/http"
+	"testing"
+	"time"
+
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/Gargair/clockwork/server/internal/service"
+)
+
+func TestProjectHandlerArchiveAndUnarchive(t *testing.T) {
+	id := uuid.New()
+	archivedAt := time.Date(2025, 11, 3, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+	f := &fakeProjectService{archiveFn: func(got uuid.UUID, archived bool) (domain.Project, error) {
+		if got != id {
+			return domain.Project{}, repository.ErrNotFound
+		}
+		if archived {
+			return domain.Project{ID: id, Name: "P", Version: 4, ArchivedAt: &archivedAt}, nil
+		}
+		return domain.Project{ID: id, Name: "P", Version: 5}, nil
+	}}
+	r := mountRoutes(projectRoute, NewProjectHandler(f, slog.Default()).RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodPost, projectRoute+"/"+id.String()+"/archive", nil, nil)
+	if w.Code != stdhttp.StatusOK || w.Header().Get("ETag") != `"4"` {
+		t.Fatalf("expected 200 with ETag \"4\", got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+	var resp ProjectResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if resp.ArchivedAt == nil || resp.ArchivedAt.Location() != time.UTC || !resp.ArchivedAt.Equal(archivedAt) {
+		t.Fatalf("unexpected archivedAt: %+v", resp.ArchivedAt)
+	}
+
+	w = doRequest(r, stdhttp.MethodPost, projectRoute+"/"+id.String()+"/unarchive", nil, nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if string(raw["archivedAt"]) != "null" {
+		t.Fatalf("expected archivedAt to be null, got %s", w.Body.String())
+	}
+
+	w = doRequest(r, stdhttp.MethodPost, projectRoute+"/"+uuid.NewString()+"/archive", nil, nil)
+	if w.Code != stdhttp.StatusNotFound {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusNotFound, w.Code)
+	}
+}
+
+func TestProjectHandlerListIncludeArchived(t *testing.T) {
+	f := &fakeProjectService{listFn: func() ([]domain.Project, error) { return nil, nil }}
+	r := mountRoutes(projectRoute, NewProjectHandler(f, slog.Default()).RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodGet, projectRoute, nil, nil)
+	if w.Code != stdhttp.StatusOK || f.lastIncludeArchived {
+		t.Fatalf("expected archived projects excluded by default, got %d %v", w.Code, f.lastIncludeArchived)
+	}
+	w = doRequest(r, stdhttp.MethodGet, projectRoute+"?includeArchived=true", nil, nil)
+	if w.Code != stdhttp.StatusOK || !f.lastIncludeArchived {
+		t.Fatalf("expected includeArchived passed through, got %d %v", w.Code, f.lastIncludeArchived)
+	}
+	w = doRequest(r, stdhttp.MethodGet, projectRoute+"?includeArchived=maybe", nil, nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+}
+
+func TestCategoryHandlerArchiveAndListIncludeArchived(t *testing.T) {
+	projectID, id := uuid.New(), uuid.New()
+	f := &fakeCategoryService{
+		archiveFn: func(got uuid.UUID, archived bool) (domain.Category, error) {
+			now := time.Now()
+			return domain.Category{ID: got, ProjectID: projectID, Name: "C", Version: 2, ArchivedAt: &now}, nil
+		},
+		listByProjectFn: func(uuid.UUID) ([]domain.Category, error) { return nil, nil },
+	}
+	r := mountRoutes("/api/projects/{projectId}/categories", NewCategoryHandler(f, slog.Default()).RegisterRoutes)
+	base := fmt.Sprintf(categoriesRoute, projectID)
+
+	w := doRequest(r, stdhttp.MethodPost, base+"/"+id.String()+"/archive", nil, nil)
+	if w.Code != stdhttp.StatusOK || w.Header().Get("ETag") != `"2"` {
+		t.Fatalf("expected 200 with ETag \"2\", got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+	var resp CategoryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if resp.ID != id || resp.ArchivedAt == nil {
+		t.Fatalf("unexpected category: %+v", resp)
+	}
+
+	w = doRequest(r, stdhttp.MethodGet, base+"?includeArchived=1", nil, nil)
+	if w.Code != stdhttp.StatusOK || !f.lastIncludeArchived {
+		t.Fatalf("expected includeArchived passed through, got %d %v", w.Code, f.lastIncludeArchived)
+	}
+	w = doRequest(r, stdhttp.MethodGet, base+"?includeArchived=yes", nil, nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+	var er ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &er); err != nil || er.Code != string(codeInvalidQuery) {
+		t.Fatalf("expected %s, got %+v (%v)", codeInvalidQuery, er, err)
+	}
+}
+
+func TestTimeHandlerStartArchivedCategoryMaps409(t *testing.T) {
+	f := &fakeTimeService{startFn: func(uuid.UUID) (domain.TimeEntry, error) {
+		return domain.TimeEntry{}, service.ErrCategoryArchived
+	}}
+	r := mountRoutes(timeRoute, NewTimeHandler(f, slog.Default()).RegisterRoutes)
+
+	data, _ := json.Marshal(TimeStartRequest{CategoryID: uuid.NewString()})
+	w := doRequest(r, stdhttp.MethodPost, timeRoute+"/start", data, nil)
+	if w.Code != stdhttp.StatusConflict {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusConflict, w.Code)
+	}
+	var er ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &er); err != nil || er.Code != string(codeCategoryArchived) {
+		t.Fatalf("expected %s, got %+v (%v)", codeCategoryArchived, er, err)
+	}
+}
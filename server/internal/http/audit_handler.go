@@ -0,0 +1,112 @@
+package http
+
+import (
+	"net/http"
+
+	"log/slog"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/Gargair/clockwork/server/internal/service"
+)
+
+// AuditHandler serves the audit log.
+type AuditHandler struct {
+	svc    service.AuditService
+	logger *slog.Logger
+}
+
+// NewAuditHandler constructs an AuditHandler.
+func NewAuditHandler(svc service.AuditService, logger *slog.Logger) AuditHandler {
+	return AuditHandler{svc: svc, logger: logger}
+}
+
+// RegisterRoutes mounts audit routes under the provided router (expects base path to be set by caller).
+func (h AuditHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/", h.handleList)
+}
+
+// handleList lists audit events, most recent first, optionally narrowed by
+// entityType, entityId and the from/to bounds of the time they were recorded at.
+func (h AuditHandler) handleList(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	h.logger.Info("audit_list_start", slog.String("request_id", reqID))
+	q := r.URL.Query()
+	var filter repository.AuditFilter
+	switch entityType := domain.AuditEntity(q.Get("entityType")); entityType {
+	case "", domain.AuditProject, domain.AuditCategory, domain.AuditTimeEntry:
+		filter.EntityType = entityType
+	default:
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidQuery), "invalid entityType")
+		h.logger.Warn("audit_list_invalid_entity_type", slog.String("request_id", reqID), slog.String("entity_type", string(entityType)))
+		return
+	}
+	if s := q.Get("entityId"); s != "" {
+		id, err := parseUUID(s)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidID), "invalid entityId")
+			h.logger.Warn("audit_list_invalid_entity_id", slog.String("request_id", reqID), slog.String("entity_id", s))
+			return
+		}
+		filter.EntityID = &id
+	}
+	if s := q.Get("from"); s != "" {
+		t, err := parseTimeRFC3339(s)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+			h.logger.Warn("audit_list_invalid_from", slog.String("request_id", reqID), slog.String("from", s))
+			return
+		}
+		filter.From = &t
+	}
+	if s := q.Get("to"); s != "" {
+		t, err := parseTimeRFC3339(s)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+			h.logger.Warn("audit_list_invalid_to", slog.String("request_id", reqID), slog.String("to", s))
+			return
+		}
+		filter.To = &t
+	}
+	page, err := parseListPage(r, "-createdAt")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidQuery), err.Error())
+		h.logger.Warn("audit_list_invalid_page", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	filter.Page = page.request()
+	events, err := h.svc.List(r.Context(), filter)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("audit_list_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	count := writeList(w, page, events, auditEventToResponse, auditEventCursor)
+	h.logger.Info("audit_list_success", slog.String("request_id", reqID), slog.Int("count", count))
+}
+
+func auditEventToResponse(e domain.AuditEvent) AuditEventResponse {
+	var requestID *string
+	if e.RequestID != "" {
+		requestID = &e.RequestID
+	}
+	return AuditEventResponse{
+		ID:         e.ID,
+		Actor:      e.Actor,
+		RequestID:  requestID,
+		Action:     string(e.Action),
+		EntityType: string(e.EntityType),
+		EntityID:   e.EntityID,
+		Before:     e.Before,
+		After:      e.After,
+		CreatedAt:  e.CreatedAt,
+	}
+}
+
+// auditEventCursor positions an audit listing, which is ordered by recording time, after e.
+func auditEventCursor(e domain.AuditEvent) repository.Cursor {
+	return repository.Cursor{At: e.CreatedAt, ID: e.ID}
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"testing"
+	"time"
+
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/Gargair/clockwork/server/internal/service"
+)
+
+type fakeAuditService struct {
+	listFn     func(filter repository.AuditFilter) ([]domain.AuditEvent, error)
+	lastFilter repository.AuditFilter
+}
+
+func (f *fakeAuditService) List(_ context.Context, filter repository.AuditFilter) ([]domain.AuditEvent, error) {
+	f.lastFilter = filter
+	return f.listFn(filter)
+}
+
+var _ service.AuditService = (*fakeAuditService)(nil)
+
+const auditRoute = "/api/audit"
+
+func TestAuditHandlerListFiltersAndPages(t *testing.T) {
+	now := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	entityID := uuid.New()
+	events := []domain.AuditEvent{
+		{ID: uuid.New(), Actor: "alice", RequestID: "req-2", Action: domain.AuditUpdate, EntityType: domain.AuditCategory, EntityID: entityID,
+			Before: json.RawMessage(`{"name":"Old"}`), After: json.RawMessage(`{"name":"New"}`), CreatedAt: now.Add(time.Minute)},
+		{ID: uuid.New(), Actor: "system", Action: domain.AuditCreate, EntityType: domain.AuditCategory, EntityID: entityID,
+			After: json.RawMessage(`{"name":"Old"}`), CreatedAt: now},
+	}
+	f := &fakeAuditService{listFn: func(repository.AuditFilter) ([]domain.AuditEvent, error) { return events, nil }}
+	r := mountRoutes(auditRoute, NewAuditHandler(f, slog.Default()).RegisterRoutes)
+
+	url := auditRoute + "?entityType=category&entityId=" + entityID.String() + "&from=2025-11-03T00:00:00Z&to=2025-11-04T00:00:00Z&limit=1"
+	w := doRequest(r, stdhttp.MethodGet, url, nil, nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	got := f.lastFilter
+	if got.EntityType != domain.AuditCategory || got.EntityID == nil || *got.EntityID != entityID ||
+		got.From == nil || !got.From.Equal(now.Add(-9*time.Hour)) || got.To == nil || got.Page.Limit != 2 {
+		t.Fatalf("unexpected filter: %+v", got)
+	}
+	var resp PageResponse[AuditEventResponse]
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if len(resp.Items) != 1 || resp.NextCursor == nil {
+		t.Fatalf("expected one item and a next cursor, got %+v", resp)
+	}
+	item := resp.Items[0]
+	if item.Actor != "alice" || item.RequestID == nil || *item.RequestID != "req-2" || item.Action != "update" ||
+		item.EntityType != "category" || string(item.After) != `{"name":"New"}` {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+
+	// The cursor resumes behind the last item
+	w = doRequest(r, stdhttp.MethodGet, auditRoute+"?cursor="+*resp.NextCursor, nil, nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	if after := f.lastFilter.Page.After; after == nil || after.ID != events[0].ID || !after.At.Equal(events[0].CreatedAt) {
+		t.Fatalf("unexpected cursor: %+v", after)
+	}
+
+	// Events without a request or a before snapshot render them as null
+	w = doRequest(r, stdhttp.MethodGet, auditRoute, nil, nil)
+	var raw struct {
+		Items []map[string]json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if len(raw.Items) != 2 || string(raw.Items[1]["requestId"]) != "null" || string(raw.Items[1]["before"]) != "null" {
+		t.Fatalf("expected null requestId and before, got %+v", raw.Items)
+	}
+}
+
+func TestAuditHandlerListRejectsInvalidQuery(t *testing.T) {
+	f := &fakeAuditService{listFn: func(repository.AuditFilter) ([]domain.AuditEvent, error) {
+		return nil, service.ErrInvalidTimeRange
+	}}
+	r := mountRoutes(auditRoute, NewAuditHandler(f, slog.Default()).RegisterRoutes)
+
+	cases := []struct {
+		query  string
+		status int
+		code   apiErrorCode
+	}{
+		{"?entityType=tag", stdhttp.StatusBadRequest, codeInvalidQuery},
+		{"?entityId=nope", stdhttp.StatusBadRequest, codeInvalidID},
+		{"?from=yesterday", stdhttp.StatusBadRequest, codeInvalidTime},
+		{"?to=tomorrow", stdhttp.StatusBadRequest, codeInvalidTime},
+		{"?cursor=***", stdhttp.StatusBadRequest, codeInvalidQuery},
+		{"?from=2025-11-04T00:00:00Z&to=2025-11-03T00:00:00Z", stdhttp.StatusBadRequest, codeInvalidTimeRange},
+	}
+	for _, c := range cases {
+		w := doRequest(r, stdhttp.MethodGet, auditRoute+c.query, nil, nil)
+		if w.Code != c.status {
+			t.Fatalf("%s: "+statusCodeFailedExpectationMessage, c.query, c.status, w.Code)
+		}
+		var er ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &er); err != nil || er.Code != string(c.code) {
+			t.Fatalf("%s: expected %s, got %+v (%v)", c.query, c.code, er, err)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package http
+
+import (
+	"net/http"
+
+	"log/slog"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/service"
+)
+
+// BillingHandler handles rate and billing endpoints under a project.
+type BillingHandler struct {
+	svc    service.BillingService
+	logger *slog.Logger
+}
+
+// NewBillingHandler constructs a BillingHandler.
+func NewBillingHandler(svc service.BillingService, logger *slog.Logger) BillingHandler {
+	return BillingHandler{svc: svc, logger: logger}
+}
+
+// RegisterRoutes mounts billing routes on the provided router (expects base path to be /api/projects/{projectId}/billing).
+func (h BillingHandler) RegisterRoutes(r chi.Router) {
+	r.Put("/", h.handleSetProjectRate)
+	r.Put("/categories"+categoryIdRoute, h.handleSetCategoryBilling)
+	r.Get("/summary", h.handleSummary)
+}
+
+func (h BillingHandler) handleSetProjectRate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	projID, ok := h.parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	var req ProjectBillingRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidJSON), errInvalidJsonPayload)
+		h.logger.Warn("billing_project_invalid_json", slog.String("request_id", reqID))
+		return
+	}
+	p, err := h.svc.SetProjectRate(r.Context(), projID, rateFromRequest(req.Rate))
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("billing_project_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("project_id", projID.String()))
+		return
+	}
+	writeJSON(w, http.StatusOK, projectToResponse(p))
+	h.logger.Info("billing_project_success", slog.String("request_id", reqID), slog.String("project_id", projID.String()))
+}
+
+func (h BillingHandler) handleSetCategoryBilling(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	projID, ok := h.parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	idStr := chi.URLParam(r, categoryIdParam)
+	catID, err := parseUUID(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidCategoryId)
+		h.logger.Warn("billing_category_invalid_id", slog.String("request_id", reqID), slog.String("category_id", idStr))
+		return
+	}
+	var req CategoryBillingRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidJSON), errInvalidJsonPayload)
+		h.logger.Warn("billing_category_invalid_json", slog.String("request_id", reqID))
+		return
+	}
+	c, err := h.svc.SetCategoryBilling(r.Context(), projID, catID, rateFromRequest(req.Rate), req.Billable)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("billing_category_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("category_id", catID.String()))
+		return
+	}
+	writeJSON(w, http.StatusOK, categoryToResponse(c))
+	h.logger.Info("billing_category_success", slog.String("request_id", reqID), slog.String("category_id", catID.String()))
+}
+
+func (h BillingHandler) handleSummary(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	projID, ok := h.parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	from, err := parseTimeRFC3339(q.Get("from"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+		h.logger.Warn("billing_summary_invalid_from", slog.String("request_id", reqID), slog.String("from", q.Get("from")))
+		return
+	}
+	to, err := parseTimeRFC3339(q.Get("to"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+		h.logger.Warn("billing_summary_invalid_to", slog.String("request_id", reqID), slog.String("to", q.Get("to")))
+		return
+	}
+	summary, err := h.svc.Summary(r.Context(), projID, from, to)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("billing_summary_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+
+	resp := BillingSummaryResponse{
+		ProjectID: summary.ProjectID,
+		From:      summary.Start.UTC(),
+		To:        summary.End.UTC(),
+		Lines:     make([]BillingLineResponse, 0, len(summary.Lines)),
+		Totals:    make([]MoneyResponse, 0, len(summary.Totals)),
+	}
+	for _, l := range summary.Lines {
+		resp.Lines = append(resp.Lines, BillingLineResponse{
+			CategoryID:                l.CategoryID,
+			CategoryName:              l.CategoryName,
+			Rate:                      rateToResponse(l.Rate),
+			BillableSeconds:           l.BillableSeconds,
+			NonBillableSeconds:        l.NonBillableSeconds,
+			RoundedBillableSeconds:    l.RoundedBillableSeconds,
+			RoundedNonBillableSeconds: l.RoundedNonBillableSeconds,
+			Amount:                    moneyToResponse(l.Amount),
+		})
+	}
+	for _, t := range summary.Totals {
+		resp.Totals = append(resp.Totals, *moneyToResponse(&t))
+	}
+	writeJSON(w, http.StatusOK, resp)
+	h.logger.Info("billing_summary_success", slog.String("request_id", reqID), slog.Int("lines", len(resp.Lines)))
+}
+
+func (h BillingHandler) parseProjectID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	idStr := chi.URLParam(r, projectIdParam)
+	id, err := parseUUID(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidProjectId)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func rateFromRequest(req *RateRequest) *domain.Rate {
+	if req == nil {
+		return nil
+	}
+	return &domain.Rate{HourlyRateCents: req.HourlyRateCents, Currency: req.Currency}
+}
+
+func rateToResponse(rate *domain.Rate) *RateResponse {
+	if rate == nil {
+		return nil
+	}
+	return &RateResponse{HourlyRateCents: rate.HourlyRateCents, Currency: rate.Currency}
+}
+
+func moneyToResponse(m *domain.Money) *MoneyResponse {
+	if m == nil {
+		return nil
+	}
+	return &MoneyResponse{AmountCents: m.Cents, Currency: m.Currency}
+}
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"testing"
+	"time"
+
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/service"
+)
+
+type fakeBillingService struct {
+	setProjectRateFn     func(projectID uuid.UUID, rate *domain.Rate) (domain.Project, error)
+	setCategoryBillingFn func(projectID uuid.UUID, categoryID uuid.UUID, rate *domain.Rate, billable *bool) (domain.Category, error)
+	summaryFn            func(projectID uuid.UUID, start time.Time, end time.Time) (domain.BillingSummary, error)
+}
+
+func (f *fakeBillingService) SetProjectRate(_ context.Context, projectID uuid.UUID, rate *domain.Rate) (domain.Project, error) {
+	return f.setProjectRateFn(projectID, rate)
+}
+func (f *fakeBillingService) SetCategoryBilling(_ context.Context, projectID uuid.UUID, categoryID uuid.UUID, rate *domain.Rate, billable *bool) (domain.Category, error) {
+	return f.setCategoryBillingFn(projectID, categoryID, rate, billable)
+}
+func (f *fakeBillingService) EffectiveRate(context.Context, uuid.UUID) (*domain.Rate, error) {
+	return nil, nil
+}
+func (f *fakeBillingService) EntryAmount(context.Context, domain.TimeEntry) (*domain.Money, error) {
+	return nil, nil
+}
+func (f *fakeBillingService) Summary(_ context.Context, projectID uuid.UUID, start time.Time, end time.Time) (domain.BillingSummary, error) {
+	return f.summaryFn(projectID, start, end)
+}
+
+var _ service.BillingService = (*fakeBillingService)(nil)
+
+const (
+	billingRoute        = "/api/projects/%s/billing"
+	billingRoutePattern = "/api/projects/{projectId}/billing"
+)
+
+func TestBillingHandlerSetRates(t *testing.T) {
+	var gotRate *domain.Rate
+	var gotBillable *bool
+	f := &fakeBillingService{
+		setProjectRateFn: func(projectID uuid.UUID, rate *domain.Rate) (domain.Project, error) {
+			gotRate = rate
+			if rate != nil && rate.HourlyRateCents < 0 {
+				return domain.Project{}, service.ErrInvalidRate
+			}
+			return domain.Project{ID: projectID, Name: "Client", Rate: rate}, nil
+		},
+		setCategoryBillingFn: func(projectID uuid.UUID, categoryID uuid.UUID, rate *domain.Rate, billable *bool) (domain.Category, error) {
+			gotRate, gotBillable = rate, billable
+			return domain.Category{ID: categoryID, ProjectID: projectID, Rate: rate, Billable: billable != nil && *billable}, nil
+		},
+	}
+	projectID := uuid.New()
+	base := sprintf(billingRoute, projectID.String())
+	r := mountRoutes(billingRoutePattern, NewBillingHandler(f, slog.Default()).RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodPut, base, []byte(`{"rate":{"hourlyRateCents":9500,"currency":"EUR"}}`), nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	var proj ProjectResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &proj); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if proj.Rate == nil || proj.Rate.HourlyRateCents != 9500 || proj.Rate.Currency != "EUR" {
+		t.Fatalf("unexpected project rate: %+v", proj.Rate)
+	}
+
+	w = doRequest(r, stdhttp.MethodPut, base, []byte(`{"rate":null}`), nil)
+	if w.Code != stdhttp.StatusOK || gotRate != nil {
+		t.Fatalf("expected rate cleared, got %d %+v", w.Code, gotRate)
+	}
+	w = doRequest(r, stdhttp.MethodPut, base, []byte(`{"rate":{"hourlyRateCents":-1,"currency":"EUR"}}`), nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+
+	categoryID := uuid.New()
+	w = doRequest(r, stdhttp.MethodPut, base+"/categories/"+categoryID.String(), []byte(`{"rate":null,"billable":true}`), nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	if gotRate != nil || gotBillable == nil || !*gotBillable {
+		t.Fatalf("unexpected category billing input: %+v %v", gotRate, gotBillable)
+	}
+	w = doRequest(r, stdhttp.MethodPut, base+"/categories/"+invalidId, []byte(`{"rate":null}`), nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+}
+
+func TestBillingHandlerSummary(t *testing.T) {
+	projectID := uuid.New()
+	categoryID := uuid.New()
+	f := &fakeBillingService{
+		summaryFn: func(pid uuid.UUID, start time.Time, end time.Time) (domain.BillingSummary, error) {
+			if start.After(end) {
+				return domain.BillingSummary{}, service.ErrInvalidTimeRange
+			}
+			rate := &domain.Rate{HourlyRateCents: 6000, Currency: "EUR"}
+			return domain.BillingSummary{
+				ProjectID: pid,
+				Start:     start,
+				End:       end,
+				Lines: []domain.BillingLine{{
+					CategoryID: categoryID, CategoryName: "Dev", Rate: rate,
+					BillableSeconds: 3600, NonBillableSeconds: 600,
+					Amount: &domain.Money{Cents: 6000, Currency: "EUR"},
+				}},
+				Totals: []domain.Money{{Cents: 6000, Currency: "EUR"}},
+			}, nil
+		},
+	}
+	base := sprintf(billingRoute, projectID.String())
+	r := mountRoutes(billingRoutePattern, NewBillingHandler(f, slog.Default()).RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodGet, base+"/summary?from=2025-11-01T00:00:00Z&to=2025-12-01T00:00:00Z", nil, nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	var resp BillingSummaryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if resp.ProjectID != projectID || len(resp.Lines) != 1 || resp.Lines[0].Amount == nil || resp.Lines[0].Amount.AmountCents != 6000 {
+		t.Fatalf("unexpected summary: %+v", resp)
+	}
+	if len(resp.Totals) != 1 || resp.Totals[0].Currency != "EUR" {
+		t.Fatalf("unexpected totals: %+v", resp.Totals)
+	}
+
+	w = doRequest(r, stdhttp.MethodGet, base+"/summary?from=2025-11-01T00:00:00Z", nil, nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+	w = doRequest(r, stdhttp.MethodGet, base+"/summary?from=2025-12-01T00:00:00Z&to=2025-11-01T00:00:00Z", nil, nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"log/slog"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
 	"github.com/Gargair/clockwork/server/internal/service"
 )
 
@@ -35,9 +37,13 @@ const (
 func (h CategoryHandler) RegisterRoutes(r chi.Router) {
 	r.Post("/", h.handleCreate)
 	r.Get("/", h.handleList)
+	r.Get("/tree", h.handleTree)
 	r.Get(categoryIdRoute, h.handleGetByID)
 	r.Patch(categoryIdRoute, h.handleUpdate)
 	r.Delete(categoryIdRoute, h.handleDelete)
+	r.Post(categoryIdRoute+"/restore", h.handleRestore)
+	r.Post(categoryIdRoute+"/archive", h.handleArchive)
+	r.Post(categoryIdRoute+"/unarchive", h.handleUnarchive)
 }
 
 func (h CategoryHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
@@ -75,18 +81,64 @@ func (h CategoryHandler) handleList(w http.ResponseWriter, r *http.Request) {
 	if !ok {
 		return
 	}
-	items, err := h.svc.ListByProject(r.Context(), projID)
+	page, err := parseListPage(r, "createdAt")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidQuery), err.Error())
+		h.logger.Warn("category_list_invalid_page", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	includeArchived, err := parseOptionalBool(r.URL.Query().Get("includeArchived"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidQuery), errInvalidIncludeArchived)
+		h.logger.Warn("category_list_invalid_include_archived", slog.String("request_id", reqID), slog.String("include_archived", r.URL.Query().Get("includeArchived")))
+		return
+	}
+	items, err := h.svc.ListByProject(r.Context(), projID, includeArchived, page.request())
 	if err != nil {
 		writeMappedError(w, r, err)
 		h.logger.Error("category_list_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
 		return
 	}
-	resp := make([]CategoryResponse, 0, len(items))
-	for _, it := range items {
-		resp = append(resp, categoryToResponse(it))
+	count := writeList(w, page, items, categoryToResponse, categoryCursor)
+	h.logger.Info("category_list_success", slog.String("request_id", reqID), slog.Int("count", count))
+}
+
+// handleTree returns the project's categories nested under their parents with the time worked
+// inside the optional bounds [from, to) in each node and in its subtree.
+func (h CategoryHandler) handleTree(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	projID, ok := h.parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	var from, to *time.Time
+	if s := q.Get("from"); s != "" {
+		t, err := parseTimeRFC3339(s)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+			h.logger.Warn("category_tree_invalid_from", slog.String("request_id", reqID), slog.String("from", s))
+			return
+		}
+		from = &t
+	}
+	if s := q.Get("to"); s != "" {
+		t, err := parseTimeRFC3339(s)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+			h.logger.Warn("category_tree_invalid_to", slog.String("request_id", reqID), slog.String("to", s))
+			return
+		}
+		to = &t
+	}
+	roots, err := h.svc.Tree(r.Context(), projID, from, to)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("category_tree_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
 	}
-	writeJSON(w, http.StatusOK, resp)
-	h.logger.Info("category_list_success", slog.String("request_id", reqID), slog.Int("count", len(resp)))
+	writeJSON(w, http.StatusOK, categoryNodesToResponse(roots))
+	h.logger.Info("category_tree_success", slog.String("request_id", reqID), slog.Int("count", len(roots)))
 }
 
 func (h CategoryHandler) handleGetByID(w http.ResponseWriter, r *http.Request) {
@@ -107,6 +159,11 @@ func (h CategoryHandler) handleGetByID(w http.ResponseWriter, r *http.Request) {
 		h.logger.Error("category_get_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("category_id", id.String()))
 		return
 	}
+	if notModified(w, r, entityTag(c.Version)) {
+		h.logger.Info("category_get_not_modified", slog.String("request_id", reqID), slog.String("category_id", id.String()))
+		return
+	}
+	setETag(w, entityTag(c.Version))
 	writeJSON(w, http.StatusOK, categoryToResponse(c))
 	h.logger.Info("category_get_success", slog.String("request_id", reqID), slog.String("category_id", id.String()))
 }
@@ -123,6 +180,12 @@ func (h CategoryHandler) handleUpdate(w http.ResponseWriter, r *http.Request) {
 		h.logger.Warn("category_update_invalid_id", slog.String("request_id", reqID), slog.String("category_id", idStr))
 		return
 	}
+	ctx, ok := ifMatch(r, id)
+	if !ok {
+		writeError(w, r, http.StatusPreconditionFailed, string(codePreconditionFailed), errPreconditionFailed)
+		h.logger.Warn("category_update_precondition_failed", slog.String("request_id", reqID), slog.String("category_id", id.String()))
+		return
+	}
 
 	var req CategoryUpdateRequest
 	if err := decodeJSON(r, &req); err != nil {
@@ -143,16 +206,19 @@ func (h CategoryHandler) handleUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	updated, err := h.svc.Update(r.Context(), id, name, req.Description, parentUUID)
+	updated, err := h.svc.Update(ctx, id, name, req.Description, parentUUID)
 	if err != nil {
 		writeMappedError(w, r, err)
 		h.logger.Error("category_update_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("category_id", id.String()))
 		return
 	}
+	setETag(w, entityTag(updated.Version))
 	writeJSON(w, http.StatusOK, categoryToResponse(updated))
 	h.logger.Info("category_update_success", slog.String("request_id", reqID), slog.String("category_id", id.String()))
 }
 
+// handleDelete moves the category, its whole subtree and their entries to the trash. Categories
+// with children or entries used to be detached or refused; they are now trashed along with it.
 func (h CategoryHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetReqID(r.Context())
 	if _, ok := h.parseProjectID(w, r); !ok {
@@ -165,7 +231,13 @@ func (h CategoryHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
 		h.logger.Warn("category_delete_invalid_id", slog.String("request_id", reqID), slog.String("category_id", idStr))
 		return
 	}
-	if err := h.svc.Delete(r.Context(), id); err != nil {
+	ctx, ok := ifMatch(r, id)
+	if !ok {
+		writeError(w, r, http.StatusPreconditionFailed, string(codePreconditionFailed), errPreconditionFailed)
+		h.logger.Warn("category_delete_precondition_failed", slog.String("request_id", reqID), slog.String("category_id", id.String()))
+		return
+	}
+	if err := h.svc.Delete(ctx, id); err != nil {
 		writeMappedError(w, r, err)
 		h.logger.Error("category_delete_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("category_id", id.String()))
 		return
@@ -174,6 +246,78 @@ func (h CategoryHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("category_delete_success", slog.String("request_id", reqID), slog.String("category_id", id.String()))
 }
 
+// handleRestore brings back the category with the subtree and entries trashed together with it.
+// It conflicts when another category of the project has taken the name in the meantime.
+func (h CategoryHandler) handleRestore(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	if _, ok := h.parseProjectID(w, r); !ok {
+		return
+	}
+	idStr := chi.URLParam(r, "categoryId")
+	id, err := parseUUID(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidCategoryId)
+		h.logger.Warn("category_restore_invalid_id", slog.String("request_id", reqID), slog.String("category_id", idStr))
+		return
+	}
+	restored, err := h.svc.Restore(r.Context(), id)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("category_restore_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("category_id", id.String()))
+		return
+	}
+	setETag(w, entityTag(restored.Version))
+	writeJSON(w, http.StatusOK, categoryToResponse(restored))
+	h.logger.Info("category_restore_success", slog.String("request_id", reqID), slog.String("category_id", id.String()))
+}
+
+func (h CategoryHandler) handleArchive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, true)
+}
+
+func (h CategoryHandler) handleUnarchive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, false)
+}
+
+// setArchived serves the archive and unarchive routes, which only differ in the state they set.
+func (h CategoryHandler) setArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	reqID := middleware.GetReqID(r.Context())
+	event := "category_unarchive"
+	if archived {
+		event = "category_archive"
+	}
+	if _, ok := h.parseProjectID(w, r); !ok {
+		return
+	}
+	idStr := chi.URLParam(r, "categoryId")
+	id, err := parseUUID(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidCategoryId)
+		h.logger.Warn(event+"_invalid_id", slog.String("request_id", reqID), slog.String("category_id", idStr))
+		return
+	}
+	ctx, ok := ifMatch(r, id)
+	if !ok {
+		writeError(w, r, http.StatusPreconditionFailed, string(codePreconditionFailed), errPreconditionFailed)
+		h.logger.Warn(event+"_precondition_failed", slog.String("request_id", reqID), slog.String("category_id", id.String()))
+		return
+	}
+	var updated domain.Category
+	if archived {
+		updated, err = h.svc.Archive(ctx, id)
+	} else {
+		updated, err = h.svc.Unarchive(ctx, id)
+	}
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error(event+"_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("category_id", id.String()))
+		return
+	}
+	setETag(w, entityTag(updated.Version))
+	writeJSON(w, http.StatusOK, categoryToResponse(updated))
+	h.logger.Info(event+"_success", slog.String("request_id", reqID), slog.String("category_id", id.String()))
+}
+
 func (h CategoryHandler) parseProjectID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
 	idStr := chi.URLParam(r, projectIdParam)
 	id, err := parseUUID(idStr)
@@ -202,7 +346,29 @@ func categoryToResponse(c domain.Category) CategoryResponse {
 		ParentCategoryID: c.ParentCategoryID,
 		Name:             c.Name,
 		Description:      c.Description,
+		Rate:             rateToResponse(c.Rate),
+		Billable:         c.Billable,
 		CreatedAt:        c.CreatedAt.UTC(),
 		UpdatedAt:        c.UpdatedAt.UTC(),
+		ArchivedAt:       utcOrNil(c.ArchivedAt),
+		DeletedAt:        utcOrNil(c.DeletedAt),
+	}
+}
+
+func categoryNodesToResponse(nodes []domain.CategoryNode) []CategoryTreeNodeResponse {
+	resp := make([]CategoryTreeNodeResponse, 0, len(nodes))
+	for _, n := range nodes {
+		resp = append(resp, CategoryTreeNodeResponse{
+			CategoryResponse: categoryToResponse(n.Category),
+			OwnSeconds:       n.OwnSeconds,
+			SubtreeSeconds:   n.SubtreeSeconds,
+			Children:         categoryNodesToResponse(n.Children),
+		})
 	}
+	return resp
+}
+
+// categoryCursor positions a category listing, which is ordered by creation time, after c.
+func categoryCursor(c domain.Category) repository.Cursor {
+	return repository.Cursor{At: c.CreatedAt, ID: c.ID}
 }
@@ -26,28 +26,55 @@ type fakeCategoryService struct {
 	createFn        func(projectID uuid.UUID, name string, description *string, parentCategoryID *uuid.UUID) (domain.Category, error)
 	updateFn        func(id uuid.UUID, name string, description *string, parentCategoryID *uuid.UUID) (domain.Category, error)
 	deleteFn        func(id uuid.UUID) error
+	restoreFn       func(id uuid.UUID) (domain.Category, error)
+	archiveFn       func(id uuid.UUID, archived bool) (domain.Category, error)
 	getFn           func(id uuid.UUID) (domain.Category, error)
 	listByProjectFn func(projectID uuid.UUID) ([]domain.Category, error)
 	listChildrenFn  func(parentID uuid.UUID) ([]domain.Category, error)
+	treeFn          func(projectID uuid.UUID, start, end *time.Time) ([]domain.CategoryNode, error)
+
+	// Captured arguments for assertions
+	lastPage            repository.PageRequest
+	lastIncludeArchived bool
+	lastCtx             context.Context
 }
 
 func (f *fakeCategoryService) Create(_ context.Context, projectID uuid.UUID, name string, description *string, parentCategoryID *uuid.UUID) (domain.Category, error) {
 	return f.createFn(projectID, name, description, parentCategoryID)
 }
-func (f *fakeCategoryService) Update(_ context.Context, id uuid.UUID, name string, description *string, parentCategoryID *uuid.UUID) (domain.Category, error) {
+func (f *fakeCategoryService) Update(ctx context.Context, id uuid.UUID, name string, description *string, parentCategoryID *uuid.UUID) (domain.Category, error) {
+	f.lastCtx = ctx
 	return f.updateFn(id, name, description, parentCategoryID)
 }
-func (f *fakeCategoryService) Delete(_ context.Context, id uuid.UUID) error { return f.deleteFn(id) }
+func (f *fakeCategoryService) Delete(ctx context.Context, id uuid.UUID) error {
+	f.lastCtx = ctx
+	return f.deleteFn(id)
+}
+func (f *fakeCategoryService) Restore(_ context.Context, id uuid.UUID) (domain.Category, error) {
+	return f.restoreFn(id)
+}
+func (f *fakeCategoryService) Archive(_ context.Context, id uuid.UUID) (domain.Category, error) {
+	return f.archiveFn(id, true)
+}
+func (f *fakeCategoryService) Unarchive(_ context.Context, id uuid.UUID) (domain.Category, error) {
+	return f.archiveFn(id, false)
+}
 func (f *fakeCategoryService) GetByID(_ context.Context, id uuid.UUID) (domain.Category, error) {
 	return f.getFn(id)
 }
-func (f *fakeCategoryService) ListByProject(_ context.Context, projectID uuid.UUID) ([]domain.Category, error) {
+func (f *fakeCategoryService) ListByProject(_ context.Context, projectID uuid.UUID, includeArchived bool, page repository.PageRequest) ([]domain.Category, error) {
+	f.lastPage = page
+	f.lastIncludeArchived = includeArchived
 	return f.listByProjectFn(projectID)
 }
 func (f *fakeCategoryService) ListChildren(_ context.Context, parentID uuid.UUID) ([]domain.Category, error) {
 	return f.listChildrenFn(parentID)
 }
 
+func (f *fakeCategoryService) Tree(_ context.Context, projectID uuid.UUID, start *time.Time, end *time.Time) ([]domain.CategoryNode, error) {
+	return f.treeFn(projectID, start, end)
+}
+
 var _ service.CategoryService = (*fakeCategoryService)(nil)
 
 const categoriesRoute = "/api/projects/%s/categories"
@@ -323,11 +350,12 @@ func TestCategoryHandlerListHappyPath(t *testing.T) {
 	if w.Code != stdhttp.StatusOK {
 		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
 	}
-	var resp []CategoryResponse
-	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+	var page PageResponse[CategoryResponse]
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
 		t.Fatalf("invalid JSON: %v", err)
 	}
-	if len(resp) != 1 || resp[0].Name != "cat" {
+	resp := page.Items
+	if len(resp) != 1 || resp[0].Name != "cat" || page.NextCursor != nil {
 		t.Fatalf("unexpected list: %+v", resp)
 	}
 }
@@ -588,3 +616,130 @@ func TestCategoryHandlerUpdateUnknownFieldInvalidJSON(t *testing.T) {
 }
 
 func sprintf(format string, a ...any) string { return fmt.Sprintf(format, a...) }
+
+func TestCategoryHandlerETagAndConditionalRequests(t *testing.T) {
+	projectID := uuid.New()
+	id := uuid.New()
+	f := &fakeCategoryService{
+		getFn: func(uuid.UUID) (domain.Category, error) {
+			return domain.Category{ID: id, ProjectID: projectID, Name: "A", Version: 3}, nil
+		},
+		updateFn: func(uuid.UUID, string, *string, *uuid.UUID) (domain.Category, error) {
+			return domain.Category{ID: id, ProjectID: projectID, Name: "B", Version: 4}, nil
+		},
+		deleteFn: func(uuid.UUID) error { return repository.ErrVersionMismatch },
+	}
+	h := NewCategoryHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route("/api/projects/{projectId}/categories", h.RegisterRoutes)
+	url := sprintf(categoriesRoute+"/%s", projectID, id)
+	do := func(method string, body []byte, header string, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, bytes.NewReader(body))
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(stdhttp.MethodGet, nil, "", "")
+	if w.Code != stdhttp.StatusOK || w.Header().Get("ETag") != `"3"` {
+		t.Fatalf("expected 200 with ETag \"3\", got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+	w = do(stdhttp.MethodGet, nil, "If-None-Match", `"2", W/"3"`)
+	if w.Code != stdhttp.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("expected 304 without body, got %d %s", w.Code, w.Body.String())
+	}
+	w = do(stdhttp.MethodGet, nil, "If-None-Match", `"2"`)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+
+	w = do(stdhttp.MethodPatch, []byte(`{"name":"B"}`), "If-Match", `"3"`)
+	if w.Code != stdhttp.StatusOK || w.Header().Get("ETag") != `"4"` {
+		t.Fatalf("expected 200 with ETag \"4\", got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+	if v, ok := repository.ExpectedVersion(f.lastCtx, id); !ok || v != 3 {
+		t.Fatalf("expected version 3 passed to Update, got %d %v", v, ok)
+	}
+	w = do(stdhttp.MethodPatch, []byte(`{"name":"B"}`), "", "")
+	if _, ok := repository.ExpectedVersion(f.lastCtx, id); w.Code != stdhttp.StatusOK || ok {
+		t.Fatalf("expected an unconditional update, got %d", w.Code)
+	}
+	w = do(stdhttp.MethodPatch, []byte(`{"name":"B"}`), "If-Match", `W/"3"`)
+	if w.Code != stdhttp.StatusPreconditionFailed || !bytes.Contains(w.Body.Bytes(), []byte(codePreconditionFailed)) {
+		t.Fatalf("expected 412 for a weak If-Match, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = do(stdhttp.MethodDelete, nil, "If-Match", `"2"`)
+	if w.Code != stdhttp.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for a stale delete, got %d", w.Code)
+	}
+	if v, ok := repository.ExpectedVersion(f.lastCtx, id); !ok || v != 2 {
+		t.Fatalf("expected version 2 passed to Delete, got %d %v", v, ok)
+	}
+}
+
+func TestCategoryHandlerTreeNestsNodes(t *testing.T) {
+	projectID := uuid.New()
+	parent := domain.Category{ID: uuid.New(), ProjectID: projectID, Name: "Dev"}
+	child := domain.Category{ID: uuid.New(), ProjectID: projectID, ParentCategoryID: &parent.ID, Name: "API"}
+	var gotFrom, gotTo *time.Time
+	f := &fakeCategoryService{
+		treeFn: func(id uuid.UUID, start, end *time.Time) ([]domain.CategoryNode, error) {
+			if id != projectID {
+				t.Fatalf("unexpected project %s", id)
+			}
+			gotFrom, gotTo = start, end
+			if start != nil && end != nil && start.After(*end) {
+				return nil, service.ErrInvalidTimeRange
+			}
+			return []domain.CategoryNode{{
+				CategoryTotal: domain.CategoryTotal{Category: parent, OwnSeconds: 60, SubtreeSeconds: 3660},
+				Children: []domain.CategoryNode{{
+					CategoryTotal: domain.CategoryTotal{Category: child, OwnSeconds: 3600, SubtreeSeconds: 3600},
+					Children:      []domain.CategoryNode{},
+				}},
+			}}, nil
+		},
+	}
+	r := chi.NewRouter()
+	r.Route("/api/projects/{projectId}/categories", NewCategoryHandler(f, slog.Default()).RegisterRoutes)
+	url := sprintf(categoriesRoute+"/tree", projectID)
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(stdhttp.MethodGet, url+query, nil))
+		return w
+	}
+
+	w := get("?from=2025-11-03T00:00:00Z")
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	if gotFrom == nil || !gotFrom.Equal(time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)) || gotTo != nil {
+		t.Fatalf("unexpected bounds: %v %v", gotFrom, gotTo)
+	}
+	var roots []CategoryTreeNodeResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &roots); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if len(roots) != 1 || roots[0].ID != parent.ID || roots[0].OwnSeconds != 60 || roots[0].SubtreeSeconds != 3660 || len(roots[0].Children) != 1 {
+		t.Fatalf("unexpected tree: %s", w.Body.String())
+	}
+	if leaf := roots[0].Children[0]; leaf.ID != child.ID || *leaf.ParentCategoryID != parent.ID || leaf.OwnSeconds != 3600 {
+		t.Fatalf("unexpected child: %s", w.Body.String())
+	}
+	if !bytes.Contains(w.Body.Bytes(), []byte(`"children":[]`)) {
+		t.Fatalf("expected an empty children array on the leaf, got %s", w.Body.String())
+	}
+
+	w = get("?to=tomorrow")
+	if w.Code != stdhttp.StatusBadRequest || !bytes.Contains(w.Body.Bytes(), []byte(codeInvalidTime)) {
+		t.Fatalf("expected 400 invalid_time, got %d %s", w.Code, w.Body.String())
+	}
+	w = get("?from=2025-11-03T00:00:00Z&to=2025-11-02T00:00:00Z")
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+}
@@ -19,6 +19,31 @@ const (
 	codeInvalidJSON        apiErrorCode = "invalid_json"
 	codeInvalidID          apiErrorCode = "invalid_id"
 	codeInvalidTime        apiErrorCode = "invalid_time"
+	codeInvalidTimeRange   apiErrorCode = "invalid_time_range"
+	codeInvalidQuery       apiErrorCode = "invalid_query"
+	codeTimeEntryOverlap   apiErrorCode = "time_entry_overlap"
+	codeActiveTimerExists  apiErrorCode = "active_timer_exists"
+	codeInvalidTagName     apiErrorCode = "invalid_tag_name"
+	codeDuplicateTagName   apiErrorCode = "duplicate_tag_name"
+	codeDuplicateCategory  apiErrorCode = "duplicate_category_name"
+	codeInvalidTag         apiErrorCode = "invalid_tag"
+	codeCrossProjectTag    apiErrorCode = "cross_project_tag"
+	codeInvalidRate        apiErrorCode = "invalid_rate"
+	codeTimerPaused        apiErrorCode = "timer_paused"
+	codeTimerNotPaused     apiErrorCode = "timer_not_paused"
+	codeInvalidSplitTime   apiErrorCode = "invalid_split_time"
+	codeInvalidMerge       apiErrorCode = "invalid_merge"
+	codeCrossCategoryMerge apiErrorCode = "cross_category_merge"
+	codeNotContiguous      apiErrorCode = "entries_not_contiguous"
+	codeInvalidRounding    apiErrorCode = "invalid_rounding"
+	codeInvalidTarget      apiErrorCode = "invalid_target"
+	codeInvalidTimerTime   apiErrorCode = "invalid_timer_time"
+	codeNothingToContinue  apiErrorCode = "nothing_to_continue"
+	codePreconditionFailed apiErrorCode = "precondition_failed"
+	codeParentDeleted      apiErrorCode = "parent_deleted"
+	codeCategoryArchived   apiErrorCode = "category_archived"
+	codeInvalidGroupBy     apiErrorCode = "invalid_group_by"
+	codeInvalidTimezone    apiErrorCode = "invalid_timezone"
 	codeNotFound           apiErrorCode = "not_found"
 	codeInternal           apiErrorCode = "internal"
 )
@@ -26,9 +51,13 @@ const (
 const (
 	errInvalidJsonPayload              = "invalid JSON payload"
 	errInvalidProjectId                = "invalid projectId"
+	errInvalidIncludeArchived          = "invalid includeArchived"
 	errInvalidCategoryId               = "invalid categoryId"
+	errInvalidEntryId                  = "invalid entryId"
+	errInvalidTagId                    = "invalid tagId"
 	errInvalidTime                     = "invalid time"
 	errInvalidTimeRange                = "invalid time range"
+	errInvalidDryRun                   = "invalid dryRun"
 	statusCodeFailedExpectationMessage = "expected %d, got %d"
 )
 
@@ -45,6 +74,56 @@ func mapErrorToHTTP(err error) (status int, code apiErrorCode) {
 		return http.StatusConflict, codeCategoryCycle
 	case service.ErrNoActiveTimer:
 		return http.StatusConflict, codeNoActiveTimer
+	case service.ErrInvalidTimeRange:
+		return http.StatusBadRequest, codeInvalidTimeRange
+	case service.ErrTimeEntryOverlap:
+		return http.StatusConflict, codeTimeEntryOverlap
+	case service.ErrActiveTimerExists:
+		return http.StatusConflict, codeActiveTimerExists
+	case service.ErrInvalidTagName:
+		return http.StatusBadRequest, codeInvalidTagName
+	case service.ErrDuplicateTagName:
+		return http.StatusConflict, codeDuplicateTagName
+	case service.ErrDuplicateCategoryName:
+		return http.StatusConflict, codeDuplicateCategory
+	case service.ErrInvalidTag:
+		return http.StatusBadRequest, codeInvalidTag
+	case service.ErrCrossProjectTag:
+		return http.StatusBadRequest, codeCrossProjectTag
+	case service.ErrInvalidRate:
+		return http.StatusBadRequest, codeInvalidRate
+	case service.ErrTimerPaused:
+		return http.StatusConflict, codeTimerPaused
+	case service.ErrTimerNotPaused:
+		return http.StatusConflict, codeTimerNotPaused
+	case service.ErrInvalidSplitTime:
+		return http.StatusBadRequest, codeInvalidSplitTime
+	case service.ErrInvalidMerge:
+		return http.StatusBadRequest, codeInvalidMerge
+	case service.ErrCrossCategoryMerge:
+		return http.StatusBadRequest, codeCrossCategoryMerge
+	case service.ErrEntriesNotContiguous:
+		return http.StatusConflict, codeNotContiguous
+	case service.ErrInvalidRounding:
+		return http.StatusBadRequest, codeInvalidRounding
+	case service.ErrInvalidTarget:
+		return http.StatusBadRequest, codeInvalidTarget
+	case service.ErrInvalidTimerTime:
+		return http.StatusBadRequest, codeInvalidTimerTime
+	case service.ErrNothingToContinue:
+		return http.StatusConflict, codeNothingToContinue
+	case service.ErrCategoryArchived:
+		return http.StatusConflict, codeCategoryArchived
+	case service.ErrInvalidGroupBy:
+		return http.StatusBadRequest, codeInvalidGroupBy
+	case service.ErrInvalidTimezone:
+		return http.StatusBadRequest, codeInvalidTimezone
+	case repository.ErrRunningEntryConflict:
+		return http.StatusConflict, codeActiveTimerExists
+	case repository.ErrVersionMismatch:
+		return http.StatusPreconditionFailed, codePreconditionFailed
+	case repository.ErrParentDeleted:
+		return http.StatusConflict, codeParentDeleted
 	case repository.ErrNotFound:
 		return http.StatusNotFound, codeNotFound
 	default:
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Gargair/clockwork/server/internal/repository"
+)
+
+// errPreconditionFailed is the message of requests whose If-Match names no current version.
+const errPreconditionFailed = "If-Match does not match the current version"
+
+// entityTag renders a row version as a strong entity tag.
+func entityTag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// derivedEntityTag renders a row version followed by a checksum of data the response derives
+// from other rows, such as amounts computed from rates, so that the tag changes with either.
+// ifMatch only compares the version.
+func derivedEntityTag(version int64, derived any) string {
+	sum := fnv.New64a()
+	_ = json.NewEncoder(sum).Encode(derived)
+	return `"` + strconv.FormatInt(version, 10) + "-" + strconv.FormatUint(sum.Sum64(), 36) + `"`
+}
+
+// setETag sets the ETag header of a response carrying a resource with the entity tag tag.
+func setETag(w http.ResponseWriter, tag string) {
+	w.Header().Set("ETag", tag)
+}
+
+// notModified answers a conditional GET: when If-None-Match lists tag (weakly compared) or "*",
+// it writes 304 Not Modified and reports true.
+func notModified(w http.ResponseWriter, r *http.Request, tag string) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			setETag(w, tag)
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatch applies the If-Match header of r to writes of id. It returns a context under which they
+// only succeed while id is still at the version the header names. Without the header, or with
+// "*", writes stay unconditional. Only the version of a derived tag counts. ok is false when the
+// header names no version this API hands out (weak or malformed tags, lists of tags); such requests
+// must fail with 412.
+func ifMatch(r *http.Request, id uuid.UUID) (ctx context.Context, ok bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return r.Context(), true
+	}
+	raw, found := strings.CutPrefix(header, `"`)
+	raw, closed := strings.CutSuffix(raw, `"`)
+	raw, _, _ = strings.Cut(raw, "-")
+	version, err := strconv.ParseInt(raw, 10, 64)
+	if !found || !closed || err != nil {
+		return nil, false
+	}
+	return repository.WithExpectedVersion(r.Context(), id, version), true
+}
@@ -23,13 +23,14 @@ func NewRouter(cfg config.Config, dbConn *sql.DB, clk clock.Clock, logger *slog.
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Recoverer)
 	r.Use(loggingMiddleware(logger))
+	r.Use(actorMiddleware)
 
 	// CORS configuration
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   cfg.AllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
-		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID", "X-API-Version", "X-Actor", "If-Match", "If-None-Match"},
+		ExposedHeaders:   []string{"X-Request-ID", "ETag"},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
@@ -31,6 +32,41 @@ func decodeJSON(r *http.Request, dst any) error {
 	return nil
 }
 
+// decodeOptionalJSON behaves like decodeJSON but accepts an empty request body,
+// leaving dst untouched in that case.
+func decodeOptionalJSON(r *http.Request, dst any) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	err := decodeJSON(r, dst)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+// nullableString is a JSON string field that distinguishes an absent field from an explicit null.
+// Set is true whenever the field was present in the payload; Value is nil for null.
+type nullableString struct {
+	Set   bool
+	Value *string
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *nullableString) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	n.Value = &s
+	return nil
+}
+
 // writeJSON writes v as a JSON response with the given status code.
 func writeJSON(w http.ResponseWriter, status int, v any) {
 	w.Header().Set("Content-Type", "application/json")
@@ -56,3 +92,48 @@ func parseUUID(str string) (uuid.UUID, error) {
 func parseTimeRFC3339(str string) (time.Time, error) {
 	return time.Parse(time.RFC3339, str)
 }
+
+// parseOptionalTimeRFC3339 parses an optional RFC3339 time. A nil input yields nil.
+func parseOptionalTimeRFC3339(str *string) (*time.Time, error) {
+	if str == nil {
+		return nil, nil
+	}
+	t, err := parseTimeRFC3339(*str)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// parseOptionalBool parses a boolean query value. An empty string yields false.
+func parseOptionalBool(str string) (bool, error) {
+	if str == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(str)
+}
+
+// parseUUIDs parses each string as a UUID. A nil input yields a nil slice.
+func parseUUIDs(strs []string) ([]uuid.UUID, error) {
+	if strs == nil {
+		return nil, nil
+	}
+	out := make([]uuid.UUID, 0, len(strs))
+	for _, s := range strs {
+		u, err := parseUUID(s)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, nil
+}
+
+// utcOrNil returns t in UTC. A nil input yields nil.
+func utcOrNil(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
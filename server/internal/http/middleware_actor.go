@@ -0,0 +1,26 @@
+package http
+
+import (
+	stdhttp "net/http"
+	"strings"
+
+	"github.com/Gargair/clockwork/server/internal/service"
+)
+
+// actorHeader names the client making a request; the API has no authentication, so the name
+// is taken on trust and only serves to attribute changes in the audit log.
+const actorHeader = "X-Actor"
+
+// anonymousActor is the actor of requests without an actorHeader.
+const anonymousActor = "anonymous"
+
+// actorMiddleware attributes the changes made while serving a request to its actorHeader.
+func actorMiddleware(next stdhttp.Handler) stdhttp.Handler {
+	return stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+		actor := strings.TrimSpace(r.Header.Get(actorHeader))
+		if actor == "" {
+			actor = anonymousActor
+		}
+		next.ServeHTTP(w, r.WithContext(service.WithActor(r.Context(), actor)))
+	})
+}
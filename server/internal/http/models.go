@@ -1,6 +1,7 @@
 package http
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -21,11 +22,35 @@ type ProjectUpdateRequest struct {
 
 // ProjectResponse is the API response shape for a project.
 type ProjectResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description *string   `json:"description,omitempty"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID          uuid.UUID               `json:"id"`
+	Name        string                  `json:"name"`
+	Description *string                 `json:"description,omitempty"`
+	Rate        *RateResponse           `json:"rate"`
+	Settings    ProjectSettingsResponse `json:"settings"`
+	CreatedAt   time.Time               `json:"createdAt"`
+	UpdatedAt   time.Time               `json:"updatedAt"`
+	ArchivedAt  *time.Time              `json:"archivedAt"`
+	DeletedAt   *time.Time              `json:"deletedAt,omitempty"`
+}
+
+// ProjectSettingsRequest updates project settings. Omitted fields are unchanged.
+type ProjectSettingsRequest struct {
+	ConcurrentTimers *bool            `json:"concurrentTimers,omitempty"`
+	Rounding         *RoundingPayload `json:"rounding,omitempty"`
+}
+
+// ProjectSettingsResponse is the API response shape for project settings.
+type ProjectSettingsResponse struct {
+	ConcurrentTimers bool            `json:"concurrentTimers"`
+	Rounding         RoundingPayload `json:"rounding"`
+}
+
+// RoundingPayload describes the rounding of reported durations. An incrementSeconds of 0
+// disables rounding; mode is up, down or nearest and scope is entry or day.
+type RoundingPayload struct {
+	IncrementSeconds int32  `json:"incrementSeconds"`
+	Mode             string `json:"mode"`
+	Scope            string `json:"scope"`
 }
 
 // CategoryCreateRequest represents the payload to create a category.
@@ -45,35 +70,265 @@ type CategoryUpdateRequest struct {
 
 // CategoryResponse is the API response shape for a category.
 type CategoryResponse struct {
-	ID               uuid.UUID  `json:"id"`
-	ProjectID        uuid.UUID  `json:"projectId"`
-	ParentCategoryID *uuid.UUID `json:"parentCategoryId"`
-	Name             string     `json:"name"`
-	Description      *string    `json:"description,omitempty"`
-	CreatedAt        time.Time  `json:"createdAt"`
-	UpdatedAt        time.Time  `json:"updatedAt"`
+	ID               uuid.UUID     `json:"id"`
+	ProjectID        uuid.UUID     `json:"projectId"`
+	ParentCategoryID *uuid.UUID    `json:"parentCategoryId"`
+	Name             string        `json:"name"`
+	Description      *string       `json:"description,omitempty"`
+	Rate             *RateResponse `json:"rate"`
+	Billable         bool          `json:"billable"`
+	CreatedAt        time.Time     `json:"createdAt"`
+	UpdatedAt        time.Time     `json:"updatedAt"`
+	ArchivedAt       *time.Time    `json:"archivedAt"`
+	DeletedAt        *time.Time    `json:"deletedAt,omitempty"`
+}
+
+// CategoryTreeNodeResponse is a category in the category tree. OwnSeconds is the time tracked in
+// the category itself, SubtreeSeconds adds the time of all its descendants.
+type CategoryTreeNodeResponse struct {
+	CategoryResponse
+	OwnSeconds     int64                      `json:"ownSeconds"`
+	SubtreeSeconds int64                      `json:"subtreeSeconds"`
+	Children       []CategoryTreeNodeResponse `json:"children"`
+}
+
+// RateRequest is an hourly rate in minor units of an ISO 4217 currency.
+type RateRequest struct {
+	HourlyRateCents int64  `json:"hourlyRateCents"`
+	Currency        string `json:"currency"`
+}
+
+// RateResponse is the API response shape for an hourly rate.
+type RateResponse struct {
+	HourlyRateCents int64  `json:"hourlyRateCents"`
+	Currency        string `json:"currency"`
+}
+
+// MoneyResponse is an amount in minor units of an ISO 4217 currency.
+type MoneyResponse struct {
+	AmountCents int64  `json:"amountCents"`
+	Currency    string `json:"currency"`
+}
+
+// ProjectBillingRequest sets the project's hourly rate; a null rate clears it.
+type ProjectBillingRequest struct {
+	Rate *RateRequest `json:"rate"`
+}
+
+// CategoryBillingRequest sets a category's own rate (null inherits) and optionally its billable default.
+type CategoryBillingRequest struct {
+	Rate     *RateRequest `json:"rate"`
+	Billable *bool        `json:"billable,omitempty"`
+}
+
+// BillingLineResponse is the billing breakdown of one category.
+type BillingLineResponse struct {
+	CategoryID                uuid.UUID      `json:"categoryId"`
+	CategoryName              string         `json:"categoryName"`
+	Rate                      *RateResponse  `json:"rate"`
+	BillableSeconds           int64          `json:"billableSeconds"`
+	NonBillableSeconds        int64          `json:"nonBillableSeconds"`
+	RoundedBillableSeconds    int64          `json:"roundedBillableSeconds"`
+	RoundedNonBillableSeconds int64          `json:"roundedNonBillableSeconds"`
+	Amount                    *MoneyResponse `json:"amount"`
+}
+
+// BillingSummaryResponse aggregates billable time and amounts of a project over a range.
+type BillingSummaryResponse struct {
+	ProjectID uuid.UUID             `json:"projectId"`
+	From      time.Time             `json:"from"`
+	To        time.Time             `json:"to"`
+	Lines     []BillingLineResponse `json:"lines"`
+	Totals    []MoneyResponse       `json:"totals"`
+}
+
+// SummaryReportResponse totals the worked time inside [from, to) per group.
+// TotalSeconds counts time carrying several tags once.
+type SummaryReportResponse struct {
+	From                time.Time            `json:"from"`
+	To                  time.Time            `json:"to"`
+	Timezone            string               `json:"timezone"`
+	GroupBy             []string             `json:"groupBy"`
+	Rows                []SummaryRowResponse `json:"rows"`
+	TotalSeconds        int64                `json:"totalSeconds"`
+	RoundedTotalSeconds int64                `json:"roundedTotalSeconds"`
+}
+
+// SummaryRowResponse is the worked time of one group. Fields of dimensions not grouped by are
+// null, as are the tag fields of the row of untagged time. Period is the start of the day,
+// week or month in the report's time zone.
+type SummaryRowResponse struct {
+	ProjectID      *uuid.UUID `json:"projectId"`
+	ProjectName    *string    `json:"projectName"`
+	CategoryID     *uuid.UUID `json:"categoryId"`
+	CategoryName   *string    `json:"categoryName"`
+	TagID          *uuid.UUID `json:"tagId"`
+	TagName        *string    `json:"tagName"`
+	Period         *time.Time `json:"period"`
+	Seconds        int64      `json:"seconds"`
+	RoundedSeconds int64      `json:"roundedSeconds"`
+}
+
+// TagCreateRequest represents the payload to create a tag.
+type TagCreateRequest struct {
+	Name string `json:"name"`
+}
+
+// TagUpdateRequest represents the payload to rename a tag.
+type TagUpdateRequest struct {
+	Name string `json:"name"`
+}
+
+// TagResponse is the API response shape for a tag.
+type TagResponse struct {
+	ID        uuid.UUID `json:"id"`
+	ProjectID uuid.UUID `json:"projectId"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TagTotalResponse reports the tracked seconds attributed to a tag.
+type TagTotalResponse struct {
+	TagID          uuid.UUID `json:"tagId"`
+	Name           string    `json:"name"`
+	Seconds        int64     `json:"seconds"`
+	RoundedSeconds int64     `json:"roundedSeconds"`
 }
 
 // TimeStartRequest represents the payload to start a timer.
+// TargetSeconds has the server stop the timer after that much worked time; Break marks a break timer.
+// StartedAt optionally backdates the start as an RFC3339 string.
 type TimeStartRequest struct {
+	CategoryID    string   `json:"categoryId"`
+	Note          *string  `json:"note,omitempty"`
+	TagIDs        []string `json:"tagIds,omitempty"`
+	Billable      *bool    `json:"billable,omitempty"`
+	TargetSeconds *int32   `json:"targetSeconds,omitempty"`
+	Break         bool     `json:"break,omitempty"`
+	StartedAt     *string  `json:"startedAt,omitempty"`
+}
+
+// TimeContinueRequest represents the optional payload to continue an entry.
+// Without EntryID the most recently stopped entry is continued.
+type TimeContinueRequest struct {
+	EntryID *string `json:"entryId,omitempty"`
+}
+
+// TimePauseRequest represents the optional payload to pause or resume a timer. EntryID names the
+// entry, CategoryID the category whose running entry is meant; without either the most recently
+// started running entry is paused or resumed.
+type TimePauseRequest struct {
+	EntryID    *string `json:"entryId,omitempty"`
+	CategoryID *string `json:"categoryId,omitempty"`
+}
+
+// TimeStopRequest represents the optional payload to stop the active timer.
+// StoppedAt optionally sets an earlier stop time as an RFC3339 string.
+type TimeStopRequest struct {
+	Note      *string `json:"note,omitempty"`
+	StoppedAt *string `json:"stoppedAt,omitempty"`
+}
+
+// TimeEntryCreateRequest represents the payload to record a completed entry after the fact.
+// Timestamps are RFC3339 strings so that malformed values map to invalid_time.
+type TimeEntryCreateRequest struct {
 	CategoryID string `json:"categoryId"`
+	StartedAt  string `json:"startedAt"`
+	StoppedAt  string `json:"stoppedAt"`
+}
+
+// TimeEntryUpdateRequest represents the payload to edit a time entry.
+// Absent fields are left unchanged; an explicit null stoppedAt makes the entry running again
+// and an explicit null or empty note removes the note. A present tagIds replaces all tags.
+type TimeEntryUpdateRequest struct {
+	CategoryID *string        `json:"categoryId,omitempty"`
+	StartedAt  *string        `json:"startedAt,omitempty"`
+	StoppedAt  nullableString `json:"stoppedAt"`
+	Note       nullableString `json:"note"`
+	TagIDs     *[]string      `json:"tagIds,omitempty"`
+	Billable   *bool          `json:"billable,omitempty"`
+}
+
+// TimeEntrySplitRequest represents the payload to split an entry at a given instant.
+// CategoryID optionally moves the second half to another category.
+type TimeEntrySplitRequest struct {
+	At         string  `json:"at"`
+	CategoryID *string `json:"categoryId,omitempty"`
+}
+
+// TimeEntryMergeRequest represents the payload to merge entries of one category.
+// GapToleranceSeconds is the largest gap allowed between consecutive entries (default 0).
+type TimeEntryMergeRequest struct {
+	EntryIDs            []string `json:"entryIds"`
+	GapToleranceSeconds int      `json:"gapToleranceSeconds,omitempty"`
 }
 
 // TimeEntryResponse is the API response shape for a time entry.
 type TimeEntryResponse struct {
-	ID              uuid.UUID  `json:"id"`
-	CategoryID      uuid.UUID  `json:"categoryId"`
-	StartedAt       time.Time  `json:"startedAt"`
-	StoppedAt       *time.Time `json:"stoppedAt"`
-	DurationSeconds *int32     `json:"durationSeconds"`
-	CreatedAt       time.Time  `json:"createdAt"`
-	UpdatedAt       time.Time  `json:"updatedAt"`
+	ID                     uuid.UUID      `json:"id"`
+	CategoryID             uuid.UUID      `json:"categoryId"`
+	CategoryName           string         `json:"categoryName,omitempty"`
+	CategoryPath           []string       `json:"categoryPath,omitempty"`
+	StartedAt              time.Time      `json:"startedAt"`
+	StoppedAt              *time.Time     `json:"stoppedAt"`
+	DurationSeconds        *int32         `json:"durationSeconds"`
+	RoundedDurationSeconds *int32         `json:"roundedDurationSeconds"`
+	Note                   *string        `json:"note"`
+	TagIDs                 []uuid.UUID    `json:"tagIds"`
+	Billable               bool           `json:"billable"`
+	BillableAmount         *MoneyResponse `json:"billableAmount"`
+	AutoStopped            bool           `json:"autoStopped"`
+	TargetSeconds          *int32         `json:"targetSeconds"`
+	Break                  bool           `json:"break"`
+	Paused                 bool           `json:"paused"`
+	ElapsedSeconds         *int32         `json:"elapsedSeconds"`
+	RemainingSeconds       *int32         `json:"remainingSeconds"`
+	ClippedSeconds         *int32         `json:"clippedSeconds,omitempty"`
+	CreatedAt              time.Time      `json:"createdAt"`
+	UpdatedAt              time.Time      `json:"updatedAt"`
+	DeletedAt              *time.Time     `json:"deletedAt,omitempty"`
+}
+
+// TrashResponse lists the deleted projects, categories and time entries that have not been purged yet.
+type TrashResponse struct {
+	Projects   []ProjectResponse   `json:"projects"`
+	Categories []CategoryResponse  `json:"categories"`
+	Entries    []TimeEntryResponse `json:"entries"`
+}
+
+// TimeEntryBulkDeleteResponse reports the entries removed by a bulk delete,
+// or the entries that would be removed when DryRun is true.
+type TimeEntryBulkDeleteResponse struct {
+	DryRun  bool                `json:"dryRun"`
+	Count   int                 `json:"count"`
+	Entries []TimeEntryResponse `json:"entries"`
+}
+
+// TimeEntrySplitResponse returns both halves of a split entry.
+type TimeEntrySplitResponse struct {
+	First  TimeEntryResponse `json:"first"`
+	Second TimeEntryResponse `json:"second"`
 }
 
 // ActiveTimerResponse represents the response of the active timer endpoint when an entry exists.
 // When no active timer exists, the endpoint should return a JSON null.
 type ActiveTimerResponse = TimeEntryResponse
 
+// AuditEventResponse is the API response shape for an audit log event.
+// Before is null for creations and After for deletions.
+type AuditEventResponse struct {
+	ID         uuid.UUID       `json:"id"`
+	Actor      string          `json:"actor"`
+	RequestID  *string         `json:"requestId"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entityType"`
+	EntityID   uuid.UUID       `json:"entityId"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
 // ErrorResponse is the standard error envelope for API errors.
 type ErrorResponse struct {
 	Code      string `json:"code"`
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Gargair/clockwork/server/internal/repository"
+)
+
+// apiVersionHeader selects the response shape of list endpoints. Version 1 returns bare arrays
+// holding every item; version 2, the default, returns one page wrapped in a PageResponse.
+const apiVersionHeader = "X-API-Version"
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+var (
+	errInvalidAPIVersion = errors.New("invalid " + apiVersionHeader)
+	errInvalidLimit      = errors.New("invalid limit")
+	errInvalidCursor     = errors.New("invalid cursor")
+)
+
+// PageResponse wraps one page of a list. NextCursor is null on the last page.
+type PageResponse[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"nextCursor"`
+}
+
+// listPage holds the paging options of a list request.
+type listPage struct {
+	legacy bool
+	limit  int
+	after  *repository.Cursor
+	// order names the ordering the cursor belongs to; cursors of another ordering are rejected.
+	order string
+}
+
+// pageCursor is the decoded form of the opaque cursor handed to clients.
+type pageCursor struct {
+	Order   string    `json:"o,omitempty"`
+	At      time.Time `json:"t,omitzero"`
+	Seconds int64     `json:"s,omitempty"`
+	ID      uuid.UUID `json:"id"`
+}
+
+// parseListPage reads the API version header and the limit and cursor query params of a list
+// request ordered by order. Version 1 lists are unpaginated, so limit and cursor are ignored there.
+func parseListPage(r *http.Request, order string) (listPage, error) {
+	p := listPage{limit: defaultPageLimit, order: order}
+	switch r.Header.Get(apiVersionHeader) {
+	case "", "2":
+	case "1":
+		p.legacy = true
+		return p, nil
+	default:
+		return listPage{}, errInvalidAPIVersion
+	}
+	q := r.URL.Query()
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxPageLimit {
+			return listPage{}, errInvalidLimit
+		}
+		p.limit = n
+	}
+	if s := q.Get("cursor"); s != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			return listPage{}, errInvalidCursor
+		}
+		var c pageCursor
+		if err := json.Unmarshal(raw, &c); err != nil || c.Order != order {
+			return listPage{}, errInvalidCursor
+		}
+		p.after = &repository.Cursor{At: c.At, Seconds: c.Seconds, ID: c.ID}
+	}
+	return p, nil
+}
+
+// request returns the PageRequest to pass down. It asks for one item more than the page holds
+// to learn whether another page follows.
+func (p listPage) request() repository.PageRequest {
+	if p.legacy {
+		return repository.PageRequest{}
+	}
+	return repository.PageRequest{After: p.after, Limit: p.limit + 1}
+}
+
+// encodeCursor renders c as an opaque cursor bound to order.
+func encodeCursor(order string, c repository.Cursor) string {
+	raw, _ := json.Marshal(pageCursor{Order: order, At: c.At, Seconds: c.Seconds, ID: c.ID})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// writeList writes items fetched with p.request() either as a bare array (version 1) or as a
+// PageResponse carrying the cursor of the last item when more follow. It returns the number of
+// items written.
+func writeList[T any, R any](w http.ResponseWriter, p listPage, items []T, toResponse func(T) R, cursorOf func(T) repository.Cursor) int {
+	var next *string
+	if !p.legacy && len(items) > p.limit {
+		items = items[:p.limit]
+		c := encodeCursor(p.order, cursorOf(items[len(items)-1]))
+		next = &c
+	}
+	resp := make([]R, 0, len(items))
+	for _, it := range items {
+		resp = append(resp, toResponse(it))
+	}
+	if p.legacy {
+		writeJSON(w, http.StatusOK, resp)
+	} else {
+		writeJSON(w, http.StatusOK, PageResponse[R]{Items: resp, NextCursor: next})
+	}
+	return len(resp)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 
 	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
 	"github.com/Gargair/clockwork/server/internal/service"
 )
 
@@ -32,7 +33,11 @@ func (h ProjectHandler) RegisterRoutes(r chi.Router) {
 	r.Get("/", h.handleList)
 	r.Get("/"+projectIdRoute, h.handleGetByID)
 	r.Patch("/"+projectIdRoute, h.handleUpdate)
+	r.Patch("/"+projectIdRoute+"/settings", h.handleUpdateSettings)
 	r.Delete("/"+projectIdRoute, h.handleDelete)
+	r.Post("/"+projectIdRoute+"/restore", h.handleRestore)
+	r.Post("/"+projectIdRoute+"/archive", h.handleArchive)
+	r.Post("/"+projectIdRoute+"/unarchive", h.handleUnarchive)
 }
 
 func (h ProjectHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
@@ -58,18 +63,26 @@ func (h ProjectHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 func (h ProjectHandler) handleList(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetReqID(r.Context())
 	h.logger.Info("project_list_start", slog.String("request_id", reqID))
-	items, err := h.svc.List(r.Context())
+	page, err := parseListPage(r, "createdAt")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidQuery), err.Error())
+		h.logger.Warn("project_list_invalid_page", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	includeArchived, err := parseOptionalBool(r.URL.Query().Get("includeArchived"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidQuery), errInvalidIncludeArchived)
+		h.logger.Warn("project_list_invalid_include_archived", slog.String("request_id", reqID), slog.String("include_archived", r.URL.Query().Get("includeArchived")))
+		return
+	}
+	items, err := h.svc.List(r.Context(), includeArchived, page.request())
 	if err != nil {
 		writeMappedError(w, r, err)
 		h.logger.Error("project_list_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
 		return
 	}
-	resp := make([]ProjectResponse, 0, len(items))
-	for _, it := range items {
-		resp = append(resp, projectToResponse(it))
-	}
-	writeJSON(w, http.StatusOK, resp)
-	h.logger.Info("project_list_success", slog.String("request_id", reqID), slog.Int("count", len(resp)))
+	count := writeList(w, page, items, projectToResponse, projectCursor)
+	h.logger.Info("project_list_success", slog.String("request_id", reqID), slog.Int("count", count))
 }
 
 func (h ProjectHandler) handleGetByID(w http.ResponseWriter, r *http.Request) {
@@ -87,6 +100,11 @@ func (h ProjectHandler) handleGetByID(w http.ResponseWriter, r *http.Request) {
 		h.logger.Error("project_get_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("project_id", id.String()))
 		return
 	}
+	if notModified(w, r, entityTag(p.Version)) {
+		h.logger.Info("project_get_not_modified", slog.String("request_id", reqID), slog.String("project_id", id.String()))
+		return
+	}
+	setETag(w, entityTag(p.Version))
 	writeJSON(w, http.StatusOK, projectToResponse(p))
 	h.logger.Info("project_get_success", slog.String("request_id", reqID), slog.String("project_id", id.String()))
 }
@@ -100,6 +118,12 @@ func (h ProjectHandler) handleUpdate(w http.ResponseWriter, r *http.Request) {
 		h.logger.Warn("project_update_invalid_id", slog.String("request_id", reqID), slog.String("project_id", idStr))
 		return
 	}
+	ctx, ok := ifMatch(r, id)
+	if !ok {
+		writeError(w, r, http.StatusPreconditionFailed, string(codePreconditionFailed), errPreconditionFailed)
+		h.logger.Warn("project_update_precondition_failed", slog.String("request_id", reqID), slog.String("project_id", id.String()))
+		return
+	}
 
 	var req ProjectUpdateRequest
 	if err := decodeJSON(r, &req); err != nil {
@@ -117,16 +141,57 @@ func (h ProjectHandler) handleUpdate(w http.ResponseWriter, r *http.Request) {
 	name := strings.TrimSpace(*req.Name)
 	desc := req.Description
 
-	updated, err := h.svc.Update(r.Context(), id, name, desc)
+	updated, err := h.svc.Update(ctx, id, name, desc)
 	if err != nil {
 		writeMappedError(w, r, err)
 		h.logger.Error("project_update_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("project_id", id.String()))
 		return
 	}
+	setETag(w, entityTag(updated.Version))
 	writeJSON(w, http.StatusOK, projectToResponse(updated))
 	h.logger.Info("project_update_success", slog.String("request_id", reqID), slog.String("project_id", id.String()))
 }
 
+func (h ProjectHandler) handleUpdateSettings(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	idStr := chi.URLParam(r, "projectId")
+	id, err := parseUUID(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidProjectId)
+		h.logger.Warn("project_settings_invalid_id", slog.String("request_id", reqID), slog.String("project_id", idStr))
+		return
+	}
+	ctx, ok := ifMatch(r, id)
+	if !ok {
+		writeError(w, r, http.StatusPreconditionFailed, string(codePreconditionFailed), errPreconditionFailed)
+		h.logger.Warn("project_settings_precondition_failed", slog.String("request_id", reqID), slog.String("project_id", id.String()))
+		return
+	}
+	var req ProjectSettingsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidJSON), errInvalidJsonPayload)
+		h.logger.Warn("project_settings_invalid_json", slog.String("request_id", reqID))
+		return
+	}
+	update := service.ProjectSettingsUpdate{ConcurrentTimers: req.ConcurrentTimers}
+	if req.Rounding != nil {
+		update.Rounding = &domain.Rounding{
+			IncrementSeconds: req.Rounding.IncrementSeconds,
+			Mode:             domain.RoundingMode(req.Rounding.Mode),
+			Scope:            domain.RoundingScope(req.Rounding.Scope),
+		}
+	}
+	updated, err := h.svc.UpdateSettings(ctx, id, update)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("project_settings_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("project_id", id.String()))
+		return
+	}
+	setETag(w, entityTag(updated.Version))
+	writeJSON(w, http.StatusOK, projectToResponse(updated))
+	h.logger.Info("project_settings_success", slog.String("request_id", reqID), slog.String("project_id", id.String()))
+}
+
 func (h ProjectHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetReqID(r.Context())
 	idStr := chi.URLParam(r, "projectId")
@@ -136,7 +201,13 @@ func (h ProjectHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
 		h.logger.Warn("project_delete_invalid_id", slog.String("request_id", reqID), slog.String("project_id", idStr))
 		return
 	}
-	if err := h.svc.Delete(r.Context(), id); err != nil {
+	ctx, ok := ifMatch(r, id)
+	if !ok {
+		writeError(w, r, http.StatusPreconditionFailed, string(codePreconditionFailed), errPreconditionFailed)
+		h.logger.Warn("project_delete_precondition_failed", slog.String("request_id", reqID), slog.String("project_id", id.String()))
+		return
+	}
+	if err := h.svc.Delete(ctx, id); err != nil {
 		writeMappedError(w, r, err)
 		h.logger.Error("project_delete_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("project_id", id.String()))
 		return
@@ -145,12 +216,92 @@ func (h ProjectHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("project_delete_success", slog.String("request_id", reqID), slog.String("project_id", id.String()))
 }
 
+func (h ProjectHandler) handleRestore(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	idStr := chi.URLParam(r, "projectId")
+	id, err := parseUUID(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidProjectId)
+		h.logger.Warn("project_restore_invalid_id", slog.String("request_id", reqID), slog.String("project_id", idStr))
+		return
+	}
+	restored, err := h.svc.Restore(r.Context(), id)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("project_restore_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("project_id", id.String()))
+		return
+	}
+	setETag(w, entityTag(restored.Version))
+	writeJSON(w, http.StatusOK, projectToResponse(restored))
+	h.logger.Info("project_restore_success", slog.String("request_id", reqID), slog.String("project_id", id.String()))
+}
+
+func (h ProjectHandler) handleArchive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, true)
+}
+
+func (h ProjectHandler) handleUnarchive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, false)
+}
+
+// setArchived serves the archive and unarchive routes, which only differ in the state they set.
+func (h ProjectHandler) setArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	reqID := middleware.GetReqID(r.Context())
+	event := "project_unarchive"
+	if archived {
+		event = "project_archive"
+	}
+	idStr := chi.URLParam(r, "projectId")
+	id, err := parseUUID(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidProjectId)
+		h.logger.Warn(event+"_invalid_id", slog.String("request_id", reqID), slog.String("project_id", idStr))
+		return
+	}
+	ctx, ok := ifMatch(r, id)
+	if !ok {
+		writeError(w, r, http.StatusPreconditionFailed, string(codePreconditionFailed), errPreconditionFailed)
+		h.logger.Warn(event+"_precondition_failed", slog.String("request_id", reqID), slog.String("project_id", id.String()))
+		return
+	}
+	var updated domain.Project
+	if archived {
+		updated, err = h.svc.Archive(ctx, id)
+	} else {
+		updated, err = h.svc.Unarchive(ctx, id)
+	}
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error(event+"_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("project_id", id.String()))
+		return
+	}
+	setETag(w, entityTag(updated.Version))
+	writeJSON(w, http.StatusOK, projectToResponse(updated))
+	h.logger.Info(event+"_success", slog.String("request_id", reqID), slog.String("project_id", id.String()))
+}
+
 func projectToResponse(p domain.Project) ProjectResponse {
 	return ProjectResponse{
 		ID:          p.ID,
 		Name:        p.Name,
 		Description: p.Description,
-		CreatedAt:   p.CreatedAt.UTC(),
-		UpdatedAt:   p.UpdatedAt.UTC(),
+		Rate:        rateToResponse(p.Rate),
+		Settings: ProjectSettingsResponse{
+			ConcurrentTimers: p.Settings.ConcurrentTimers,
+			Rounding: RoundingPayload{
+				IncrementSeconds: p.Settings.Rounding.IncrementSeconds,
+				Mode:             string(p.Settings.Rounding.Mode),
+				Scope:            string(p.Settings.Rounding.Scope),
+			},
+		},
+		CreatedAt:  p.CreatedAt.UTC(),
+		UpdatedAt:  p.UpdatedAt.UTC(),
+		ArchivedAt: utcOrNil(p.ArchivedAt),
+		DeletedAt:  utcOrNil(p.DeletedAt),
 	}
 }
+
+// projectCursor positions a project listing, which is ordered by creation time, after p.
+func projectCursor(p domain.Project) repository.Cursor {
+	return repository.Cursor{At: p.CreatedAt, ID: p.ID}
+}
@@ -23,11 +23,19 @@ import (
 )
 
 type fakeProjectService struct {
-	createFn func(name string, description *string) (domain.Project, error)
-	updateFn func(id uuid.UUID, name string, description *string) (domain.Project, error)
-	deleteFn func(id uuid.UUID) error
-	getFn    func(id uuid.UUID) (domain.Project, error)
-	listFn   func() ([]domain.Project, error)
+	createFn  func(name string, description *string) (domain.Project, error)
+	updateFn  func(id uuid.UUID, name string, description *string) (domain.Project, error)
+	deleteFn  func(id uuid.UUID) error
+	restoreFn func(id uuid.UUID) (domain.Project, error)
+	archiveFn func(id uuid.UUID, archived bool) (domain.Project, error)
+	getFn     func(id uuid.UUID) (domain.Project, error)
+	listFn    func() ([]domain.Project, error)
+
+	settingsFn func(id uuid.UUID, update service.ProjectSettingsUpdate) (domain.Project, error)
+
+	// Captured list arguments for assertions
+	lastPage            repository.PageRequest
+	lastIncludeArchived bool
 }
 
 func (f *fakeProjectService) Create(_ context.Context, name string, description *string) (domain.Project, error) {
@@ -37,10 +45,26 @@ func (f *fakeProjectService) Update(_ context.Context, id uuid.UUID, name string
 	return f.updateFn(id, name, description)
 }
 func (f *fakeProjectService) Delete(_ context.Context, id uuid.UUID) error { return f.deleteFn(id) }
+func (f *fakeProjectService) Restore(_ context.Context, id uuid.UUID) (domain.Project, error) {
+	return f.restoreFn(id)
+}
+func (f *fakeProjectService) Archive(_ context.Context, id uuid.UUID) (domain.Project, error) {
+	return f.archiveFn(id, true)
+}
+func (f *fakeProjectService) Unarchive(_ context.Context, id uuid.UUID) (domain.Project, error) {
+	return f.archiveFn(id, false)
+}
 func (f *fakeProjectService) GetByID(_ context.Context, id uuid.UUID) (domain.Project, error) {
 	return f.getFn(id)
 }
-func (f *fakeProjectService) List(_ context.Context) ([]domain.Project, error) { return f.listFn() }
+func (f *fakeProjectService) List(_ context.Context, includeArchived bool, page repository.PageRequest) ([]domain.Project, error) {
+	f.lastPage = page
+	f.lastIncludeArchived = includeArchived
+	return f.listFn()
+}
+func (f *fakeProjectService) UpdateSettings(_ context.Context, id uuid.UUID, update service.ProjectSettingsUpdate) (domain.Project, error) {
+	return f.settingsFn(id, update)
+}
 
 // Ensure interface compliance
 var _ service.ProjectService = (*fakeProjectService)(nil)
@@ -105,11 +129,12 @@ func TestProjectHandlerListHappyPath(t *testing.T) {
 	if w.Code != stdhttp.StatusOK {
 		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
 	}
-	var resp []ProjectResponse
-	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+	var page PageResponse[ProjectResponse]
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
 		t.Fatalf(invalidJsonErrorMessage, err)
 	}
-	if len(resp) != 1 || resp[0].Name != "A" {
+	resp := page.Items
+	if len(resp) != 1 || resp[0].Name != "A" || page.NextCursor != nil {
 		t.Fatalf("unexpected list response: %+v", resp)
 	}
 }
@@ -484,3 +509,117 @@ func TestProjectHandlerListUnknownErrorMaps500(t *testing.T) {
 
 // helpers
 func ptr[T any](v T) *T { return &v }
+
+func TestProjectHandlerUpdateSettings(t *testing.T) {
+	now := time.Now().UTC()
+	var got service.ProjectSettingsUpdate
+	f := &fakeProjectService{
+		settingsFn: func(id uuid.UUID, update service.ProjectSettingsUpdate) (domain.Project, error) {
+			got = update
+			return domain.Project{ID: id, Name: "P", Settings: domain.ProjectSettings{ConcurrentTimers: true}, CreatedAt: now, UpdatedAt: now}, nil
+		},
+	}
+	h := NewProjectHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(projectRoute, h.RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodPatch, projectRoute+"/"+uuid.New().String()+"/settings", []byte(`{"concurrentTimers":true}`), nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	if got.ConcurrentTimers == nil || !*got.ConcurrentTimers {
+		t.Fatalf("expected concurrentTimers=true passed to service, got %+v", got)
+	}
+	var resp ProjectResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !resp.Settings.ConcurrentTimers {
+		t.Fatalf("expected settings in response, got %+v", resp)
+	}
+
+	w = doRequest(r, stdhttp.MethodPatch, projectRoute+"/"+uuid.New().String()+"/settings", []byte(`{"rounding":{"incrementSeconds":360,"mode":"up","scope":"day"}}`), nil)
+	want := domain.Rounding{IncrementSeconds: 360, Mode: domain.RoundUp, Scope: domain.RoundPerDay}
+	if w.Code != stdhttp.StatusOK || got.ConcurrentTimers != nil || got.Rounding == nil || *got.Rounding != want {
+		t.Fatalf("expected rounding passed to service, got %d %+v", w.Code, got)
+	}
+
+	w = doRequest(r, stdhttp.MethodPatch, projectRoute+"/"+uuid.New().String()+"/settings", []byte(`{"unknown":1}`), nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+	w = doRequest(r, stdhttp.MethodPatch, projectRoute+"/"+invalidId+"/settings", []byte(`{}`), nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+}
+
+func TestProjectHandlerSettingsRoundingResponseAndErrors(t *testing.T) {
+	now := time.Now().UTC()
+	rounding := domain.Rounding{IncrementSeconds: 900, Mode: domain.RoundNearest, Scope: domain.RoundPerEntry}
+	f := &fakeProjectService{
+		settingsFn: func(id uuid.UUID, update service.ProjectSettingsUpdate) (domain.Project, error) {
+			if update.Rounding != nil && update.Rounding.IncrementSeconds < 0 {
+				return domain.Project{}, service.ErrInvalidRounding
+			}
+			if update.ConcurrentTimers != nil && !*update.ConcurrentTimers {
+				return domain.Project{}, repository.ErrRunningEntryConflict
+			}
+			return domain.Project{ID: id, Name: "P", Settings: domain.ProjectSettings{Rounding: rounding}, CreatedAt: now, UpdatedAt: now}, nil
+		},
+	}
+	h := NewProjectHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(projectRoute, h.RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodPatch, projectRoute+"/"+uuid.New().String()+"/settings", []byte(`{}`), nil)
+	var resp ProjectResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if resp.Settings.Rounding != (RoundingPayload{IncrementSeconds: 900, Mode: "nearest", Scope: "entry"}) {
+		t.Fatalf("expected rounding in response, got %+v", resp.Settings)
+	}
+
+	w = doRequest(r, stdhttp.MethodPatch, projectRoute+"/"+uuid.New().String()+"/settings", []byte(`{"rounding":{"incrementSeconds":-1}}`), nil)
+	if w.Code != stdhttp.StatusBadRequest || !bytes.Contains(w.Body.Bytes(), []byte(codeInvalidRounding)) {
+		t.Fatalf("expected 400 invalid_rounding, got %d %s", w.Code, w.Body.String())
+	}
+
+	// Turning concurrent timers off while several of them run would put them in one slot
+	w = doRequest(r, stdhttp.MethodPatch, projectRoute+"/"+uuid.New().String()+"/settings", []byte(`{"concurrentTimers":false}`), nil)
+	if w.Code != stdhttp.StatusConflict || !bytes.Contains(w.Body.Bytes(), []byte(codeActiveTimerExists)) {
+		t.Fatalf("expected 409 active_timer_exists, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestProjectHandlerListPagination(t *testing.T) {
+	now := time.Now().UTC()
+	first := domain.Project{ID: uuid.New(), Name: "A", CreatedAt: now}
+	f := &fakeProjectService{
+		listFn: func() ([]domain.Project, error) {
+			return []domain.Project{first, {ID: uuid.New(), Name: "B", CreatedAt: now.Add(time.Second)}}, nil
+		},
+	}
+	h := NewProjectHandler(f, slog.Default())
+	r := mountRoutes(projectRoute, h.RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodGet, projectRoute+"?limit=1", nil, nil)
+	var page PageResponse[ProjectResponse]
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if len(page.Items) != 1 || page.Items[0].ID != first.ID || page.NextCursor == nil {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+
+	w = doRequest(r, stdhttp.MethodGet, projectRoute+"?limit=1&cursor="+*page.NextCursor, nil, nil)
+	if w.Code != stdhttp.StatusOK || f.lastPage.After == nil || f.lastPage.After.ID != first.ID || !f.lastPage.After.At.Equal(first.CreatedAt) {
+		t.Fatalf("expected cursor of the first project, got %d %+v", w.Code, f.lastPage)
+	}
+
+	w = doRequest(r, stdhttp.MethodGet, projectRoute+"?limit=-1", nil, nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+}
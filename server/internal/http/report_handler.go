@@ -0,0 +1,120 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"log/slog"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/service"
+)
+
+// ReportHandler serves reports over tracked time.
+type ReportHandler struct {
+	svc    service.ReportService
+	logger *slog.Logger
+}
+
+// NewReportHandler constructs a ReportHandler.
+func NewReportHandler(svc service.ReportService, logger *slog.Logger) ReportHandler {
+	return ReportHandler{svc: svc, logger: logger}
+}
+
+// RegisterRoutes mounts report routes under the provided router (expects base path to be set by caller).
+func (h ReportHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/summary", h.handleSummary)
+}
+
+// handleSummary totals the worked time inside [from, to), grouped by the comma-separated or
+// repeated groupBy values and optionally narrowed by projectId and categoryId.
+func (h ReportHandler) handleSummary(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	h.logger.Info("report_summary_start", slog.String("request_id", reqID))
+	q := r.URL.Query()
+	from, err := parseTimeRFC3339(q.Get("from"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+		h.logger.Warn("report_summary_invalid_from", slog.String("request_id", reqID), slog.String("from", q.Get("from")))
+		return
+	}
+	to, err := parseTimeRFC3339(q.Get("to"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+		h.logger.Warn("report_summary_invalid_to", slog.String("request_id", reqID), slog.String("to", q.Get("to")))
+		return
+	}
+	opts := service.SummaryOptions{Timezone: q.Get("timezone")}
+	for _, v := range q["groupBy"] {
+		for _, g := range strings.Split(v, ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				opts.GroupBy = append(opts.GroupBy, domain.ReportGroup(g))
+			}
+		}
+	}
+	if s := q.Get("projectId"); s != "" {
+		id, err := parseUUID(s)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidProjectId)
+			h.logger.Warn("report_summary_invalid_project_id", slog.String("request_id", reqID), slog.String("project_id", s))
+			return
+		}
+		opts.ProjectID = &id
+	}
+	if s := q.Get("categoryId"); s != "" {
+		id, err := parseUUID(s)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidCategoryId)
+			h.logger.Warn("report_summary_invalid_category_id", slog.String("request_id", reqID), slog.String("category_id", s))
+			return
+		}
+		opts.CategoryID = &id
+	}
+	report, err := h.svc.Summary(r.Context(), from, to, opts)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("report_summary_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	writeJSON(w, http.StatusOK, summaryReportToResponse(report))
+	h.logger.Info("report_summary_success", slog.String("request_id", reqID), slog.Int("rows", len(report.Rows)))
+}
+
+func summaryReportToResponse(rep domain.SummaryReport) SummaryReportResponse {
+	resp := SummaryReportResponse{
+		From:                rep.Start.UTC(),
+		To:                  rep.End.UTC(),
+		Timezone:            rep.Location.String(),
+		GroupBy:             make([]string, 0, len(rep.GroupBy)),
+		Rows:                make([]SummaryRowResponse, 0, len(rep.Rows)),
+		TotalSeconds:        rep.TotalSeconds,
+		RoundedTotalSeconds: rep.RoundedTotalSeconds,
+	}
+	for _, g := range rep.GroupBy {
+		resp.GroupBy = append(resp.GroupBy, string(g))
+	}
+	for _, row := range rep.Rows {
+		out := SummaryRowResponse{
+			ProjectID:      row.ProjectID,
+			CategoryID:     row.CategoryID,
+			TagID:          row.TagID,
+			Period:         row.Period,
+			Seconds:        row.Seconds,
+			RoundedSeconds: row.RoundedSeconds,
+		}
+		if row.ProjectID != nil {
+			out.ProjectName = &row.ProjectName
+		}
+		if row.CategoryID != nil {
+			out.CategoryName = &row.CategoryName
+		}
+		if row.TagID != nil {
+			out.TagName = &row.TagName
+		}
+		resp.Rows = append(resp.Rows, out)
+	}
+	return resp
+}
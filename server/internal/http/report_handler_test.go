@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"testing"
+	"time"
+
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/service"
+)
+
+type fakeReportService struct {
+	summaryFn func(start, end time.Time, opts service.SummaryOptions) (domain.SummaryReport, error)
+}
+
+func (f *fakeReportService) Summary(_ context.Context, start time.Time, end time.Time, opts service.SummaryOptions) (domain.SummaryReport, error) {
+	return f.summaryFn(start, end, opts)
+}
+
+var _ service.ReportService = (*fakeReportService)(nil)
+
+const reportsRoute = "/api/reports"
+
+func TestReportHandlerSummary(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	tagID := uuid.New()
+	day := time.Date(2025, 3, 30, 0, 0, 0, 0, berlin)
+	var got service.SummaryOptions
+	f := &fakeReportService{summaryFn: func(start, end time.Time, opts service.SummaryOptions) (domain.SummaryReport, error) {
+		got = opts
+		return domain.SummaryReport{
+			Start:    start,
+			End:      end,
+			Location: berlin,
+			GroupBy:  opts.GroupBy,
+			Rows: []domain.ReportRow{
+				{TagID: &tagID, TagName: "deep work", Period: &day, Seconds: 5400, RoundedSeconds: 5400},
+				{Period: &day, Seconds: 600, RoundedSeconds: 900},
+			},
+			TotalSeconds:        6000,
+			RoundedTotalSeconds: 6300,
+		}, nil
+	}}
+	r := mountRoutes(reportsRoute, NewReportHandler(f, slog.Default()).RegisterRoutes)
+
+	url := reportsRoute + "/summary?from=2025-03-29T23:00:00Z&to=2025-03-30T22:00:00Z&timezone=Europe/Berlin&groupBy=tag,&groupBy=day"
+	w := doRequest(r, stdhttp.MethodGet, url, nil, nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	if got.Timezone != "Europe/Berlin" || len(got.GroupBy) != 2 || got.GroupBy[0] != domain.ReportByTag || got.GroupBy[1] != domain.ReportByDay {
+		t.Fatalf("unexpected options: %+v", got)
+	}
+	var raw struct {
+		Timezone            string                       `json:"timezone"`
+		GroupBy             []string                     `json:"groupBy"`
+		Rows                []map[string]json.RawMessage `json:"rows"`
+		TotalSeconds        int64                        `json:"totalSeconds"`
+		RoundedTotalSeconds int64                        `json:"roundedTotalSeconds"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if raw.Timezone != "Europe/Berlin" || raw.TotalSeconds != 6000 || raw.RoundedTotalSeconds != 6300 || len(raw.Rows) != 2 {
+		t.Fatalf("unexpected report: %s", w.Body.String())
+	}
+	tagged, untagged := raw.Rows[0], raw.Rows[1]
+	if string(tagged["tagName"]) != `"deep work"` || string(tagged["period"]) != `"2025-03-30T00:00:00+01:00"` {
+		t.Fatalf("unexpected tagged row: %s", w.Body.String())
+	}
+	if string(untagged["roundedSeconds"]) != "900" {
+		t.Fatalf("expected the rounded seconds of the row, got %s", w.Body.String())
+	}
+	if string(untagged["tagId"]) != "null" || string(untagged["tagName"]) != "null" || string(untagged["projectId"]) != "null" {
+		t.Fatalf("expected null fields on the untagged row, got %s", w.Body.String())
+	}
+}
+
+func TestReportHandlerSummaryValidation(t *testing.T) {
+	f := &fakeReportService{summaryFn: func(time.Time, time.Time, service.SummaryOptions) (domain.SummaryReport, error) {
+		return domain.SummaryReport{}, service.ErrInvalidGroupBy
+	}}
+	r := mountRoutes(reportsRoute, NewReportHandler(f, slog.Default()).RegisterRoutes)
+
+	cases := []struct {
+		query string
+		code  apiErrorCode
+	}{
+		{"?to=2025-03-30T00:00:00Z", codeInvalidTime},
+		{"?from=2025-03-29T00:00:00Z&to=tomorrow", codeInvalidTime},
+		{"?from=2025-03-29T00:00:00Z&to=2025-03-30T00:00:00Z&projectId=nope", codeInvalidID},
+		{"?from=2025-03-29T00:00:00Z&to=2025-03-30T00:00:00Z&categoryId=nope", codeInvalidID},
+		{"?from=2025-03-29T00:00:00Z&to=2025-03-30T00:00:00Z&groupBy=year", codeInvalidGroupBy},
+	}
+	for _, tc := range cases {
+		w := doRequest(r, stdhttp.MethodGet, reportsRoute+"/summary"+tc.query, nil, nil)
+		if w.Code != stdhttp.StatusBadRequest {
+			t.Fatalf("%s: "+statusCodeFailedExpectationMessage, tc.query, stdhttp.StatusBadRequest, w.Code)
+		}
+		var er ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &er); err != nil || er.Code != string(tc.code) {
+			t.Fatalf("%s: expected %s, got %+v (%v)", tc.query, tc.code, er, err)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"log/slog"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/Gargair/clockwork/server/internal/service"
+)
+
+// TagHandler handles tag endpoints under a project.
+type TagHandler struct {
+	svc    service.TagService
+	logger *slog.Logger
+}
+
+// NewTagHandler constructs a TagHandler.
+func NewTagHandler(svc service.TagService, logger *slog.Logger) TagHandler {
+	return TagHandler{svc: svc, logger: logger}
+}
+
+const (
+	tagIdRoute = "/{tagId}"
+	tagIdParam = "tagId"
+)
+
+// RegisterRoutes mounts tag routes on the provided router (expects base path to be /api/projects/{projectId}/tags).
+func (h TagHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/", h.handleCreate)
+	r.Get("/", h.handleList)
+	r.Get("/totals", h.handleTotals)
+	r.Patch(tagIdRoute, h.handleRename)
+	r.Delete(tagIdRoute, h.handleDelete)
+}
+
+func (h TagHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	projID, ok := h.parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	var req TagCreateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidJSON), errInvalidJsonPayload)
+		h.logger.Warn("tag_create_invalid_json", slog.String("request_id", reqID))
+		return
+	}
+	created, err := h.svc.Create(r.Context(), projID, req.Name)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("tag_create_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	writeJSON(w, http.StatusCreated, tagToResponse(created))
+	h.logger.Info("tag_create_success", slog.String("request_id", reqID), slog.String("tag_id", created.ID.String()))
+}
+
+func (h TagHandler) handleList(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	projID, ok := h.parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	items, err := h.svc.ListByProject(r.Context(), projID)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("tag_list_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	resp := make([]TagResponse, 0, len(items))
+	for _, it := range items {
+		resp = append(resp, tagToResponse(it))
+	}
+	writeJSON(w, http.StatusOK, resp)
+	h.logger.Info("tag_list_success", slog.String("request_id", reqID), slog.Int("count", len(resp)))
+}
+
+func (h TagHandler) handleRename(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	projID, ok := h.parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.parseTagID(w, r)
+	if !ok {
+		return
+	}
+	var req TagUpdateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidJSON), errInvalidJsonPayload)
+		h.logger.Warn("tag_rename_invalid_json", slog.String("request_id", reqID))
+		return
+	}
+	if !h.ensureInProject(w, r, projID, id) {
+		return
+	}
+	renamed, err := h.svc.Rename(r.Context(), id, req.Name)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("tag_rename_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("tag_id", id.String()))
+		return
+	}
+	writeJSON(w, http.StatusOK, tagToResponse(renamed))
+	h.logger.Info("tag_rename_success", slog.String("request_id", reqID), slog.String("tag_id", id.String()))
+}
+
+func (h TagHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	projID, ok := h.parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.parseTagID(w, r)
+	if !ok {
+		return
+	}
+	if !h.ensureInProject(w, r, projID, id) {
+		return
+	}
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("tag_delete_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("tag_id", id.String()))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+	h.logger.Info("tag_delete_success", slog.String("request_id", reqID), slog.String("tag_id", id.String()))
+}
+
+func (h TagHandler) handleTotals(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	projID, ok := h.parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	var from, to *time.Time
+	if s := q.Get("from"); s != "" {
+		t, err := parseTimeRFC3339(s)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+			h.logger.Warn("tag_totals_invalid_from", slog.String("request_id", reqID), slog.String("from", s))
+			return
+		}
+		from = &t
+	}
+	if s := q.Get("to"); s != "" {
+		t, err := parseTimeRFC3339(s)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+			h.logger.Warn("tag_totals_invalid_to", slog.String("request_id", reqID), slog.String("to", s))
+			return
+		}
+		to = &t
+	}
+	totals, err := h.svc.Totals(r.Context(), projID, from, to)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("tag_totals_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	resp := make([]TagTotalResponse, 0, len(totals))
+	for _, t := range totals {
+		resp = append(resp, TagTotalResponse{TagID: t.TagID, Name: t.Name, Seconds: t.Seconds, RoundedSeconds: t.RoundedSeconds})
+	}
+	writeJSON(w, http.StatusOK, resp)
+	h.logger.Info("tag_totals_success", slog.String("request_id", reqID), slog.Int("count", len(resp)))
+}
+
+// ensureInProject reports 404 when the tag does not exist or belongs to another project.
+func (h TagHandler) ensureInProject(w http.ResponseWriter, r *http.Request, projID uuid.UUID, id uuid.UUID) bool {
+	t, err := h.svc.GetByID(r.Context(), id)
+	if err == nil && t.ProjectID != projID {
+		err = repository.ErrNotFound
+	}
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Warn("tag_lookup_error", slog.String("request_id", middleware.GetReqID(r.Context())), slog.String("error", err.Error()), slog.String("tag_id", id.String()))
+		return false
+	}
+	return true
+}
+
+func (h TagHandler) parseProjectID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	idStr := chi.URLParam(r, projectIdParam)
+	id, err := parseUUID(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidProjectId)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h TagHandler) parseTagID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	idStr := chi.URLParam(r, tagIdParam)
+	id, err := parseUUID(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidTagId)
+		h.logger.Warn("tag_invalid_id", slog.String("request_id", middleware.GetReqID(r.Context())), slog.String("tag_id", idStr))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func tagToResponse(t domain.Tag) TagResponse {
+	return TagResponse{
+		ID:        t.ID,
+		ProjectID: t.ProjectID,
+		Name:      t.Name,
+		CreatedAt: t.CreatedAt.UTC(),
+		UpdatedAt: t.UpdatedAt.UTC(),
+	}
+}
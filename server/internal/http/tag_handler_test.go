@@ -0,0 +1,181 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"testing"
+	"time"
+
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/Gargair/clockwork/server/internal/service"
+)
+
+type fakeTagService struct {
+	createFn func(projectID uuid.UUID, name string) (domain.Tag, error)
+	renameFn func(id uuid.UUID, name string) (domain.Tag, error)
+	deleteFn func(id uuid.UUID) error
+	getFn    func(id uuid.UUID) (domain.Tag, error)
+	listFn   func(projectID uuid.UUID) ([]domain.Tag, error)
+	totalsFn func(projectID uuid.UUID, start *time.Time, end *time.Time) ([]domain.TagTotal, error)
+}
+
+func (f *fakeTagService) Create(_ context.Context, projectID uuid.UUID, name string) (domain.Tag, error) {
+	return f.createFn(projectID, name)
+}
+func (f *fakeTagService) Rename(_ context.Context, id uuid.UUID, name string) (domain.Tag, error) {
+	return f.renameFn(id, name)
+}
+func (f *fakeTagService) Delete(_ context.Context, id uuid.UUID) error { return f.deleteFn(id) }
+func (f *fakeTagService) GetByID(_ context.Context, id uuid.UUID) (domain.Tag, error) {
+	return f.getFn(id)
+}
+func (f *fakeTagService) ListByProject(_ context.Context, projectID uuid.UUID) ([]domain.Tag, error) {
+	return f.listFn(projectID)
+}
+func (f *fakeTagService) Totals(_ context.Context, projectID uuid.UUID, start *time.Time, end *time.Time) ([]domain.TagTotal, error) {
+	return f.totalsFn(projectID, start, end)
+}
+
+var _ service.TagService = (*fakeTagService)(nil)
+
+const (
+	tagsRoute        = "/api/projects/%s/tags"
+	tagsRoutePattern = "/api/projects/{projectId}/tags"
+)
+
+func TestTagHandlerCreateAndErrors(t *testing.T) {
+	now := time.Now().UTC()
+	f := &fakeTagService{
+		createFn: func(projectID uuid.UUID, name string) (domain.Tag, error) {
+			switch name {
+			case "":
+				return domain.Tag{}, service.ErrInvalidTagName
+			case "dup":
+				return domain.Tag{}, service.ErrDuplicateTagName
+			}
+			return domain.Tag{ID: uuid.New(), ProjectID: projectID, Name: name, CreatedAt: now, UpdatedAt: now}, nil
+		},
+	}
+	projectID := uuid.New()
+	base := sprintf(tagsRoute, projectID.String())
+	r := mountRoutes(tagsRoutePattern, NewTagHandler(f, slog.Default()).RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodPost, base, mustJSON(t, TagCreateRequest{Name: "billing"}), nil)
+	if w.Code != stdhttp.StatusCreated {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusCreated, w.Code)
+	}
+	var resp TagResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if resp.ProjectID != projectID || resp.Name != "billing" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	cases := []struct {
+		name   string
+		url    string
+		body   []byte
+		status int
+	}{
+		{"invalid json", base, []byte(`{"bogus":1}`), stdhttp.StatusBadRequest},
+		{"empty name", base, mustJSON(t, TagCreateRequest{}), stdhttp.StatusBadRequest},
+		{"duplicate", base, mustJSON(t, TagCreateRequest{Name: "dup"}), stdhttp.StatusConflict},
+		{"invalid project", sprintf(tagsRoute, invalidId), mustJSON(t, TagCreateRequest{Name: "x"}), stdhttp.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		w := doRequest(r, stdhttp.MethodPost, tc.url, tc.body, nil)
+		if w.Code != tc.status {
+			t.Fatalf("%s: "+statusCodeFailedExpectationMessage, tc.name, tc.status, w.Code)
+		}
+	}
+}
+
+func TestTagHandlerRenameAndDeleteScopedToProject(t *testing.T) {
+	projectID := uuid.New()
+	own := domain.Tag{ID: uuid.New(), ProjectID: projectID, Name: "a"}
+	foreign := domain.Tag{ID: uuid.New(), ProjectID: uuid.New(), Name: "b"}
+	deleted := false
+	f := &fakeTagService{
+		getFn: func(id uuid.UUID) (domain.Tag, error) {
+			switch id {
+			case own.ID:
+				return own, nil
+			case foreign.ID:
+				return foreign, nil
+			}
+			return domain.Tag{}, repository.ErrNotFound
+		},
+		renameFn: func(id uuid.UUID, name string) (domain.Tag, error) {
+			return domain.Tag{ID: id, ProjectID: projectID, Name: name}, nil
+		},
+		deleteFn: func(id uuid.UUID) error {
+			deleted = true
+			return nil
+		},
+	}
+	base := sprintf(tagsRoute, projectID.String())
+	r := mountRoutes(tagsRoutePattern, NewTagHandler(f, slog.Default()).RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodPatch, base+"/"+own.ID.String(), mustJSON(t, TagUpdateRequest{Name: "c"}), nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	w = doRequest(r, stdhttp.MethodPatch, base+"/"+foreign.ID.String(), mustJSON(t, TagUpdateRequest{Name: "c"}), nil)
+	if w.Code != stdhttp.StatusNotFound {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusNotFound, w.Code)
+	}
+	w = doRequest(r, stdhttp.MethodPatch, base+"/"+invalidId, mustJSON(t, TagUpdateRequest{Name: "c"}), nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+
+	w = doRequest(r, stdhttp.MethodDelete, base+"/"+foreign.ID.String(), nil, nil)
+	if w.Code != stdhttp.StatusNotFound || deleted {
+		t.Fatalf("expected 404 without delete, got %d", w.Code)
+	}
+	w = doRequest(r, stdhttp.MethodDelete, base+"/"+own.ID.String(), nil, nil)
+	if w.Code != stdhttp.StatusNoContent || !deleted {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusNoContent, w.Code)
+	}
+}
+
+func TestTagHandlerTotals(t *testing.T) {
+	projectID := uuid.New()
+	tagID := uuid.New()
+	var gotFrom, gotTo *time.Time
+	f := &fakeTagService{
+		totalsFn: func(pid uuid.UUID, start *time.Time, end *time.Time) ([]domain.TagTotal, error) {
+			gotFrom, gotTo = start, end
+			return []domain.TagTotal{{TagID: tagID, Name: "billing", Seconds: 3600}}, nil
+		},
+	}
+	base := sprintf(tagsRoute, projectID.String())
+	r := mountRoutes(tagsRoutePattern, NewTagHandler(f, slog.Default()).RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodGet, base+"/totals?from=2025-11-01T00:00:00Z", nil, nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	if gotFrom == nil || gotTo != nil {
+		t.Fatalf("expected only from to be set, got %v %v", gotFrom, gotTo)
+	}
+	var resp []TagTotalResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if len(resp) != 1 || resp[0].TagID != tagID || resp[0].Seconds != 3600 {
+		t.Fatalf("unexpected totals: %+v", resp)
+	}
+
+	w = doRequest(r, stdhttp.MethodGet, base+"/totals?to=yesterday", nil, nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+}
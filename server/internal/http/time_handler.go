@@ -1,15 +1,19 @@
 package http
 
 import (
+	"context"
 	"net/http"
+	"strconv"
 	"time"
 
 	"log/slog"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
 
 	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
 	"github.com/Gargair/clockwork/server/internal/service"
 )
 
@@ -27,9 +31,23 @@ func NewTimeHandler(svc service.TimeTrackingService, logger *slog.Logger) TimeHa
 // RegisterRoutes mounts time routes under the provided router (expects base path /api/time).
 func (h TimeHandler) RegisterRoutes(r chi.Router) {
 	r.Post("/start", h.handleStart)
+	r.Post("/continue", h.handleContinue)
 	r.Post("/stop", h.handleStop)
+	r.Post("/pause", h.handlePause)
+	r.Post("/resume", h.handleResume)
 	r.Get("/active", h.handleActive)
+	r.Get("/running", h.handleRunning)
+	r.Get("/categories/{categoryId}/active", h.handleCategoryActive)
+	r.Post("/categories/{categoryId}/stop", h.handleCategoryStop)
 	r.Get("/entries", h.handleEntries)
+	r.Post("/entries", h.handleCreateEntry)
+	r.Delete("/entries", h.handleBulkDeleteEntries)
+	r.Post("/entries/merge", h.handleMergeEntries)
+	r.Get("/entries/{entryId}", h.handleGetEntry)
+	r.Patch("/entries/{entryId}", h.handleUpdateEntry)
+	r.Delete("/entries/{entryId}", h.handleDeleteEntry)
+	r.Post("/entries/{entryId}/split", h.handleSplitEntry)
+	r.Post("/entries/{entryId}/restore", h.handleRestoreEntry)
 }
 
 func (h TimeHandler) handleStart(w http.ResponseWriter, r *http.Request) {
@@ -46,7 +64,26 @@ func (h TimeHandler) handleStart(w http.ResponseWriter, r *http.Request) {
 		h.logger.Warn("time_start_invalid_category", slog.String("request_id", reqID), slog.String("category_id", req.CategoryID))
 		return
 	}
-	entry, err := h.svc.Start(r.Context(), catID)
+	tagIDs, err := parseUUIDs(req.TagIDs)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidTagId)
+		h.logger.Warn("time_start_invalid_tag", slog.String("request_id", reqID))
+		return
+	}
+	startedAt, err := parseOptionalTimeRFC3339(req.StartedAt)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+		h.logger.Warn("time_start_invalid_started_at", slog.String("request_id", reqID), slog.String("started_at", *req.StartedAt))
+		return
+	}
+	entry, err := h.svc.Start(r.Context(), catID, service.StartOptions{
+		Note:          req.Note,
+		TagIDs:        tagIDs,
+		Billable:      req.Billable,
+		TargetSeconds: req.TargetSeconds,
+		Break:         req.Break,
+		StartedAt:     startedAt,
+	})
 	if err != nil {
 		writeMappedError(w, r, err)
 		h.logger.Error("time_start_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
@@ -56,9 +93,81 @@ func (h TimeHandler) handleStart(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("time_start_success", slog.String("request_id", reqID), slog.String("category_id", catID.String()))
 }
 
+func (h TimeHandler) handleContinue(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	var req TimeContinueRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidJSON), errInvalidJsonPayload)
+		h.logger.Warn("time_continue_invalid_json", slog.String("request_id", reqID))
+		return
+	}
+	entryID, err := parseOptionalUUID(req.EntryID)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidEntryId)
+		h.logger.Warn("time_continue_invalid_entry", slog.String("request_id", reqID), slog.String("entry_id", *req.EntryID))
+		return
+	}
+	entry, err := h.svc.Continue(r.Context(), entryID)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Warn("time_continue_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	writeJSON(w, http.StatusCreated, timeEntryToResponse(entry))
+	h.logger.Info("time_continue_success", slog.String("request_id", reqID), slog.String("entry_id", entry.ID.String()))
+}
+
+func (h TimeHandler) handleCreateEntry(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	var req TimeEntryCreateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidJSON), errInvalidJsonPayload)
+		h.logger.Warn("time_entry_create_invalid_json", slog.String("request_id", reqID))
+		return
+	}
+	catID, err := parseUUID(req.CategoryID)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidCategoryId)
+		h.logger.Warn("time_entry_create_invalid_category", slog.String("request_id", reqID), slog.String("category_id", req.CategoryID))
+		return
+	}
+	startedAt, err := parseTimeRFC3339(req.StartedAt)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+		h.logger.Warn("time_entry_create_invalid_started_at", slog.String("request_id", reqID), slog.String("started_at", req.StartedAt))
+		return
+	}
+	stoppedAt, err := parseTimeRFC3339(req.StoppedAt)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+		h.logger.Warn("time_entry_create_invalid_stopped_at", slog.String("request_id", reqID), slog.String("stopped_at", req.StoppedAt))
+		return
+	}
+	entry, err := h.svc.CreateManual(r.Context(), catID, startedAt, stoppedAt)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("time_entry_create_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	writeJSON(w, http.StatusCreated, timeEntryToResponse(entry))
+	h.logger.Info("time_entry_create_success", slog.String("request_id", reqID), slog.String("entry_id", entry.ID.String()))
+}
+
 func (h TimeHandler) handleStop(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetReqID(r.Context())
-	entry, err := h.svc.StopActive(r.Context())
+	var req TimeStopRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidJSON), errInvalidJsonPayload)
+		h.logger.Warn("time_stop_invalid_json", slog.String("request_id", reqID))
+		return
+	}
+	stoppedAt, err := parseOptionalTimeRFC3339(req.StoppedAt)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+		h.logger.Warn("time_stop_invalid_stopped_at", slog.String("request_id", reqID), slog.String("stopped_at", *req.StoppedAt))
+		return
+	}
+	entry, err := h.svc.StopActive(r.Context(), service.StopOptions{Note: req.Note, StoppedAt: stoppedAt})
 	if err != nil {
 		writeMappedError(w, r, err)
 		h.logger.Warn("time_stop_no_active", slog.String("request_id", reqID))
@@ -68,6 +177,46 @@ func (h TimeHandler) handleStop(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("time_stop_success", slog.String("request_id", reqID), slog.String("entry_id", entry.ID.String()))
 }
 
+func (h TimeHandler) handlePause(w http.ResponseWriter, r *http.Request) {
+	h.handlePauseOrResume(w, r, "time_pause", h.svc.Pause)
+}
+
+func (h TimeHandler) handleResume(w http.ResponseWriter, r *http.Request) {
+	h.handlePauseOrResume(w, r, "time_resume", h.svc.Resume)
+}
+
+// handlePauseOrResume parses the optional TimePauseRequest and applies op to the entry it names;
+// event prefixes the log events.
+func (h TimeHandler) handlePauseOrResume(w http.ResponseWriter, r *http.Request, event string, op func(context.Context, service.TimerTarget) (domain.TimeEntry, error)) {
+	reqID := middleware.GetReqID(r.Context())
+	var req TimePauseRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidJSON), errInvalidJsonPayload)
+		h.logger.Warn(event+"_invalid_json", slog.String("request_id", reqID))
+		return
+	}
+	var target service.TimerTarget
+	var err error
+	if target.EntryID, err = parseOptionalUUID(req.EntryID); err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidEntryId)
+		h.logger.Warn(event+"_invalid_entry", slog.String("request_id", reqID), slog.String("entry_id", *req.EntryID))
+		return
+	}
+	if target.CategoryID, err = parseOptionalUUID(req.CategoryID); err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidCategoryId)
+		h.logger.Warn(event+"_invalid_category", slog.String("request_id", reqID), slog.String("category_id", *req.CategoryID))
+		return
+	}
+	entry, err := op(r.Context(), target)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Warn(event+"_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	writeJSON(w, http.StatusOK, timeEntryToResponse(entry))
+	h.logger.Info(event+"_success", slog.String("request_id", reqID), slog.String("entry_id", entry.ID.String()))
+}
+
 func (h TimeHandler) handleActive(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetReqID(r.Context())
 	entry, err := h.svc.GetActive(r.Context())
@@ -87,21 +236,121 @@ func (h TimeHandler) handleActive(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("time_active_success", slog.String("request_id", reqID), slog.String("entry_id", resp.ID.String()))
 }
 
-func (h TimeHandler) handleEntries(w http.ResponseWriter, r *http.Request) {
+func (h TimeHandler) handleRunning(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetReqID(r.Context())
-	q := r.URL.Query()
-	catStr := q.Get("categoryId")
-	if catStr == "" {
+	entries, err := h.svc.ListActive(r.Context())
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("time_running_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	resp := make([]TimeEntryResponse, 0, len(entries))
+	for _, e := range entries {
+		resp = append(resp, timeEntryToResponse(e))
+	}
+	writeJSON(w, http.StatusOK, resp)
+	h.logger.Info("time_running_success", slog.String("request_id", reqID), slog.Int("count", len(resp)))
+}
+
+func (h TimeHandler) handleCategoryActive(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	catStr := chi.URLParam(r, "categoryId")
+	catID, err := parseUUID(catStr)
+	if err != nil {
 		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidCategoryId)
-		h.logger.Warn("time_entries_missing_category", slog.String("request_id", reqID))
+		h.logger.Warn("time_category_active_invalid_category", slog.String("request_id", reqID), slog.String("category_id", catStr))
+		return
+	}
+	entry, err := h.svc.GetActiveInCategory(r.Context(), catID)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("time_category_active_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
 		return
 	}
+	if entry == nil {
+		writeJSON(w, http.StatusOK, nil)
+		h.logger.Info("time_category_active_none", slog.String("request_id", reqID), slog.String("category_id", catID.String()))
+		return
+	}
+	writeJSON(w, http.StatusOK, timeEntryToResponse(*entry))
+	h.logger.Info("time_category_active_success", slog.String("request_id", reqID), slog.String("entry_id", entry.ID.String()))
+}
+
+func (h TimeHandler) handleCategoryStop(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	catStr := chi.URLParam(r, "categoryId")
 	catID, err := parseUUID(catStr)
 	if err != nil {
 		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidCategoryId)
-		h.logger.Warn("time_entries_invalid_category", slog.String("request_id", reqID), slog.String("category_id", catStr))
+		h.logger.Warn("time_category_stop_invalid_category", slog.String("request_id", reqID), slog.String("category_id", catStr))
+		return
+	}
+	var req TimeStopRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidJSON), errInvalidJsonPayload)
+		h.logger.Warn("time_category_stop_invalid_json", slog.String("request_id", reqID))
+		return
+	}
+	stoppedAt, err := parseOptionalTimeRFC3339(req.StoppedAt)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+		h.logger.Warn("time_category_stop_invalid_stopped_at", slog.String("request_id", reqID), slog.String("stopped_at", *req.StoppedAt))
+		return
+	}
+	entry, err := h.svc.StopActiveInCategory(r.Context(), catID, service.StopOptions{Note: req.Note, StoppedAt: stoppedAt})
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Warn("time_category_stop_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	writeJSON(w, http.StatusOK, timeEntryToResponse(entry))
+	h.logger.Info("time_category_stop_success", slog.String("request_id", reqID), slog.String("entry_id", entry.ID.String()))
+}
+
+func (h TimeHandler) handleEntries(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	q := r.URL.Query()
+	catStr := q.Get("categoryId")
+	projStr := q.Get("projectId")
+	if catStr != "" && projStr != "" {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidQuery), "categoryId and projectId are mutually exclusive")
+		h.logger.Warn("time_entries_ambiguous_scope", slog.String("request_id", reqID))
+		return
+	}
+	var (
+		catID  uuid.UUID
+		projID uuid.UUID
+		err    error
+	)
+	switch {
+	case projStr != "":
+		projID, err = parseUUID(projStr)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidProjectId)
+			h.logger.Warn("time_entries_invalid_project", slog.String("request_id", reqID), slog.String("project_id", projStr))
+			return
+		}
+	case catStr != "":
+		catID, err = parseUUID(catStr)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidCategoryId)
+			h.logger.Warn("time_entries_invalid_category", slog.String("request_id", reqID), slog.String("category_id", catStr))
+			return
+		}
+	default:
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidCategoryId)
+		h.logger.Warn("time_entries_missing_category", slog.String("request_id", reqID))
 		return
 	}
+	includeDescendants := false
+	if s := q.Get("includeDescendants"); s != "" {
+		includeDescendants, err = strconv.ParseBool(s)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidQuery), "invalid includeDescendants")
+			h.logger.Warn("time_entries_invalid_include_descendants", slog.String("request_id", reqID), slog.String("include_descendants", s))
+			return
+		}
+	}
 
 	fromStr := q.Get("from")
 	toStr := q.Get("to")
@@ -137,31 +386,357 @@ func (h TimeHandler) handleEntries(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	filter := repository.TimeEntryFilter{NoteContains: q.Get("note")}
+	if tagStr := q.Get("tag"); tagStr != "" {
+		tagID, err := parseUUID(tagStr)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidTagId)
+			h.logger.Warn("time_entries_invalid_tag", slog.String("request_id", reqID), slog.String("tag_id", tagStr))
+			return
+		}
+		filter.TagID = &tagID
+	}
+	switch sort := repository.TimeEntrySort(q.Get("sort")); sort {
+	case "":
+		filter.Sort = repository.SortStartedAtDesc
+	case repository.SortStartedAtDesc, repository.SortStartedAtAsc, repository.SortDuration:
+		filter.Sort = sort
+	default:
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidQuery), "invalid sort")
+		h.logger.Warn("time_entries_invalid_sort", slog.String("request_id", reqID), slog.String("sort", string(sort)))
+		return
+	}
+	switch mode := repository.RangeMode(q.Get("mode")); mode {
+	case "":
+		filter.Range = repository.RangeStarted
+	case repository.RangeStarted, repository.RangeOverlap:
+		filter.Range = mode
+	default:
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidQuery), "invalid mode")
+		h.logger.Warn("time_entries_invalid_mode", slog.String("request_id", reqID), slog.String("mode", string(mode)))
+		return
+	}
+	if s := q.Get("clip"); s != "" {
+		filter.Clip, err = strconv.ParseBool(s)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidQuery), "invalid clip")
+			h.logger.Warn("time_entries_invalid_clip", slog.String("request_id", reqID), slog.String("clip", s))
+			return
+		}
+	}
+	page, err := parseListPage(r, string(filter.Sort))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidQuery), err.Error())
+		h.logger.Warn("time_entries_invalid_page", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	filter.Page = page.request()
+
+	// Project and subtree listings accept either bound on its own.
+	var start, end *time.Time
+	if hasFrom {
+		start = &from
+	}
+	if hasTo {
+		end = &to
+	}
+
 	var entries []domain.TimeEntry
-	if hasFrom && hasTo {
-		entries, err = h.svc.ListByCategoryAndRange(r.Context(), catID, from, to)
-	} else {
-		entries, err = h.svc.ListByCategory(r.Context(), catID)
+	switch {
+	case projStr != "":
+		entries, err = h.svc.ListByProject(r.Context(), projID, start, end, filter)
+	case includeDescendants:
+		entries, err = h.svc.ListByCategoryTree(r.Context(), catID, start, end, filter)
+	case hasFrom && hasTo:
+		entries, err = h.svc.ListByCategoryAndRange(r.Context(), catID, from, to, filter)
+	default:
+		entries, err = h.svc.ListByCategory(r.Context(), catID, filter)
 	}
 	if err != nil {
 		writeMappedError(w, r, err)
 		h.logger.Error("time_entries_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
 		return
 	}
+	count := writeList(w, page, entries, timeEntryToResponse, filter.Sort.CursorOf)
+	h.logger.Info("time_entries_success", slog.String("request_id", reqID), slog.Int("count", count))
+}
 
-	resp := make([]TimeEntryResponse, 0, len(entries))
+// handleGetEntry returns a single entry. Running entries are never answered with 304 because
+// their elapsed and remaining seconds change without a new version.
+func (h TimeHandler) handleGetEntry(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	idStr := chi.URLParam(r, "entryId")
+	id, err := parseUUID(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidEntryId)
+		h.logger.Warn("time_entry_get_invalid_id", slog.String("request_id", reqID), slog.String("entry_id", idStr))
+		return
+	}
+	entry, err := h.svc.GetEntry(r.Context(), id)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("time_entry_get_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("entry_id", id.String()))
+		return
+	}
+	resp := timeEntryToResponse(entry)
+	tag := timeEntryTag(entry.Version, resp)
+	if entry.StoppedAt != nil && notModified(w, r, tag) {
+		h.logger.Info("time_entry_get_not_modified", slog.String("request_id", reqID), slog.String("entry_id", id.String()))
+		return
+	}
+	setETag(w, tag)
+	writeJSON(w, http.StatusOK, resp)
+	h.logger.Info("time_entry_get_success", slog.String("request_id", reqID), slog.String("entry_id", id.String()))
+}
+
+func (h TimeHandler) handleUpdateEntry(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	idStr := chi.URLParam(r, "entryId")
+	id, err := parseUUID(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidEntryId)
+		h.logger.Warn("time_entry_update_invalid_id", slog.String("request_id", reqID), slog.String("entry_id", idStr))
+		return
+	}
+	ctx, ok := ifMatch(r, id)
+	if !ok {
+		writeError(w, r, http.StatusPreconditionFailed, string(codePreconditionFailed), errPreconditionFailed)
+		h.logger.Warn("time_entry_update_precondition_failed", slog.String("request_id", reqID), slog.String("entry_id", id.String()))
+		return
+	}
+	var req TimeEntryUpdateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidJSON), errInvalidJsonPayload)
+		h.logger.Warn("time_entry_update_invalid_json", slog.String("request_id", reqID))
+		return
+	}
+
+	var update service.TimeEntryUpdate
+	catID, err := parseOptionalUUID(req.CategoryID)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidCategoryId)
+		h.logger.Warn("time_entry_update_invalid_category", slog.String("request_id", reqID))
+		return
+	}
+	update.CategoryID = catID
+	if req.StartedAt != nil {
+		t, err := parseTimeRFC3339(*req.StartedAt)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+			h.logger.Warn("time_entry_update_invalid_started_at", slog.String("request_id", reqID), slog.String("started_at", *req.StartedAt))
+			return
+		}
+		update.StartedAt = &t
+	}
+	if req.StoppedAt.Set {
+		if req.StoppedAt.Value == nil {
+			update.ClearStoppedAt = true
+		} else {
+			t, err := parseTimeRFC3339(*req.StoppedAt.Value)
+			if err != nil {
+				writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+				h.logger.Warn("time_entry_update_invalid_stopped_at", slog.String("request_id", reqID), slog.String("stopped_at", *req.StoppedAt.Value))
+				return
+			}
+			update.StoppedAt = &t
+		}
+	}
+	if req.Note.Set {
+		note := ""
+		if req.Note.Value != nil {
+			note = *req.Note.Value
+		}
+		update.Note = &note
+	}
+	if req.TagIDs != nil {
+		tagIDs, err := parseUUIDs(*req.TagIDs)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidTagId)
+			h.logger.Warn("time_entry_update_invalid_tag", slog.String("request_id", reqID))
+			return
+		}
+		update.TagIDs = tagIDs
+	}
+	update.Billable = req.Billable
+
+	entry, err := h.svc.UpdateEntry(ctx, id, update)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("time_entry_update_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("entry_id", id.String()))
+		return
+	}
+	resp := timeEntryToResponse(entry)
+	setETag(w, timeEntryTag(entry.Version, resp))
+	writeJSON(w, http.StatusOK, resp)
+	h.logger.Info("time_entry_update_success", slog.String("request_id", reqID), slog.String("entry_id", id.String()))
+}
+
+func (h TimeHandler) handleSplitEntry(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	idStr := chi.URLParam(r, "entryId")
+	id, err := parseUUID(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidEntryId)
+		h.logger.Warn("time_entry_split_invalid_id", slog.String("request_id", reqID), slog.String("entry_id", idStr))
+		return
+	}
+	var req TimeEntrySplitRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidJSON), errInvalidJsonPayload)
+		h.logger.Warn("time_entry_split_invalid_json", slog.String("request_id", reqID))
+		return
+	}
+	at, err := parseTimeRFC3339(req.At)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+		h.logger.Warn("time_entry_split_invalid_at", slog.String("request_id", reqID), slog.String("at", req.At))
+		return
+	}
+	catID, err := parseOptionalUUID(req.CategoryID)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidCategoryId)
+		h.logger.Warn("time_entry_split_invalid_category", slog.String("request_id", reqID))
+		return
+	}
+	first, second, err := h.svc.Split(r.Context(), id, at, catID)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("time_entry_split_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("entry_id", id.String()))
+		return
+	}
+	writeJSON(w, http.StatusOK, TimeEntrySplitResponse{First: timeEntryToResponse(first), Second: timeEntryToResponse(second)})
+	h.logger.Info("time_entry_split_success", slog.String("request_id", reqID), slog.String("entry_id", id.String()), slog.String("new_entry_id", second.ID.String()))
+}
+
+func (h TimeHandler) handleMergeEntries(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	var req TimeEntryMergeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidJSON), errInvalidJsonPayload)
+		h.logger.Warn("time_entry_merge_invalid_json", slog.String("request_id", reqID))
+		return
+	}
+	ids, err := parseUUIDs(req.EntryIDs)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidEntryId)
+		h.logger.Warn("time_entry_merge_invalid_id", slog.String("request_id", reqID))
+		return
+	}
+	gapTolerance := time.Duration(req.GapToleranceSeconds) * time.Second
+	entry, err := h.svc.Merge(r.Context(), ids, gapTolerance)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("time_entry_merge_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	writeJSON(w, http.StatusOK, timeEntryToResponse(entry))
+	h.logger.Info("time_entry_merge_success", slog.String("request_id", reqID), slog.String("entry_id", entry.ID.String()), slog.Int("merged", len(ids)))
+}
+
+func (h TimeHandler) handleDeleteEntry(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	idStr := chi.URLParam(r, "entryId")
+	id, err := parseUUID(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidEntryId)
+		h.logger.Warn("time_entry_delete_invalid_id", slog.String("request_id", reqID), slog.String("entry_id", idStr))
+		return
+	}
+	ctx, ok := ifMatch(r, id)
+	if !ok {
+		writeError(w, r, http.StatusPreconditionFailed, string(codePreconditionFailed), errPreconditionFailed)
+		h.logger.Warn("time_entry_delete_precondition_failed", slog.String("request_id", reqID), slog.String("entry_id", id.String()))
+		return
+	}
+	if err := h.svc.DeleteEntry(ctx, id); err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("time_entry_delete_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("entry_id", id.String()))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+	h.logger.Info("time_entry_delete_success", slog.String("request_id", reqID), slog.String("entry_id", id.String()))
+}
+
+func (h TimeHandler) handleRestoreEntry(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	idStr := chi.URLParam(r, "entryId")
+	id, err := parseUUID(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidEntryId)
+		h.logger.Warn("time_entry_restore_invalid_id", slog.String("request_id", reqID), slog.String("entry_id", idStr))
+		return
+	}
+	restored, err := h.svc.RestoreEntry(r.Context(), id)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("time_entry_restore_error", slog.String("request_id", reqID), slog.String("error", err.Error()), slog.String("entry_id", id.String()))
+		return
+	}
+	resp := timeEntryToResponse(restored)
+	setETag(w, timeEntryTag(restored.Version, resp))
+	writeJSON(w, http.StatusOK, resp)
+	h.logger.Info("time_entry_restore_success", slog.String("request_id", reqID), slog.String("entry_id", id.String()))
+}
+
+func (h TimeHandler) handleBulkDeleteEntries(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	q := r.URL.Query()
+	catStr := q.Get("categoryId")
+	catID, err := parseUUID(catStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidID), errInvalidCategoryId)
+		h.logger.Warn("time_entries_delete_invalid_category", slog.String("request_id", reqID), slog.String("category_id", catStr))
+		return
+	}
+	from, err := parseTimeRFC3339(q.Get("from"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+		h.logger.Warn("time_entries_delete_invalid_from", slog.String("request_id", reqID), slog.String("from", q.Get("from")))
+		return
+	}
+	to, err := parseTimeRFC3339(q.Get("to"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, string(codeInvalidTime), errInvalidTime)
+		h.logger.Warn("time_entries_delete_invalid_to", slog.String("request_id", reqID), slog.String("to", q.Get("to")))
+		return
+	}
+	dryRun := false
+	if v := q.Get("dryRun"); v != "" {
+		dryRun, err = strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, string(codeInvalidQuery), errInvalidDryRun)
+			h.logger.Warn("time_entries_delete_invalid_dry_run", slog.String("request_id", reqID), slog.String("dry_run", v))
+			return
+		}
+	}
+
+	entries, err := h.svc.DeleteEntries(r.Context(), catID, from, to, dryRun)
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("time_entries_delete_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	resp := TimeEntryBulkDeleteResponse{DryRun: dryRun, Count: len(entries), Entries: make([]TimeEntryResponse, 0, len(entries))}
 	for _, e := range entries {
-		resp = append(resp, timeEntryToResponse(e))
+		resp.Entries = append(resp.Entries, timeEntryToResponse(e))
 	}
 	writeJSON(w, http.StatusOK, resp)
-	h.logger.Info("time_entries_success", slog.String("request_id", reqID), slog.Int("count", len(resp)))
+	h.logger.Info("time_entries_delete_success", slog.String("request_id", reqID), slog.Bool("dry_run", dryRun), slog.Int("count", resp.Count))
+}
+
+// timeEntryTag is the entity tag of an entry. Rates, rounding settings and tag deletions change
+// the entry's billable amount, rounded duration and tags without a new version, so these count too.
+func timeEntryTag(version int64, resp TimeEntryResponse) string {
+	return derivedEntityTag(version, []any{resp.BillableAmount, resp.RoundedDurationSeconds, resp.TagIDs})
 }
 
 func timeEntryToResponse(e domain.TimeEntry) TimeEntryResponse {
 	return TimeEntryResponse{
-		ID:         e.ID,
-		CategoryID: e.CategoryID,
-		StartedAt:  e.StartedAt.UTC(),
+		ID:           e.ID,
+		CategoryID:   e.CategoryID,
+		CategoryName: e.CategoryName,
+		CategoryPath: e.CategoryPath,
+		StartedAt:    e.StartedAt.UTC(),
 		StoppedAt: func() *time.Time {
 			if e.StoppedAt != nil {
 				t := e.StoppedAt.UTC()
@@ -170,8 +745,26 @@ func timeEntryToResponse(e domain.TimeEntry) TimeEntryResponse {
 				return nil
 			}
 		}(),
-		DurationSeconds: e.DurationSeconds,
-		CreatedAt:       e.CreatedAt.UTC(),
-		UpdatedAt:       e.UpdatedAt.UTC(),
+		DurationSeconds:        e.DurationSeconds,
+		RoundedDurationSeconds: e.RoundedSeconds,
+		Note:                   e.Note,
+		TagIDs: func() []uuid.UUID {
+			if e.TagIDs == nil {
+				return []uuid.UUID{}
+			}
+			return e.TagIDs
+		}(),
+		Billable:         e.Billable,
+		BillableAmount:   moneyToResponse(e.BillableAmount),
+		AutoStopped:      e.AutoStopped,
+		TargetSeconds:    e.TargetSeconds,
+		Break:            e.Break,
+		Paused:           e.Paused,
+		ElapsedSeconds:   e.ElapsedSeconds,
+		RemainingSeconds: e.RemainingSeconds,
+		ClippedSeconds:   e.ClippedSeconds,
+		CreatedAt:        e.CreatedAt.UTC(),
+		UpdatedAt:        e.UpdatedAt.UTC(),
+		DeletedAt:        utcOrNil(e.DeletedAt),
 	}
 }
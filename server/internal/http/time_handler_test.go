@@ -7,6 +7,7 @@ import (
 	"io"
 	stdhttp "net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,26 +25,115 @@ import (
 type fakeTimeService struct {
 	startFn                  func(categoryID uuid.UUID) (domain.TimeEntry, error)
 	stopActiveFn             func() (domain.TimeEntry, error)
+	pauseFn                  func() (domain.TimeEntry, error)
+	resumeFn                 func() (domain.TimeEntry, error)
+	createManualFn           func(categoryID uuid.UUID, startedAt time.Time, stoppedAt time.Time) (domain.TimeEntry, error)
+	updateEntryFn            func(id uuid.UUID, update service.TimeEntryUpdate) (domain.TimeEntry, error)
+	splitFn                  func(id uuid.UUID, at time.Time, categoryID *uuid.UUID) (domain.TimeEntry, domain.TimeEntry, error)
+	mergeFn                  func(ids []uuid.UUID, gapTolerance time.Duration) (domain.TimeEntry, error)
+	deleteEntryFn            func(id uuid.UUID) error
+	restoreEntryFn           func(id uuid.UUID) (domain.TimeEntry, error)
+	deleteEntriesFn          func(categoryID uuid.UUID, start time.Time, end time.Time, dryRun bool) ([]domain.TimeEntry, error)
 	getActiveFn              func() (*domain.TimeEntry, error)
+	continueFn               func(entryID *uuid.UUID) (domain.TimeEntry, error)
+	getEntryFn               func(id uuid.UUID) (domain.TimeEntry, error)
+	stopInCategoryFn         func(categoryID uuid.UUID) (domain.TimeEntry, error)
+	getActiveInCategoryFn    func(categoryID uuid.UUID) (*domain.TimeEntry, error)
+	listActiveFn             func() ([]domain.TimeEntry, error)
 	listByCategoryFn         func(categoryID uuid.UUID) ([]domain.TimeEntry, error)
 	listByCategoryAndRangeFn func(categoryID uuid.UUID, start time.Time, end time.Time) ([]domain.TimeEntry, error)
+	listByProjectFn          func(projectID uuid.UUID, start *time.Time, end *time.Time) ([]domain.TimeEntry, error)
+	listByCategoryTreeFn     func(categoryID uuid.UUID, start *time.Time, end *time.Time) ([]domain.TimeEntry, error)
+
+	// Captured option arguments for assertions
+	lastStartOpts service.StartOptions
+	lastStopOpts  service.StopOptions
+	lastTarget    service.TimerTarget
+	lastFilter    repository.TimeEntryFilter
+	// lastCtx is the context of the last entry update or delete
+	lastCtx context.Context
 }
 
-func (f *fakeTimeService) Start(_ context.Context, categoryID uuid.UUID) (domain.TimeEntry, error) {
+func (f *fakeTimeService) Start(_ context.Context, categoryID uuid.UUID, opts service.StartOptions) (domain.TimeEntry, error) {
+	f.lastStartOpts = opts
 	return f.startFn(categoryID)
 }
-func (f *fakeTimeService) StopActive(_ context.Context) (domain.TimeEntry, error) {
+func (f *fakeTimeService) StopActive(_ context.Context, opts service.StopOptions) (domain.TimeEntry, error) {
+	f.lastStopOpts = opts
 	return f.stopActiveFn()
 }
+func (f *fakeTimeService) Pause(_ context.Context, target service.TimerTarget) (domain.TimeEntry, error) {
+	f.lastTarget = target
+	return f.pauseFn()
+}
+func (f *fakeTimeService) Resume(_ context.Context, target service.TimerTarget) (domain.TimeEntry, error) {
+	f.lastTarget = target
+	return f.resumeFn()
+}
+func (f *fakeTimeService) CreateManual(_ context.Context, categoryID uuid.UUID, startedAt time.Time, stoppedAt time.Time) (domain.TimeEntry, error) {
+	return f.createManualFn(categoryID, startedAt, stoppedAt)
+}
+func (f *fakeTimeService) UpdateEntry(ctx context.Context, id uuid.UUID, update service.TimeEntryUpdate) (domain.TimeEntry, error) {
+	f.lastCtx = ctx
+	return f.updateEntryFn(id, update)
+}
+func (f *fakeTimeService) RestoreEntry(_ context.Context, id uuid.UUID) (domain.TimeEntry, error) {
+	return f.restoreEntryFn(id)
+}
+func (f *fakeTimeService) DeleteEntry(ctx context.Context, id uuid.UUID) error {
+	f.lastCtx = ctx
+	return f.deleteEntryFn(id)
+}
+func (f *fakeTimeService) DeleteEntries(_ context.Context, categoryID uuid.UUID, start time.Time, end time.Time, dryRun bool) ([]domain.TimeEntry, error) {
+	return f.deleteEntriesFn(categoryID, start, end, dryRun)
+}
+func (f *fakeTimeService) GetEntry(_ context.Context, id uuid.UUID) (domain.TimeEntry, error) {
+	return f.getEntryFn(id)
+}
 func (f *fakeTimeService) GetActive(_ context.Context) (*domain.TimeEntry, error) {
 	return f.getActiveFn()
 }
-func (f *fakeTimeService) ListByCategory(_ context.Context, categoryID uuid.UUID) ([]domain.TimeEntry, error) {
+func (f *fakeTimeService) StopActiveInCategory(_ context.Context, categoryID uuid.UUID, opts service.StopOptions) (domain.TimeEntry, error) {
+	f.lastStopOpts = opts
+	return f.stopInCategoryFn(categoryID)
+}
+func (f *fakeTimeService) GetActiveInCategory(_ context.Context, categoryID uuid.UUID) (*domain.TimeEntry, error) {
+	return f.getActiveInCategoryFn(categoryID)
+}
+func (f *fakeTimeService) ListActive(_ context.Context) ([]domain.TimeEntry, error) {
+	return f.listActiveFn()
+}
+func (f *fakeTimeService) Split(_ context.Context, id uuid.UUID, at time.Time, categoryID *uuid.UUID) (domain.TimeEntry, domain.TimeEntry, error) {
+	return f.splitFn(id, at, categoryID)
+}
+func (f *fakeTimeService) Merge(_ context.Context, ids []uuid.UUID, gapTolerance time.Duration) (domain.TimeEntry, error) {
+	return f.mergeFn(ids, gapTolerance)
+}
+func (f *fakeTimeService) ReapRunaway(context.Context, service.ReaperPolicy) ([]domain.TimeEntry, error) {
+	return nil, nil
+}
+func (f *fakeTimeService) Continue(_ context.Context, entryID *uuid.UUID) (domain.TimeEntry, error) {
+	return f.continueFn(entryID)
+}
+func (f *fakeTimeService) StopReachedTargets(context.Context) ([]domain.TimeEntry, error) {
+	return nil, nil
+}
+func (f *fakeTimeService) ListByCategory(_ context.Context, categoryID uuid.UUID, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+	f.lastFilter = filter
 	return f.listByCategoryFn(categoryID)
 }
-func (f *fakeTimeService) ListByCategoryAndRange(_ context.Context, categoryID uuid.UUID, start time.Time, end time.Time) ([]domain.TimeEntry, error) {
+func (f *fakeTimeService) ListByCategoryAndRange(_ context.Context, categoryID uuid.UUID, start time.Time, end time.Time, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+	f.lastFilter = filter
 	return f.listByCategoryAndRangeFn(categoryID, start, end)
 }
+func (f *fakeTimeService) ListByProject(_ context.Context, projectID uuid.UUID, start *time.Time, end *time.Time, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+	f.lastFilter = filter
+	return f.listByProjectFn(projectID, start, end)
+}
+func (f *fakeTimeService) ListByCategoryTree(_ context.Context, categoryID uuid.UUID, start *time.Time, end *time.Time, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+	f.lastFilter = filter
+	return f.listByCategoryTreeFn(categoryID, start, end)
+}
 
 var _ service.TimeTrackingService = (*fakeTimeService)(nil)
 
@@ -314,10 +404,11 @@ func TestTimeHandlerEntriesParsingValidation(t *testing.T) {
 	if w.Code != stdhttp.StatusOK {
 		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
 	}
-	var list []TimeEntryResponse
-	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+	var page PageResponse[TimeEntryResponse]
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
 		t.Fatalf(invalidJsonErrorMessage, err)
 	}
+	list := page.Items
 	if len(list) != 1 || list[0].CategoryID != catID {
 		t.Fatalf("unexpected list: %+v", list)
 	}
@@ -358,6 +449,28 @@ func TestTimeHandlerStartCategoryNotFoundMaps404(t *testing.T) {
 	}
 }
 
+func TestTimeHandlerStartRunningEntryConflictMaps409(t *testing.T) {
+	f := &fakeTimeService{
+		startFn: func(categoryID uuid.UUID) (domain.TimeEntry, error) {
+			return domain.TimeEntry{}, repository.ErrRunningEntryConflict
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	body := mustJSON(t, TimeStartRequest{CategoryID: uuid.New().String()})
+	w := doRequest(r, stdhttp.MethodPost, timeRoute+"/start", body, nil)
+	if w.Code != stdhttp.StatusConflict {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusConflict, w.Code)
+	}
+	var resp ErrorResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Code != string(codeActiveTimerExists) {
+		t.Fatalf("expected code %s, got %s", codeActiveTimerExists, resp.Code)
+	}
+}
+
 func TestTimeHandlerEntriesServiceErrorMaps500(t *testing.T) {
 	f := &fakeTimeService{
 		listByCategoryFn: func(categoryID uuid.UUID) ([]domain.TimeEntry, error) { return nil, repository.ErrDuplicate },
@@ -418,3 +531,1023 @@ func TestTimeHandlerActiveServiceErrorMaps500(t *testing.T) {
 		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusInternalServerError, w.Code)
 	}
 }
+
+func TestTimeHandlerCreateEntryCreated(t *testing.T) {
+	catID := uuid.New()
+	start := time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC)
+	stop := start.Add(45 * time.Minute)
+	f := &fakeTimeService{
+		createManualFn: func(categoryID uuid.UUID, startedAt time.Time, stoppedAt time.Time) (domain.TimeEntry, error) {
+			if categoryID != catID || !startedAt.Equal(start) || !stoppedAt.Equal(stop) {
+				t.Fatalf("unexpected args: %s %v %v", categoryID, startedAt, stoppedAt)
+			}
+			dur := int32(stoppedAt.Sub(startedAt).Seconds())
+			return domain.TimeEntry{ID: uuid.New(), CategoryID: categoryID, StartedAt: startedAt, StoppedAt: &stoppedAt, DurationSeconds: &dur}, nil
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	body := mustJSON(t, TimeEntryCreateRequest{CategoryID: catID.String(), StartedAt: start.Format(time.RFC3339), StoppedAt: stop.Format(time.RFC3339)})
+	w := doRequest(r, stdhttp.MethodPost, timeRoute+"/entries", body, nil)
+	if w.Code != stdhttp.StatusCreated {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusCreated, w.Code)
+	}
+	var resp TimeEntryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if resp.DurationSeconds == nil || *resp.DurationSeconds != int32(45*60) {
+		t.Fatalf("unexpected duration: %v", resp.DurationSeconds)
+	}
+}
+
+func TestTimeHandlerCreateEntryValidation(t *testing.T) {
+	f := &fakeTimeService{}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	start := time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	cases := []TimeEntryCreateRequest{
+		{CategoryID: "not-a-uuid", StartedAt: start, StoppedAt: start},
+		{CategoryID: uuid.New().String(), StartedAt: "bad-time", StoppedAt: start},
+		{CategoryID: uuid.New().String(), StartedAt: start, StoppedAt: ""},
+	}
+	for _, c := range cases {
+		w := doRequest(r, stdhttp.MethodPost, timeRoute+"/entries", mustJSON(t, c), nil)
+		if w.Code != stdhttp.StatusBadRequest {
+			t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+func TestTimeHandlerCreateEntryServiceErrors(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   apiErrorCode
+	}{
+		{service.ErrTimeEntryOverlap, stdhttp.StatusConflict, codeTimeEntryOverlap},
+		{service.ErrInvalidTimeRange, stdhttp.StatusBadRequest, codeInvalidTimeRange},
+		{repository.ErrNotFound, stdhttp.StatusNotFound, codeNotFound},
+	}
+	start := time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC)
+	body := TimeEntryCreateRequest{CategoryID: uuid.New().String(), StartedAt: start.Format(time.RFC3339), StoppedAt: start.Add(time.Hour).Format(time.RFC3339)}
+	for _, c := range cases {
+		svcErr := c.err
+		f := &fakeTimeService{
+			createManualFn: func(uuid.UUID, time.Time, time.Time) (domain.TimeEntry, error) {
+				return domain.TimeEntry{}, svcErr
+			},
+		}
+		h := NewTimeHandler(f, slog.Default())
+		r := chi.NewRouter()
+		r.Route(timeRoute, h.RegisterRoutes)
+
+		w := doRequest(r, stdhttp.MethodPost, timeRoute+"/entries", mustJSON(t, body), nil)
+		if w.Code != c.status {
+			t.Fatalf(statusCodeFailedExpectationMessage, c.status, w.Code)
+		}
+		var errResp ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+			t.Fatalf(invalidJsonErrorMessage, err)
+		}
+		if errResp.Code != string(c.code) {
+			t.Fatalf("expected code %s, got %s", c.code, errResp.Code)
+		}
+	}
+}
+
+func TestTimeHandlerUpdateEntryParsesPatch(t *testing.T) {
+	entryID := uuid.New()
+	catID := uuid.New()
+	start := time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC)
+	var got service.TimeEntryUpdate
+	f := &fakeTimeService{
+		updateEntryFn: func(id uuid.UUID, update service.TimeEntryUpdate) (domain.TimeEntry, error) {
+			if id != entryID {
+				t.Fatalf("unexpected entry id: %s", id)
+			}
+			got = update
+			return domain.TimeEntry{ID: id, CategoryID: catID, StartedAt: start}, nil
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	// Explicit null stoppedAt reopens the entry
+	body := []byte(`{"categoryId":"` + catID.String() + `","startedAt":"` + start.Format(time.RFC3339) + `","stoppedAt":null}`)
+	w := doRequest(r, stdhttp.MethodPatch, timeRoute+"/entries/"+entryID.String(), body, nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	if got.CategoryID == nil || *got.CategoryID != catID || got.StartedAt == nil || !got.StartedAt.Equal(start) || !got.ClearStoppedAt || got.StoppedAt != nil {
+		t.Fatalf("unexpected update: %+v", got)
+	}
+
+	// Absent stoppedAt leaves it unchanged
+	w = doRequest(r, stdhttp.MethodPatch, timeRoute+"/entries/"+entryID.String(), []byte(`{"startedAt":"`+start.Format(time.RFC3339)+`"}`), nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	if got.ClearStoppedAt || got.StoppedAt != nil || got.CategoryID != nil {
+		t.Fatalf("unexpected update: %+v", got)
+	}
+}
+
+func TestTimeHandlerUpdateEntryValidationAndErrors(t *testing.T) {
+	f := &fakeTimeService{
+		updateEntryFn: func(uuid.UUID, service.TimeEntryUpdate) (domain.TimeEntry, error) {
+			return domain.TimeEntry{}, service.ErrActiveTimerExists
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodPatch, timeRoute+"/entries/not-a-uuid", []byte(`{}`), nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+	entryURL := timeRoute + "/entries/" + uuid.New().String()
+	w = doRequest(r, stdhttp.MethodPatch, entryURL, []byte(`{"stoppedAt":"bad-time"}`), nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+	w = doRequest(r, stdhttp.MethodPatch, entryURL, []byte(`{"stoppedAt":null}`), nil)
+	if w.Code != stdhttp.StatusConflict {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusConflict, w.Code)
+	}
+	var errResp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if errResp.Code != string(codeActiveTimerExists) {
+		t.Fatalf("expected code %s, got %s", codeActiveTimerExists, errResp.Code)
+	}
+}
+
+func TestTimeHandlerDeleteEntry(t *testing.T) {
+	existing := uuid.New()
+	f := &fakeTimeService{
+		deleteEntryFn: func(id uuid.UUID) error {
+			if id != existing {
+				return repository.ErrNotFound
+			}
+			return nil
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodDelete, timeRoute+"/entries/"+existing.String(), nil, nil)
+	if w.Code != stdhttp.StatusNoContent {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusNoContent, w.Code)
+	}
+	w = doRequest(r, stdhttp.MethodDelete, timeRoute+"/entries/"+uuid.New().String(), nil, nil)
+	if w.Code != stdhttp.StatusNotFound {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusNotFound, w.Code)
+	}
+	w = doRequest(r, stdhttp.MethodDelete, timeRoute+"/entries/not-a-uuid", nil, nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+}
+
+func TestTimeHandlerBulkDeleteEntries(t *testing.T) {
+	catID := uuid.New()
+	from := time.Date(2025, 11, 2, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	var gotDryRun bool
+	f := &fakeTimeService{
+		deleteEntriesFn: func(categoryID uuid.UUID, start time.Time, end time.Time, dryRun bool) ([]domain.TimeEntry, error) {
+			if categoryID != catID || !start.Equal(from) || !end.Equal(to) {
+				t.Fatalf("unexpected args: %s %v %v", categoryID, start, end)
+			}
+			gotDryRun = dryRun
+			return []domain.TimeEntry{{ID: uuid.New(), CategoryID: categoryID, StartedAt: start}}, nil
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	base := timeRoute + "/entries?categoryId=" + catID.String() + "&from=" + from.Format(time.RFC3339) + "&to=" + to.Format(time.RFC3339)
+	w := doRequest(r, stdhttp.MethodDelete, base+"&dryRun=true", nil, nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	var resp TimeEntryBulkDeleteResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if !gotDryRun || !resp.DryRun || resp.Count != 1 || len(resp.Entries) != 1 {
+		t.Fatalf("unexpected dry run response: %+v", resp)
+	}
+
+	w = doRequest(r, stdhttp.MethodDelete, base, nil, nil)
+	if w.Code != stdhttp.StatusOK || gotDryRun {
+		t.Fatalf("expected real delete with 200, got %d dryRun=%v", w.Code, gotDryRun)
+	}
+
+	w = doRequest(r, stdhttp.MethodDelete, base+"&dryRun=maybe", nil, nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+	w = doRequest(r, stdhttp.MethodDelete, timeRoute+"/entries?categoryId="+catID.String(), nil, nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+}
+
+func TestTimeHandlerNotesOnStartStopAndList(t *testing.T) {
+	now := time.Now().UTC()
+	note := "fixing the login bug"
+	f := &fakeTimeService{
+		startFn: func(categoryID uuid.UUID) (domain.TimeEntry, error) {
+			return domain.TimeEntry{ID: uuid.New(), CategoryID: categoryID, StartedAt: now, Note: &note}, nil
+		},
+		stopActiveFn: func() (domain.TimeEntry, error) {
+			return domain.TimeEntry{ID: uuid.New(), CategoryID: uuid.New(), StartedAt: now, StoppedAt: &now}, nil
+		},
+		listByCategoryFn: func(categoryID uuid.UUID) ([]domain.TimeEntry, error) { return nil, nil },
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	body := mustJSON(t, TimeStartRequest{CategoryID: uuid.New().String(), Note: &note})
+	w := doRequest(r, stdhttp.MethodPost, timeRoute+"/start", body, nil)
+	if w.Code != stdhttp.StatusCreated {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusCreated, w.Code)
+	}
+	if f.lastStartOpts.Note == nil || *f.lastStartOpts.Note != note {
+		t.Fatalf("expected note passed to Start, got %v", f.lastStartOpts.Note)
+	}
+	var resp TimeEntryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if resp.Note == nil || *resp.Note != note {
+		t.Fatalf("expected note in response, got %v", resp.Note)
+	}
+
+	// Stop accepts an optional body
+	w = doRequest(r, stdhttp.MethodPost, timeRoute+"/stop", []byte(`{"note":"done"}`), nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	if f.lastStopOpts.Note == nil || *f.lastStopOpts.Note != "done" {
+		t.Fatalf("expected note passed to StopActive, got %v", f.lastStopOpts.Note)
+	}
+	w = doRequest(r, stdhttp.MethodPost, timeRoute+"/stop", []byte(`{"bogus":1}`), nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+
+	// Note filter on listing
+	w = doRequest(r, stdhttp.MethodGet, categoryEntriesRoute+uuid.New().String()+"&note=login", nil, nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	if f.lastFilter.NoteContains != "login" {
+		t.Fatalf("expected note filter, got %+v", f.lastFilter)
+	}
+}
+
+func TestTimeHandlerUpdateEntryNote(t *testing.T) {
+	var got service.TimeEntryUpdate
+	f := &fakeTimeService{
+		updateEntryFn: func(id uuid.UUID, update service.TimeEntryUpdate) (domain.TimeEntry, error) {
+			got = update
+			return domain.TimeEntry{ID: id}, nil
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+	entryURL := timeRoute + "/entries/" + uuid.New().String()
+
+	w := doRequest(r, stdhttp.MethodPatch, entryURL, []byte(`{"note":"reviewed PR"}`), nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	if got.Note == nil || *got.Note != "reviewed PR" {
+		t.Fatalf("expected note update, got %+v", got)
+	}
+	w = doRequest(r, stdhttp.MethodPatch, entryURL, []byte(`{"note":null}`), nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	if got.Note == nil || *got.Note != "" {
+		t.Fatalf("expected note removal, got %+v", got)
+	}
+	w = doRequest(r, stdhttp.MethodPatch, entryURL, []byte(`{}`), nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	if got.Note != nil {
+		t.Fatalf("expected note untouched, got %+v", got)
+	}
+}
+
+func TestTimeHandlerTagsOnStartUpdateAndList(t *testing.T) {
+	tagID := uuid.New()
+	var got service.TimeEntryUpdate
+	f := &fakeTimeService{
+		startFn: func(categoryID uuid.UUID) (domain.TimeEntry, error) {
+			return domain.TimeEntry{ID: uuid.New(), CategoryID: categoryID, TagIDs: []uuid.UUID{tagID}}, nil
+		},
+		updateEntryFn: func(id uuid.UUID, update service.TimeEntryUpdate) (domain.TimeEntry, error) {
+			got = update
+			return domain.TimeEntry{ID: id}, nil
+		},
+		listByCategoryFn: func(categoryID uuid.UUID) ([]domain.TimeEntry, error) { return nil, nil },
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	body := mustJSON(t, TimeStartRequest{CategoryID: uuid.New().String(), TagIDs: []string{tagID.String()}})
+	w := doRequest(r, stdhttp.MethodPost, timeRoute+"/start", body, nil)
+	if w.Code != stdhttp.StatusCreated {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusCreated, w.Code)
+	}
+	if len(f.lastStartOpts.TagIDs) != 1 || f.lastStartOpts.TagIDs[0] != tagID {
+		t.Fatalf("expected tags passed to Start, got %v", f.lastStartOpts.TagIDs)
+	}
+	var resp TimeEntryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if len(resp.TagIDs) != 1 || resp.TagIDs[0] != tagID {
+		t.Fatalf("expected tags in response, got %v", resp.TagIDs)
+	}
+	body = mustJSON(t, TimeStartRequest{CategoryID: uuid.New().String(), TagIDs: []string{invalidId}})
+	w = doRequest(r, stdhttp.MethodPost, timeRoute+"/start", body, nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+
+	// Absent tagIds leaves tags alone, an empty array clears them
+	entryURL := timeRoute + "/entries/" + uuid.New().String()
+	w = doRequest(r, stdhttp.MethodPatch, entryURL, []byte(`{}`), nil)
+	if w.Code != stdhttp.StatusOK || got.TagIDs != nil {
+		t.Fatalf("expected tags untouched, got %d %+v", w.Code, got)
+	}
+	if !bytes.Contains(w.Body.Bytes(), []byte(`"tagIds":[]`)) {
+		t.Fatalf("expected empty tagIds array in response, got %s", w.Body.String())
+	}
+	w = doRequest(r, stdhttp.MethodPatch, entryURL, []byte(`{"tagIds":[]}`), nil)
+	if w.Code != stdhttp.StatusOK || got.TagIDs == nil || len(got.TagIDs) != 0 {
+		t.Fatalf("expected tags cleared, got %d %+v", w.Code, got)
+	}
+
+	w = doRequest(r, stdhttp.MethodGet, categoryEntriesRoute+uuid.New().String()+"&tag="+tagID.String(), nil, nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	if f.lastFilter.TagID == nil || *f.lastFilter.TagID != tagID {
+		t.Fatalf("expected tag filter, got %+v", f.lastFilter)
+	}
+	w = doRequest(r, stdhttp.MethodGet, categoryEntriesRoute+uuid.New().String()+"&tag="+invalidId, nil, nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+}
+
+func TestTimeHandlerBillableOnStartAndResponse(t *testing.T) {
+	now := time.Now().UTC()
+	var got service.TimeEntryUpdate
+	f := &fakeTimeService{
+		startFn: func(categoryID uuid.UUID) (domain.TimeEntry, error) {
+			return domain.TimeEntry{ID: uuid.New(), CategoryID: categoryID, StartedAt: now}, nil
+		},
+		updateEntryFn: func(id uuid.UUID, update service.TimeEntryUpdate) (domain.TimeEntry, error) {
+			got = update
+			secs := int32(3600)
+			return domain.TimeEntry{ID: id, StartedAt: now, StoppedAt: &now, DurationSeconds: &secs, Billable: true,
+				BillableAmount: &domain.Money{Cents: 12000, Currency: "EUR"}}, nil
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodPost, timeRoute+"/start", []byte(`{"categoryId":"`+uuid.New().String()+`","billable":false}`), nil)
+	if w.Code != stdhttp.StatusCreated {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusCreated, w.Code)
+	}
+	if f.lastStartOpts.Billable == nil || *f.lastStartOpts.Billable {
+		t.Fatalf("expected billable=false passed to Start, got %v", f.lastStartOpts.Billable)
+	}
+	if !bytes.Contains(w.Body.Bytes(), []byte(`"billableAmount":null`)) {
+		t.Fatalf("expected null billableAmount, got %s", w.Body.String())
+	}
+
+	w = doRequest(r, stdhttp.MethodPatch, timeRoute+"/entries/"+uuid.New().String(), []byte(`{"billable":true}`), nil)
+	if w.Code != stdhttp.StatusOK || got.Billable == nil || !*got.Billable {
+		t.Fatalf("expected billable update, got %d %+v", w.Code, got)
+	}
+	var resp TimeEntryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if !resp.Billable || resp.BillableAmount == nil || resp.BillableAmount.AmountCents != 12000 {
+		t.Fatalf("unexpected billing fields: %+v", resp)
+	}
+}
+
+func TestTimeHandlerPauseAndResume(t *testing.T) {
+	now := time.Now().UTC()
+	elapsed := int32(600)
+	id := uuid.New()
+	paused := false
+	f := &fakeTimeService{
+		pauseFn: func() (domain.TimeEntry, error) {
+			if paused {
+				return domain.TimeEntry{}, service.ErrTimerPaused
+			}
+			paused = true
+			return domain.TimeEntry{ID: id, StartedAt: now, Paused: true, ElapsedSeconds: &elapsed}, nil
+		},
+		resumeFn: func() (domain.TimeEntry, error) {
+			if !paused {
+				return domain.TimeEntry{}, service.ErrTimerNotPaused
+			}
+			paused = false
+			return domain.TimeEntry{ID: id, StartedAt: now, ElapsedSeconds: &elapsed}, nil
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodPost, timeRoute+"/pause", nil, nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	var resp TimeEntryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if !resp.Paused || resp.ElapsedSeconds == nil || *resp.ElapsedSeconds != 600 {
+		t.Fatalf("unexpected pause response: %+v", resp)
+	}
+
+	w = doRequest(r, stdhttp.MethodPost, timeRoute+"/pause", nil, nil)
+	if w.Code != stdhttp.StatusConflict || !bytes.Contains(w.Body.Bytes(), []byte(`"timer_paused"`)) {
+		t.Fatalf("expected 409 timer_paused, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(r, stdhttp.MethodPost, timeRoute+"/resume", nil, nil)
+	if w.Code != stdhttp.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte(`"paused":false`)) {
+		t.Fatalf("expected resumed entry, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(r, stdhttp.MethodPost, timeRoute+"/resume", nil, nil)
+	if w.Code != stdhttp.StatusConflict || !bytes.Contains(w.Body.Bytes(), []byte(`"timer_not_paused"`)) {
+		t.Fatalf("expected 409 timer_not_paused, got %d %s", w.Code, w.Body.String())
+	}
+	if f.lastTarget.EntryID != nil || f.lastTarget.CategoryID != nil {
+		t.Fatalf("expected the most recent running entry without a body, got %+v", f.lastTarget)
+	}
+
+	catID := uuid.New()
+	w = doRequest(r, stdhttp.MethodPost, timeRoute+"/pause", []byte(`{"entryId":"`+id.String()+`","categoryId":"`+catID.String()+`"}`), nil)
+	if w.Code != stdhttp.StatusOK || f.lastTarget.EntryID == nil || *f.lastTarget.EntryID != id || *f.lastTarget.CategoryID != catID {
+		t.Fatalf("expected the named entry and category to be passed, got %d %+v", w.Code, f.lastTarget)
+	}
+	w = doRequest(r, stdhttp.MethodPost, timeRoute+"/resume", []byte(`{"categoryId":"nope"}`), nil)
+	if w.Code != stdhttp.StatusBadRequest || !bytes.Contains(w.Body.Bytes(), []byte(`"invalid_id"`)) {
+		t.Fatalf("expected 400 invalid_id, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTimeHandlerCategoryScopedTimers(t *testing.T) {
+	now := time.Now().UTC()
+	catID := uuid.New()
+	running := domain.TimeEntry{ID: uuid.New(), CategoryID: catID, StartedAt: now}
+	f := &fakeTimeService{
+		listActiveFn: func() ([]domain.TimeEntry, error) {
+			return []domain.TimeEntry{running, {ID: uuid.New(), CategoryID: uuid.New(), StartedAt: now}}, nil
+		},
+		getActiveInCategoryFn: func(categoryID uuid.UUID) (*domain.TimeEntry, error) {
+			if categoryID != catID {
+				return nil, nil
+			}
+			return &running, nil
+		},
+		stopInCategoryFn: func(categoryID uuid.UUID) (domain.TimeEntry, error) {
+			if categoryID != catID {
+				return domain.TimeEntry{}, service.ErrNoActiveTimer
+			}
+			return running, nil
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodGet, timeRoute+"/running", nil, nil)
+	var list []TimeEntryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if w.Code != stdhttp.StatusOK || len(list) != 2 {
+		t.Fatalf("expected two running entries, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(r, stdhttp.MethodGet, timeRoute+"/categories/"+catID.String()+"/active", nil, nil)
+	if w.Code != stdhttp.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte(running.ID.String())) {
+		t.Fatalf("expected running entry, got %d %s", w.Code, w.Body.String())
+	}
+	w = doRequest(r, stdhttp.MethodGet, timeRoute+"/categories/"+uuid.New().String()+"/active", nil, nil)
+	if w.Code != stdhttp.StatusOK || strings.TrimSpace(w.Body.String()) != "null" {
+		t.Fatalf("expected null, got %d %s", w.Code, w.Body.String())
+	}
+	w = doRequest(r, stdhttp.MethodGet, timeRoute+"/categories/"+invalidId+"/active", nil, nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+
+	w = doRequest(r, stdhttp.MethodPost, timeRoute+"/categories/"+catID.String()+"/stop", []byte(`{"note":"done"}`), nil)
+	if w.Code != stdhttp.StatusOK || f.lastStopOpts.Note == nil || *f.lastStopOpts.Note != "done" {
+		t.Fatalf("expected scoped stop with note, got %d %+v", w.Code, f.lastStopOpts)
+	}
+	w = doRequest(r, stdhttp.MethodPost, timeRoute+"/categories/"+uuid.New().String()+"/stop", nil, nil)
+	if w.Code != stdhttp.StatusConflict {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusConflict, w.Code)
+	}
+}
+
+func TestTimeHandlerSplitEntry(t *testing.T) {
+	entryID := uuid.New()
+	otherCat := uuid.New()
+	at := time.Date(2025, 11, 2, 10, 30, 0, 0, time.UTC)
+	var gotAt time.Time
+	var gotCat *uuid.UUID
+	f := &fakeTimeService{
+		splitFn: func(id uuid.UUID, splitAt time.Time, categoryID *uuid.UUID) (domain.TimeEntry, domain.TimeEntry, error) {
+			if id != entryID {
+				return domain.TimeEntry{}, domain.TimeEntry{}, repository.ErrNotFound
+			}
+			if !splitAt.Before(at.Add(time.Hour)) {
+				return domain.TimeEntry{}, domain.TimeEntry{}, service.ErrInvalidSplitTime
+			}
+			gotAt, gotCat = splitAt, categoryID
+			first := domain.TimeEntry{ID: id, StartedAt: splitAt.Add(-time.Hour), StoppedAt: &splitAt}
+			second := domain.TimeEntry{ID: uuid.New(), CategoryID: otherCat, StartedAt: splitAt}
+			return first, second, nil
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	splitURL := timeRoute + "/entries/" + entryID.String() + "/split"
+	body := []byte(`{"at":"2025-11-02T11:30:00+01:00","categoryId":"` + otherCat.String() + `"}`)
+	w := doRequest(r, stdhttp.MethodPost, splitURL, body, nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	var resp TimeEntrySplitResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if !gotAt.Equal(at) || gotCat == nil || *gotCat != otherCat {
+		t.Fatalf("unexpected split arguments: %v %v", gotAt, gotCat)
+	}
+	if resp.First.ID != entryID || resp.First.StoppedAt == nil || resp.Second.StoppedAt != nil || resp.Second.CategoryID != otherCat {
+		t.Fatalf("unexpected split response: %+v", resp)
+	}
+
+	cases := []struct {
+		name   string
+		url    string
+		body   string
+		status int
+		code   apiErrorCode
+	}{
+		{"invalid id", timeRoute + "/entries/" + invalidId + "/split", `{"at":"2025-11-02T10:30:00Z"}`, stdhttp.StatusBadRequest, codeInvalidID},
+		{"invalid json", splitURL, `{"at":`, stdhttp.StatusBadRequest, codeInvalidJSON},
+		{"invalid time", splitURL, `{"at":"noon"}`, stdhttp.StatusBadRequest, codeInvalidTime},
+		{"invalid category", splitURL, `{"at":"2025-11-02T10:30:00Z","categoryId":"x"}`, stdhttp.StatusBadRequest, codeInvalidID},
+		{"outside entry", splitURL, `{"at":"2025-11-02T12:30:00Z"}`, stdhttp.StatusBadRequest, codeInvalidSplitTime},
+		{"unknown entry", timeRoute + "/entries/" + uuid.New().String() + "/split", `{"at":"2025-11-02T10:30:00Z"}`, stdhttp.StatusNotFound, codeNotFound},
+	}
+	for _, tc := range cases {
+		w := doRequest(r, stdhttp.MethodPost, tc.url, []byte(tc.body), nil)
+		if w.Code != tc.status || !bytes.Contains(w.Body.Bytes(), []byte(`"`+string(tc.code)+`"`)) {
+			t.Fatalf("%s: expected %d %s, got %d %s", tc.name, tc.status, tc.code, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestTimeHandlerMergeEntries(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	var gotIDs []uuid.UUID
+	var gotGap time.Duration
+	f := &fakeTimeService{
+		mergeFn: func(ids []uuid.UUID, gapTolerance time.Duration) (domain.TimeEntry, error) {
+			if len(ids) < 2 {
+				return domain.TimeEntry{}, service.ErrInvalidMerge
+			}
+			if gapTolerance == 0 {
+				return domain.TimeEntry{}, service.ErrEntriesNotContiguous
+			}
+			gotIDs, gotGap = ids, gapTolerance
+			return domain.TimeEntry{ID: ids[0]}, nil
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	mergeURL := timeRoute + "/entries/merge"
+	body := []byte(`{"entryIds":["` + a.String() + `","` + b.String() + `"],"gapToleranceSeconds":90}`)
+	w := doRequest(r, stdhttp.MethodPost, mergeURL, body, nil)
+	if w.Code != stdhttp.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte(a.String())) {
+		t.Fatalf("expected merged entry, got %d %s", w.Code, w.Body.String())
+	}
+	if len(gotIDs) != 2 || gotIDs[1] != b || gotGap != 90*time.Second {
+		t.Fatalf("unexpected merge arguments: %v %v", gotIDs, gotGap)
+	}
+
+	cases := []struct {
+		name   string
+		body   string
+		status int
+		code   apiErrorCode
+	}{
+		{"invalid json", `{"entryIds":`, stdhttp.StatusBadRequest, codeInvalidJSON},
+		{"invalid id", `{"entryIds":["x"]}`, stdhttp.StatusBadRequest, codeInvalidID},
+		{"single entry", `{"entryIds":["` + a.String() + `"]}`, stdhttp.StatusBadRequest, codeInvalidMerge},
+		{"gap", `{"entryIds":["` + a.String() + `","` + b.String() + `"]}`, stdhttp.StatusConflict, codeNotContiguous},
+	}
+	for _, tc := range cases {
+		w := doRequest(r, stdhttp.MethodPost, mergeURL, []byte(tc.body), nil)
+		if w.Code != tc.status || !bytes.Contains(w.Body.Bytes(), []byte(`"`+string(tc.code)+`"`)) {
+			t.Fatalf("%s: expected %d %s, got %d %s", tc.name, tc.status, tc.code, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestTimeHandlerEntriesByProjectAndSubtree(t *testing.T) {
+	projID, catID := uuid.New(), uuid.New()
+	var gotProject, gotTree uuid.UUID
+	var gotStart, gotEnd *time.Time
+	f := &fakeTimeService{
+		listByProjectFn: func(projectID uuid.UUID, start *time.Time, end *time.Time) ([]domain.TimeEntry, error) {
+			gotProject, gotStart, gotEnd = projectID, start, end
+			return []domain.TimeEntry{{ID: uuid.New(), CategoryID: catID, CategoryName: "Child", CategoryPath: []string{"Root", "Child"}}}, nil
+		},
+		listByCategoryTreeFn: func(categoryID uuid.UUID, start *time.Time, end *time.Time) ([]domain.TimeEntry, error) {
+			gotTree = categoryID
+			return nil, nil
+		},
+		listByCategoryFn: func(categoryID uuid.UUID) ([]domain.TimeEntry, error) { return nil, nil },
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	from := time.Date(2025, 11, 3, 8, 0, 0, 0, time.UTC)
+	w := doRequest(r, stdhttp.MethodGet, timeRoute+"/entries?projectId="+projID.String()+"&from="+from.Format(time.RFC3339)+"&note=x", nil, nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	if gotProject != projID || gotStart == nil || !gotStart.Equal(from) || gotEnd != nil || f.lastFilter.NoteContains != "x" {
+		t.Fatalf("unexpected project listing arguments: %v %v %v %+v", gotProject, gotStart, gotEnd, f.lastFilter)
+	}
+	var page PageResponse[TimeEntryResponse]
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	list := page.Items
+	if len(list) != 1 || list[0].CategoryName != "Child" || len(list[0].CategoryPath) != 2 || list[0].CategoryPath[0] != "Root" {
+		t.Fatalf("expected category name and path, got %+v", list)
+	}
+
+	w = doRequest(r, stdhttp.MethodGet, categoryEntriesRoute+catID.String()+"&includeDescendants=true", nil, nil)
+	if w.Code != stdhttp.StatusOK || gotTree != catID {
+		t.Fatalf("expected subtree listing, got %d %v", w.Code, gotTree)
+	}
+	// The plain category listing omits the category fields entirely
+	w = doRequest(r, stdhttp.MethodGet, categoryEntriesRoute+catID.String()+"&includeDescendants=false", nil, nil)
+	if w.Code != stdhttp.StatusOK || bytes.Contains(w.Body.Bytes(), []byte("categoryPath")) {
+		t.Fatalf("expected plain category listing, got %d %s", w.Code, w.Body.String())
+	}
+
+	cases := []struct {
+		name   string
+		query  string
+		status int
+		code   apiErrorCode
+	}{
+		{"both scopes", "?projectId=" + projID.String() + "&categoryId=" + catID.String(), stdhttp.StatusBadRequest, codeInvalidQuery},
+		{"invalid project", "?projectId=nope", stdhttp.StatusBadRequest, codeInvalidID},
+		{"no scope", "", stdhttp.StatusBadRequest, codeInvalidID},
+		{"invalid includeDescendants", "?categoryId=" + catID.String() + "&includeDescendants=maybe", stdhttp.StatusBadRequest, codeInvalidQuery},
+	}
+	for _, tc := range cases {
+		w := doRequest(r, stdhttp.MethodGet, timeRoute+"/entries"+tc.query, nil, nil)
+		if w.Code != tc.status || !bytes.Contains(w.Body.Bytes(), []byte(`"`+string(tc.code)+`"`)) {
+			t.Fatalf("%s: expected %d %s, got %d %s", tc.name, tc.status, tc.code, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestTimeHandlerEntriesPaginationAndSort(t *testing.T) {
+	catID := uuid.New()
+	base := time.Date(2025, 11, 3, 8, 0, 0, 0, time.UTC)
+	entries := []domain.TimeEntry{
+		{ID: uuid.New(), CategoryID: catID, StartedAt: base.Add(2 * time.Hour)},
+		{ID: uuid.New(), CategoryID: catID, StartedAt: base.Add(time.Hour)},
+		{ID: uuid.New(), CategoryID: catID, StartedAt: base},
+	}
+	f := &fakeTimeService{
+		listByCategoryFn: func(categoryID uuid.UUID) ([]domain.TimeEntry, error) { return entries, nil },
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	list := func(query string, version string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(stdhttp.MethodGet, categoryEntriesRoute+catID.String()+query, nil)
+		if version != "" {
+			req.Header.Set(apiVersionHeader, version)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// One extra entry is requested to detect a following page
+	w := list("&limit=2", "")
+	var page PageResponse[TimeEntryResponse]
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if len(page.Items) != 2 || page.NextCursor == nil || f.lastFilter.Page.Limit != 3 || f.lastFilter.Sort != repository.SortStartedAtDesc {
+		t.Fatalf("unexpected first page: %+v (filter %+v)", page, f.lastFilter)
+	}
+
+	w = list("&limit=2&cursor="+*page.NextCursor, "")
+	after := f.lastFilter.Page.After
+	if w.Code != stdhttp.StatusOK || after == nil || after.ID != entries[1].ID || !after.At.Equal(entries[1].StartedAt) {
+		t.Fatalf("expected cursor of the second entry, got %d %+v", w.Code, after)
+	}
+
+	w = list("&sort=duration", "")
+	if w.Code != stdhttp.StatusOK || f.lastFilter.Sort != repository.SortDuration || f.lastFilter.Page.Limit != defaultPageLimit+1 {
+		t.Fatalf("expected duration sort with the default limit, got %d %+v", w.Code, f.lastFilter)
+	}
+
+	// Version 1 keeps the unpaginated array
+	w = list("&limit=1", "1")
+	var legacy []TimeEntryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &legacy); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if len(legacy) != 3 || f.lastFilter.Page != (repository.PageRequest{}) {
+		t.Fatalf("expected all entries as an array, got %d (page %+v)", len(legacy), f.lastFilter.Page)
+	}
+
+	cases := []struct {
+		name    string
+		query   string
+		version string
+	}{
+		{"unknown sort", "&sort=note", ""},
+		{"zero limit", "&limit=0", ""},
+		{"limit above max", "&limit=501", ""},
+		{"garbled cursor", "&cursor=@@", ""},
+		{"cursor of another order", "&sort=duration&cursor=" + *page.NextCursor, ""},
+		{"unknown version", "", "3"},
+	}
+	for _, tc := range cases {
+		w := list(tc.query, tc.version)
+		if w.Code != stdhttp.StatusBadRequest || !bytes.Contains(w.Body.Bytes(), []byte(`"`+string(codeInvalidQuery)+`"`)) {
+			t.Fatalf("%s: expected 400 invalid_query, got %d %s", tc.name, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestTimeHandlerEntriesOverlapModeAndClip(t *testing.T) {
+	catID := uuid.New()
+	clipped := int32(1800)
+	f := &fakeTimeService{
+		listByCategoryAndRangeFn: func(categoryID uuid.UUID, start time.Time, end time.Time) ([]domain.TimeEntry, error) {
+			return []domain.TimeEntry{{ID: uuid.New(), CategoryID: catID, StartedAt: start.Add(-time.Hour), ClippedSeconds: &clipped}}, nil
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+	bounds := "&from=2025-11-03T00:00:00Z&to=2025-11-04T00:00:00Z"
+
+	w := doRequest(r, stdhttp.MethodGet, categoryEntriesRoute+catID.String()+bounds+"&mode=overlap&clip=true", nil, nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	if f.lastFilter.Range != repository.RangeOverlap || !f.lastFilter.Clip {
+		t.Fatalf("expected overlap mode with clipping, got %+v", f.lastFilter)
+	}
+	var page PageResponse[TimeEntryResponse]
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if len(page.Items) != 1 || page.Items[0].ClippedSeconds == nil || *page.Items[0].ClippedSeconds != clipped {
+		t.Fatalf("expected clippedSeconds in the response, got %s", w.Body.String())
+	}
+
+	doRequest(r, stdhttp.MethodGet, categoryEntriesRoute+catID.String()+bounds, nil, nil)
+	if f.lastFilter.Range != repository.RangeStarted || f.lastFilter.Clip {
+		t.Fatalf("expected started mode without clipping by default, got %+v", f.lastFilter)
+	}
+
+	for _, query := range []string{"&mode=contains", "&clip=sometimes"} {
+		w := doRequest(r, stdhttp.MethodGet, categoryEntriesRoute+catID.String()+bounds+query, nil, nil)
+		if w.Code != stdhttp.StatusBadRequest || !bytes.Contains(w.Body.Bytes(), []byte(`"`+string(codeInvalidQuery)+`"`)) {
+			t.Fatalf("%s: expected 400 invalid_query, got %d %s", query, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestTimeHandlerTargetTimers(t *testing.T) {
+	now := time.Now().UTC()
+	target, remaining := int32(1500), int32(600)
+	f := &fakeTimeService{
+		startFn: func(categoryID uuid.UUID) (domain.TimeEntry, error) {
+			return domain.TimeEntry{ID: uuid.New(), CategoryID: categoryID, StartedAt: now, TargetSeconds: &target, Break: true}, nil
+		},
+		getActiveFn: func() (*domain.TimeEntry, error) {
+			return &domain.TimeEntry{ID: uuid.New(), StartedAt: now, TargetSeconds: &target, RemainingSeconds: &remaining}, nil
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodPost, timeRoute+"/start", []byte(`{"categoryId":"`+uuid.New().String()+`","targetSeconds":1500,"break":true}`), nil)
+	if w.Code != stdhttp.StatusCreated {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusCreated, w.Code)
+	}
+	if f.lastStartOpts.TargetSeconds == nil || *f.lastStartOpts.TargetSeconds != target || !f.lastStartOpts.Break {
+		t.Fatalf("expected target and break passed to Start, got %+v", f.lastStartOpts)
+	}
+	var started TimeEntryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &started); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if started.TargetSeconds == nil || *started.TargetSeconds != target || !started.Break {
+		t.Fatalf("unexpected target fields: %+v", started)
+	}
+
+	w = doRequest(r, stdhttp.MethodGet, timeRoute+"/active", nil, nil)
+	var active TimeEntryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &active); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if active.RemainingSeconds == nil || *active.RemainingSeconds != remaining {
+		t.Fatalf("expected remainingSeconds=600, got %+v", active)
+	}
+
+	f.startFn = func(uuid.UUID) (domain.TimeEntry, error) { return domain.TimeEntry{}, service.ErrInvalidTarget }
+	w = doRequest(r, stdhttp.MethodPost, timeRoute+"/start", []byte(`{"categoryId":"`+uuid.New().String()+`","targetSeconds":0}`), nil)
+	if w.Code != stdhttp.StatusBadRequest || !bytes.Contains(w.Body.Bytes(), []byte(codeInvalidTarget)) {
+		t.Fatalf("expected 400 invalid_target, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTimeHandlerBackdatedStartAndStop(t *testing.T) {
+	now := time.Now().UTC()
+	f := &fakeTimeService{
+		startFn: func(categoryID uuid.UUID) (domain.TimeEntry, error) {
+			return domain.TimeEntry{ID: uuid.New(), CategoryID: categoryID, StartedAt: now}, nil
+		},
+		stopActiveFn: func() (domain.TimeEntry, error) {
+			return domain.TimeEntry{}, service.ErrInvalidTimerTime
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodPost, timeRoute+"/start", []byte(`{"categoryId":"`+uuid.New().String()+`","startedAt":"2025-11-03T09:20:00Z"}`), nil)
+	if w.Code != stdhttp.StatusCreated {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusCreated, w.Code)
+	}
+	want := time.Date(2025, 11, 3, 9, 20, 0, 0, time.UTC)
+	if f.lastStartOpts.StartedAt == nil || !f.lastStartOpts.StartedAt.Equal(want) {
+		t.Fatalf("expected startedAt passed to Start, got %v", f.lastStartOpts.StartedAt)
+	}
+
+	w = doRequest(r, stdhttp.MethodPost, timeRoute+"/start", []byte(`{"categoryId":"`+uuid.New().String()+`","startedAt":"yesterday"}`), nil)
+	if w.Code != stdhttp.StatusBadRequest || !bytes.Contains(w.Body.Bytes(), []byte(codeInvalidTime)) {
+		t.Fatalf("expected 400 invalid_time, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(r, stdhttp.MethodPost, timeRoute+"/stop", []byte(`{"stoppedAt":"2025-11-03T17:00:00+01:00"}`), nil)
+	if w.Code != stdhttp.StatusBadRequest || !bytes.Contains(w.Body.Bytes(), []byte(codeInvalidTimerTime)) {
+		t.Fatalf("expected 400 invalid_timer_time, got %d %s", w.Code, w.Body.String())
+	}
+	if f.lastStopOpts.StoppedAt == nil || !f.lastStopOpts.StoppedAt.Equal(time.Date(2025, 11, 3, 16, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected stoppedAt passed to StopActive, got %v", f.lastStopOpts.StoppedAt)
+	}
+}
+
+func TestTimeHandlerContinue(t *testing.T) {
+	var got *uuid.UUID
+	f := &fakeTimeService{
+		continueFn: func(entryID *uuid.UUID) (domain.TimeEntry, error) {
+			got = entryID
+			if entryID == nil {
+				return domain.TimeEntry{}, service.ErrNothingToContinue
+			}
+			return domain.TimeEntry{ID: uuid.New(), StartedAt: time.Now().UTC()}, nil
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodPost, timeRoute+"/continue", nil, nil)
+	if w.Code != stdhttp.StatusConflict || got != nil || !bytes.Contains(w.Body.Bytes(), []byte(codeNothingToContinue)) {
+		t.Fatalf("expected 409 nothing_to_continue, got %d %s", w.Code, w.Body.String())
+	}
+
+	id := uuid.New()
+	w = doRequest(r, stdhttp.MethodPost, timeRoute+"/continue", []byte(`{"entryId":"`+id.String()+`"}`), nil)
+	if w.Code != stdhttp.StatusCreated || got == nil || *got != id {
+		t.Fatalf("expected 201 continuing %s, got %d %v", id, w.Code, got)
+	}
+
+	w = doRequest(r, stdhttp.MethodPost, timeRoute+"/continue", []byte(`{"entryId":"nope"}`), nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+}
+
+func TestTimeHandlerGetEntryETag(t *testing.T) {
+	id := uuid.New()
+	now := time.Now().UTC()
+	entry := domain.TimeEntry{ID: id, StartedAt: now.Add(-time.Hour), StoppedAt: &now, Version: 5}
+	f := &fakeTimeService{
+		getEntryFn: func(uuid.UUID) (domain.TimeEntry, error) { return entry, nil },
+		updateEntryFn: func(uuid.UUID, service.TimeEntryUpdate) (domain.TimeEntry, error) {
+			return domain.TimeEntry{}, repository.ErrVersionMismatch
+		},
+	}
+	h := NewTimeHandler(f, slog.Default())
+	r := chi.NewRouter()
+	r.Route(timeRoute, h.RegisterRoutes)
+	url := timeRoute + "/entries/" + id.String()
+	get := func(tag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(stdhttp.MethodGet, url, nil)
+		if tag != "" {
+			req.Header.Set("If-None-Match", tag)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	tag := get("").Header().Get("ETag")
+	if !strings.HasPrefix(tag, `"5-`) {
+		t.Fatalf("expected a tag led by version 5, got %s", tag)
+	}
+	if w := get(tag); w.Code != stdhttp.StatusNotModified || w.Header().Get("ETag") != tag {
+		t.Fatalf("expected 304 for an unchanged stopped entry, got %d", w.Code)
+	}
+	// A new rate changes the billable amount but not the version
+	entry.Billable = true
+	entry.BillableAmount = &domain.Money{Cents: 5000, Currency: "EUR"}
+	w := get(tag)
+	if w.Code != stdhttp.StatusOK || w.Header().Get("ETag") == tag || !strings.HasPrefix(w.Header().Get("ETag"), `"5-`) {
+		t.Fatalf("expected 200 with a new tag after a rate change, got %d %s", w.Code, w.Header().Get("ETag"))
+	}
+	// Running entries change with every second, so they are always sent in full
+	entry.StoppedAt = nil
+	tag = get("").Header().Get("ETag")
+	if w := get(tag); w.Code != stdhttp.StatusOK {
+		t.Fatalf("expected 200 for a running entry, got %d", w.Code)
+	}
+
+	// If-Match compares the version of a derived tag only
+	req := httptest.NewRequest(stdhttp.MethodPatch, url, bytes.NewReader([]byte(`{"billable":true}`)))
+	req.Header.Set("If-Match", `"4-1a2b"`)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != stdhttp.StatusPreconditionFailed {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusPreconditionFailed, w.Code)
+	}
+	if v, ok := repository.ExpectedVersion(f.lastCtx, id); !ok || v != 4 {
+		t.Fatalf("expected version 4 passed to UpdateEntry, got %d %v", v, ok)
+	}
+}
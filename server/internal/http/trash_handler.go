@@ -0,0 +1,65 @@
+package http
+
+import (
+	"net/http"
+
+	"log/slog"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/service"
+)
+
+// TrashHandler serves the deleted projects, categories and time entries awaiting their purge.
+type TrashHandler struct {
+	svc    service.TrashService
+	logger *slog.Logger
+}
+
+// NewTrashHandler constructs a TrashHandler.
+func NewTrashHandler(svc service.TrashService, logger *slog.Logger) TrashHandler {
+	return TrashHandler{svc: svc, logger: logger}
+}
+
+// RegisterRoutes mounts trash routes under the provided router (expects base path to be set by caller).
+func (h TrashHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/", h.handleList)
+}
+
+// handleList lists the trash; restores go through the restore route of each resource.
+func (h TrashHandler) handleList(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	h.logger.Info("trash_list_start", slog.String("request_id", reqID))
+	trash, err := h.svc.List(r.Context())
+	if err != nil {
+		writeMappedError(w, r, err)
+		h.logger.Error("trash_list_error", slog.String("request_id", reqID), slog.String("error", err.Error()))
+		return
+	}
+	writeJSON(w, http.StatusOK, trashToResponse(trash))
+	h.logger.Info("trash_list_success", slog.String("request_id", reqID),
+		slog.Int("projects", len(trash.Projects)),
+		slog.Int("categories", len(trash.Categories)),
+		slog.Int("entries", len(trash.Entries)),
+	)
+}
+
+func trashToResponse(t domain.Trash) TrashResponse {
+	resp := TrashResponse{
+		Projects:   make([]ProjectResponse, 0, len(t.Projects)),
+		Categories: make([]CategoryResponse, 0, len(t.Categories)),
+		Entries:    make([]TimeEntryResponse, 0, len(t.Entries)),
+	}
+	for _, p := range t.Projects {
+		resp.Projects = append(resp.Projects, projectToResponse(p))
+	}
+	for _, c := range t.Categories {
+		resp.Categories = append(resp.Categories, categoryToResponse(c))
+	}
+	for _, e := range t.Entries {
+		resp.Entries = append(resp.Entries, timeEntryToResponse(e))
+	}
+	return resp
+}
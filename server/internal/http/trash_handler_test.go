@@ -0,0 +1,141 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	stdhttp "net/http"
+	"testing"
+	"time"
+
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/Gargair/clockwork/server/internal/service"
+)
+
+type fakeTrashService struct {
+	listFn func() (domain.Trash, error)
+}
+
+func (f *fakeTrashService) List(_ context.Context) (domain.Trash, error) {
+	return f.listFn()
+}
+
+func (f *fakeTrashService) Purge(_ context.Context, _ time.Duration) (domain.PurgeResult, error) {
+	return domain.PurgeResult{}, nil
+}
+
+var _ service.TrashService = (*fakeTrashService)(nil)
+
+const trashRoute = "/api/trash"
+
+func TestTrashHandlerList(t *testing.T) {
+	deletedAt := time.Date(2025, 11, 3, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+	p := domain.Project{ID: uuid.New(), Name: "P", Version: 2, DeletedAt: &deletedAt}
+	f := &fakeTrashService{listFn: func() (domain.Trash, error) {
+		return domain.Trash{Projects: []domain.Project{p}}, nil
+	}}
+	r := mountRoutes(trashRoute, NewTrashHandler(f, slog.Default()).RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodGet, trashRoute, nil, nil)
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusOK, w.Code)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if string(raw["categories"]) != "[]" || string(raw["entries"]) != "[]" {
+		t.Fatalf("expected empty lists rather than null, got %s", w.Body.String())
+	}
+	var resp TrashResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if len(resp.Projects) != 1 || resp.Projects[0].ID != p.ID || resp.Projects[0].DeletedAt == nil ||
+		resp.Projects[0].DeletedAt.Location() != time.UTC || !resp.Projects[0].DeletedAt.Equal(deletedAt) {
+		t.Fatalf("unexpected projects: %+v", resp.Projects)
+	}
+}
+
+func TestProjectHandlerRestore(t *testing.T) {
+	id := uuid.New()
+	f := &fakeProjectService{restoreFn: func(got uuid.UUID) (domain.Project, error) {
+		if got != id {
+			return domain.Project{}, repository.ErrNotFound
+		}
+		return domain.Project{ID: id, Name: "P", Version: 3}, nil
+	}}
+	r := mountRoutes(projectRoute, NewProjectHandler(f, slog.Default()).RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodPost, projectRoute+"/"+id.String()+"/restore", nil, nil)
+	if w.Code != stdhttp.StatusOK || w.Header().Get("ETag") != `"3"` {
+		t.Fatalf("expected 200 with ETag \"3\", got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+	var resp ProjectResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(invalidJsonErrorMessage, err)
+	}
+	if resp.ID != id || resp.DeletedAt != nil {
+		t.Fatalf("unexpected project: %+v", resp)
+	}
+
+	w = doRequest(r, stdhttp.MethodPost, projectRoute+"/"+uuid.NewString()+"/restore", nil, nil)
+	if w.Code != stdhttp.StatusNotFound {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusNotFound, w.Code)
+	}
+}
+
+func TestCategoryHandlerRestoreParentDeletedMaps409(t *testing.T) {
+	f := &fakeCategoryService{restoreFn: func(uuid.UUID) (domain.Category, error) {
+		return domain.Category{}, repository.ErrParentDeleted
+	}}
+	r := mountRoutes("/api/projects/{projectId}/categories", NewCategoryHandler(f, slog.Default()).RegisterRoutes)
+
+	url := fmt.Sprintf(categoriesRoute, uuid.NewString()) + "/" + uuid.NewString() + "/restore"
+	w := doRequest(r, stdhttp.MethodPost, url, nil, nil)
+	if w.Code != stdhttp.StatusConflict {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusConflict, w.Code)
+	}
+	var er ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &er); err != nil || er.Code != string(codeParentDeleted) {
+		t.Fatalf("expected %s, got %+v (%v)", codeParentDeleted, er, err)
+	}
+}
+
+func TestCategoryHandlerRestoreDuplicateNameMaps409(t *testing.T) {
+	f := &fakeCategoryService{restoreFn: func(uuid.UUID) (domain.Category, error) {
+		return domain.Category{}, service.ErrDuplicateCategoryName
+	}}
+	r := mountRoutes("/api/projects/{projectId}/categories", NewCategoryHandler(f, slog.Default()).RegisterRoutes)
+
+	url := fmt.Sprintf(categoriesRoute, uuid.NewString()) + "/" + uuid.NewString() + "/restore"
+	w := doRequest(r, stdhttp.MethodPost, url, nil, nil)
+	if w.Code != stdhttp.StatusConflict {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusConflict, w.Code)
+	}
+	var er ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &er); err != nil || er.Code != string(codeDuplicateCategory) {
+		t.Fatalf("expected %s, got %+v (%v)", codeDuplicateCategory, er, err)
+	}
+}
+
+func TestTimeHandlerRestoreEntryOverlapMaps409(t *testing.T) {
+	f := &fakeTimeService{restoreEntryFn: func(uuid.UUID) (domain.TimeEntry, error) {
+		return domain.TimeEntry{}, service.ErrTimeEntryOverlap
+	}}
+	r := mountRoutes(timeRoute, NewTimeHandler(f, slog.Default()).RegisterRoutes)
+
+	w := doRequest(r, stdhttp.MethodPost, timeRoute+"/entries/"+uuid.NewString()+"/restore", nil, nil)
+	if w.Code != stdhttp.StatusConflict {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusConflict, w.Code)
+	}
+	w = doRequest(r, stdhttp.MethodPost, timeRoute+"/entries/not-a-uuid/restore", nil, nil)
+	if w.Code != stdhttp.StatusBadRequest {
+		t.Fatalf(statusCodeFailedExpectationMessage, stdhttp.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+)
+
+// auditColumns is the column list shared by every query returning audit events.
+// It must stay in sync with scanAuditEvent.
+const auditColumns = `id, actor, request_id, action, entity_type, entity_id, before_data, after_data, created_at`
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) repository.AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func scanAuditEvent(row rowScanner) (domain.AuditEvent, error) {
+	var (
+		e         domain.AuditEvent
+		requestID *string
+		before    []byte
+		after     []byte
+	)
+	err := row.Scan(
+		&e.ID,
+		&e.Actor,
+		&requestID,
+		&e.Action,
+		&e.EntityType,
+		&e.EntityID,
+		&before,
+		&after,
+		&e.CreatedAt,
+	)
+	if requestID != nil {
+		e.RequestID = *requestID
+	}
+	e.Before = jsonColumn(before)
+	e.After = jsonColumn(after)
+	return e, err
+}
+
+// jsonColumn returns a scanned jsonb value, or nil for NULL.
+func jsonColumn(raw []byte) json.RawMessage {
+	if raw == nil {
+		return nil
+	}
+	return json.RawMessage(raw)
+}
+
+// jsonArg returns the jsonb parameter of a snapshot, NULL when there is none.
+func jsonArg(raw json.RawMessage) *string {
+	if raw == nil {
+		return nil
+	}
+	s := string(raw)
+	return &s
+}
+
+func (r *auditRepository) Create(ctx context.Context, event domain.AuditEvent) (domain.AuditEvent, error) {
+	const query = `
+		INSERT INTO audit_event (id, actor, request_id, action, entity_type, entity_id, before_data, after_data)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7::jsonb, $8::jsonb)
+		RETURNING ` + auditColumns
+	out, err := scanAuditEvent(conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		event.ID,
+		event.Actor,
+		event.RequestID,
+		event.Action,
+		event.EntityType,
+		event.EntityID,
+		jsonArg(event.Before),
+		jsonArg(event.After),
+	))
+	if err != nil {
+		return domain.AuditEvent{}, MapError(err)
+	}
+	return out, nil
+}
+
+func (r *auditRepository) List(ctx context.Context, filter repository.AuditFilter) ([]domain.AuditEvent, error) {
+	const query = `
+		SELECT ` + auditColumns + `
+		FROM audit_event
+		WHERE ($1 = '' OR entity_type = $1)
+		  AND ($2::uuid IS NULL OR entity_id = $2)
+		  AND ($3::timestamptz IS NULL OR created_at >= $3)
+		  AND ($4::timestamptz IS NULL OR created_at <= $4)
+		  AND ($5::timestamptz IS NULL OR (created_at, id) < ($5::timestamptz, $6::uuid))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $7
+	`
+	after, afterID, limit := createdAtPageArgs(filter.Page)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query,
+		filter.EntityType,
+		filter.EntityID,
+		filter.From,
+		filter.To,
+		after,
+		afterID,
+		limit,
+	)
+	if err != nil {
+		return nil, MapError(err)
+	}
+	defer rows.Close()
+
+	var events []domain.AuditEvent
+	for rows.Next() {
+		e, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, MapError(err)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, MapError(err)
+	}
+	return events, nil
+}
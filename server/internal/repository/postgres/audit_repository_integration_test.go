@@ -0,0 +1,95 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+func TestAuditRepositoryCreateAndListIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	ar := NewAuditRepository(db)
+
+	projectID, entryID := uuid.New(), uuid.New()
+	created, err := ar.Create(ctx, domain.AuditEvent{
+		ID:         uuid.New(),
+		Actor:      "alice",
+		RequestID:  "req-1",
+		Action:     domain.AuditCreate,
+		EntityType: domain.AuditProject,
+		EntityID:   projectID,
+		After:      json.RawMessage(`{"name": "P"}`),
+	})
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "audit event", err)
+	}
+	if created.RequestID != "req-1" || created.Before != nil || created.CreatedAt.IsZero() {
+		t.Fatalf("unexpected created event: %+v", created)
+	}
+	var after map[string]string
+	if err := json.Unmarshal(created.After, &after); err != nil || after["name"] != "P" {
+		t.Fatalf("unexpected after snapshot %s: %v", created.After, err)
+	}
+	for _, action := range []domain.AuditAction{domain.AuditCreate, domain.AuditStop} {
+		if _, err := ar.Create(ctx, domain.AuditEvent{
+			ID:         uuid.New(),
+			Actor:      "system",
+			Action:     action,
+			EntityType: domain.AuditTimeEntry,
+			EntityID:   entryID,
+			After:      json.RawMessage(`{}`),
+		}); err != nil {
+			t.Fatalf(CreateFailedErrorMessage, "audit event", err)
+		}
+	}
+
+	all, err := ar.List(ctx, repository.AuditFilter{})
+	if err != nil || len(all) != 3 {
+		t.Fatalf("expected 3 events, got %d (%v)", len(all), err)
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].CreatedAt.After(all[i-1].CreatedAt) {
+			t.Fatalf("expected events newest first")
+		}
+	}
+	for _, e := range all {
+		if e.Actor == "system" && e.RequestID != "" {
+			t.Fatalf("expected no request ID on system events, got %q", e.RequestID)
+		}
+	}
+
+	entries, err := ar.List(ctx, repository.AuditFilter{EntityType: domain.AuditTimeEntry, EntityID: &entryID})
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("expected the entry's 2 events, got %d (%v)", len(entries), err)
+	}
+	future := time.Now().Add(time.Hour)
+	if none, err := ar.List(ctx, repository.AuditFilter{From: &future}); err != nil || len(none) != 0 {
+		t.Fatalf("expected no events after %v, got %d (%v)", future, len(none), err)
+	}
+
+	first, err := ar.List(ctx, repository.AuditFilter{Page: repository.PageRequest{Limit: 2}})
+	if err != nil || len(first) != 2 {
+		t.Fatalf("expected a page of 2, got %d (%v)", len(first), err)
+	}
+	last := first[1]
+	rest, err := ar.List(ctx, repository.AuditFilter{Page: repository.PageRequest{After: &repository.Cursor{At: last.CreatedAt, ID: last.ID}}})
+	if err != nil || len(rest) != 1 || rest[0].ID != all[2].ID {
+		t.Fatalf("expected the oldest event on the next page, got %+v (%v)", rest, err)
+	}
+
+	if _, err := ar.Create(ctx, domain.AuditEvent{ID: uuid.New(), Actor: "a", Action: "rename", EntityType: domain.AuditProject, EntityID: projectID}); err == nil {
+		t.Fatalf("expected unknown actions to be rejected")
+	}
+}
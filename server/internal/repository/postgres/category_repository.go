@@ -3,12 +3,17 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/Gargair/clockwork/server/internal/domain"
 	"github.com/Gargair/clockwork/server/internal/repository"
 	"github.com/google/uuid"
 )
 
+// categoryColumns is the column list shared by every query returning categories.
+// It must stay in sync with scanCategory.
+const categoryColumns = `id, project_id, parent_category_id, name, description, hourly_rate_cents, currency, billable, version, created_at, updated_at, archived_at, deleted_at`
+
 type categoryRepository struct {
 	db *sql.DB
 }
@@ -17,14 +22,67 @@ func NewCategoryRepository(db *sql.DB) repository.CategoryRepository {
 	return &categoryRepository{db: db}
 }
 
+func scanCategory(row rowScanner) (domain.Category, error) {
+	var (
+		c        domain.Category
+		rate     *int64
+		currency *string
+	)
+	err := row.Scan(
+		&c.ID,
+		&c.ProjectID,
+		&c.ParentCategoryID,
+		&c.Name,
+		&c.Description,
+		&rate,
+		&currency,
+		&c.Billable,
+		&c.Version,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+		&c.ArchivedAt,
+		&c.DeletedAt,
+	)
+	c.Rate = rateFromColumns(rate, currency)
+	return c, err
+}
+
+// trailingScanner scans the columns a query selects after categoryColumns into extra.
+type trailingScanner struct {
+	rowScanner
+	extra []any
+}
+
+func (s trailingScanner) Scan(dest ...any) error {
+	return s.rowScanner.Scan(append(dest, s.extra...)...)
+}
+
+func collectCategories(rows *sql.Rows) ([]domain.Category, error) {
+	defer rows.Close()
+
+	var categories []domain.Category
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			return nil, MapError(err)
+		}
+		categories = append(categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, MapError(err)
+	}
+	return categories, nil
+}
+
 func (r *categoryRepository) Create(ctx context.Context, category domain.Category) (domain.Category, error) {
+	// Projects in the trash take no new categories; unknown projects still fail on the foreign key.
 	const query = `
-		INSERT INTO category (id, project_id, parent_category_id, name, description)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, project_id, parent_category_id, name, description, created_at, updated_at
-	`
-	var out domain.Category
-	if err := r.db.QueryRowContext(
+		INSERT INTO category (id, project_id, parent_category_id, name, description, hourly_rate_cents, currency, billable)
+		SELECT $1::uuid, $2::uuid, $3::uuid, $4::text, $5::text, $6::bigint, $7::text, $8::boolean
+		WHERE NOT EXISTS (SELECT 1 FROM project WHERE id = $2 AND deleted_at IS NOT NULL)
+		RETURNING ` + categoryColumns
+	rate, currency := rateColumns(category.Rate)
+	out, err := scanCategory(conn(ctx, r.db).QueryRowContext(
 		ctx,
 		query,
 		category.ID,
@@ -32,15 +90,14 @@ func (r *categoryRepository) Create(ctx context.Context, category domain.Categor
 		category.ParentCategoryID,
 		category.Name,
 		category.Description,
-	).Scan(
-		&out.ID,
-		&out.ProjectID,
-		&out.ParentCategoryID,
-		&out.Name,
-		&out.Description,
-		&out.CreatedAt,
-		&out.UpdatedAt,
-	); err != nil {
+		rate,
+		currency,
+		category.Billable,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Category{}, repository.ErrNotFound
+		}
 		return domain.Category{}, MapError(err)
 	}
 	return out, nil
@@ -48,20 +105,12 @@ func (r *categoryRepository) Create(ctx context.Context, category domain.Categor
 
 func (r *categoryRepository) GetByID(ctx context.Context, id uuid.UUID) (domain.Category, error) {
 	const query = `
-		SELECT id, project_id, parent_category_id, name, description, created_at, updated_at
+		SELECT ` + categoryColumns + `
 		FROM category
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
-	var out domain.Category
-	if err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&out.ID,
-		&out.ProjectID,
-		&out.ParentCategoryID,
-		&out.Name,
-		&out.Description,
-		&out.CreatedAt,
-		&out.UpdatedAt,
-	); err != nil {
+	out, err := scanCategory(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Category{}, repository.ErrNotFound
 		}
@@ -70,77 +119,62 @@ func (r *categoryRepository) GetByID(ctx context.Context, id uuid.UUID) (domain.
 	return out, nil
 }
 
-func (r *categoryRepository) ListByProject(ctx context.Context, projectID uuid.UUID) ([]domain.Category, error) {
+func (r *categoryRepository) ListByProject(ctx context.Context, projectID uuid.UUID, includeArchived bool, page repository.PageRequest) ([]domain.Category, error) {
 	const query = `
-		SELECT id, project_id, parent_category_id, name, description, created_at, updated_at
+		SELECT ` + categoryColumns + `
 		FROM category
-		WHERE project_id = $1
-		ORDER BY created_at ASC
+		WHERE project_id = $1 AND deleted_at IS NULL AND ($5::boolean OR archived_at IS NULL)
+		  AND ($2::timestamptz IS NULL OR (created_at, id) > ($2::timestamptz, $3::uuid))
+		ORDER BY created_at ASC, id ASC
+		LIMIT $4
 	`
-	rows, err := r.db.QueryContext(ctx, query, projectID)
+	after, afterID, limit := createdAtPageArgs(page)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, projectID, after, afterID, limit, includeArchived)
 	if err != nil {
 		return nil, MapError(err)
 	}
-	defer rows.Close()
-
-	var categories []domain.Category
-	for rows.Next() {
-		var c domain.Category
-		if err := rows.Scan(&c.ID, &c.ProjectID, &c.ParentCategoryID, &c.Name, &c.Description, &c.CreatedAt, &c.UpdatedAt); err != nil {
-			return nil, MapError(err)
-		}
-		categories = append(categories, c)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, MapError(err)
-	}
-	return categories, nil
+	return collectCategories(rows)
 }
 
 func (r *categoryRepository) ListChildren(ctx context.Context, parentID uuid.UUID) ([]domain.Category, error) {
 	const query = `
-		SELECT id, project_id, parent_category_id, name, description, created_at, updated_at
+		SELECT ` + categoryColumns + `
 		FROM category
-		WHERE parent_category_id = $1
+		WHERE parent_category_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at ASC
 	`
-	rows, err := r.db.QueryContext(ctx, query, parentID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, parentID)
 	if err != nil {
 		return nil, MapError(err)
 	}
-	defer rows.Close()
+	return collectCategories(rows)
+}
 
-	var categories []domain.Category
-	for rows.Next() {
-		var c domain.Category
-		if err := rows.Scan(&c.ID, &c.ProjectID, &c.ParentCategoryID, &c.Name, &c.Description, &c.CreatedAt, &c.UpdatedAt); err != nil {
-			return nil, MapError(err)
+func (r *categoryRepository) Update(ctx context.Context, id uuid.UUID, name string, description *string, parentCategoryID *uuid.UUID) (domain.Category, error) {
+	const query = `
+		UPDATE category
+		SET name = $1, description = $2, parent_category_id = $3, version = version + 1, updated_at = now()
+		WHERE id = $4 AND deleted_at IS NULL AND ($5::bigint IS NULL OR version = $5)
+		RETURNING ` + categoryColumns
+	out, err := scanCategory(conn(ctx, r.db).QueryRowContext(ctx, query, name, description, parentCategoryID, id, versionArg(ctx, id)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Category{}, missingOrStale(ctx, r.db, "category", id)
 		}
-		categories = append(categories, c)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, MapError(err)
+		return domain.Category{}, MapError(err)
 	}
-	return categories, nil
+	return out, nil
 }
 
-func (r *categoryRepository) Update(ctx context.Context, id uuid.UUID, name string, description *string, parentCategoryID *uuid.UUID) (domain.Category, error) {
+func (r *categoryRepository) UpdateBilling(ctx context.Context, id uuid.UUID, rate *domain.Rate, billable bool) (domain.Category, error) {
 	const query = `
 		UPDATE category
-		SET name = $1, description = $2, parent_category_id = $3, updated_at = now()
-		WHERE id = $4
-		RETURNING id, project_id, parent_category_id, name, description, created_at, updated_at
-	`
-	var out domain.Category
-	if err := r.db.QueryRowContext(ctx, query, name, description, parentCategoryID, id).Scan(
-		&out.ID,
-		&out.ProjectID,
-		&out.ParentCategoryID,
-		&out.Name,
-		&out.Description,
-		&out.CreatedAt,
-		&out.UpdatedAt,
-	); err != nil {
+		SET hourly_rate_cents = $2, currency = $3, billable = $4, version = version + 1, updated_at = now()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING ` + categoryColumns
+	cents, currency := rateColumns(rate)
+	out, err := scanCategory(conn(ctx, r.db).QueryRowContext(ctx, query, id, cents, currency, billable))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Category{}, repository.ErrNotFound
 		}
@@ -149,21 +183,173 @@ func (r *categoryRepository) Update(ctx context.Context, id uuid.UUID, name stri
 	return out, nil
 }
 
-func (r *categoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *categoryRepository) SetArchived(ctx context.Context, id uuid.UUID, archived bool) (domain.Category, error) {
 	const query = `
-		DELETE FROM category
-		WHERE id = $1
-	`
-	res, err := r.db.ExecContext(ctx, query, id)
+		UPDATE category
+		SET archived_at = CASE WHEN $2::boolean THEN coalesce(archived_at, now()) END,
+		    version = version + 1, updated_at = now()
+		WHERE id = $1 AND deleted_at IS NULL AND ($3::bigint IS NULL OR version = $3)
+		RETURNING ` + categoryColumns
+	out, err := scanCategory(conn(ctx, r.db).QueryRowContext(ctx, query, id, archived, versionArg(ctx, id)))
 	if err != nil {
-		return MapError(err)
+		if err == sql.ErrNoRows {
+			return domain.Category{}, missingOrStale(ctx, r.db, "category", id)
+		}
+		return domain.Category{}, MapError(err)
 	}
-	n, err := res.RowsAffected()
-	if err != nil {
+	return out, nil
+}
+
+func (r *categoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	// The subtree and its entries share the category's deleted_at, which lets Restore find them.
+	const query = `
+		WITH RECURSIVE trashed AS (
+			UPDATE category
+			SET deleted_at = now(), version = version + 1, updated_at = now()
+			WHERE id = $1 AND deleted_at IS NULL AND ($2::bigint IS NULL OR version = $2)
+			RETURNING id
+		), subtree AS (
+			SELECT id FROM trashed
+			UNION ALL
+			SELECT c.id
+			FROM category c
+			JOIN subtree s ON c.parent_category_id = s.id
+			WHERE c.deleted_at IS NULL
+		), trashed_descendants AS (
+			UPDATE category
+			SET deleted_at = now(), version = version + 1, updated_at = now()
+			WHERE id IN (SELECT id FROM subtree) AND id <> $1
+		), trashed_entries AS (
+			UPDATE time_entry
+			SET deleted_at = now(), version = version + 1, updated_at = now()
+			WHERE category_id IN (SELECT id FROM subtree) AND deleted_at IS NULL
+		)
+		SELECT count(*) FROM trashed
+	`
+	var n int64
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, id, versionArg(ctx, id)).Scan(&n); err != nil {
 		return MapError(err)
 	}
 	if n == 0 {
-		return repository.ErrNotFound
+		return missingOrStale(ctx, r.db, "category", id)
 	}
 	return nil
 }
+
+func (r *categoryRepository) ListDeleted(ctx context.Context) ([]domain.Category, error) {
+	const query = `
+		SELECT ` + categoryColumns + `
+		FROM category
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, MapError(err)
+	}
+	return collectCategories(rows)
+}
+
+func (r *categoryRepository) Restore(ctx context.Context, id uuid.UUID) (domain.Category, error) {
+	// The descendants deleted together with the category come back under their original parents.
+	const query = `
+		WITH RECURSIVE target AS (
+			SELECT c.id, c.deleted_at
+			FROM category c
+			JOIN project p ON p.id = c.project_id AND p.deleted_at IS NULL
+			LEFT JOIN category parent ON parent.id = c.parent_category_id
+			WHERE c.id = $1 AND c.deleted_at IS NOT NULL AND parent.deleted_at IS NULL
+		), subtree AS (
+			SELECT id, deleted_at FROM target
+			UNION ALL
+			SELECT c.id, c.deleted_at
+			FROM category c
+			JOIN subtree s ON c.parent_category_id = s.id AND c.deleted_at = s.deleted_at
+		), restored_descendants AS (
+			UPDATE category
+			SET deleted_at = NULL, version = version + 1, updated_at = now()
+			WHERE id IN (SELECT id FROM subtree) AND id <> $1
+		), restored_entries AS (
+			UPDATE time_entry e
+			SET deleted_at = NULL, version = e.version + 1, updated_at = now()
+			FROM subtree s
+			WHERE e.category_id = s.id AND e.deleted_at = s.deleted_at
+		)
+		UPDATE category
+		SET deleted_at = NULL, version = version + 1, updated_at = now()
+		WHERE id IN (SELECT id FROM target) AND deleted_at IS NOT NULL
+		RETURNING ` + categoryColumns
+	out, err := scanCategory(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Category{}, notRestorable(ctx, r.db, "category", id)
+		}
+		return domain.Category{}, MapError(err)
+	}
+	return out, nil
+}
+
+func (r *categoryRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return purgeDeleted(ctx, r.db, "category", before)
+}
+
+func (r *categoryRepository) TotalsByProject(ctx context.Context, projectID uuid.UUID, start *time.Time, end *time.Time, now time.Time) ([]domain.CategoryTotal, error) {
+	// Entries that were paused run in their segments, the others from start to stop. The subtree
+	// of a category pairs it with itself and each of its descendants; UNION keeps the recursion
+	// finite should the parent links ever form a cycle.
+	const query = `
+		WITH RECURSIVE worked AS (
+			SELECT te.category_id,
+			       GREATEST(COALESCE(s.started_at, te.started_at), COALESCE($3::timestamptz, '-infinity')) AS lo,
+			       LEAST(COALESCE(s.stopped_at, te.stopped_at, $2), COALESCE($4::timestamptz, 'infinity')) AS hi
+			FROM time_entry te
+			JOIN category c ON c.id = te.category_id
+			LEFT JOIN time_entry_segment s ON s.time_entry_id = te.id
+			WHERE c.project_id = $1 AND te.deleted_at IS NULL
+		), own AS (
+			SELECT category_id, FLOOR(SUM(EXTRACT(EPOCH FROM hi - lo)))::bigint AS seconds
+			FROM worked
+			WHERE hi > lo
+			GROUP BY category_id
+		), subtree AS (
+			SELECT id AS root_id, id AS category_id
+			FROM category
+			WHERE project_id = $1 AND deleted_at IS NULL
+			UNION
+			SELECT s.root_id, c.id
+			FROM subtree s
+			JOIN category c ON c.parent_category_id = s.category_id AND c.deleted_at IS NULL
+		), rolled AS (
+			SELECT s.root_id, COALESCE(SUM(o.seconds), 0)::bigint AS seconds
+			FROM subtree s
+			LEFT JOIN own o ON o.category_id = s.category_id
+			GROUP BY s.root_id
+		)
+		SELECT ` + categoryColumns + `, COALESCE(own.seconds, 0), rolled.seconds
+		FROM category
+		JOIN rolled ON rolled.root_id = category.id
+		LEFT JOIN own ON own.category_id = category.id
+		WHERE category.project_id = $1 AND category.deleted_at IS NULL
+		ORDER BY category.created_at ASC, category.id ASC
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, projectID, now, start, end)
+	if err != nil {
+		return nil, MapError(err)
+	}
+	defer rows.Close()
+
+	var totals []domain.CategoryTotal
+	for rows.Next() {
+		var t domain.CategoryTotal
+		c, err := scanCategory(trailingScanner{rows, []any{&t.OwnSeconds, &t.SubtreeSeconds}})
+		if err != nil {
+			return nil, MapError(err)
+		}
+		t.Category = c
+		totals = append(totals, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, MapError(err)
+	}
+	return totals, nil
+}
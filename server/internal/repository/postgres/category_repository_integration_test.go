@@ -8,7 +8,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Gargair/clockwork/server/internal/domain"
 	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/google/uuid"
 )
 
 func TestCategoryRepositoryCreateSucceedsWithValidProjectIntegration(t *testing.T) {
@@ -63,7 +65,7 @@ func TestCategoryRepositoryUniqueCategoryNameWithinProjectEnforcedIntegration(t
 	}
 }
 
-func TestCategoryRepositoryParentChildAndDeleteParentSetsChildrenNullIntegration(t *testing.T) {
+func TestCategoryRepositoryDeleteParentTrashesChildrenAndRestoreKeepsLinksIntegration(t *testing.T) {
 	db := OpenDBFromEnv(t)
 	t.Cleanup(func() { _ = db.Close() })
 	TruncateAll(t, db)
@@ -101,20 +103,74 @@ func TestCategoryRepositoryParentChildAndDeleteParentSetsChildrenNullIntegration
 	if err := cr.Delete(ctx, parent.ID); err != nil {
 		t.Fatalf("delete parent: %v", err)
 	}
+	if _, err := cr.GetByID(ctx, childA.ID); err != repository.ErrNotFound {
+		t.Fatalf("expected childA in the trash with its parent, got %v", err)
+	}
+	trashed, err := cr.ListDeleted(ctx)
+	if err != nil {
+		t.Fatalf("ListDeleted: %v", err)
+	}
+	if len(trashed) != 3 {
+		t.Fatalf("expected the parent and both children in the trash, got %d", len(trashed))
+	}
+	if _, err := cr.Restore(ctx, childA.ID); err != repository.ErrParentDeleted {
+		t.Fatalf("expected ErrParentDeleted restoring a child alone, got %v", err)
+	}
+
+	if _, err := cr.Restore(ctx, parent.ID); err != nil {
+		t.Fatalf("restore parent: %v", err)
+	}
+	for _, child := range []domain.Category{childA, childB} {
+		fetched, err := cr.GetByID(ctx, child.ID)
+		if err != nil {
+			t.Fatalf("get %s: %v", child.Name, err)
+		}
+		if fetched.ParentCategoryID == nil || *fetched.ParentCategoryID != parent.ID || fetched.DeletedAt != nil {
+			t.Fatalf("expected %s back under its parent, got %+v", child.Name, fetched)
+		}
+	}
+}
 
-	aFetched, err := cr.GetByID(ctx, childA.ID)
+func TestCategoryRepositoryRestoreKeepsSeparatelyDeletedChildrenInTrashIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("separate-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	parent, err := cr.Create(ctx, NewCategory(p.ID, "parent", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "parent category", err)
+	}
+	child, err := cr.Create(ctx, NewCategory(p.ID, "child", &parent.ID, nil))
 	if err != nil {
-		t.Fatalf("get childA: %v", err)
+		t.Fatalf(CreateFailedErrorMessage, "child", err)
+	}
+	if err := cr.Delete(ctx, child.ID); err != nil {
+		t.Fatalf("delete child: %v", err)
+	}
+	if err := cr.Delete(ctx, parent.ID); err != nil {
+		t.Fatalf("delete parent: %v", err)
+	}
+
+	if _, err := cr.Restore(ctx, parent.ID); err != nil {
+		t.Fatalf("restore parent: %v", err)
 	}
-	if aFetched.ParentCategoryID != nil {
-		t.Fatalf("expected childA parent to be NULL, got %v", aFetched.ParentCategoryID)
+	if _, err := cr.GetByID(ctx, child.ID); err != repository.ErrNotFound {
+		t.Fatalf("expected the earlier deleted child to stay in the trash, got %v", err)
 	}
-	bFetched, err := cr.GetByID(ctx, childB.ID)
+	restored, err := cr.Restore(ctx, child.ID)
 	if err != nil {
-		t.Fatalf("get childB: %v", err)
+		t.Fatalf("restore child: %v", err)
 	}
-	if bFetched.ParentCategoryID != nil {
-		t.Fatalf("expected childB parent to be NULL, got %v", bFetched.ParentCategoryID)
+	if restored.ParentCategoryID == nil || *restored.ParentCategoryID != parent.ID {
+		t.Fatalf("expected the child back under its parent, got %+v", restored)
 	}
 }
 
@@ -193,7 +249,7 @@ func TestCategoryRepositoryListByProjectFiltersIntegration(t *testing.T) {
 		t.Fatalf(CreateFailedErrorMessage, "cat c", err)
 	}
 
-	catsP1, err := cr.ListByProject(ctx, p1.ID)
+	catsP1, err := cr.ListByProject(ctx, p1.ID, false, repository.PageRequest{})
 	if err != nil {
 		t.Fatalf("ListByProject p1: %v", err)
 	}
@@ -206,3 +262,267 @@ func TestCategoryRepositoryListByProjectFiltersIntegration(t *testing.T) {
 		}
 	}
 }
+
+func TestCategoryRepositoryBillingIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("billing-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	if p.Rate != nil {
+		t.Fatalf("expected no project rate, got %+v", p.Rate)
+	}
+	p, err = pr.UpdateRate(ctx, p.ID, &domain.Rate{HourlyRateCents: 9000, Currency: "EUR"})
+	if err != nil {
+		t.Fatalf("UpdateRate: %v", err)
+	}
+	got, err := pr.GetByID(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Rate == nil || got.Rate.HourlyRateCents != 9000 || got.Rate.Currency != "EUR" {
+		t.Fatalf("unexpected project rate: %+v", got.Rate)
+	}
+
+	c, err := cr.Create(ctx, NewCategory(p.ID, "billing-cat", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+	c, err = cr.UpdateBilling(ctx, c.ID, &domain.Rate{HourlyRateCents: 15000, Currency: "USD"}, true)
+	if err != nil {
+		t.Fatalf("UpdateBilling: %v", err)
+	}
+	if c.Rate == nil || c.Rate.Currency != "USD" || !c.Billable {
+		t.Fatalf("unexpected category billing: %+v", c)
+	}
+	c, err = cr.UpdateBilling(ctx, c.ID, nil, false)
+	if err != nil {
+		t.Fatalf("UpdateBilling clear: %v", err)
+	}
+	if c.Rate != nil || c.Billable {
+		t.Fatalf("expected rate cleared and non-billable, got %+v", c)
+	}
+	if _, err := cr.UpdateBilling(ctx, uuid.New(), nil, true); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	tr := NewTimeEntryRepository(db)
+	e := NewTimeEntry(c.ID, time.Now().UTC().Add(-time.Hour))
+	e.Billable = true
+	if _, err := tr.Create(ctx, e); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "time entry", err)
+	}
+	stored, err := tr.GetByID(ctx, e.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if !stored.Billable {
+		t.Fatalf("expected billable entry to round-trip")
+	}
+}
+
+func TestCategoryRepositoryConditionalWritesIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("version-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	c, err := cr.Create(ctx, NewCategory(p.ID, "versioned", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+	if c.Version != 1 {
+		t.Fatalf("expected version 1 on create, got %d", c.Version)
+	}
+
+	updated, err := cr.Update(repository.WithExpectedVersion(ctx, c.ID, 1), c.ID, "renamed", nil, nil)
+	if err != nil {
+		t.Fatalf("conditional update: %v", err)
+	}
+	if updated.Version != 2 {
+		t.Fatalf("expected version 2 after update, got %d", updated.Version)
+	}
+	if _, err := cr.Update(repository.WithExpectedVersion(ctx, c.ID, 1), c.ID, "stale", nil, nil); err != repository.ErrVersionMismatch {
+		t.Fatalf("expected ErrVersionMismatch for a stale update, got %v", err)
+	}
+	if err := cr.Delete(repository.WithExpectedVersion(ctx, c.ID, 1), c.ID); err != repository.ErrVersionMismatch {
+		t.Fatalf("expected ErrVersionMismatch for a stale delete, got %v", err)
+	}
+	if err := cr.Delete(repository.WithExpectedVersion(ctx, c.ID, 2), c.ID); err != nil {
+		t.Fatalf("conditional delete: %v", err)
+	}
+	if err := cr.Delete(repository.WithExpectedVersion(ctx, c.ID, 2), c.ID); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound for a deleted category, got %v", err)
+	}
+}
+
+func TestCategoryRepositorySetArchivedIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("archive-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	active, err := cr.Create(ctx, NewCategory(p.ID, "active", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+	done, err := cr.Create(ctx, NewCategory(p.ID, "done", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+
+	archived, err := cr.SetArchived(repository.WithExpectedVersion(ctx, done.ID, 1), done.ID, true)
+	if err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	if archived.ArchivedAt == nil || archived.Version != 2 {
+		t.Fatalf("unexpected archived category: %+v", archived)
+	}
+	again, err := cr.SetArchived(ctx, done.ID, true)
+	if err != nil || again.ArchivedAt == nil || !again.ArchivedAt.Equal(*archived.ArchivedAt) {
+		t.Fatalf("expected archiving twice to keep archived_at, got %+v (%v)", again, err)
+	}
+	if _, err := cr.SetArchived(repository.WithExpectedVersion(ctx, done.ID, 1), done.ID, false); err != repository.ErrVersionMismatch {
+		t.Fatalf("expected ErrVersionMismatch for a stale unarchive, got %v", err)
+	}
+
+	visible, err := cr.ListByProject(ctx, p.ID, false, repository.PageRequest{})
+	if err != nil || len(visible) != 1 || visible[0].ID != active.ID {
+		t.Fatalf("expected only the active category, got %+v (%v)", visible, err)
+	}
+	all, err := cr.ListByProject(ctx, p.ID, true, repository.PageRequest{})
+	if err != nil || len(all) != 2 {
+		t.Fatalf("expected both categories with includeArchived, got %+v (%v)", all, err)
+	}
+
+	unarchived, err := cr.SetArchived(ctx, done.ID, false)
+	if err != nil || unarchived.ArchivedAt != nil {
+		t.Fatalf("expected the category unarchived, got %+v (%v)", unarchived, err)
+	}
+	if err := cr.Delete(ctx, done.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := cr.SetArchived(ctx, done.ID, true); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound for a trashed category, got %v", err)
+	}
+}
+
+func TestCategoryRepositoryTotalsByProjectIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("tree-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	dev, err := cr.Create(ctx, NewCategory(p.ID, "dev", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+	backend, err := cr.Create(ctx, NewCategory(p.ID, "backend", &dev.ID, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+	api, err := cr.Create(ctx, NewCategory(p.ID, "api", &backend.ID, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+	ops, err := cr.Create(ctx, NewCategory(p.ID, "ops", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+	gone, err := cr.Create(ctx, NewCategory(p.ID, "gone", &dev.ID, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+
+	base := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	if _, err := tr.Create(ctx, NewStoppedTimeEntry(dev.ID, base, time.Hour)); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "time entry", err)
+	}
+	// Paused for 30 minutes in the middle: only its segments count
+	paused, err := tr.Create(ctx, NewStoppedTimeEntry(backend.ID, base.Add(2*time.Hour), 90*time.Minute))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "time entry", err)
+	}
+	for _, seg := range [][2]time.Time{
+		{base.Add(2 * time.Hour), base.Add(150 * time.Minute)},
+		{base.Add(180 * time.Minute), base.Add(210 * time.Minute)},
+	} {
+		stop := seg[1]
+		if _, err := tr.CreateSegment(ctx, domain.TimeEntrySegment{ID: uuid.New(), TimeEntryID: paused.ID, StartedAt: seg[0], StoppedAt: &stop}); err != nil {
+			t.Fatalf(CreateFailedErrorMessage, "segment", err)
+		}
+	}
+	// Running for 15 minutes at now
+	if _, err := tr.Create(ctx, NewTimeEntry(api.ID, base.Add(5*time.Hour))); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "time entry", err)
+	}
+	// Time in a deleted category leaves the tree with it
+	if _, err := tr.Create(ctx, NewStoppedTimeEntry(gone.ID, base.Add(6*time.Hour), time.Hour)); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "time entry", err)
+	}
+	if err := cr.Delete(ctx, gone.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	now := base.Add(5*time.Hour + 15*time.Minute)
+	totals, err := cr.TotalsByProject(ctx, p.ID, nil, nil, now)
+	if err != nil {
+		t.Fatalf("TotalsByProject: %v", err)
+	}
+	want := []struct {
+		id           uuid.UUID
+		own, subtree int64
+	}{
+		{dev.ID, 3600, 8100},
+		{backend.ID, 3600, 4500},
+		{api.ID, 900, 900},
+		{ops.ID, 0, 0},
+	}
+	if len(totals) != len(want) {
+		t.Fatalf("expected %d categories, got %+v", len(want), totals)
+	}
+	for i, w := range want {
+		if got := totals[i]; got.Category.ID != w.id || got.OwnSeconds != w.own || got.SubtreeSeconds != w.subtree {
+			t.Fatalf("category %d: expected %s with %d/%ds, got %+v", i, w.id, w.own, w.subtree, got)
+		}
+	}
+
+	// Bounds clip the first hour and the running entry
+	from, to := base.Add(30*time.Minute), base.Add(5*time.Hour+10*time.Minute)
+	totals, err = cr.TotalsByProject(ctx, p.ID, &from, &to, now)
+	if err != nil {
+		t.Fatalf("TotalsByProject range: %v", err)
+	}
+	if totals[0].OwnSeconds != 1800 || totals[0].SubtreeSeconds != 1800+3600+600 || totals[2].OwnSeconds != 600 {
+		t.Fatalf("unexpected clipped totals: %+v", totals)
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"github.com/jackc/pgconn"
 )
 
+// runningSlotIndex is the partial unique index allowing one running time entry per timer slot.
+const runningSlotIndex = "time_entry_running_slot_unique"
+
 // MapError translates low-level Postgres errors into repository-level errors where appropriate.
 // Unknown errors are returned unchanged.
 func MapError(err error) error {
@@ -19,6 +22,9 @@ func MapError(err error) error {
 	if errors.As(err, &pgErrPtr) {
 		switch pgErrPtr.Code {
 		case "23505": // unique_violation
+			if pgErrPtr.ConstraintName == runningSlotIndex {
+				return repository.ErrRunningEntryConflict
+			}
 			return repository.ErrDuplicate
 		case "23503": // foreign_key_violation
 			return repository.ErrForeignKeyViolation
@@ -27,6 +33,9 @@ func MapError(err error) error {
 	// Fallback: match common SQLSTATE codes in the error string
 	msg := err.Error()
 	if strings.Contains(msg, "SQLSTATE 23505") { // unique_violation
+		if strings.Contains(msg, runningSlotIndex) {
+			return repository.ErrRunningEntryConflict
+		}
 		return repository.ErrDuplicate
 	}
 	if strings.Contains(msg, "SQLSTATE 23503") { // foreign_key_violation
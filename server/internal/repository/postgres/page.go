@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+// createdAtPageArgs returns the arguments of a listing ordered by (created_at, id): the cursor's
+// timestamp and ID, both NULL on the first page, and the limit, NULL when unlimited.
+func createdAtPageArgs(page repository.PageRequest) (*time.Time, *uuid.UUID, *int) {
+	var (
+		after   *time.Time
+		afterID *uuid.UUID
+		limit   *int
+	)
+	if page.After != nil {
+		after, afterID = &page.After.At, &page.After.ID
+	}
+	if page.Limit > 0 {
+		limit = &page.Limit
+	}
+	return after, afterID, limit
+}
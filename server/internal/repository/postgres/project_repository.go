@@ -3,12 +3,18 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/Gargair/clockwork/server/internal/domain"
 	"github.com/Gargair/clockwork/server/internal/repository"
 	"github.com/google/uuid"
 )
 
+// projectColumns is the column list shared by every query returning projects.
+// It must stay in sync with scanProject.
+const projectColumns = `id, name, description, hourly_rate_cents, currency, concurrent_timers,
+	rounding_increment_seconds, rounding_mode, rounding_scope, version, created_at, updated_at, archived_at, deleted_at`
+
 type projectRepository struct {
 	db *sql.DB
 }
@@ -17,26 +23,54 @@ func NewProjectRepository(db *sql.DB) repository.ProjectRepository {
 	return &projectRepository{db: db}
 }
 
+func scanProject(row rowScanner) (domain.Project, error) {
+	var (
+		p        domain.Project
+		rate     *int64
+		currency *string
+	)
+	err := row.Scan(
+		&p.ID,
+		&p.Name,
+		&p.Description,
+		&rate,
+		&currency,
+		&p.Settings.ConcurrentTimers,
+		&p.Settings.Rounding.IncrementSeconds,
+		&p.Settings.Rounding.Mode,
+		&p.Settings.Rounding.Scope,
+		&p.Version,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+		&p.ArchivedAt,
+		&p.DeletedAt,
+	)
+	p.Rate = rateFromColumns(rate, currency)
+	return p, err
+}
+
 func (r *projectRepository) Create(ctx context.Context, project domain.Project) (domain.Project, error) {
 	const query = `
-		INSERT INTO project (id, name, description)
-		VALUES ($1, $2, $3)
-		RETURNING id, name, description, created_at, updated_at
-	`
-	var out domain.Project
-	if err := r.db.QueryRowContext(
+		INSERT INTO project (id, name, description, hourly_rate_cents, currency, concurrent_timers,
+		                     rounding_increment_seconds, rounding_mode, rounding_scope)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING ` + projectColumns
+	rate, currency := rateColumns(project.Rate)
+	rounding := roundingColumns(project.Settings.Rounding)
+	out, err := scanProject(conn(ctx, r.db).QueryRowContext(
 		ctx,
 		query,
 		project.ID,
 		project.Name,
 		project.Description,
-	).Scan(
-		&out.ID,
-		&out.Name,
-		&out.Description,
-		&out.CreatedAt,
-		&out.UpdatedAt,
-	); err != nil {
+		rate,
+		currency,
+		project.Settings.ConcurrentTimers,
+		rounding.IncrementSeconds,
+		rounding.Mode,
+		rounding.Scope,
+	))
+	if err != nil {
 		return domain.Project{}, MapError(err)
 	}
 	return out, nil
@@ -44,18 +78,12 @@ func (r *projectRepository) Create(ctx context.Context, project domain.Project)
 
 func (r *projectRepository) GetByID(ctx context.Context, id uuid.UUID) (domain.Project, error) {
 	const query = `
-		SELECT id, name, description, created_at, updated_at
+		SELECT ` + projectColumns + `
 		FROM project
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
-	var out domain.Project
-	if err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&out.ID,
-		&out.Name,
-		&out.Description,
-		&out.CreatedAt,
-		&out.UpdatedAt,
-	); err != nil {
+	out, err := scanProject(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Project{}, repository.ErrNotFound
 		}
@@ -64,13 +92,17 @@ func (r *projectRepository) GetByID(ctx context.Context, id uuid.UUID) (domain.P
 	return out, nil
 }
 
-func (r *projectRepository) List(ctx context.Context) ([]domain.Project, error) {
+func (r *projectRepository) List(ctx context.Context, includeArchived bool, page repository.PageRequest) ([]domain.Project, error) {
 	const query = `
-		SELECT id, name, description, created_at, updated_at
+		SELECT ` + projectColumns + `
 		FROM project
-		ORDER BY created_at ASC
+		WHERE deleted_at IS NULL AND ($4::boolean OR archived_at IS NULL)
+		  AND ($1::timestamptz IS NULL OR (created_at, id) > ($1::timestamptz, $2::uuid))
+		ORDER BY created_at ASC, id ASC
+		LIMIT $3
 	`
-	rows, err := r.db.QueryContext(ctx, query)
+	after, afterID, limit := createdAtPageArgs(page)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, after, afterID, limit, includeArchived)
 	if err != nil {
 		return nil, MapError(err)
 	}
@@ -78,8 +110,8 @@ func (r *projectRepository) List(ctx context.Context) ([]domain.Project, error)
 
 	var projects []domain.Project
 	for rows.Next() {
-		var p domain.Project
-		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		p, err := scanProject(rows)
+		if err != nil {
 			return nil, MapError(err)
 		}
 		projects = append(projects, p)
@@ -93,18 +125,28 @@ func (r *projectRepository) List(ctx context.Context) ([]domain.Project, error)
 func (r *projectRepository) Update(ctx context.Context, id uuid.UUID, name string, description *string) (domain.Project, error) {
 	const query = `
 		UPDATE project
-		SET name = $1, description = $2, updated_at = now()
-		WHERE id = $3
-		RETURNING id, name, description, created_at, updated_at
-	`
-	var out domain.Project
-	if err := r.db.QueryRowContext(ctx, query, name, description, id).Scan(
-		&out.ID,
-		&out.Name,
-		&out.Description,
-		&out.CreatedAt,
-		&out.UpdatedAt,
-	); err != nil {
+		SET name = $1, description = $2, version = version + 1, updated_at = now()
+		WHERE id = $3 AND deleted_at IS NULL AND ($4::bigint IS NULL OR version = $4)
+		RETURNING ` + projectColumns
+	out, err := scanProject(conn(ctx, r.db).QueryRowContext(ctx, query, name, description, id, versionArg(ctx, id)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Project{}, missingOrStale(ctx, r.db, "project", id)
+		}
+		return domain.Project{}, MapError(err)
+	}
+	return out, nil
+}
+
+func (r *projectRepository) UpdateRate(ctx context.Context, id uuid.UUID, rate *domain.Rate) (domain.Project, error) {
+	const query = `
+		UPDATE project
+		SET hourly_rate_cents = $2, currency = $3, version = version + 1, updated_at = now()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING ` + projectColumns
+	cents, currency := rateColumns(rate)
+	out, err := scanProject(conn(ctx, r.db).QueryRowContext(ctx, query, id, cents, currency))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Project{}, repository.ErrNotFound
 		}
@@ -113,21 +155,178 @@ func (r *projectRepository) Update(ctx context.Context, id uuid.UUID, name strin
 	return out, nil
 }
 
-func (r *projectRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *projectRepository) UpdateSettings(ctx context.Context, id uuid.UUID, settings domain.ProjectSettings) (domain.Project, error) {
+	// Running entries move to the timer slots of the new concurrent_timers setting in the same
+	// statement; entries that would then share a slot fail it with the running slot index.
 	const query = `
-		DELETE FROM project
-		WHERE id = $1
-	`
-	res, err := r.db.ExecContext(ctx, query, id)
+		WITH updated AS (
+			UPDATE project
+			SET concurrent_timers = $2,
+			    rounding_increment_seconds = $3, rounding_mode = $4, rounding_scope = $5,
+			    version = version + 1, updated_at = now()
+			WHERE id = $1 AND deleted_at IS NULL AND ($6::bigint IS NULL OR version = $6)
+			RETURNING ` + projectColumns + `
+		), reslotted AS (
+			UPDATE time_entry te
+			SET timer_slot = CASE WHEN $2 THEN te.category_id ELSE '00000000-0000-0000-0000-000000000000'::uuid END
+			FROM category c
+			WHERE c.id = te.category_id AND c.project_id = $1 AND te.stopped_at IS NULL
+			  AND EXISTS (SELECT 1 FROM updated)
+		)
+		SELECT ` + projectColumns + ` FROM updated`
+	rounding := roundingColumns(settings.Rounding)
+	out, err := scanProject(conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		id,
+		settings.ConcurrentTimers,
+		rounding.IncrementSeconds,
+		rounding.Mode,
+		rounding.Scope,
+		versionArg(ctx, id),
+	))
 	if err != nil {
-		return MapError(err)
+		if err == sql.ErrNoRows {
+			return domain.Project{}, missingOrStale(ctx, r.db, "project", id)
+		}
+		return domain.Project{}, MapError(err)
 	}
-	n, err := res.RowsAffected()
+	return out, nil
+}
+
+func (r *projectRepository) SetArchived(ctx context.Context, id uuid.UUID, archived bool) (domain.Project, error) {
+	const query = `
+		UPDATE project
+		SET archived_at = CASE WHEN $2::boolean THEN coalesce(archived_at, now()) END,
+		    version = version + 1, updated_at = now()
+		WHERE id = $1 AND deleted_at IS NULL AND ($3::bigint IS NULL OR version = $3)
+		RETURNING ` + projectColumns
+	out, err := scanProject(conn(ctx, r.db).QueryRowContext(ctx, query, id, archived, versionArg(ctx, id)))
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Project{}, missingOrStale(ctx, r.db, "project", id)
+		}
+		return domain.Project{}, MapError(err)
+	}
+	return out, nil
+}
+
+func (r *projectRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	// The categories and entries share the project's deleted_at, which lets Restore find them.
+	const query = `
+		WITH trashed AS (
+			UPDATE project
+			SET deleted_at = now(), version = version + 1, updated_at = now()
+			WHERE id = $1 AND deleted_at IS NULL AND ($2::bigint IS NULL OR version = $2)
+			RETURNING id
+		), trashed_categories AS (
+			UPDATE category
+			SET deleted_at = now(), version = version + 1, updated_at = now()
+			WHERE project_id IN (SELECT id FROM trashed) AND deleted_at IS NULL
+			RETURNING id
+		), trashed_entries AS (
+			UPDATE time_entry
+			SET deleted_at = now(), version = version + 1, updated_at = now()
+			WHERE category_id IN (SELECT id FROM trashed_categories) AND deleted_at IS NULL
+		)
+		SELECT count(*) FROM trashed
+	`
+	var n int64
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, id, versionArg(ctx, id)).Scan(&n); err != nil {
 		return MapError(err)
 	}
 	if n == 0 {
-		return repository.ErrNotFound
+		return missingOrStale(ctx, r.db, "project", id)
 	}
 	return nil
 }
+
+func (r *projectRepository) ListDeleted(ctx context.Context) ([]domain.Project, error) {
+	const query = `
+		SELECT ` + projectColumns + `
+		FROM project
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, MapError(err)
+	}
+	defer rows.Close()
+
+	var projects []domain.Project
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			return nil, MapError(err)
+		}
+		projects = append(projects, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, MapError(err)
+	}
+	return projects, nil
+}
+
+func (r *projectRepository) Restore(ctx context.Context, id uuid.UUID) (domain.Project, error) {
+	const query = `
+		WITH target AS (
+			SELECT id, deleted_at
+			FROM project
+			WHERE id = $1 AND deleted_at IS NOT NULL
+		), restored_categories AS (
+			UPDATE category c
+			SET deleted_at = NULL, version = c.version + 1, updated_at = now()
+			FROM target t
+			WHERE c.project_id = t.id AND c.deleted_at = t.deleted_at
+			RETURNING c.id
+		), restored_entries AS (
+			UPDATE time_entry e
+			SET deleted_at = NULL, version = e.version + 1, updated_at = now()
+			FROM target t
+			WHERE e.category_id IN (SELECT id FROM restored_categories) AND e.deleted_at = t.deleted_at
+		)
+		UPDATE project
+		SET deleted_at = NULL, version = version + 1, updated_at = now()
+		WHERE id IN (SELECT id FROM target) AND deleted_at IS NOT NULL
+		RETURNING ` + projectColumns
+	out, err := scanProject(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Project{}, repository.ErrNotFound
+		}
+		return domain.Project{}, MapError(err)
+	}
+	return out, nil
+}
+
+func (r *projectRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return purgeDeleted(ctx, r.db, "project", before)
+}
+
+// rateFromColumns builds a Rate from its nullable columns; either being NULL means no rate.
+func rateFromColumns(cents *int64, currency *string) *domain.Rate {
+	if cents == nil || currency == nil {
+		return nil
+	}
+	return &domain.Rate{HourlyRateCents: *cents, Currency: *currency}
+}
+
+// rateColumns splits a Rate into its nullable column values.
+func rateColumns(rate *domain.Rate) (*int64, *string) {
+	if rate == nil {
+		return nil, nil
+	}
+	return &rate.HourlyRateCents, &rate.Currency
+}
+
+// roundingColumns fills in the column defaults for an unset rounding mode or scope.
+func roundingColumns(rounding domain.Rounding) domain.Rounding {
+	if rounding.Mode == "" {
+		rounding.Mode = domain.RoundNearest
+	}
+	if rounding.Scope == "" {
+		rounding.Scope = domain.RoundPerEntry
+	}
+	return rounding
+}
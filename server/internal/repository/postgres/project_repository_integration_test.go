@@ -7,6 +7,11 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
 )
 
 func TestProjectRepositoryCreateAndGetByIDIntegration(t *testing.T) {
@@ -54,7 +59,7 @@ func TestProjectRepositoryListIntegration(t *testing.T) {
 		t.Fatalf("create p2: %v", err)
 	}
 
-	list, err := r.List(ctx)
+	list, err := r.List(ctx, false, repository.PageRequest{})
 	if err != nil {
 		t.Fatalf("List failed: %v", err)
 	}
@@ -72,6 +77,16 @@ func TestProjectRepositoryListIntegration(t *testing.T) {
 			t.Fatalf("expected project id %s in list", id)
 		}
 	}
+
+	// Pages follow creation order and resume after the cursor
+	first, err := r.List(ctx, false, repository.PageRequest{Limit: 1})
+	if err != nil || len(first) != 1 || first[0].ID != list[0].ID {
+		t.Fatalf("unexpected first page: %+v %v", first, err)
+	}
+	second, err := r.List(ctx, false, repository.PageRequest{After: &repository.Cursor{At: first[0].CreatedAt, ID: first[0].ID}, Limit: 1})
+	if err != nil || len(second) != 1 || second[0].ID != list[1].ID {
+		t.Fatalf("unexpected second page: %+v %v", second, err)
+	}
 }
 
 func TestProjectRepositoryUpdateIntegration(t *testing.T) {
@@ -107,6 +122,107 @@ func TestProjectRepositoryUpdateIntegration(t *testing.T) {
 	}
 }
 
+func TestProjectRepositoryRoundingSettingsIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	r := NewProjectRepository(db)
+	ctx := context.Background()
+
+	created, err := r.Create(ctx, NewProject("proj-rounding", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	if created.Settings.Rounding != (domain.Rounding{Mode: domain.RoundNearest, Scope: domain.RoundPerEntry}) {
+		t.Fatalf("expected rounding disabled by default, got %+v", created.Settings.Rounding)
+	}
+
+	rounding := domain.Rounding{IncrementSeconds: 360, Mode: domain.RoundUp, Scope: domain.RoundPerDay}
+	if _, err := r.UpdateSettings(ctx, created.ID, domain.ProjectSettings{ConcurrentTimers: true, Rounding: rounding}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	fetched, err := r.GetByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if !fetched.Settings.ConcurrentTimers || fetched.Settings.Rounding != rounding {
+		t.Fatalf("expected stored settings, got %+v", fetched.Settings)
+	}
+
+	// The table rejects rounding the service would not produce
+	bad := domain.ProjectSettings{Rounding: domain.Rounding{IncrementSeconds: 360, Mode: "sideways"}}
+	if _, err := r.UpdateSettings(ctx, created.ID, bad); err == nil {
+		t.Fatalf("expected check constraint violation")
+	}
+}
+
+func TestProjectRepositoryUpdateSettingsReslotsRunningEntriesIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+
+	toggled, err := pr.Create(ctx, NewProject("reslot-toggled", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	other, err := pr.Create(ctx, NewProject("reslot-other", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	a, _ := cr.Create(ctx, NewCategory(toggled.ID, "reslot-a", nil, nil))
+	b, _ := cr.Create(ctx, NewCategory(toggled.ID, "reslot-b", nil, nil))
+	o, _ := cr.Create(ctx, NewCategory(other.ID, "reslot-o", nil, nil))
+
+	base := time.Now().UTC().Add(-time.Hour)
+	running, err := tr.Create(ctx, NewTimeEntry(a.ID, base))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "running", err)
+	}
+
+	// Turning concurrent timers on moves the running entry out of the shared slot
+	if _, err := pr.UpdateSettings(ctx, toggled.ID, domain.ProjectSettings{ConcurrentTimers: true}); err != nil {
+		t.Fatalf("UpdateSettings on: %v", err)
+	}
+	shared, err := tr.Create(ctx, NewTimeEntry(o.ID, base.Add(time.Minute)))
+	if err != nil {
+		t.Fatalf("expected the shared slot to be free, got %v", err)
+	}
+	parallel, err := tr.Create(ctx, NewTimeEntry(b.ID, base.Add(time.Minute)))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "parallel", err)
+	}
+
+	// Turning them off is rejected while the running entries would share a slot
+	if _, err := pr.UpdateSettings(ctx, toggled.ID, domain.ProjectSettings{}); err != repository.ErrRunningEntryConflict {
+		t.Fatalf("expected ErrRunningEntryConflict, got %v", err)
+	}
+	if got, _ := pr.GetByID(ctx, toggled.ID); !got.Settings.ConcurrentTimers {
+		t.Fatalf("expected the rejected toggle to leave the settings, got %+v", got.Settings)
+	}
+
+	for _, id := range []uuid.UUID{shared.ID, parallel.ID} {
+		if _, err := tr.Stop(ctx, id, base.Add(2*time.Minute), nil, nil, false); err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+	}
+	if _, err := pr.UpdateSettings(ctx, toggled.ID, domain.ProjectSettings{}); err != nil {
+		t.Fatalf("UpdateSettings off: %v", err)
+	}
+	// The running entry is back in the shared slot
+	if _, err := tr.Create(ctx, NewTimeEntry(o.ID, base.Add(3*time.Minute))); err != repository.ErrRunningEntryConflict {
+		t.Fatalf("expected ErrRunningEntryConflict in the shared slot, got %v", err)
+	}
+	if got, err := tr.GetByID(ctx, running.ID); err != nil || got.StoppedAt != nil {
+		t.Fatalf("expected the entry to keep running, got %+v %v", got, err)
+	}
+}
+
 func TestProjectRepositoryDeleteIntegration(t *testing.T) {
 	db := OpenDBFromEnv(t)
 	t.Cleanup(func() { _ = db.Close() })
@@ -127,3 +243,114 @@ func TestProjectRepositoryDeleteIntegration(t *testing.T) {
 		t.Fatalf("expected ErrNotFound after delete")
 	}
 }
+
+func TestProjectRepositoryDeleteTrashesTreeThenRestoreAndPurgeIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("trash-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	parent, err := cr.Create(ctx, NewCategory(p.ID, "parent", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "parent category", err)
+	}
+	child, err := cr.Create(ctx, NewCategory(p.ID, "child", &parent.ID, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "child category", err)
+	}
+	e, err := tr.Create(ctx, NewStoppedTimeEntry(child.ID, time.Now().UTC().Add(-time.Hour), time.Minute))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "entry", err)
+	}
+
+	if err := pr.Delete(ctx, p.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := tr.GetByID(ctx, e.ID); err != repository.ErrNotFound {
+		t.Fatalf("expected the entry in the trash with its project, got %v", err)
+	}
+	if _, err := cr.Create(ctx, NewCategory(p.ID, "late", nil, nil)); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound creating a category in a deleted project, got %v", err)
+	}
+	if _, err := cr.Restore(ctx, parent.ID); err != repository.ErrParentDeleted {
+		t.Fatalf("expected ErrParentDeleted restoring a category of a deleted project, got %v", err)
+	}
+
+	restored, err := pr.Restore(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if restored.DeletedAt != nil {
+		t.Fatalf("expected a restored project without deletedAt, got %+v", restored)
+	}
+	fetched, err := cr.GetByID(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("get child: %v", err)
+	}
+	if fetched.ParentCategoryID == nil || *fetched.ParentCategoryID != parent.ID {
+		t.Fatalf("expected the child back under its parent, got %+v", fetched)
+	}
+	if _, err := tr.GetByID(ctx, e.ID); err != nil {
+		t.Fatalf("expected the entry restored, got %v", err)
+	}
+
+	// Purging respects the cutoff and runs children first
+	if err := pr.Delete(ctx, p.ID); err != nil {
+		t.Fatalf("second Delete failed: %v", err)
+	}
+	if n, err := tr.PurgeDeleted(ctx, time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Fatalf("expected nothing purged before the cutoff, got %d (%v)", n, err)
+	}
+	cutoff := time.Now().Add(time.Minute)
+	if n, err := tr.PurgeDeleted(ctx, cutoff); err != nil || n != 1 {
+		t.Fatalf("expected 1 entry purged, got %d (%v)", n, err)
+	}
+	if n, err := cr.PurgeDeleted(ctx, cutoff); err != nil || n != 2 {
+		t.Fatalf("expected 2 categories purged, got %d (%v)", n, err)
+	}
+	if n, err := pr.PurgeDeleted(ctx, cutoff); err != nil || n != 1 {
+		t.Fatalf("expected 1 project purged, got %d (%v)", n, err)
+	}
+	if _, err := pr.Restore(ctx, p.ID); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound restoring a purged project, got %v", err)
+	}
+}
+
+func TestProjectRepositorySetArchivedIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	r := NewProjectRepository(db)
+	p, err := r.Create(ctx, NewProject("archive-me", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+
+	archived, err := r.SetArchived(ctx, p.ID, true)
+	if err != nil || archived.ArchivedAt == nil || archived.Version != p.Version+1 {
+		t.Fatalf("unexpected archived project: %+v (%v)", archived, err)
+	}
+	if list, err := r.List(ctx, false, repository.PageRequest{}); err != nil || len(list) != 0 {
+		t.Fatalf("expected archived projects hidden, got %+v (%v)", list, err)
+	}
+	if list, err := r.List(ctx, true, repository.PageRequest{}); err != nil || len(list) != 1 {
+		t.Fatalf("expected archived projects with includeArchived, got %+v (%v)", list, err)
+	}
+	got, err := r.GetByID(ctx, p.ID)
+	if err != nil || got.ArchivedAt == nil {
+		t.Fatalf("expected GetByID to return the archived project, got %+v (%v)", got, err)
+	}
+	if _, err := r.SetArchived(ctx, uuid.New(), true); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+)
+
+type reportRepository struct {
+	db *sql.DB
+}
+
+func NewReportRepository(db *sql.DB) repository.ReportRepository {
+	return &reportRepository{db: db}
+}
+
+// reportWorked selects the running intervals of the entries a summary covers, clipped to [$1, $2).
+// Entries that were paused run in their segments, the others from start to stop; running entries
+// and open segments run until $3.
+const reportWorked = `
+		WITH worked AS (
+			SELECT te.id AS entry_id, c.project_id, te.category_id, te.billable, te.started_at AS entry_started,
+			       GREATEST(COALESCE(s.started_at, te.started_at), $1) AS lo,
+			       LEAST(COALESCE(s.stopped_at, te.stopped_at, $3), $2) AS hi
+			FROM time_entry te
+			JOIN category c ON c.id = te.category_id
+			LEFT JOIN time_entry_segment s ON s.time_entry_id = te.id
+			WHERE te.deleted_at IS NULL
+			  AND te.started_at < $2
+			  AND COALESCE(te.stopped_at, $3) > $1
+			  AND ($4::uuid IS NULL OR c.project_id = $4)
+			  AND ($5::uuid IS NULL OR te.category_id = $5)
+		),`
+
+// reportWholePieces counts each interval as a whole.
+const reportWholePieces = `
+		pieces AS (
+			SELECT entry_id, project_id, category_id, billable, entry_started, NULL::timestamptz AS period,
+			       EXTRACT(EPOCH FROM hi - lo) AS seconds
+			FROM worked
+		)`
+
+// reportPeriodPieces cuts each interval at the period boundaries of time zone $6. Periods are
+// stepped in local time and only then converted back to instants, so a day across a DST
+// transition lasts 23 or 25 hours. The %[1]s verb is the period unit.
+const reportPeriodPieces = `
+		pieces AS (
+			SELECT w.entry_id, w.project_id, w.category_id, w.billable, w.entry_started, b.bucket AT TIME ZONE $6::text AS period,
+			       EXTRACT(EPOCH FROM LEAST(w.hi, (b.bucket + interval '1 %[1]s') AT TIME ZONE $6::text)
+			                        - GREATEST(w.lo, b.bucket AT TIME ZONE $6::text)) AS seconds
+			FROM worked w
+			CROSS JOIN LATERAL generate_series(
+			    date_trunc('%[1]s', w.lo AT TIME ZONE $6::text),
+			    w.hi AT TIME ZONE $6::text,
+			    interval '1 %[1]s'
+			) AS b(bucket)
+			WHERE w.hi > w.lo
+		)`
+
+// reportUnits tags the pieces with the unit their project's rounding applies to, as defined on
+// domain.Rounding: the entry or, for per-day rounding, the UTC day of the entry's start. Units
+// are grouped by category and billable flag as well, which only matters for per-day rounding.
+const reportUnits = `,
+		units AS (
+			SELECT p.*, rp.rounding_increment_seconds AS increment, rp.rounding_mode AS mode,
+			       CASE WHEN rp.rounding_scope = 'day' THEN date_trunc('day', p.entry_started AT TIME ZONE 'UTC') END AS unit_day,
+			       CASE WHEN rp.rounding_scope = 'day' THEN NULL ELSE p.entry_id END AS unit_entry
+			FROM pieces p
+			JOIN project rp ON rp.id = p.project_id
+			WHERE p.seconds > 0
+		)`
+
+// reportRounded rounds the whole seconds of the rounding unit u like domain.Rounding.Round.
+const reportRounded = `CASE
+			WHEN u.increment <= 0 OR FLOOR(u.seconds) <= 0 THEN FLOOR(u.seconds)::bigint
+			WHEN u.mode = 'up' THEN (FLOOR(u.seconds)::bigint + u.increment - 1) / u.increment * u.increment
+			WHEN u.mode = 'down' THEN FLOOR(u.seconds)::bigint / u.increment * u.increment
+			ELSE (FLOOR(u.seconds)::bigint + u.increment / 2) / u.increment * u.increment
+		END`
+
+// reportColumn is a value a group selects under name.
+type reportColumn struct {
+	name string
+	expr string
+}
+
+// reportGroup describes how a summary selects, joins and orders one of its groups. order refers
+// to the names of the selected columns.
+type reportGroup struct {
+	columns []reportColumn
+	join    string
+	order   []string
+}
+
+var reportGroups = map[domain.ReportGroup]reportGroup{
+	domain.ReportByProject: {
+		columns: []reportColumn{{"project_id", "pr.id"}, {"project_name", "pr.name"}},
+		join:    "JOIN project pr ON pr.id = p.project_id",
+		order:   []string{"project_name", "project_id"},
+	},
+	domain.ReportByCategory: {
+		columns: []reportColumn{{"category_id", "c.id"}, {"category_name", "c.name"}},
+		join:    "JOIN category c ON c.id = p.category_id",
+		order:   []string{"category_name", "category_id"},
+	},
+	domain.ReportByTag: {
+		columns: []reportColumn{{"tag_id", "t.id"}, {"tag_name", "COALESCE(t.name, '')"}},
+		join:    "LEFT JOIN time_entry_tag tt ON tt.time_entry_id = p.entry_id\n\t\t\tLEFT JOIN tag t ON t.id = tt.tag_id",
+		order:   []string{"tag_id IS NULL", "tag_name", "tag_id"},
+	},
+	domain.ReportByDay:   {columns: []reportColumn{{"period", "p.period"}}, order: []string{"period"}},
+	domain.ReportByWeek:  {columns: []reportColumn{{"period", "p.period"}}, order: []string{"period"}},
+	domain.ReportByMonth: {columns: []reportColumn{{"period", "p.period"}}, order: []string{"period"}},
+}
+
+// reportDest returns the scan destinations in row of the columns that group g selects.
+func reportDest(row *domain.ReportRow, g domain.ReportGroup) []any {
+	switch g {
+	case domain.ReportByProject:
+		return []any{&row.ProjectID, &row.ProjectName}
+	case domain.ReportByCategory:
+		return []any{&row.CategoryID, &row.CategoryName}
+	case domain.ReportByTag:
+		return []any{&row.TagID, &row.TagName}
+	default:
+		return []any{&row.Period}
+	}
+}
+
+func (r *reportRepository) Summary(ctx context.Context, q repository.SummaryQuery) ([]domain.ReportRow, int64, int64, error) {
+	pieces := reportWholePieces
+	args := []any{q.Start, q.End, q.Now, q.ProjectID, q.CategoryID}
+	var names, exprs, joins, order []string
+	for _, g := range q.GroupBy {
+		rg, ok := reportGroups[g]
+		if !ok {
+			return nil, 0, 0, fmt.Errorf("postgres: unknown report group %q", g)
+		}
+		if g.IsPeriod() {
+			pieces = fmt.Sprintf(reportPeriodPieces, g)
+			args = append(args, q.Timezone)
+		}
+		for _, c := range rg.columns {
+			names = append(names, c.name)
+			exprs = append(exprs, c.expr)
+		}
+		if rg.join != "" {
+			joins = append(joins, rg.join)
+		}
+		order = append(order, rg.order...)
+	}
+
+	// The inner query sums each rounding unit per group, the outer one rounds and adds up the units
+	var b strings.Builder
+	b.WriteString(reportWorked)
+	b.WriteString(pieces)
+	b.WriteString(reportUnits)
+	b.WriteString("\n\t\tSELECT ")
+	for _, n := range names {
+		b.WriteString("u." + n + ", ")
+	}
+	b.WriteString("FLOOR(SUM(u.seconds))::bigint, SUM(" + reportRounded + ")::bigint,")
+	b.WriteString("\n\t\t       (SELECT FLOOR(COALESCE(SUM(seconds), 0))::bigint FROM units),")
+	b.WriteString("\n\t\t       (SELECT COALESCE(SUM(" + reportRounded + "), 0)::bigint FROM (")
+	b.WriteString("\n\t\t\tSELECT increment, mode, SUM(seconds) AS seconds FROM units")
+	b.WriteString("\n\t\t\tGROUP BY category_id, billable, unit_day, unit_entry, increment, mode\n\t\t) u)")
+	b.WriteString("\n\t\tFROM (\n\t\t\tSELECT ")
+	for i, e := range exprs {
+		b.WriteString(e + " AS " + names[i] + ", ")
+	}
+	b.WriteString("p.increment, p.mode, SUM(p.seconds) AS seconds")
+	b.WriteString("\n\t\t\tFROM units p")
+	for _, j := range joins {
+		b.WriteString("\n\t\t\t" + j)
+	}
+	b.WriteString("\n\t\t\tGROUP BY ")
+	for _, e := range exprs {
+		b.WriteString(e + ", ")
+	}
+	b.WriteString("p.category_id, p.billable, p.unit_day, p.unit_entry, p.increment, p.mode\n\t\t) u")
+	if len(names) > 0 {
+		fmt.Fprintf(&b, "\n\t\tGROUP BY %s", strings.Join(names, ", "))
+	}
+	b.WriteString("\n\t\tHAVING SUM(u.seconds) > 0")
+	if len(order) > 0 {
+		fmt.Fprintf(&b, "\n\t\tORDER BY %s", strings.Join(order, ", "))
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, b.String(), args...)
+	if err != nil {
+		return nil, 0, 0, MapError(err)
+	}
+	defer rows.Close()
+
+	var (
+		out          []domain.ReportRow
+		total        int64
+		roundedTotal int64
+	)
+	for rows.Next() {
+		var row domain.ReportRow
+		var dest []any
+		for _, g := range q.GroupBy {
+			dest = append(dest, reportDest(&row, g)...)
+		}
+		dest = append(dest, &row.Seconds, &row.RoundedSeconds, &total, &roundedTotal)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, 0, 0, MapError(err)
+		}
+		out = append(out, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, 0, MapError(err)
+	}
+	return out, total, roundedTotal, nil
+}
@@ -0,0 +1,292 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/Gargair/clockwork/server/internal/service"
+)
+
+func TestReportRepositorySummarySplitsDaysAcrossDSTIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+	rr := NewReportRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("report-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	c, err := cr.Create(ctx, NewCategory(p.ID, "report-cat", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+	// 23:00 on Saturday to 01:30 on Monday in Berlin, across the spring-forward night
+	start := time.Date(2025, 3, 29, 22, 0, 0, 0, time.UTC)
+	if _, err := tr.Create(ctx, NewStoppedTimeEntry(c.ID, start, 25*time.Hour+30*time.Minute)); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "time entry", err)
+	}
+
+	q := repository.SummaryQuery{
+		Start:    time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		End:      time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		Now:      time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC),
+		Timezone: "Europe/Berlin",
+		GroupBy:  []domain.ReportGroup{domain.ReportByDay},
+	}
+	rows, total, rounded, err := rr.Summary(ctx, q)
+	if err != nil {
+		t.Fatalf("Summary: %v", err)
+	}
+	// The Sunday in between lasts 23 hours; without rounding the rounded time is exact
+	want := []struct {
+		period  time.Time
+		seconds int64
+	}{
+		{time.Date(2025, 3, 28, 23, 0, 0, 0, time.UTC), 3600},
+		{time.Date(2025, 3, 29, 23, 0, 0, 0, time.UTC), 23 * 3600},
+		{time.Date(2025, 3, 30, 22, 0, 0, 0, time.UTC), 5400},
+	}
+	if len(rows) != len(want) || total != 91800 || rounded != total {
+		t.Fatalf("expected %d days totalling 91800s, got %+v (%d)", len(want), rows, total)
+	}
+	for i, w := range want {
+		if rows[i].Period == nil || !rows[i].Period.Equal(w.period) || rows[i].Seconds != w.seconds || rows[i].RoundedSeconds != w.seconds {
+			t.Fatalf("day %d: expected %v with %ds, got %+v", i, w.period, w.seconds, rows[i])
+		}
+	}
+
+	// Bounds clip the entry; the week bucket starts on Monday
+	q.Start = time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC)
+	q.GroupBy = []domain.ReportGroup{domain.ReportByWeek}
+	rows, total, _, err = rr.Summary(ctx, q)
+	if err != nil {
+		t.Fatalf("Summary by week: %v", err)
+	}
+	if len(rows) != 2 || total != 84600 || rows[0].Seconds != 79200 || rows[1].Seconds != 5400 ||
+		!rows[1].Period.Equal(time.Date(2025, 3, 30, 22, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected weeks: %+v (%d)", rows, total)
+	}
+}
+
+func TestReportRepositorySummaryGroupsByProjectCategoryAndTagIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+	tg := NewTagRepository(db)
+	rr := NewReportRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("group-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	a, err := cr.Create(ctx, NewCategory(p.ID, "a", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+	b, err := cr.Create(ctx, NewCategory(p.ID, "b", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+	red, err := tg.Create(ctx, domain.Tag{ID: uuid.New(), ProjectID: p.ID, Name: "red"})
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "tag", err)
+	}
+	blue, err := tg.Create(ctx, domain.Tag{ID: uuid.New(), ProjectID: p.ID, Name: "blue"})
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "tag", err)
+	}
+
+	base := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	tagged, err := tr.Create(ctx, NewStoppedTimeEntry(a.ID, base, time.Hour))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "time entry", err)
+	}
+	if err := tr.SetTags(ctx, tagged.ID, []uuid.UUID{red.ID, blue.ID}); err != nil {
+		t.Fatalf("SetTags: %v", err)
+	}
+	// Paused for 30 minutes in the middle: only its segments count
+	paused, err := tr.Create(ctx, NewStoppedTimeEntry(b.ID, base.Add(2*time.Hour), 90*time.Minute))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "time entry", err)
+	}
+	for _, seg := range [][2]time.Time{
+		{base.Add(2 * time.Hour), base.Add(150 * time.Minute)},
+		{base.Add(180 * time.Minute), base.Add(210 * time.Minute)},
+	} {
+		stop := seg[1]
+		if _, err := tr.CreateSegment(ctx, domain.TimeEntrySegment{ID: uuid.New(), TimeEntryID: paused.ID, StartedAt: seg[0], StoppedAt: &stop}); err != nil {
+			t.Fatalf(CreateFailedErrorMessage, "segment", err)
+		}
+	}
+	// Running for 15 minutes at now
+	if _, err := tr.Create(ctx, NewTimeEntry(b.ID, base.Add(5*time.Hour))); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "time entry", err)
+	}
+	deleted, err := tr.Create(ctx, NewStoppedTimeEntry(a.ID, base.Add(-2*time.Hour), time.Hour))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "time entry", err)
+	}
+	if err := tr.Delete(ctx, deleted.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	q := repository.SummaryQuery{
+		Start:    base.Add(-24 * time.Hour),
+		End:      base.Add(24 * time.Hour),
+		Now:      base.Add(5*time.Hour + 15*time.Minute),
+		Timezone: "UTC",
+		GroupBy:  []domain.ReportGroup{domain.ReportByProject, domain.ReportByCategory},
+	}
+	rows, total, _, err := rr.Summary(ctx, q)
+	if err != nil {
+		t.Fatalf("Summary: %v", err)
+	}
+	if total != 3600+3600+900 || len(rows) != 2 ||
+		*rows[0].CategoryID != a.ID || rows[0].Seconds != 3600 || rows[0].ProjectName != "group-proj" ||
+		*rows[1].CategoryID != b.ID || rows[1].Seconds != 4500 {
+		t.Fatalf("unexpected rows by category: %+v (%d)", rows, total)
+	}
+
+	// Rounding up to 1000s per entry: 3600s and 3600s become 4000s each, the running 900s 1000s
+	rounding := domain.Rounding{IncrementSeconds: 1000, Mode: domain.RoundUp, Scope: domain.RoundPerEntry}
+	if _, err := pr.UpdateSettings(ctx, p.ID, domain.ProjectSettings{Rounding: rounding}); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	rows, _, rounded, err := rr.Summary(ctx, q)
+	if err != nil || len(rows) != 2 || rows[0].RoundedSeconds != 4000 || rows[1].RoundedSeconds != 5000 || rounded != 9000 {
+		t.Fatalf("unexpected rounded rows per entry: %+v (%d, %v)", rows, rounded, err)
+	}
+	// Rounding down to 2000s per day rounds each category's day, in the rows and in the total
+	rounding = domain.Rounding{IncrementSeconds: 2000, Mode: domain.RoundDown, Scope: domain.RoundPerDay}
+	if _, err := pr.UpdateSettings(ctx, p.ID, domain.ProjectSettings{Rounding: rounding}); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	rows, _, rounded, err = rr.Summary(ctx, q)
+	if err != nil || len(rows) != 2 || rows[0].RoundedSeconds != 2000 || rows[1].RoundedSeconds != 4000 || rounded != 6000 {
+		t.Fatalf("unexpected rounded rows per day: %+v (%d, %v)", rows, rounded, err)
+	}
+	if _, err := pr.UpdateSettings(ctx, p.ID, domain.ProjectSettings{}); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+
+	q.GroupBy = []domain.ReportGroup{domain.ReportByTag}
+	rows, total, _, err = rr.Summary(ctx, q)
+	if err != nil {
+		t.Fatalf("Summary by tag: %v", err)
+	}
+	// The tagged hour counts toward both tags but once in the total; untagged time comes last
+	if total != 8100 || len(rows) != 3 ||
+		*rows[0].TagID != blue.ID || rows[0].Seconds != 3600 ||
+		*rows[1].TagID != red.ID || rows[1].Seconds != 3600 ||
+		rows[2].TagID != nil || rows[2].TagName != "" || rows[2].Seconds != 4500 {
+		t.Fatalf("unexpected rows by tag: %+v (%d)", rows, total)
+	}
+
+	q.GroupBy = nil
+	q.CategoryID = &a.ID
+	rows, total, _, err = rr.Summary(ctx, q)
+	if err != nil || len(rows) != 1 || rows[0].Seconds != 3600 || total != 3600 {
+		t.Fatalf("unexpected rows for one category: %+v (%d, %v)", rows, total, err)
+	}
+	q.CategoryID = nil
+	q.Start, q.End = base.Add(-48*time.Hour), base.Add(-24*time.Hour)
+	if rows, total, _, err := rr.Summary(ctx, q); err != nil || len(rows) != 0 || total != 0 {
+		t.Fatalf("expected an empty report, got %+v (%d, %v)", rows, total, err)
+	}
+}
+
+func TestReportRepositorySummaryRoundsLikeBillingIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+	rr := NewReportRepository(db)
+	billing := service.NewBillingService(pr, cr, tr, NewAuditRepository(db), NewUnitOfWork(db))
+
+	p, err := pr.Create(ctx, NewProject("rounding-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	rounding := domain.Rounding{IncrementSeconds: 900, Mode: domain.RoundUp, Scope: domain.RoundPerDay}
+	if _, err := pr.UpdateSettings(ctx, p.ID, domain.ProjectSettings{Rounding: rounding}); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	a, err := cr.Create(ctx, NewCategory(p.ID, "a", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+	b, err := cr.Create(ctx, NewCategory(p.ID, "b", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+
+	// Ten minutes each on one day: two entries in a, a billable and a non-billable one in b
+	base := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	billable := NewStoppedTimeEntry(b.ID, base.Add(2*time.Hour), 10*time.Minute)
+	billable.Billable = true
+	for _, e := range []domain.TimeEntry{
+		NewStoppedTimeEntry(a.ID, base, 10*time.Minute),
+		NewStoppedTimeEntry(a.ID, base.Add(time.Hour), 10*time.Minute),
+		billable,
+		NewStoppedTimeEntry(b.ID, base.Add(3*time.Hour), 10*time.Minute),
+	} {
+		if _, err := tr.Create(ctx, e); err != nil {
+			t.Fatalf(CreateFailedErrorMessage, "time entry", err)
+		}
+	}
+
+	start, end := base.Add(-time.Hour), base.Add(12*time.Hour)
+	summary, err := billing.Summary(ctx, p.ID, start, end)
+	if err != nil {
+		t.Fatalf("billing Summary: %v", err)
+	}
+	billed := map[uuid.UUID]int64{}
+	var billedTotal int64
+	for _, line := range summary.Lines {
+		billed[line.CategoryID] = line.RoundedBillableSeconds + line.RoundedNonBillableSeconds
+		billedTotal += billed[line.CategoryID]
+	}
+	q := repository.SummaryQuery{
+		Start:    start,
+		End:      end,
+		Now:      end,
+		Timezone: "UTC",
+		GroupBy:  []domain.ReportGroup{domain.ReportByCategory},
+	}
+	rows, _, rounded, err := rr.Summary(ctx, q)
+	if err != nil {
+		t.Fatalf("Summary: %v", err)
+	}
+	// a rounds its 1200s up to 1800s; b rounds its billable and non-billable 600s up apart
+	if billedTotal != 3600 || rounded != billedTotal || len(rows) != 2 {
+		t.Fatalf("expected the report to round to billing's 3600s, got %+v (%d, billed %d)", rows, rounded, billedTotal)
+	}
+	for _, row := range rows {
+		if row.RoundedSeconds != billed[*row.CategoryID] {
+			t.Fatalf("category %s: report rounds to %ds, billing to %ds", *row.CategoryID, row.RoundedSeconds, billed[*row.CategoryID])
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+type tagRepository struct {
+	db *sql.DB
+}
+
+func NewTagRepository(db *sql.DB) repository.TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) Create(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+	const query = `
+		INSERT INTO tag (id, project_id, name)
+		VALUES ($1, $2, $3)
+		RETURNING id, project_id, name, created_at, updated_at
+	`
+	var out domain.Tag
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, tag.ID, tag.ProjectID, tag.Name).Scan(
+		&out.ID,
+		&out.ProjectID,
+		&out.Name,
+		&out.CreatedAt,
+		&out.UpdatedAt,
+	); err != nil {
+		return domain.Tag{}, MapError(err)
+	}
+	return out, nil
+}
+
+func (r *tagRepository) GetByID(ctx context.Context, id uuid.UUID) (domain.Tag, error) {
+	const query = `
+		SELECT id, project_id, name, created_at, updated_at
+		FROM tag
+		WHERE id = $1
+	`
+	var out domain.Tag
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&out.ID,
+		&out.ProjectID,
+		&out.Name,
+		&out.CreatedAt,
+		&out.UpdatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Tag{}, repository.ErrNotFound
+		}
+		return domain.Tag{}, MapError(err)
+	}
+	return out, nil
+}
+
+func (r *tagRepository) ListByProject(ctx context.Context, projectID uuid.UUID) ([]domain.Tag, error) {
+	const query = `
+		SELECT id, project_id, name, created_at, updated_at
+		FROM tag
+		WHERE project_id = $1
+		ORDER BY name ASC
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, projectID)
+	if err != nil {
+		return nil, MapError(err)
+	}
+	defer rows.Close()
+
+	var tags []domain.Tag
+	for rows.Next() {
+		var t domain.Tag
+		if err := rows.Scan(&t.ID, &t.ProjectID, &t.Name, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, MapError(err)
+		}
+		tags = append(tags, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, MapError(err)
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) Rename(ctx context.Context, id uuid.UUID, name string) (domain.Tag, error) {
+	const query = `
+		UPDATE tag
+		SET name = $2, updated_at = now()
+		WHERE id = $1
+		RETURNING id, project_id, name, created_at, updated_at
+	`
+	var out domain.Tag
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, id, name).Scan(
+		&out.ID,
+		&out.ProjectID,
+		&out.Name,
+		&out.CreatedAt,
+		&out.UpdatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Tag{}, repository.ErrNotFound
+		}
+		return domain.Tag{}, MapError(err)
+	}
+	return out, nil
+}
+
+func (r *tagRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	const query = `
+		DELETE FROM tag
+		WHERE id = $1
+	`
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return MapError(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return MapError(err)
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *tagRepository) TotalsByProject(ctx context.Context, projectID uuid.UUID, start *time.Time, end *time.Time, now time.Time) ([]domain.TagTotal, error) {
+	// Rounding units are those of domain.Rounding: single entries or, for per-day rounding, a
+	// category's billable or non-billable time per UTC day; each unit is rounded on its own and
+	// the rounded units are summed. Running entries count their worked
+	// segments up to now when they were paused, else the time since their start.
+	const query = `
+		WITH worked AS (
+			SELECT t.id AS tag_id, t.name, p.rounding_increment_seconds AS inc, p.rounding_mode AS mode,
+			       CASE WHEN p.rounding_scope = 'day'
+			            THEN concat_ws('/', te.category_id, te.billable, date_trunc('day', te.started_at AT TIME ZONE 'UTC'))
+			            ELSE te.id::text
+			       END AS unit,
+			       COALESCE(
+			           te.duration_seconds,
+			           (SELECT FLOOR(SUM(GREATEST(EXTRACT(EPOCH FROM COALESCE(s.stopped_at, $2::timestamptz) - s.started_at), 0)))::integer
+			            FROM time_entry_segment s
+			            WHERE s.time_entry_id = te.id),
+			           GREATEST(EXTRACT(EPOCH FROM ($2::timestamptz - te.started_at)), 0)::integer
+			       )::bigint AS seconds
+			FROM tag t
+			JOIN project p ON p.id = t.project_id
+			LEFT JOIN time_entry_tag tt ON tt.tag_id = t.id
+			LEFT JOIN time_entry te
+			       ON te.id = tt.time_entry_id
+			      AND te.deleted_at IS NULL
+			      AND ($3::timestamptz IS NULL OR te.started_at >= $3)
+			      AND ($4::timestamptz IS NULL OR te.started_at <= $4)
+			WHERE t.project_id = $1
+		), units AS (
+			SELECT tag_id, name, inc, mode, SUM(seconds) AS seconds
+			FROM worked
+			GROUP BY tag_id, name, inc, mode, unit
+		)
+		SELECT tag_id, name,
+		       COALESCE(SUM(seconds), 0)::bigint AS seconds,
+		       COALESCE(SUM(CASE
+		           WHEN inc = 0 OR seconds <= 0 THEN seconds
+		           WHEN mode = 'up' THEN (seconds + inc - 1) / inc * inc
+		           WHEN mode = 'down' THEN seconds / inc * inc
+		           ELSE (seconds + inc / 2) / inc * inc
+		       END), 0)::bigint AS rounded_seconds
+		FROM units
+		GROUP BY tag_id, name
+		ORDER BY name ASC
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, projectID, now, start, end)
+	if err != nil {
+		return nil, MapError(err)
+	}
+	defer rows.Close()
+
+	var totals []domain.TagTotal
+	for rows.Next() {
+		var t domain.TagTotal
+		if err := rows.Scan(&t.TagID, &t.Name, &t.Seconds, &t.RoundedSeconds); err != nil {
+			return nil, MapError(err)
+		}
+		totals = append(totals, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, MapError(err)
+	}
+	return totals, nil
+}
@@ -0,0 +1,199 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+func TestTagRepositoryCRUDIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	tg := NewTagRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("tag-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+
+	a, err := tg.Create(ctx, domain.Tag{ID: uuid.New(), ProjectID: p.ID, Name: "billing"})
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "tag", err)
+	}
+	if _, err := tg.Create(ctx, domain.Tag{ID: uuid.New(), ProjectID: p.ID, Name: "billing"}); err != repository.ErrDuplicate {
+		t.Fatalf("expected ErrDuplicate, got %v", err)
+	}
+	b, err := tg.Create(ctx, domain.Tag{ID: uuid.New(), ProjectID: p.ID, Name: "admin"})
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "tag", err)
+	}
+
+	renamed, err := tg.Rename(ctx, a.ID, "invoicing")
+	if err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if renamed.Name != "invoicing" {
+		t.Fatalf("expected renamed tag, got %q", renamed.Name)
+	}
+	if _, err := tg.Rename(ctx, b.ID, "invoicing"); err != repository.ErrDuplicate {
+		t.Fatalf("expected ErrDuplicate on rename, got %v", err)
+	}
+
+	list, err := tg.ListByProject(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("ListByProject: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "admin" {
+		t.Fatalf("expected 2 tags ordered by name, got %+v", list)
+	}
+
+	if err := tg.Delete(ctx, b.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := tg.Delete(ctx, b.ID); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestTagRepositoryEntryTagsAndTotalsIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+	tg := NewTagRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("tag-totals", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	c, err := cr.Create(ctx, NewCategory(p.ID, "cat-tags", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+	deep, err := tg.Create(ctx, domain.Tag{ID: uuid.New(), ProjectID: p.ID, Name: "deep"})
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "tag", err)
+	}
+	meet, err := tg.Create(ctx, domain.Tag{ID: uuid.New(), ProjectID: p.ID, Name: "meet"})
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "tag", err)
+	}
+
+	base := time.Now().UTC().Add(-4 * time.Hour).Truncate(time.Second)
+	stopped := NewTimeEntry(c.ID, base)
+	stopAt := base.Add(time.Hour)
+	dur := int32(3600)
+	stopped.StoppedAt = &stopAt
+	stopped.DurationSeconds = &dur
+	if _, err := tr.Create(ctx, stopped); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "stopped entry", err)
+	}
+	if err := tr.SetTags(ctx, stopped.ID, []uuid.UUID{deep.ID, meet.ID}); err != nil {
+		t.Fatalf("SetTags: %v", err)
+	}
+	// Replacing keeps the overlap and drops the rest
+	if err := tr.SetTags(ctx, stopped.ID, []uuid.UUID{deep.ID}); err != nil {
+		t.Fatalf("SetTags replace: %v", err)
+	}
+
+	running := NewTimeEntry(c.ID, base.Add(2*time.Hour))
+	if _, err := tr.Create(ctx, running); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "running entry", err)
+	}
+	if err := tr.SetTags(ctx, running.ID, []uuid.UUID{deep.ID}); err != nil {
+		t.Fatalf("SetTags running: %v", err)
+	}
+
+	tags, err := tr.ListTagIDs(ctx, []uuid.UUID{stopped.ID, running.ID})
+	if err != nil {
+		t.Fatalf("ListTagIDs: %v", err)
+	}
+	if len(tags[stopped.ID]) != 1 || tags[stopped.ID][0] != deep.ID {
+		t.Fatalf("expected only deep on stopped entry, got %v", tags[stopped.ID])
+	}
+
+	filtered, err := tr.ListByCategory(ctx, c.ID, repository.TimeEntryFilter{TagID: &meet.ID})
+	if err != nil {
+		t.Fatalf("ListByCategory: %v", err)
+	}
+	if len(filtered) != 0 {
+		t.Fatalf("expected no entries tagged meet, got %d", len(filtered))
+	}
+
+	now := running.StartedAt.Add(30 * time.Minute)
+	totals, err := tg.TotalsByProject(ctx, p.ID, nil, nil, now)
+	if err != nil {
+		t.Fatalf("TotalsByProject: %v", err)
+	}
+	if len(totals) != 2 || totals[0].TagID != deep.ID || totals[0].Seconds != 3600+1800 || totals[1].Seconds != 0 {
+		t.Fatalf("unexpected totals: %+v", totals)
+	}
+
+	from := base.Add(90 * time.Minute)
+	totals, err = tg.TotalsByProject(ctx, p.ID, &from, nil, now)
+	if err != nil {
+		t.Fatalf("TotalsByProject range: %v", err)
+	}
+	if totals[0].Seconds != 1800 {
+		t.Fatalf("expected only running entry in range, got %+v", totals)
+	}
+
+	// Rounding applies to each entry, the running one included
+	if totals[0].RoundedSeconds != totals[0].Seconds {
+		t.Fatalf("expected unrounded totals by default, got %+v", totals)
+	}
+	rounding := domain.Rounding{IncrementSeconds: 2400, Mode: domain.RoundUp, Scope: domain.RoundPerEntry}
+	if _, err := pr.UpdateSettings(ctx, p.ID, domain.ProjectSettings{Rounding: rounding}); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	totals, err = tg.TotalsByProject(ctx, p.ID, nil, nil, now)
+	if err != nil {
+		t.Fatalf("TotalsByProject rounded: %v", err)
+	}
+	if totals[0].Seconds != 3600+1800 || totals[0].RoundedSeconds != 4800+2400 || totals[1].RoundedSeconds != 0 {
+		t.Fatalf("unexpected rounded totals: %+v", totals)
+	}
+
+	// A paused running entry counts its worked segments only: 20 minutes, a pause, then 5 minutes
+	pauseAt := running.StartedAt.Add(20 * time.Minute)
+	if _, err := tr.CreateSegment(ctx, domain.TimeEntrySegment{ID: uuid.New(), TimeEntryID: running.ID, StartedAt: running.StartedAt, StoppedAt: &pauseAt}); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "segment", err)
+	}
+	if _, err := tr.CreateSegment(ctx, domain.TimeEntrySegment{ID: uuid.New(), TimeEntryID: running.ID, StartedAt: now.Add(-5 * time.Minute)}); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "segment", err)
+	}
+	totals, err = tg.TotalsByProject(ctx, p.ID, &from, nil, now)
+	if err != nil {
+		t.Fatalf("TotalsByProject paused: %v", err)
+	}
+	if totals[0].Seconds != 1500 || totals[0].RoundedSeconds != 2400 {
+		t.Fatalf("expected 1500s worked rounded up to 2400s, got %+v", totals)
+	}
+
+	// Deleting a tag detaches it from entries
+	if err := tg.Delete(ctx, deep.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	tags, err = tr.ListTagIDs(ctx, []uuid.UUID{stopped.ID})
+	if err != nil {
+		t.Fatalf("ListTagIDs: %v", err)
+	}
+	if len(tags[stopped.ID]) != 0 {
+		t.Fatalf("expected no tags after delete, got %v", tags[stopped.ID])
+	}
+}
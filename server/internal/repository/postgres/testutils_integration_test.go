@@ -41,6 +41,18 @@ func TruncateAll(t *testing.T, conn *sql.DB) {
 	defer cancel()
 
 	// Delete in order to satisfy FK constraints
+	if _, err := conn.ExecContext(ctx, "DELETE FROM audit_event"); err != nil {
+		t.Fatalf("failed to delete from audit_event: %v", err)
+	}
+	if _, err := conn.ExecContext(ctx, "DELETE FROM time_entry_segment"); err != nil {
+		t.Fatalf("failed to delete from time_entry_segment: %v", err)
+	}
+	if _, err := conn.ExecContext(ctx, "DELETE FROM time_entry_tag"); err != nil {
+		t.Fatalf("failed to delete from time_entry_tag: %v", err)
+	}
+	if _, err := conn.ExecContext(ctx, "DELETE FROM tag"); err != nil {
+		t.Fatalf("failed to delete from tag: %v", err)
+	}
 	if _, err := conn.ExecContext(ctx, "DELETE FROM time_entry"); err != nil {
 		t.Fatalf("failed to delete from time_entry: %v", err)
 	}
@@ -89,3 +101,14 @@ func NewTimeEntry(categoryID uuid.UUID, startedAt time.Time) domain.TimeEntry {
 		StartedAt:  startedAt,
 	}
 }
+
+// NewStoppedTimeEntry creates a domain.TimeEntry stopped after d, ready for insertion.
+// Only one entry per timer slot may be running, so fixtures with several entries use stopped ones.
+func NewStoppedTimeEntry(categoryID uuid.UUID, startedAt time.Time, d time.Duration) domain.TimeEntry {
+	e := NewTimeEntry(categoryID, startedAt)
+	stoppedAt := e.StartedAt.Add(d)
+	durationSeconds := int32(d.Seconds())
+	e.StoppedAt = &stoppedAt
+	e.DurationSeconds = &durationSeconds
+	return e
+}
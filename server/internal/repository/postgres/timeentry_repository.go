@@ -3,6 +3,9 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Gargair/clockwork/server/internal/domain"
@@ -10,6 +13,21 @@ import (
 	"github.com/google/uuid"
 )
 
+// timeEntryColumns is the column list shared by every query returning time entries.
+// It must stay in sync with scanTimeEntry.
+const timeEntryColumns = `id, category_id, started_at, stopped_at, duration_seconds, note, billable, auto_stopped,
+	target_seconds, is_break, version, created_at, updated_at, deleted_at`
+
+// timerSlotExpr computes the timer slot of the category bound to $2: the category itself
+// when its project allows concurrent timers, else the nil UUID of the shared slot.
+// The partial unique index on (timer_slot) of running entries outside the trash keeps one running entry per slot.
+const timerSlotExpr = `(
+			SELECT CASE WHEN p.concurrent_timers THEN c.id ELSE '00000000-0000-0000-0000-000000000000'::uuid END
+			FROM category c
+			JOIN project p ON p.id = c.project_id
+			WHERE c.id = $2
+		)`
+
 type timeEntryRepository struct {
 	db *sql.DB
 }
@@ -18,14 +36,166 @@ func NewTimeEntryRepository(db *sql.DB) repository.TimeEntryRepository {
 	return &timeEntryRepository{db: db}
 }
 
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTimeEntry scans timeEntryColumns followed by any extra columns into extra.
+func scanTimeEntry(row rowScanner, extra ...any) (domain.TimeEntry, error) {
+	var e domain.TimeEntry
+	dest := []any{
+		&e.ID,
+		&e.CategoryID,
+		&e.StartedAt,
+		&e.StoppedAt,
+		&e.DurationSeconds,
+		&e.Note,
+		&e.Billable,
+		&e.AutoStopped,
+		&e.TargetSeconds,
+		&e.Break,
+		&e.Version,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+		&e.DeletedAt,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	return e, err
+}
+
+func collectTimeEntries(rows *sql.Rows) ([]domain.TimeEntry, error) {
+	defer rows.Close()
+
+	var entries []domain.TimeEntry
+	for rows.Next() {
+		e, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, MapError(err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, MapError(err)
+	}
+	return entries, nil
+}
+
+// collectTimeEntriesWithCategory scans rows of timeEntryColumns followed by the category name
+// and the category path as a JSON array.
+func collectTimeEntriesWithCategory(rows *sql.Rows) ([]domain.TimeEntry, error) {
+	defer rows.Close()
+
+	var entries []domain.TimeEntry
+	for rows.Next() {
+		var name string
+		var path []byte
+		e, err := scanTimeEntry(rows, &name, &path)
+		if err != nil {
+			return nil, MapError(err)
+		}
+		e.CategoryName = name
+		if err := json.Unmarshal(path, &e.CategoryPath); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, MapError(err)
+	}
+	return entries, nil
+}
+
+// uuidStrings converts ids into a non-nil string slice suitable for a text[] parameter.
+func uuidStrings(ids []uuid.UUID) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, id.String())
+	}
+	return out
+}
+
+// likeContains builds an ILIKE pattern matching s anywhere, escaping LIKE wildcards.
+// An empty s yields an empty pattern, which queries treat as "no filter".
+func likeContains(s string) string {
+	if s == "" {
+		return ""
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+	return "%" + escaped + "%"
+}
+
+// Range conditions on the bounds $2 and $3 of a time entry listing; NULL bounds are open.
+const (
+	startedInRange = `
+		  AND ($2::timestamptz IS NULL OR time_entry.started_at >= $2)
+		  AND ($3::timestamptz IS NULL OR time_entry.started_at <= $3)`
+	overlapsRange = `
+		  AND ($3::timestamptz IS NULL OR time_entry.started_at < $3)
+		  AND ($2::timestamptz IS NULL OR time_entry.stopped_at IS NULL OR time_entry.stopped_at > $2)`
+)
+
+// timeEntryRangeClause returns the range condition of mode for a listing whose WHERE clause is still open.
+func timeEntryRangeClause(mode repository.RangeMode) string {
+	if mode == repository.RangeOverlap {
+		return overlapsRange
+	}
+	return startedInRange
+}
+
+// timeEntryOrder describes how a TimeEntrySort orders time_entry rows.
+type timeEntryOrder struct {
+	key  string // sort key expression
+	cast string // SQL type of the cursor's sort key
+	desc bool
+}
+
+var timeEntryOrders = map[repository.TimeEntrySort]timeEntryOrder{
+	repository.SortStartedAtDesc: {key: "time_entry.started_at", cast: "timestamptz", desc: true},
+	repository.SortStartedAtAsc:  {key: "time_entry.started_at", cast: "timestamptz"},
+	repository.SortDuration:      {key: "COALESCE(time_entry.duration_seconds, 2147483647)", cast: "bigint"},
+}
+
+// timeEntryPageClause completes a time entry listing whose WHERE clause is still open with the
+// keyset condition, ORDER BY and LIMIT selected by filter. Its placeholders are numbered from
+// next; the matching arguments are returned in order.
+func timeEntryPageClause(filter repository.TimeEntryFilter, next int) (string, []any) {
+	o, ok := timeEntryOrders[filter.Sort]
+	if !ok {
+		o = timeEntryOrders[repository.SortStartedAtDesc]
+	}
+	cmp, dir := ">", "ASC"
+	if o.desc {
+		cmp, dir = "<", "DESC"
+	}
+	var (
+		b    strings.Builder
+		args []any
+	)
+	if c := filter.Page.After; c != nil {
+		var key any = c.At
+		if o.cast == "bigint" {
+			key = c.Seconds
+		}
+		fmt.Fprintf(&b, "\n\t\t  AND (%s, time_entry.id) %s ($%d::%s, $%d::uuid)", o.key, cmp, next, o.cast, next+1)
+		args = append(args, key, c.ID)
+		next += 2
+	}
+	fmt.Fprintf(&b, "\n\t\tORDER BY %s %s, time_entry.id %s", o.key, dir, dir)
+	if filter.Page.Limit > 0 {
+		fmt.Fprintf(&b, "\n\t\tLIMIT $%d", next)
+		args = append(args, filter.Page.Limit)
+	}
+	return b.String(), args
+}
+
 func (r *timeEntryRepository) Create(ctx context.Context, entry domain.TimeEntry) (domain.TimeEntry, error) {
 	const query = `
-		INSERT INTO time_entry (id, category_id, started_at, stopped_at, duration_seconds)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, category_id, started_at, stopped_at, duration_seconds, created_at, updated_at
-	`
-	var out domain.TimeEntry
-	if err := r.db.QueryRowContext(
+		INSERT INTO time_entry (id, category_id, started_at, stopped_at, duration_seconds, note, billable,
+		                        target_seconds, is_break, timer_slot)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, ` + timerSlotExpr + `)
+		RETURNING ` + timeEntryColumns
+	out, err := scanTimeEntry(conn(ctx, r.db).QueryRowContext(
 		ctx,
 		query,
 		entry.ID,
@@ -33,15 +203,12 @@ func (r *timeEntryRepository) Create(ctx context.Context, entry domain.TimeEntry
 		entry.StartedAt,
 		entry.StoppedAt,
 		entry.DurationSeconds,
-	).Scan(
-		&out.ID,
-		&out.CategoryID,
-		&out.StartedAt,
-		&out.StoppedAt,
-		&out.DurationSeconds,
-		&out.CreatedAt,
-		&out.UpdatedAt,
-	); err != nil {
+		entry.Note,
+		entry.Billable,
+		entry.TargetSeconds,
+		entry.Break,
+	))
+	if err != nil {
 		return domain.TimeEntry{}, MapError(err)
 	}
 	return out, nil
@@ -49,20 +216,12 @@ func (r *timeEntryRepository) Create(ctx context.Context, entry domain.TimeEntry
 
 func (r *timeEntryRepository) GetByID(ctx context.Context, id uuid.UUID) (domain.TimeEntry, error) {
 	const query = `
-		SELECT id, category_id, started_at, stopped_at, duration_seconds, created_at, updated_at
+		SELECT ` + timeEntryColumns + `
 		FROM time_entry
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
-	var out domain.TimeEntry
-	if err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&out.ID,
-		&out.CategoryID,
-		&out.StartedAt,
-		&out.StoppedAt,
-		&out.DurationSeconds,
-		&out.CreatedAt,
-		&out.UpdatedAt,
-	); err != nil {
+	out, err := scanTimeEntry(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return domain.TimeEntry{}, repository.ErrNotFound
 		}
@@ -71,78 +230,186 @@ func (r *timeEntryRepository) GetByID(ctx context.Context, id uuid.UUID) (domain
 	return out, nil
 }
 
-func (r *timeEntryRepository) ListByCategory(ctx context.Context, categoryID uuid.UUID) ([]domain.TimeEntry, error) {
+func (r *timeEntryRepository) GetForUpdate(ctx context.Context, id uuid.UUID) (domain.TimeEntry, error) {
 	const query = `
-		SELECT id, category_id, started_at, stopped_at, duration_seconds, created_at, updated_at
+		SELECT ` + timeEntryColumns + `
 		FROM time_entry
-		WHERE category_id = $1
-		ORDER BY started_at DESC
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
 	`
-	rows, err := r.db.QueryContext(ctx, query, categoryID)
+	out, err := scanTimeEntry(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.TimeEntry{}, repository.ErrNotFound
+		}
+		return domain.TimeEntry{}, MapError(err)
+	}
+	return out, nil
+}
+
+func (r *timeEntryRepository) ListByCategory(ctx context.Context, categoryID uuid.UUID, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+	const query = `
+		SELECT ` + timeEntryColumns + `
+		FROM time_entry
+		WHERE category_id = $1 AND deleted_at IS NULL
+		  AND ($2 = '' OR note ILIKE $2)
+		  AND ($3::uuid IS NULL OR EXISTS (
+		      SELECT 1 FROM time_entry_tag tt WHERE tt.time_entry_id = time_entry.id AND tt.tag_id = $3
+		  ))`
+	page, pageArgs := timeEntryPageClause(filter, 4)
+	args := append([]any{categoryID, likeContains(filter.NoteContains), filter.TagID}, pageArgs...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query+page, args...)
 	if err != nil {
 		return nil, MapError(err)
 	}
-	defer rows.Close()
+	return collectTimeEntries(rows)
+}
 
-	var entries []domain.TimeEntry
-	for rows.Next() {
-		var e domain.TimeEntry
-		if err := rows.Scan(&e.ID, &e.CategoryID, &e.StartedAt, &e.StoppedAt, &e.DurationSeconds, &e.CreatedAt, &e.UpdatedAt); err != nil {
-			return nil, MapError(err)
-		}
-		entries = append(entries, e)
+func (r *timeEntryRepository) ListByCategoryAndRange(ctx context.Context, categoryID uuid.UUID, start time.Time, end time.Time, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+	const query = `
+		SELECT ` + timeEntryColumns + `
+		FROM time_entry
+		WHERE category_id = $1 AND deleted_at IS NULL
+		  AND ($4 = '' OR note ILIKE $4)
+		  AND ($5::uuid IS NULL OR EXISTS (
+		      SELECT 1 FROM time_entry_tag tt WHERE tt.time_entry_id = time_entry.id AND tt.tag_id = $5
+		  ))`
+	page, pageArgs := timeEntryPageClause(filter, 6)
+	args := append([]any{categoryID, start, end, likeContains(filter.NoteContains), filter.TagID}, pageArgs...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query+timeEntryRangeClause(filter.Range)+page, args...)
+	if err != nil {
+		return nil, MapError(err)
 	}
-	if err := rows.Err(); err != nil {
+	return collectTimeEntries(rows)
+}
+
+// categoryTreeEntriesQuery selects the entries of the categories in the CTE category_tree
+// (cat_id, cat_name, cat_path) with the filters $4, $5. Its WHERE clause is left open for
+// timeEntryRangeClause on the optional bounds $2, $3 and for timeEntryPageClause.
+const categoryTreeEntriesQuery = `
+		SELECT ` + timeEntryColumns + `, cat_name, to_json(cat_path)
+		FROM time_entry
+		JOIN category_tree ON cat_id = time_entry.category_id
+		WHERE time_entry.deleted_at IS NULL
+		  AND ($4 = '' OR note ILIKE $4)
+		  AND ($5::uuid IS NULL OR EXISTS (
+		      SELECT 1 FROM time_entry_tag tt WHERE tt.time_entry_id = time_entry.id AND tt.tag_id = $5
+		  ))`
+
+func (r *timeEntryRepository) ListByProject(ctx context.Context, projectID uuid.UUID, start *time.Time, end *time.Time, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+	const query = `
+		WITH RECURSIVE category_tree (cat_id, cat_name, cat_path) AS (
+		    SELECT id, name, ARRAY[name]
+		    FROM category
+		    WHERE project_id = $1 AND parent_category_id IS NULL AND deleted_at IS NULL
+		    UNION ALL
+		    SELECT c.id, c.name, t.cat_path || c.name
+		    FROM category c
+		    JOIN category_tree t ON c.parent_category_id = t.cat_id
+		    WHERE c.deleted_at IS NULL
+		)` + categoryTreeEntriesQuery
+	page, pageArgs := timeEntryPageClause(filter, 6)
+	args := append([]any{projectID, start, end, likeContains(filter.NoteContains), filter.TagID}, pageArgs...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query+timeEntryRangeClause(filter.Range)+page, args...)
+	if err != nil {
 		return nil, MapError(err)
 	}
-	return entries, nil
+	return collectTimeEntriesWithCategory(rows)
 }
 
-func (r *timeEntryRepository) ListByCategoryAndRange(ctx context.Context, categoryID uuid.UUID, start time.Time, end time.Time) ([]domain.TimeEntry, error) {
+func (r *timeEntryRepository) ListByCategoryTree(ctx context.Context, categoryID uuid.UUID, start *time.Time, end *time.Time, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
 	const query = `
-		SELECT id, category_id, started_at, stopped_at, duration_seconds, created_at, updated_at
+		WITH RECURSIVE ancestors AS (
+		    SELECT id, parent_category_id, name, 0 AS depth
+		    FROM category
+		    WHERE id = $1
+		    UNION ALL
+		    SELECT c.id, c.parent_category_id, c.name, a.depth + 1
+		    FROM category c
+		    JOIN ancestors a ON c.id = a.parent_category_id
+		),
+		category_tree (cat_id, cat_name, cat_path) AS (
+		    SELECT c.id, c.name, (SELECT array_agg(name ORDER BY depth DESC) FROM ancestors)
+		    FROM category c
+		    WHERE c.id = $1 AND c.deleted_at IS NULL
+		    UNION ALL
+		    SELECT c.id, c.name, t.cat_path || c.name
+		    FROM category c
+		    JOIN category_tree t ON c.parent_category_id = t.cat_id
+		    WHERE c.deleted_at IS NULL
+		)` + categoryTreeEntriesQuery
+	page, pageArgs := timeEntryPageClause(filter, 6)
+	args := append([]any{categoryID, start, end, likeContains(filter.NoteContains), filter.TagID}, pageArgs...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query+timeEntryRangeClause(filter.Range)+page, args...)
+	if err != nil {
+		return nil, MapError(err)
+	}
+	return collectTimeEntriesWithCategory(rows)
+}
+
+func (r *timeEntryRepository) ListOverlapping(ctx context.Context, start time.Time, end time.Time) ([]domain.TimeEntry, error) {
+	const query = `
+		SELECT ` + timeEntryColumns + `
 		FROM time_entry
-		WHERE category_id = $1 AND started_at >= $2 AND started_at <= $3
-		ORDER BY started_at DESC
+		WHERE deleted_at IS NULL AND started_at < $2 AND (stopped_at IS NULL OR stopped_at > $1)
+		ORDER BY started_at ASC
 	`
-	rows, err := r.db.QueryContext(ctx, query, categoryID, start, end)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, start, end)
 	if err != nil {
 		return nil, MapError(err)
 	}
-	defer rows.Close()
+	return collectTimeEntries(rows)
+}
 
-	var entries []domain.TimeEntry
-	for rows.Next() {
-		var e domain.TimeEntry
-		if err := rows.Scan(&e.ID, &e.CategoryID, &e.StartedAt, &e.StoppedAt, &e.DurationSeconds, &e.CreatedAt, &e.UpdatedAt); err != nil {
-			return nil, MapError(err)
-		}
-		entries = append(entries, e)
+func (r *timeEntryRepository) LockTimerSlot(ctx context.Context, categoryIDs ...uuid.UUID) error {
+	// Transaction-level advisory locks on the slots; outside a unit of work they are released at
+	// once. Slots are locked in key order so that callers locking several cannot deadlock.
+	const query = `
+		SELECT pg_advisory_xact_lock(slot.key)
+		FROM (
+			SELECT DISTINCT hashtextextended(
+				'time_entry_slot:' || CASE WHEN p.concurrent_timers THEN c.id ELSE '00000000-0000-0000-0000-000000000000'::uuid END::text, 0) AS key
+			FROM category c
+			JOIN project p ON p.id = c.project_id
+			WHERE c.id = ANY($1::text[]::uuid[])
+			ORDER BY key
+			OFFSET 0
+		) slot
+	`
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, uuidStrings(categoryIDs)); err != nil {
+		return MapError(err)
 	}
-	if err := rows.Err(); err != nil {
+	return nil
+}
+
+func (r *timeEntryRepository) FindActive(ctx context.Context) (*domain.TimeEntry, error) {
+	const query = `
+		SELECT ` + timeEntryColumns + `
+		FROM time_entry
+		WHERE stopped_at IS NULL AND deleted_at IS NULL
+		ORDER BY started_at DESC
+		LIMIT 1
+	`
+	out, err := scanTimeEntry(conn(ctx, r.db).QueryRowContext(ctx, query))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
 		return nil, MapError(err)
 	}
-	return entries, nil
+	return &out, nil
 }
 
-func (r *timeEntryRepository) FindActive(ctx context.Context) (*domain.TimeEntry, error) {
+func (r *timeEntryRepository) FindActiveByCategory(ctx context.Context, categoryID uuid.UUID) (*domain.TimeEntry, error) {
 	const query = `
-		SELECT id, category_id, started_at, stopped_at, duration_seconds, created_at, updated_at
+		SELECT ` + timeEntryColumns + `
 		FROM time_entry
-		WHERE stopped_at IS NULL
+		WHERE category_id = $1 AND stopped_at IS NULL AND deleted_at IS NULL
 		ORDER BY started_at DESC
 		LIMIT 1
 	`
-	var out domain.TimeEntry
-	if err := r.db.QueryRowContext(ctx, query).Scan(
-		&out.ID,
-		&out.CategoryID,
-		&out.StartedAt,
-		&out.StoppedAt,
-		&out.DurationSeconds,
-		&out.CreatedAt,
-		&out.UpdatedAt,
-	); err != nil {
+	out, err := scanTimeEntry(conn(ctx, r.db).QueryRowContext(ctx, query, categoryID))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -151,27 +418,272 @@ func (r *timeEntryRepository) FindActive(ctx context.Context) (*domain.TimeEntry
 	return &out, nil
 }
 
-func (r *timeEntryRepository) Stop(ctx context.Context, id uuid.UUID, stoppedAt time.Time, durationSeconds *int32) (domain.TimeEntry, error) {
+func (r *timeEntryRepository) FindLastStopped(ctx context.Context) (*domain.TimeEntry, error) {
+	const query = `
+		SELECT ` + timeEntryColumns + `
+		FROM time_entry
+		WHERE stopped_at IS NOT NULL AND deleted_at IS NULL AND NOT is_break
+		ORDER BY stopped_at DESC, started_at DESC
+		LIMIT 1
+	`
+	out, err := scanTimeEntry(conn(ctx, r.db).QueryRowContext(ctx, query))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, MapError(err)
+	}
+	return &out, nil
+}
+
+func (r *timeEntryRepository) ListActive(ctx context.Context) ([]domain.TimeEntry, error) {
+	const query = `
+		SELECT ` + timeEntryColumns + `
+		FROM time_entry
+		WHERE stopped_at IS NULL AND deleted_at IS NULL
+		ORDER BY started_at DESC
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, MapError(err)
+	}
+	return collectTimeEntries(rows)
+}
+
+func (r *timeEntryRepository) Stop(ctx context.Context, id uuid.UUID, stoppedAt time.Time, durationSeconds *int32, note *string, autoStopped bool) (domain.TimeEntry, error) {
+	const query = `
+		UPDATE time_entry
+		SET stopped_at = $2, duration_seconds = $3, note = COALESCE($4, note), auto_stopped = $5,
+		    version = version + 1, updated_at = now()
+		WHERE id = $1 AND stopped_at IS NULL AND deleted_at IS NULL
+		RETURNING ` + timeEntryColumns
+	out, err := scanTimeEntry(conn(ctx, r.db).QueryRowContext(ctx, query, id, stoppedAt, durationSeconds, note, autoStopped))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.TimeEntry{}, repository.ErrNotFound
+		}
+		return domain.TimeEntry{}, MapError(err)
+	}
+	return out, nil
+}
+
+func (r *timeEntryRepository) Update(ctx context.Context, entry domain.TimeEntry) (domain.TimeEntry, error) {
+	const query = `
+		UPDATE time_entry
+		SET category_id = $2, started_at = $3, stopped_at = $4, duration_seconds = $5, note = $6, billable = $7, auto_stopped = $8,
+		    version = version + 1, updated_at = now(),
+		    -- A running entry keeps its slot until it moves to another category or is reopened
+		    timer_slot = CASE WHEN category_id = $2 AND stopped_at IS NULL THEN timer_slot ELSE ` + timerSlotExpr + ` END
+		WHERE id = $1 AND deleted_at IS NULL AND ($9::bigint IS NULL OR version = $9)
+		RETURNING ` + timeEntryColumns
+	out, err := scanTimeEntry(conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		entry.ID,
+		entry.CategoryID,
+		entry.StartedAt,
+		entry.StoppedAt,
+		entry.DurationSeconds,
+		entry.Note,
+		entry.Billable,
+		entry.AutoStopped,
+		versionArg(ctx, entry.ID),
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.TimeEntry{}, missingOrStale(ctx, r.db, "time_entry", entry.ID)
+		}
+		return domain.TimeEntry{}, MapError(err)
+	}
+	return out, nil
+}
+
+func (r *timeEntryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	const query = `
+		UPDATE time_entry
+		SET deleted_at = now(), version = version + 1, updated_at = now()
+		WHERE id = $1 AND deleted_at IS NULL AND ($2::bigint IS NULL OR version = $2)
+	`
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, id, versionArg(ctx, id))
+	if err != nil {
+		return MapError(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return MapError(err)
+	}
+	if n == 0 {
+		return missingOrStale(ctx, r.db, "time_entry", id)
+	}
+	return nil
+}
+
+func (r *timeEntryRepository) DeleteByCategoryAndRange(ctx context.Context, categoryID uuid.UUID, start time.Time, end time.Time) ([]domain.TimeEntry, error) {
+	const query = `
+		WITH deleted AS (
+			UPDATE time_entry
+			SET deleted_at = now(), version = version + 1, updated_at = now()
+			WHERE category_id = $1 AND deleted_at IS NULL AND started_at >= $2 AND started_at <= $3
+			RETURNING ` + timeEntryColumns + `
+		)
+		SELECT ` + timeEntryColumns + `
+		FROM deleted
+		ORDER BY started_at DESC
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, categoryID, start, end)
+	if err != nil {
+		return nil, MapError(err)
+	}
+	return collectTimeEntries(rows)
+}
+
+func (r *timeEntryRepository) ListDeleted(ctx context.Context) ([]domain.TimeEntry, error) {
+	const query = `
+		SELECT ` + timeEntryColumns + `
+		FROM time_entry
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, started_at DESC, id DESC
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, MapError(err)
+	}
+	return collectTimeEntries(rows)
+}
+
+func (r *timeEntryRepository) Restore(ctx context.Context, id uuid.UUID) (domain.TimeEntry, error) {
 	const query = `
 		UPDATE time_entry
-		SET stopped_at = $2, duration_seconds = $3, updated_at = now()
-		WHERE id = $1
-		RETURNING id, category_id, started_at, stopped_at, duration_seconds, created_at, updated_at
+		SET deleted_at = NULL, version = version + 1, updated_at = now()
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		  AND EXISTS (SELECT 1 FROM category c WHERE c.id = time_entry.category_id AND c.deleted_at IS NULL)
+		RETURNING ` + timeEntryColumns
+	out, err := scanTimeEntry(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.TimeEntry{}, notRestorable(ctx, r.db, "time_entry", id)
+		}
+		return domain.TimeEntry{}, MapError(err)
+	}
+	return out, nil
+}
+
+func (r *timeEntryRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return purgeDeleted(ctx, r.db, "time_entry", before)
+}
+
+func (r *timeEntryRepository) SetTags(ctx context.Context, entryID uuid.UUID, tagIDs []uuid.UUID) error {
+	// Remove and insert disjoint row sets so both halves can run in one statement.
+	const query = `
+		WITH removed AS (
+			DELETE FROM time_entry_tag
+			WHERE time_entry_id = $1 AND NOT (tag_id = ANY($2::text[]::uuid[]))
+		)
+		INSERT INTO time_entry_tag (time_entry_id, tag_id)
+		SELECT $1, t FROM unnest($2::text[]::uuid[]) AS t
+		ON CONFLICT DO NOTHING
 	`
-	var out domain.TimeEntry
-	if err := r.db.QueryRowContext(ctx, query, id, stoppedAt, durationSeconds).Scan(
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, entryID, uuidStrings(tagIDs)); err != nil {
+		return MapError(err)
+	}
+	return nil
+}
+
+func (r *timeEntryRepository) ListTagIDs(ctx context.Context, entryIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	const query = `
+		SELECT tt.time_entry_id, tt.tag_id
+		FROM time_entry_tag tt
+		JOIN tag t ON t.id = tt.tag_id
+		WHERE tt.time_entry_id = ANY($1::text[]::uuid[])
+		ORDER BY t.name ASC
+	`
+	out := make(map[uuid.UUID][]uuid.UUID)
+	if len(entryIDs) == 0 {
+		return out, nil
+	}
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, uuidStrings(entryIDs))
+	if err != nil {
+		return nil, MapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entryID, tagID uuid.UUID
+		if err := rows.Scan(&entryID, &tagID); err != nil {
+			return nil, MapError(err)
+		}
+		out[entryID] = append(out[entryID], tagID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, MapError(err)
+	}
+	return out, nil
+}
+
+func (r *timeEntryRepository) CreateSegment(ctx context.Context, segment domain.TimeEntrySegment) (domain.TimeEntrySegment, error) {
+	const query = `
+		INSERT INTO time_entry_segment (id, time_entry_id, started_at, stopped_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, time_entry_id, started_at, stopped_at
+	`
+	var out domain.TimeEntrySegment
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, segment.ID, segment.TimeEntryID, segment.StartedAt, segment.StoppedAt).Scan(
 		&out.ID,
-		&out.CategoryID,
+		&out.TimeEntryID,
 		&out.StartedAt,
 		&out.StoppedAt,
-		&out.DurationSeconds,
-		&out.CreatedAt,
-		&out.UpdatedAt,
 	); err != nil {
-		if err == sql.ErrNoRows {
-			return domain.TimeEntry{}, repository.ErrNotFound
-		}
-		return domain.TimeEntry{}, MapError(err)
+		return domain.TimeEntrySegment{}, MapError(err)
 	}
 	return out, nil
 }
+
+func (r *timeEntryRepository) StopOpenSegment(ctx context.Context, entryID uuid.UUID, stoppedAt time.Time) error {
+	const query = `
+		UPDATE time_entry_segment
+		SET stopped_at = $2
+		WHERE time_entry_id = $1 AND stopped_at IS NULL
+	`
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, entryID, stoppedAt); err != nil {
+		return MapError(err)
+	}
+	return nil
+}
+
+func (r *timeEntryRepository) ListSegments(ctx context.Context, entryID uuid.UUID) ([]domain.TimeEntrySegment, error) {
+	const query = `
+		SELECT id, time_entry_id, started_at, stopped_at
+		FROM time_entry_segment
+		WHERE time_entry_id = $1
+		ORDER BY started_at ASC
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, entryID)
+	if err != nil {
+		return nil, MapError(err)
+	}
+	defer rows.Close()
+
+	var segments []domain.TimeEntrySegment
+	for rows.Next() {
+		var seg domain.TimeEntrySegment
+		if err := rows.Scan(&seg.ID, &seg.TimeEntryID, &seg.StartedAt, &seg.StoppedAt); err != nil {
+			return nil, MapError(err)
+		}
+		segments = append(segments, seg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, MapError(err)
+	}
+	return segments, nil
+}
+
+func (r *timeEntryRepository) DeleteSegments(ctx context.Context, entryID uuid.UUID) error {
+	const query = `
+		DELETE FROM time_entry_segment
+		WHERE time_entry_id = $1
+	`
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, entryID); err != nil {
+		return MapError(err)
+	}
+	return nil
+}
@@ -5,8 +5,13 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/google/uuid"
 )
 
 func TestTimeEntryRepositoryCreateAndFindActiveThenStopFlowIntegration(t *testing.T) {
@@ -51,7 +56,7 @@ func TestTimeEntryRepositoryCreateAndFindActiveThenStopFlowIntegration(t *testin
 	// Stop the active entry
 	stoppedAt := time.Now().UTC()
 	dur := int32(stoppedAt.Sub(created.StartedAt).Seconds())
-	stopped, err := tr.Stop(ctx, created.ID, stoppedAt, &dur)
+	stopped, err := tr.Stop(ctx, created.ID, stoppedAt, &dur, nil, false)
 	if err != nil {
 		t.Fatalf("Stop: %v", err)
 	}
@@ -59,6 +64,11 @@ func TestTimeEntryRepositoryCreateAndFindActiveThenStopFlowIntegration(t *testin
 		t.Fatalf("expected stopped fields to be set: stoppedAt=%v duration=%v", stopped.StoppedAt, stopped.DurationSeconds)
 	}
 
+	// A second stop leaves the stopped entry as it is
+	if _, err := tr.Stop(ctx, created.ID, stoppedAt.Add(time.Hour), nil, nil, true); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound stopping a stopped entry, got %v", err)
+	}
+
 	// GetByID reflects changes
 	fetched, err := tr.GetByID(ctx, created.ID)
 	if err != nil {
@@ -76,6 +86,25 @@ func TestTimeEntryRepositoryCreateAndFindActiveThenStopFlowIntegration(t *testin
 	if none != nil {
 		t.Fatalf("expected no active entry, got %+v", none)
 	}
+
+	last, err := tr.FindLastStopped(ctx)
+	if err != nil {
+		t.Fatalf("FindLastStopped: %v", err)
+	}
+	if last == nil || last.ID != created.ID {
+		t.Fatalf("expected the stopped entry as last stopped, got %+v", last)
+	}
+
+	// A later break is not the last stopped entry
+	lunch := NewStoppedTimeEntry(c.ID, stoppedAt.Add(time.Minute), 30*time.Minute)
+	lunch.Break = true
+	if _, err := tr.Create(ctx, lunch); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "break", err)
+	}
+	last, err = tr.FindLastStopped(ctx)
+	if err != nil || last == nil || last.ID != created.ID {
+		t.Fatalf("expected the break to be skipped, got %+v %v", last, err)
+	}
 }
 
 func TestTimeEntryRepositoryListByCategoryOrderDescIntegration(t *testing.T) {
@@ -100,17 +129,17 @@ func TestTimeEntryRepositoryListByCategoryOrderDescIntegration(t *testing.T) {
 	t0 := time.Now().UTC().Add(-3 * time.Hour)
 	t1 := time.Now().UTC().Add(-2 * time.Hour)
 	t2 := time.Now().UTC().Add(-1 * time.Hour)
-	if _, err := tr.Create(ctx, NewTimeEntry(c.ID, t0)); err != nil {
+	if _, err := tr.Create(ctx, NewStoppedTimeEntry(c.ID, t0, time.Minute)); err != nil {
 		t.Fatalf("create t0: %v", err)
 	}
-	if _, err := tr.Create(ctx, NewTimeEntry(c.ID, t1)); err != nil {
+	if _, err := tr.Create(ctx, NewStoppedTimeEntry(c.ID, t1, time.Minute)); err != nil {
 		t.Fatalf("create t1: %v", err)
 	}
-	if _, err := tr.Create(ctx, NewTimeEntry(c.ID, t2)); err != nil {
+	if _, err := tr.Create(ctx, NewStoppedTimeEntry(c.ID, t2, time.Minute)); err != nil {
 		t.Fatalf("create t2: %v", err)
 	}
 
-	list, err := tr.ListByCategory(ctx, c.ID)
+	list, err := tr.ListByCategory(ctx, c.ID, repository.TimeEntryFilter{})
 	if err != nil {
 		t.Fatalf("ListByCategory: %v", err)
 	}
@@ -145,11 +174,11 @@ func TestTimeEntryRepositoryListByCategoryAndRangeInclusiveIntegration(t *testin
 	}
 
 	base := time.Now().UTC().Add(-5 * time.Hour)
-	_, err = tr.Create(ctx, NewTimeEntry(c.ID, base))
+	_, err = tr.Create(ctx, NewStoppedTimeEntry(c.ID, base, time.Minute))
 	if err != nil {
 		t.Fatalf(CreateFailedErrorMessage, "e0", err)
 	}
-	e1, err := tr.Create(ctx, NewTimeEntry(c.ID, base.Add(1*time.Hour)))
+	e1, err := tr.Create(ctx, NewStoppedTimeEntry(c.ID, base.Add(1*time.Hour), time.Minute))
 	if err != nil {
 		t.Fatalf(CreateFailedErrorMessage, "e1", err)
 	}
@@ -161,7 +190,7 @@ func TestTimeEntryRepositoryListByCategoryAndRangeInclusiveIntegration(t *testin
 	// inclusive range [e1.started, e2.started]
 	start := e1.StartedAt
 	end := e2.StartedAt
-	list, err := tr.ListByCategoryAndRange(ctx, c.ID, start, end)
+	list, err := tr.ListByCategoryAndRange(ctx, c.ID, start, end, repository.TimeEntryFilter{})
 	if err != nil {
 		t.Fatalf("ListByCategoryAndRange: %v", err)
 	}
@@ -183,3 +212,719 @@ func TestTimeEntryRepositoryListByCategoryAndRangeInclusiveIntegration(t *testin
 		}
 	}
 }
+
+func TestTimeEntryRepositoryListOverlappingIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("overlap-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	c, err := cr.Create(ctx, NewCategory(p.ID, "cat-overlap", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+
+	base := time.Now().UTC().Add(-10 * time.Hour).Truncate(time.Second)
+	closed := NewTimeEntry(c.ID, base)
+	stop := base.Add(1 * time.Hour)
+	dur := int32(3600)
+	closed.StoppedAt = &stop
+	closed.DurationSeconds = &dur
+	if _, err := tr.Create(ctx, closed); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "closed", err)
+	}
+	running, err := tr.Create(ctx, NewTimeEntry(c.ID, base.Add(5*time.Hour)))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "running", err)
+	}
+
+	// Adjacent to the closed entry and before the running one: no overlap
+	list, err := tr.ListOverlapping(ctx, stop, base.Add(5*time.Hour))
+	if err != nil {
+		t.Fatalf("ListOverlapping: %v", err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("expected no overlaps, got %d", len(list))
+	}
+
+	// Overlaps the tail of the closed entry
+	list, err = tr.ListOverlapping(ctx, base.Add(30*time.Minute), base.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("ListOverlapping: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != closed.ID {
+		t.Fatalf("expected closed entry overlap, got %+v", list)
+	}
+
+	// Running entry is open-ended
+	list, err = tr.ListOverlapping(ctx, base.Add(8*time.Hour), base.Add(9*time.Hour))
+	if err != nil {
+		t.Fatalf("ListOverlapping: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != running.ID {
+		t.Fatalf("expected running entry overlap, got %+v", list)
+	}
+}
+
+func TestTimeEntryRepositoryUpdateIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("update-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	c1, err := cr.Create(ctx, NewCategory(p.ID, "cat-a", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+	c2, err := cr.Create(ctx, NewCategory(p.ID, "cat-b", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+
+	start := time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Second)
+	created, err := tr.Create(ctx, NewTimeEntry(c1.ID, start))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "time entry", err)
+	}
+
+	stop := start.Add(45 * time.Minute)
+	dur := int32(45 * 60)
+	created.CategoryID = c2.ID
+	created.StoppedAt = &stop
+	created.DurationSeconds = &dur
+	updated, err := tr.Update(ctx, created)
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.CategoryID != c2.ID || updated.StoppedAt == nil || !updated.StoppedAt.Equal(stop) || updated.DurationSeconds == nil || *updated.DurationSeconds != dur {
+		t.Fatalf("unexpected updated entry: %+v", updated)
+	}
+
+	missing := NewTimeEntry(c1.ID, start)
+	if _, err := tr.Update(ctx, missing); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestTimeEntryRepositoryDeleteIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("delete-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	c, err := cr.Create(ctx, NewCategory(p.ID, "cat-delete", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+
+	base := time.Now().UTC().Add(-5 * time.Hour)
+	e0, err := tr.Create(ctx, NewStoppedTimeEntry(c.ID, base, time.Minute))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "e0", err)
+	}
+	e1, err := tr.Create(ctx, NewStoppedTimeEntry(c.ID, base.Add(1*time.Hour), time.Minute))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "e1", err)
+	}
+	e2, err := tr.Create(ctx, NewTimeEntry(c.ID, base.Add(2*time.Hour)))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "e2", err)
+	}
+
+	if err := tr.Delete(ctx, e0.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := tr.Delete(ctx, e0.ID); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	deleted, err := tr.DeleteByCategoryAndRange(ctx, c.ID, e1.StartedAt, e2.StartedAt)
+	if err != nil {
+		t.Fatalf("DeleteByCategoryAndRange: %v", err)
+	}
+	if len(deleted) != 2 || deleted[0].ID != e2.ID || deleted[1].ID != e1.ID {
+		t.Fatalf("unexpected deleted rows: %+v", deleted)
+	}
+	left, err := tr.ListByCategory(ctx, c.ID, repository.TimeEntryFilter{})
+	if err != nil {
+		t.Fatalf("ListByCategory: %v", err)
+	}
+	if len(left) != 0 {
+		t.Fatalf("expected no entries left, got %d", len(left))
+	}
+}
+
+func TestTimeEntryRepositoryNoteFilterIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("note-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	c, err := cr.Create(ctx, NewCategory(p.ID, "cat-note", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+
+	base := time.Now().UTC().Add(-3 * time.Hour)
+	withNote := NewStoppedTimeEntry(c.ID, base, time.Minute)
+	note := "Fix 100% of the Login bugs"
+	withNote.Note = &note
+	if _, err := tr.Create(ctx, withNote); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "with note", err)
+	}
+	if _, err := tr.Create(ctx, NewTimeEntry(c.ID, base.Add(time.Hour))); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "without note", err)
+	}
+
+	list, err := tr.ListByCategory(ctx, c.ID, repository.TimeEntryFilter{NoteContains: "login"})
+	if err != nil {
+		t.Fatalf("ListByCategory: %v", err)
+	}
+	if len(list) != 1 || list[0].Note == nil || *list[0].Note != note {
+		t.Fatalf("expected case-insensitive note match, got %+v", list)
+	}
+	// LIKE wildcards are matched literally
+	list, err = tr.ListByCategory(ctx, c.ID, repository.TimeEntryFilter{NoteContains: "0%"})
+	if err != nil {
+		t.Fatalf("ListByCategory: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected literal percent match, got %d", len(list))
+	}
+	list, err = tr.ListByCategory(ctx, c.ID, repository.TimeEntryFilter{NoteContains: "_"})
+	if err != nil {
+		t.Fatalf("ListByCategory: %v", err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("expected no literal underscore match, got %d", len(list))
+	}
+}
+
+func TestTimeEntryRepositorySegmentsIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("segment-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	c, err := cr.Create(ctx, NewCategory(p.ID, "cat-segment", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	e, err := tr.Create(ctx, NewTimeEntry(c.ID, base))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "time entry", err)
+	}
+
+	pausedAt := base.Add(10 * time.Minute)
+	if _, err := tr.CreateSegment(ctx, domain.TimeEntrySegment{ID: uuid.New(), TimeEntryID: e.ID, StartedAt: base, StoppedAt: &pausedAt}); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "segment", err)
+	}
+	if _, err := tr.CreateSegment(ctx, domain.TimeEntrySegment{ID: uuid.New(), TimeEntryID: e.ID, StartedAt: base.Add(20 * time.Minute)}); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "open segment", err)
+	}
+	// Only one open segment per entry is allowed
+	if _, err := tr.CreateSegment(ctx, domain.TimeEntrySegment{ID: uuid.New(), TimeEntryID: e.ID, StartedAt: base.Add(30 * time.Minute)}); err != repository.ErrDuplicate {
+		t.Fatalf("expected ErrDuplicate for second open segment, got %v", err)
+	}
+
+	if err := tr.StopOpenSegment(ctx, e.ID, base.Add(40*time.Minute)); err != nil {
+		t.Fatalf("StopOpenSegment: %v", err)
+	}
+	segs, err := tr.ListSegments(ctx, e.ID)
+	if err != nil {
+		t.Fatalf("ListSegments: %v", err)
+	}
+	if len(segs) != 2 || !segs[0].StartedAt.Equal(base) || segs[1].StoppedAt == nil || !segs[1].StoppedAt.Equal(base.Add(40*time.Minute)) {
+		t.Fatalf("unexpected segments: %+v", segs)
+	}
+
+	if err := tr.DeleteSegments(ctx, e.ID); err != nil {
+		t.Fatalf("DeleteSegments: %v", err)
+	}
+	if segs, _ := tr.ListSegments(ctx, e.ID); len(segs) != 0 {
+		t.Fatalf("expected no segments, got %d", len(segs))
+	}
+}
+
+func TestTimeEntryRepositoryActiveByCategoryIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("parallel-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	p, err = pr.UpdateSettings(ctx, p.ID, domain.ProjectSettings{ConcurrentTimers: true})
+	if err != nil || !p.Settings.ConcurrentTimers {
+		t.Fatalf("UpdateSettings: %+v %v", p.Settings, err)
+	}
+	c1, err := cr.Create(ctx, NewCategory(p.ID, "cat-parallel-1", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+	c2, err := cr.Create(ctx, NewCategory(p.ID, "cat-parallel-2", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+
+	base := time.Now().UTC().Add(-time.Hour)
+	e1, err := tr.Create(ctx, NewTimeEntry(c1.ID, base))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "e1", err)
+	}
+	e2, err := tr.Create(ctx, NewTimeEntry(c2.ID, base.Add(time.Minute)))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "e2", err)
+	}
+
+	active, err := tr.ListActive(ctx)
+	if err != nil {
+		t.Fatalf("ListActive: %v", err)
+	}
+	if len(active) != 2 || active[0].ID != e2.ID || active[1].ID != e1.ID {
+		t.Fatalf("unexpected running entries: %+v", active)
+	}
+	got, err := tr.FindActiveByCategory(ctx, c1.ID)
+	if err != nil || got == nil || got.ID != e1.ID {
+		t.Fatalf("FindActiveByCategory: %+v %v", got, err)
+	}
+	reaped, err := tr.Stop(ctx, e1.ID, base.Add(time.Hour), nil, nil, true)
+	if err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if !reaped.AutoStopped {
+		t.Fatalf("expected auto_stopped to be persisted")
+	}
+	if got, err := tr.FindActiveByCategory(ctx, c1.ID); err != nil || got != nil {
+		t.Fatalf("expected no running entry in c1, got %+v %v", got, err)
+	}
+}
+
+func TestTimeEntryRepositoryRunningSlotUniqueIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+	uow := NewUnitOfWork(db)
+
+	classic, err := pr.Create(ctx, NewProject("slot-classic", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	parallel, err := pr.Create(ctx, NewProject("slot-parallel", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	if _, err := pr.UpdateSettings(ctx, parallel.ID, domain.ProjectSettings{ConcurrentTimers: true}); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	a, _ := cr.Create(ctx, NewCategory(classic.ID, "slot-a", nil, nil))
+	b, _ := cr.Create(ctx, NewCategory(classic.ID, "slot-b", nil, nil))
+	p1, _ := cr.Create(ctx, NewCategory(parallel.ID, "slot-p1", nil, nil))
+	p2, _ := cr.Create(ctx, NewCategory(parallel.ID, "slot-p2", nil, nil))
+
+	base := time.Now().UTC().Add(-time.Hour)
+	running, err := tr.Create(ctx, NewTimeEntry(a.ID, base))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "running", err)
+	}
+	// The shared slot spans categories; parallel categories have their own slots
+	if _, err := tr.Create(ctx, NewTimeEntry(b.ID, base.Add(time.Minute))); err != repository.ErrRunningEntryConflict {
+		t.Fatalf("expected ErrRunningEntryConflict, got %v", err)
+	}
+	if _, err := tr.Create(ctx, NewTimeEntry(p1.ID, base.Add(time.Minute))); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "p1", err)
+	}
+	if _, err := tr.Create(ctx, NewTimeEntry(p2.ID, base.Add(time.Minute))); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "p2", err)
+	}
+	if _, err := tr.Create(ctx, NewTimeEntry(p1.ID, base.Add(2*time.Minute))); err != repository.ErrRunningEntryConflict {
+		t.Fatalf("expected ErrRunningEntryConflict in p1, got %v", err)
+	}
+
+	// A failed unit of work rolls back the stop of the previous entry
+	err = uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := tr.Stop(ctx, running.ID, base.Add(30*time.Minute), nil, nil, false); err != nil {
+			return err
+		}
+		if _, err := tr.Create(ctx, NewTimeEntry(p2.ID, base.Add(30*time.Minute))); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != repository.ErrRunningEntryConflict {
+		t.Fatalf("expected ErrRunningEntryConflict from unit of work, got %v", err)
+	}
+	if got, err := tr.GetByID(ctx, running.ID); err != nil || got.StoppedAt != nil {
+		t.Fatalf("expected stop to be rolled back, got %+v %v", got, err)
+	}
+
+	// A successful unit of work switches the timer atomically
+	var switched domain.TimeEntry
+	err = uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := tr.Stop(ctx, running.ID, base.Add(30*time.Minute), nil, nil, false); err != nil {
+			return err
+		}
+		var err error
+		switched, err = tr.Create(ctx, NewTimeEntry(b.ID, base.Add(30*time.Minute)))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unit of work: %v", err)
+	}
+	active, err := tr.FindActiveByCategory(ctx, b.ID)
+	if err != nil || active == nil || active.ID != switched.ID {
+		t.Fatalf("expected switched entry to be running, got %+v %v", active, err)
+	}
+}
+
+func TestTimeEntryRepositoryListByProjectAndTreeIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("tree-project", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	other, err := pr.Create(ctx, NewProject("tree-other", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	root, _ := cr.Create(ctx, NewCategory(p.ID, "root", nil, nil))
+	child, _ := cr.Create(ctx, NewCategory(p.ID, "child", &root.ID, nil))
+	grandchild, _ := cr.Create(ctx, NewCategory(p.ID, "grandchild", &child.ID, nil))
+	foreign, _ := cr.Create(ctx, NewCategory(other.ID, "foreign", nil, nil))
+
+	base := time.Now().UTC().Add(-24 * time.Hour).Truncate(time.Second)
+	for i, catID := range []uuid.UUID{root.ID, child.ID, grandchild.ID, foreign.ID} {
+		if _, err := tr.Create(ctx, NewStoppedTimeEntry(catID, base.Add(time.Duration(i)*time.Hour), 30*time.Minute)); err != nil {
+			t.Fatalf(CreateFailedErrorMessage, "entry", err)
+		}
+	}
+
+	all, err := tr.ListByProject(ctx, p.ID, nil, nil, repository.TimeEntryFilter{})
+	if err != nil {
+		t.Fatalf("ListByProject: %v", err)
+	}
+	if len(all) != 3 || all[0].CategoryID != grandchild.ID {
+		t.Fatalf("expected 3 project entries newest first, got %+v", all)
+	}
+	if all[0].CategoryName != "grandchild" || len(all[0].CategoryPath) != 3 || all[0].CategoryPath[0] != "root" || all[0].CategoryPath[2] != "grandchild" {
+		t.Fatalf("unexpected category path: %q %v", all[0].CategoryName, all[0].CategoryPath)
+	}
+
+	end := base.Add(90 * time.Minute)
+	bounded, err := tr.ListByProject(ctx, p.ID, nil, &end, repository.TimeEntryFilter{})
+	if err != nil || len(bounded) != 2 {
+		t.Fatalf("expected a lone to bound to keep 2 entries, got %d %v", len(bounded), err)
+	}
+
+	tree, err := tr.ListByCategoryTree(ctx, child.ID, nil, nil, repository.TimeEntryFilter{})
+	if err != nil {
+		t.Fatalf("ListByCategoryTree: %v", err)
+	}
+	if len(tree) != 2 || tree[1].CategoryID != child.ID {
+		t.Fatalf("expected child subtree entries, got %+v", tree)
+	}
+	// Paths stay rooted at the project root even when listing a subtree
+	if len(tree[1].CategoryPath) != 2 || tree[1].CategoryPath[0] != "root" || tree[1].CategoryPath[1] != "child" {
+		t.Fatalf("unexpected subtree path: %v", tree[1].CategoryPath)
+	}
+}
+
+func TestTimeEntryRepositorySortAndPageIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("page-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	c, err := cr.Create(ctx, NewCategory(p.ID, "page-cat", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+
+	base := time.Now().UTC().Add(-10 * time.Hour).Truncate(time.Second)
+	long, _ := tr.Create(ctx, NewStoppedTimeEntry(c.ID, base, 3*time.Hour))
+	short, _ := tr.Create(ctx, NewStoppedTimeEntry(c.ID, base.Add(4*time.Hour), time.Minute))
+	running, err := tr.Create(ctx, NewTimeEntry(c.ID, base.Add(5*time.Hour)))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "running", err)
+	}
+
+	// Walk the duration order one entry per page; running entries come last
+	var got []uuid.UUID
+	filter := repository.TimeEntryFilter{Sort: repository.SortDuration, Page: repository.PageRequest{Limit: 1}}
+	for range 4 {
+		page, err := tr.ListByCategory(ctx, c.ID, filter)
+		if err != nil {
+			t.Fatalf("ListByCategory: %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		got = append(got, page[0].ID)
+		cursor := filter.Sort.CursorOf(page[0])
+		filter.Page.After = &cursor
+	}
+	if len(got) != 3 || got[0] != short.ID || got[1] != long.ID || got[2] != running.ID {
+		t.Fatalf("unexpected duration order: %v", got)
+	}
+
+	asc, err := tr.ListByProject(ctx, p.ID, nil, nil, repository.TimeEntryFilter{Sort: repository.SortStartedAtAsc, Page: repository.PageRequest{Limit: 2}})
+	if err != nil || len(asc) != 2 || asc[0].ID != long.ID || asc[1].ID != short.ID {
+		t.Fatalf("unexpected ascending page: %+v %v", asc, err)
+	}
+	cursor := repository.SortStartedAtDesc.CursorOf(running)
+	rest, err := tr.ListByCategoryAndRange(ctx, c.ID, base, base.Add(6*time.Hour), repository.TimeEntryFilter{Page: repository.PageRequest{After: &cursor}})
+	if err != nil || len(rest) != 2 || rest[0].ID != short.ID {
+		t.Fatalf("expected the entries started before the running one, got %+v %v", rest, err)
+	}
+}
+
+func TestTimeEntryRepositoryOverlapRangeIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+
+	p, err := pr.Create(ctx, NewProject("overlap-proj", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	c, err := cr.Create(ctx, NewCategory(p.ID, "cat-overlap", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+
+	base := time.Now().UTC().Add(-5 * time.Hour).Truncate(time.Second)
+	crossing, err := tr.Create(ctx, NewStoppedTimeEntry(c.ID, base.Add(-30*time.Minute), time.Hour))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "crossing", err)
+	}
+	inside, err := tr.Create(ctx, NewStoppedTimeEntry(c.ID, base.Add(time.Hour), time.Minute))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "inside", err)
+	}
+	// Ends exactly at the start of the range, which is half-open
+	if _, err := tr.Create(ctx, NewStoppedTimeEntry(c.ID, base.Add(-10*time.Minute), 10*time.Minute)); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "touching", err)
+	}
+	if _, err := tr.Create(ctx, NewStoppedTimeEntry(c.ID, base.Add(-2*time.Hour), time.Minute)); err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "before", err)
+	}
+	running, err := tr.Create(ctx, NewTimeEntry(c.ID, base.Add(-time.Hour)))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "running", err)
+	}
+
+	end := base.Add(2 * time.Hour)
+	started, err := tr.ListByCategoryAndRange(ctx, c.ID, base, end, repository.TimeEntryFilter{})
+	if err != nil {
+		t.Fatalf("ListByCategoryAndRange started: %v", err)
+	}
+	if len(started) != 1 || started[0].ID != inside.ID {
+		t.Fatalf("expected only the entry started in range, got %d entries", len(started))
+	}
+
+	overlap, err := tr.ListByCategoryAndRange(ctx, c.ID, base, end, repository.TimeEntryFilter{Range: repository.RangeOverlap})
+	if err != nil {
+		t.Fatalf("ListByCategoryAndRange overlap: %v", err)
+	}
+	want := map[uuid.UUID]bool{crossing.ID: true, inside.ID: true, running.ID: true}
+	if len(overlap) != len(want) {
+		t.Fatalf("expected %d overlapping entries, got %d", len(want), len(overlap))
+	}
+	for _, e := range overlap {
+		if !want[e.ID] {
+			t.Fatalf("unexpected entry in overlap listing: %v-%v", e.StartedAt, e.StoppedAt)
+		}
+	}
+
+	from := base.Add(90 * time.Minute)
+	tree, err := tr.ListByCategoryTree(ctx, c.ID, &from, nil, repository.TimeEntryFilter{Range: repository.RangeOverlap})
+	if err != nil {
+		t.Fatalf("ListByCategoryTree overlap: %v", err)
+	}
+	if len(tree) != 1 || tree[0].ID != running.ID {
+		t.Fatalf("expected only the open-ended running entry, got %d entries", len(tree))
+	}
+}
+
+func TestTimeEntryRepositoryLockTimerSlotIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+	uow := NewUnitOfWork(db)
+
+	classic, err := pr.Create(ctx, NewProject("lock-classic", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	parallel, err := pr.Create(ctx, NewProject("lock-parallel", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	if _, err := pr.UpdateSettings(ctx, parallel.ID, domain.ProjectSettings{ConcurrentTimers: true}); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	a, _ := cr.Create(ctx, NewCategory(classic.ID, "lock-a", nil, nil))
+	b, _ := cr.Create(ctx, NewCategory(classic.ID, "lock-b", nil, nil))
+	p1, _ := cr.Create(ctx, NewCategory(parallel.ID, "lock-p1", nil, nil))
+
+	locked, release, done := make(chan struct{}), make(chan struct{}), make(chan error, 1)
+	go func() {
+		done <- uow.Do(ctx, func(ctx context.Context) error {
+			if err := tr.LockTimerSlot(ctx, a.ID); err != nil {
+				return err
+			}
+			close(locked)
+			<-release
+			return nil
+		})
+	}()
+	<-locked
+
+	// b shares the slot of a and waits; p1 has a slot of its own
+	waiting, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	if err := uow.Do(waiting, func(ctx context.Context) error { return tr.LockTimerSlot(ctx, b.ID) }); err == nil {
+		t.Fatalf("expected the shared slot to stay locked")
+	}
+	if err := uow.Do(ctx, func(ctx context.Context) error { return tr.LockTimerSlot(ctx, p1.ID) }); err != nil {
+		t.Fatalf("LockTimerSlot p1: %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("LockTimerSlot a: %v", err)
+	}
+	if err := uow.Do(ctx, func(ctx context.Context) error { return tr.LockTimerSlot(ctx, b.ID) }); err != nil {
+		t.Fatalf("expected the slot to be free after commit, got %v", err)
+	}
+}
+
+func TestTimeEntryRepositoryGetForUpdateIntegration(t *testing.T) {
+	db := OpenDBFromEnv(t)
+	t.Cleanup(func() { _ = db.Close() })
+	TruncateAll(t, db)
+
+	ctx := context.Background()
+	pr := NewProjectRepository(db)
+	cr := NewCategoryRepository(db)
+	tr := NewTimeEntryRepository(db)
+	uow := NewUnitOfWork(db)
+
+	p, err := pr.Create(ctx, NewProject("for-update", nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "project", err)
+	}
+	c, err := cr.Create(ctx, NewCategory(p.ID, "for-update", nil, nil))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "category", err)
+	}
+	running, err := tr.Create(ctx, NewTimeEntry(c.ID, time.Now().UTC().Add(-time.Hour)))
+	if err != nil {
+		t.Fatalf(CreateFailedErrorMessage, "time entry", err)
+	}
+
+	locked, release, done := make(chan struct{}), make(chan struct{}), make(chan error, 1)
+	go func() {
+		done <- uow.Do(ctx, func(ctx context.Context) error {
+			if _, err := tr.GetForUpdate(ctx, running.ID); err != nil {
+				return err
+			}
+			close(locked)
+			<-release
+			return nil
+		})
+	}()
+	<-locked
+	waiting, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	if err := uow.Do(waiting, func(ctx context.Context) error {
+		_, err := tr.GetForUpdate(ctx, running.ID)
+		return err
+	}); err == nil {
+		t.Fatalf("expected the entry to stay locked")
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("GetForUpdate: %v", err)
+	}
+	if _, err := tr.GetForUpdate(ctx, uuid.New()); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+// notRestorable explains why a restore of id in table matched no row: the row is not in the
+// trash, or the parent it must return to still is.
+func notRestorable(ctx context.Context, db *sql.DB, table string, id uuid.UUID) error {
+	var trashed bool
+	query := `SELECT EXISTS (SELECT 1 FROM ` + table + ` WHERE id = $1 AND deleted_at IS NOT NULL)`
+	if err := conn(ctx, db).QueryRowContext(ctx, query, id).Scan(&trashed); err != nil {
+		return MapError(err)
+	}
+	if trashed {
+		return repository.ErrParentDeleted
+	}
+	return repository.ErrNotFound
+}
+
+// purgeDeleted removes the rows of table deleted before the given time and reports how many.
+func purgeDeleted(ctx context.Context, db *sql.DB, table string, before time.Time) (int64, error) {
+	query := `DELETE FROM ` + table + ` WHERE deleted_at < $1`
+	res, err := conn(ctx, db).ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, MapError(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, MapError(err)
+	}
+	return n, nil
+}
@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+// versionArg returns the version a write of id expects under ctx, NULL for unconditional writes.
+// Queries compare it with `($n::bigint IS NULL OR version = $n)`.
+func versionArg(ctx context.Context, id uuid.UUID) *int64 {
+	if v, ok := repository.ExpectedVersion(ctx, id); ok {
+		return &v
+	}
+	return nil
+}
+
+// missingOrStale explains why a write of id in table matched no row: the row is gone or in the
+// trash, or a conditional write found it at another version.
+func missingOrStale(ctx context.Context, db *sql.DB, table string, id uuid.UUID) error {
+	if versionArg(ctx, id) == nil {
+		return repository.ErrNotFound
+	}
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM ` + table + ` WHERE id = $1 AND deleted_at IS NULL)`
+	if err := conn(ctx, db).QueryRowContext(ctx, query, id).Scan(&exists); err != nil {
+		return MapError(err)
+	}
+	if exists {
+		return repository.ErrVersionMismatch
+	}
+	return repository.ErrNotFound
+}
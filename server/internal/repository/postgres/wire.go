@@ -11,6 +11,10 @@ type Repositories struct {
 	Projects    repository.ProjectRepository
 	Categories  repository.CategoryRepository
 	TimeEntries repository.TimeEntryRepository
+	Tags        repository.TagRepository
+	Audit       repository.AuditRepository
+	Reports     repository.ReportRepository
+	UnitOfWork  repository.UnitOfWork
 }
 
 // NewRepositories constructs all Postgres-backed repositories using the provided *sql.DB.
@@ -19,5 +23,9 @@ func NewRepositories(db *sql.DB) Repositories {
 		Projects:    NewProjectRepository(db),
 		Categories:  NewCategoryRepository(db),
 		TimeEntries: NewTimeEntryRepository(db),
+		Tags:        NewTagRepository(db),
+		Audit:       NewAuditRepository(db),
+		Reports:     NewReportRepository(db),
+		UnitOfWork:  NewUnitOfWork(db),
 	}
 }
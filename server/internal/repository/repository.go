@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"math"
 	"time"
 
 	"github.com/Gargair/clockwork/server/internal/domain"
@@ -14,34 +15,290 @@ var (
 	ErrNotFound            = errors.New("repository: not found")
 	ErrDuplicate           = errors.New("repository: duplicate")
 	ErrForeignKeyViolation = errors.New("repository: foreign key violation")
+	// ErrRunningEntryConflict reports a second running time entry in the same timer slot.
+	ErrRunningEntryConflict = errors.New("repository: another entry is running in the timer slot")
+	// ErrVersionMismatch reports a conditional write whose expected version is no longer current.
+	ErrVersionMismatch = errors.New("repository: version mismatch")
+	// ErrParentDeleted reports a restore of a row whose project or parent category is still in the trash.
+	ErrParentDeleted = errors.New("repository: parent is in the trash")
 )
 
+// expectedVersionKey is the context key under which WithExpectedVersion stores its precondition.
+type expectedVersionKey struct{}
+
+type expectedVersion struct {
+	id      uuid.UUID
+	version int64
+}
+
+// WithExpectedVersion returns a context under which Update and Delete of the project, category or
+// time entry id only apply while its version still equals version. Otherwise they fail with
+// ErrVersionMismatch. Writes of other rows are unaffected.
+func WithExpectedVersion(ctx context.Context, id uuid.UUID, version int64) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, expectedVersion{id: id, version: version})
+}
+
+// ExpectedVersion returns the version that writes of id expect under ctx, if any.
+func ExpectedVersion(ctx context.Context, id uuid.UUID) (int64, bool) {
+	v, ok := ctx.Value(expectedVersionKey{}).(expectedVersion)
+	if !ok || v.id != id {
+		return 0, false
+	}
+	return v.version, true
+}
+
+// UnitOfWork runs a group of repository calls atomically.
+type UnitOfWork interface {
+	// Do runs fn inside a transaction. Repository calls made with the context passed to fn take
+	// part in it. The transaction commits when fn returns nil and rolls back otherwise; nested
+	// calls join the outer transaction.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Cursor marks the last item of a page. A listing resumes after the cursor's sort key, with ID
+// breaking ties between items sharing that key.
+type Cursor struct {
+	// At is the sort key of listings ordered by a timestamp.
+	At time.Time
+	// Seconds is the sort key of listings ordered by duration.
+	Seconds int64
+	ID      uuid.UUID
+}
+
+// PageRequest selects one page of a keyset-paginated listing. The zero value lists everything.
+type PageRequest struct {
+	// After resumes the listing behind the given item; nil starts at the beginning.
+	After *Cursor
+	// Limit caps the number of items returned; zero means no limit.
+	Limit int
+}
+
 // ProjectRepository defines CRUD operations for projects.
+// Every update increments the project's version; Update, UpdateSettings, SetArchived and Delete honor WithExpectedVersion.
+// Reads and updates only see projects outside the trash.
 type ProjectRepository interface {
 	Create(ctx context.Context, project domain.Project) (domain.Project, error)
 	GetByID(ctx context.Context, id uuid.UUID) (domain.Project, error)
-	List(ctx context.Context) ([]domain.Project, error)
+	// List returns projects oldest first, archived ones only with includeArchived.
+	List(ctx context.Context, includeArchived bool, page PageRequest) ([]domain.Project, error)
 	Update(ctx context.Context, id uuid.UUID, name string, description *string) (domain.Project, error)
+	// UpdateRate sets or, with a nil rate, clears the project's hourly rate.
+	UpdateRate(ctx context.Context, id uuid.UUID, rate *domain.Rate) (domain.Project, error)
+	// UpdateSettings overwrites the project's settings.
+	UpdateSettings(ctx context.Context, id uuid.UUID, settings domain.ProjectSettings) (domain.Project, error)
+	// SetArchived archives or unarchives the project. Archiving an archived project keeps its ArchivedAt.
+	SetArchived(ctx context.Context, id uuid.UUID, archived bool) (domain.Project, error)
+	// Delete moves the project to the trash together with its categories and their entries.
 	Delete(ctx context.Context, id uuid.UUID) error
+	// ListDeleted returns the projects in the trash, most recently deleted first.
+	ListDeleted(ctx context.Context) ([]domain.Project, error)
+	// Restore takes a project out of the trash together with the categories and entries deleted
+	// with it. Projects outside the trash are reported as ErrNotFound.
+	Restore(ctx context.Context, id uuid.UUID) (domain.Project, error)
+	// PurgeDeleted removes projects deleted before the given time for good and reports how many.
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
 
 // CategoryRepository defines operations for categories.
 // Note: Update must not allow changing ProjectID (enforced by implementations/services).
+// Every update increments the category's version; Update, SetArchived and Delete honor WithExpectedVersion.
+// Reads and updates only see categories outside the trash.
 type CategoryRepository interface {
 	Create(ctx context.Context, category domain.Category) (domain.Category, error)
 	GetByID(ctx context.Context, id uuid.UUID) (domain.Category, error)
-	ListByProject(ctx context.Context, projectID uuid.UUID) ([]domain.Category, error)
+	// ListByProject returns the categories of a project, oldest first, archived ones only with includeArchived.
+	ListByProject(ctx context.Context, projectID uuid.UUID, includeArchived bool, page PageRequest) ([]domain.Category, error)
 	ListChildren(ctx context.Context, parentID uuid.UUID) ([]domain.Category, error)
 	Update(ctx context.Context, id uuid.UUID, name string, description *string, parentCategoryID *uuid.UUID) (domain.Category, error)
+	// UpdateBilling sets the category's own rate (nil inherits) and its default billable flag.
+	UpdateBilling(ctx context.Context, id uuid.UUID, rate *domain.Rate, billable bool) (domain.Category, error)
+	// SetArchived archives or unarchives the category. Archiving an archived category keeps its ArchivedAt.
+	SetArchived(ctx context.Context, id uuid.UUID, archived bool) (domain.Category, error)
+	// Delete moves the category to the trash together with its descendants and their entries.
+	// The parent links inside the trashed subtree are kept.
 	Delete(ctx context.Context, id uuid.UUID) error
+	// ListDeleted returns the categories in the trash, most recently deleted first.
+	ListDeleted(ctx context.Context) ([]domain.Category, error)
+	// Restore takes a category out of the trash together with the descendants and entries deleted
+	// with it. It fails with ErrParentDeleted while the project or parent category is in the trash;
+	// categories outside the trash are reported as ErrNotFound.
+	Restore(ctx context.Context, id uuid.UUID) (domain.Category, error)
+	// PurgeDeleted removes categories deleted before the given time for good and reports how many.
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	// TotalsByProject returns every category of a project, oldest first, with the worked time inside
+	// the optional bounds [start, end) tracked in it and in its subtree. Entries crossing a bound
+	// count with their part inside, pauses are excluded and running entries count up to now.
+	TotalsByProject(ctx context.Context, projectID uuid.UUID, start *time.Time, end *time.Time, now time.Time) ([]domain.CategoryTotal, error)
+}
+
+// TimeEntrySort orders time entry listings.
+type TimeEntrySort string
+
+const (
+	// SortStartedAtDesc lists the most recently started entries first. It is the default order.
+	SortStartedAtDesc TimeEntrySort = "-startedAt"
+	// SortStartedAtAsc lists the earliest started entries first.
+	SortStartedAtAsc TimeEntrySort = "startedAt"
+	// SortDuration lists the shortest entries first; running entries come last.
+	SortDuration TimeEntrySort = "duration"
+)
+
+// runningSortSeconds is the duration sort key of running entries, placing them after all stopped ones.
+const runningSortSeconds = math.MaxInt32
+
+// CursorOf returns the cursor that resumes a listing in this order after e.
+func (s TimeEntrySort) CursorOf(e domain.TimeEntry) Cursor {
+	if s == SortDuration {
+		seconds := int64(runningSortSeconds)
+		if e.DurationSeconds != nil {
+			seconds = int64(*e.DurationSeconds)
+		}
+		return Cursor{Seconds: seconds, ID: e.ID}
+	}
+	return Cursor{At: e.StartedAt, ID: e.ID}
+}
+
+// RangeMode selects which entries a listing bounded by [start, end] matches.
+type RangeMode string
+
+const (
+	// RangeStarted matches entries started within [start, end]. It is the default mode.
+	RangeStarted RangeMode = "started"
+	// RangeOverlap matches entries whose interval intersects [start, end); running entries are open-ended.
+	RangeOverlap RangeMode = "overlap"
+)
+
+// TimeEntryFilter narrows time entry listings. Zero values apply no restriction.
+type TimeEntryFilter struct {
+	// NoteContains matches entries whose note contains the value, case-insensitively.
+	NoteContains string
+	// TagID matches entries carrying the given tag.
+	TagID *uuid.UUID
+	// Sort orders the listing; the zero value means SortStartedAtDesc.
+	Sort TimeEntrySort
+	// Page selects a page of the ordered listing. Cursors must come from CursorOf of the same Sort.
+	Page PageRequest
+	// Range selects how the bounds of a listing apply; the zero value means RangeStarted.
+	Range RangeMode
+	// Clip asks for the worked seconds of each entry inside the bounds. It is evaluated by the
+	// service layer, which fills TimeEntry.ClippedSeconds; repositories ignore it.
+	Clip bool
 }
 
 // TimeEntryRepository defines operations for time entries.
+// Every update increments the entry's version; Update and Delete honor WithExpectedVersion.
+// Reads and updates only see entries outside the trash.
 type TimeEntryRepository interface {
 	Create(ctx context.Context, entry domain.TimeEntry) (domain.TimeEntry, error)
 	GetByID(ctx context.Context, id uuid.UUID) (domain.TimeEntry, error)
-	ListByCategory(ctx context.Context, categoryID uuid.UUID) ([]domain.TimeEntry, error)
-	ListByCategoryAndRange(ctx context.Context, categoryID uuid.UUID, start time.Time, end time.Time) ([]domain.TimeEntry, error)
+	// GetForUpdate returns an entry like GetByID and locks it against concurrent changes until the
+	// unit of work ends.
+	GetForUpdate(ctx context.Context, id uuid.UUID) (domain.TimeEntry, error)
+	ListByCategory(ctx context.Context, categoryID uuid.UUID, filter TimeEntryFilter) ([]domain.TimeEntry, error)
+	// ListByCategoryAndRange returns the entries of a category matching [start, end] under filter.Range.
+	ListByCategoryAndRange(ctx context.Context, categoryID uuid.UUID, start time.Time, end time.Time, filter TimeEntryFilter) ([]domain.TimeEntry, error)
+	// ListByProject returns the entries of all categories of a project, and ListByCategoryTree those of
+	// a category and its descendants, matching [start, end] under filter.Range (nil bounds are
+	// open), in the order of filter.Sort. Both fill the category name and path of each entry.
+	ListByProject(ctx context.Context, projectID uuid.UUID, start *time.Time, end *time.Time, filter TimeEntryFilter) ([]domain.TimeEntry, error)
+	ListByCategoryTree(ctx context.Context, categoryID uuid.UUID, start *time.Time, end *time.Time, filter TimeEntryFilter) ([]domain.TimeEntry, error)
+	// ListOverlapping returns entries whose interval intersects [start, end).
+	// Running entries are treated as open-ended.
+	ListOverlapping(ctx context.Context, start time.Time, end time.Time) ([]domain.TimeEntry, error)
+	// LockTimerSlot serializes writes to the timer slots of the categories until the unit of work
+	// ends, so that an overlap check and the write it guards cannot interleave with another one.
+	LockTimerSlot(ctx context.Context, categoryIDs ...uuid.UUID) error
+	// FindActive returns the most recently started running entry, or nil when none is running.
 	FindActive(ctx context.Context) (*domain.TimeEntry, error)
-	Stop(ctx context.Context, id uuid.UUID, stoppedAt time.Time, durationSeconds *int32) (domain.TimeEntry, error)
+	// FindActiveByCategory returns the running entry of a category, or nil when none is running.
+	FindActiveByCategory(ctx context.Context, categoryID uuid.UUID) (*domain.TimeEntry, error)
+	// ListActive returns all running entries, most recently started first.
+	ListActive(ctx context.Context) ([]domain.TimeEntry, error)
+	// FindLastStopped returns the most recently stopped entry that is not a break, or nil when there is none.
+	FindLastStopped(ctx context.Context) (*domain.TimeEntry, error)
+	// Stop ends a running entry. A nil note leaves the stored note unchanged; autoStopped marks a stop made
+	// by the server. An entry that is stopped already is reported as ErrNotFound and left as it is.
+	Stop(ctx context.Context, id uuid.UUID, stoppedAt time.Time, durationSeconds *int32, note *string, autoStopped bool) (domain.TimeEntry, error)
+	// Update overwrites the category, timestamps, duration, note and flags of an existing entry.
+	Update(ctx context.Context, entry domain.TimeEntry) (domain.TimeEntry, error)
+	// Delete moves the entry to the trash.
+	Delete(ctx context.Context, id uuid.UUID) error
+	// DeleteByCategoryAndRange moves the entries ListByCategoryAndRange would return in RangeStarted mode to the trash and reports them.
+	DeleteByCategoryAndRange(ctx context.Context, categoryID uuid.UUID, start time.Time, end time.Time) ([]domain.TimeEntry, error)
+	// ListDeleted returns the entries in the trash, most recently deleted first.
+	ListDeleted(ctx context.Context) ([]domain.TimeEntry, error)
+	// Restore takes an entry out of the trash. It fails with ErrParentDeleted while the category is
+	// in the trash; entries outside the trash are reported as ErrNotFound.
+	Restore(ctx context.Context, id uuid.UUID) (domain.TimeEntry, error)
+	// PurgeDeleted removes entries deleted before the given time for good and reports how many.
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	// SetTags replaces the tag set of an entry.
+	SetTags(ctx context.Context, entryID uuid.UUID, tagIDs []uuid.UUID) error
+	// ListTagIDs returns the tag IDs of each given entry, ordered by tag name.
+	ListTagIDs(ctx context.Context, entryIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error)
+	// CreateSegment records a running interval of an entry; a nil StoppedAt leaves it open.
+	CreateSegment(ctx context.Context, segment domain.TimeEntrySegment) (domain.TimeEntrySegment, error)
+	// StopOpenSegment closes the open segment of an entry, if any.
+	StopOpenSegment(ctx context.Context, entryID uuid.UUID, stoppedAt time.Time) error
+	// ListSegments returns the segments of an entry ordered by start.
+	ListSegments(ctx context.Context, entryID uuid.UUID) ([]domain.TimeEntrySegment, error)
+	// DeleteSegments removes all segments of an entry, turning it back into one continuous interval.
+	DeleteSegments(ctx context.Context, entryID uuid.UUID) error
+}
+
+// TagRepository defines operations for project-scoped tags.
+type TagRepository interface {
+	Create(ctx context.Context, tag domain.Tag) (domain.Tag, error)
+	GetByID(ctx context.Context, id uuid.UUID) (domain.Tag, error)
+	ListByProject(ctx context.Context, projectID uuid.UUID) ([]domain.Tag, error)
+	Rename(ctx context.Context, id uuid.UUID, name string) (domain.Tag, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// TotalsByProject sums tracked seconds per tag for entries started within the optional bounds.
+	// Running entries count their worked time up to now, pauses excluded. Tags without entries are reported with zero seconds.
+	// RoundedSeconds applies the project's rounding to the rounding units of domain.Rounding.
+	TotalsByProject(ctx context.Context, projectID uuid.UUID, start *time.Time, end *time.Time, now time.Time) ([]domain.TagTotal, error)
+}
+
+// AuditFilter narrows audit event listings. Zero values apply no restriction.
+type AuditFilter struct {
+	EntityType domain.AuditEntity
+	EntityID   *uuid.UUID
+	// From and To bound the time the events were recorded at, both inclusive.
+	From *time.Time
+	To   *time.Time
+	// Page selects a page of the listing; cursors carry the event's CreatedAt and ID.
+	Page PageRequest
+}
+
+// AuditRepository stores the append-only audit log.
+type AuditRepository interface {
+	Create(ctx context.Context, event domain.AuditEvent) (domain.AuditEvent, error)
+	// List returns matching events, most recent first.
+	List(ctx context.Context, filter AuditFilter) ([]domain.AuditEvent, error)
+}
+
+// SummaryQuery selects the worked time a summary report adds up and how it is grouped.
+type SummaryQuery struct {
+	// Start and End bound the worked time counted, [Start, End). Entries crossing a bound count
+	// with their part inside.
+	Start time.Time
+	End   time.Time
+	// Now ends running entries.
+	Now time.Time
+	// Timezone is the IANA time zone in which day, week and month periods are cut.
+	Timezone string
+	// GroupBy lists the dimensions to group by, at most one of them a period.
+	GroupBy    []domain.ReportGroup
+	ProjectID  *uuid.UUID
+	CategoryID *uuid.UUID
+}
+
+// ReportRepository aggregates tracked time for reports.
+type ReportRepository interface {
+	// Summary totals the worked time of the entries matching q, pauses excluded, per group and
+	// over all. Grouped by tag, time tagged with several tags counts toward each of them and
+	// untagged time forms a row of its own. Rows are ordered by their group values in the order
+	// of q.GroupBy. Rounded seconds apply each project's rounding to the rounding units of
+	// domain.Rounding, as billing does, taking the part of each unit that falls into a row or the report.
+	Summary(ctx context.Context, q SummaryQuery) (rows []domain.ReportRow, totalSeconds int64, roundedTotalSeconds int64, err error)
 }
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+)
+
+func TestProjectServiceArchiveHidesFromListUntilUnarchived(t *testing.T) {
+	ctx := context.Background()
+	audit := &fakeAuditRepo{}
+	svc := NewProjectService(newFakeProjectRepo(), audit, fakeUnitOfWork{})
+	kept, _ := svc.Create(ctx, "Kept", nil)
+	done, _ := svc.Create(ctx, "Done", nil)
+
+	archived, err := svc.Archive(ctx, done.ID)
+	if err != nil {
+		t.Fatalf("archive failed: %v", err)
+	}
+	if archived.ArchivedAt == nil || archived.Version != done.Version+1 {
+		t.Fatalf("unexpected archived project: %+v", archived)
+	}
+	again, err := svc.Archive(ctx, done.ID)
+	if err != nil || again.ArchivedAt == nil || !again.ArchivedAt.Equal(*archived.ArchivedAt) {
+		t.Fatalf("expected archiving twice to keep the archive time, got %+v (%v)", again, err)
+	}
+
+	list, _ := svc.List(ctx, false, repository.PageRequest{})
+	if len(list) != 1 || list[0].ID != kept.ID {
+		t.Fatalf("expected only the active project, got %+v", list)
+	}
+	all, _ := svc.List(ctx, true, repository.PageRequest{})
+	if len(all) != 2 {
+		t.Fatalf("expected both projects with includeArchived, got %+v", all)
+	}
+	if _, err := svc.GetByID(ctx, done.ID); err != nil {
+		t.Fatalf("expected archived projects to stay readable, got %v", err)
+	}
+
+	unarchived, err := svc.Unarchive(ctx, done.ID)
+	if err != nil || unarchived.ArchivedAt != nil {
+		t.Fatalf("expected the project unarchived, got %+v (%v)", unarchived, err)
+	}
+	last := audit.events[len(audit.events)-1]
+	if last.Action != domain.AuditUpdate || snapshotField(t, last.Before, "archivedAt") == nil || snapshotField(t, last.After, "archivedAt") != nil {
+		t.Fatalf("unexpected unarchive event: %s -> %s", last.Before, last.After)
+	}
+}
+
+func TestTimeTrackingServiceStartRefusesArchivedCategory(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
+	timeRepo := newFakeTimeEntryRepo()
+	timeRepo.categories = catRepo
+	base := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	clk := newTestClock(base)
+	svc := NewTimeTrackingService(timeRepo, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+	categories := NewCategoryService(catRepo, nil, fakeUnitOfWork{}, clk)
+	projects := NewProjectService(projRepo, nil, fakeUnitOfWork{})
+
+	entry, err := svc.Start(ctx, cat.ID, StartOptions{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	clk.Advance(time.Hour)
+	if _, err := categories.Archive(ctx, cat.ID); err != nil {
+		t.Fatalf("archive failed: %v", err)
+	}
+	// The running entry keeps running and can still be stopped
+	if _, err := svc.StopActive(ctx, StopOptions{}); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{}); err != ErrCategoryArchived {
+		t.Fatalf("expected ErrCategoryArchived, got %v", err)
+	}
+	if _, err := svc.Continue(ctx, &entry.ID); err != ErrCategoryArchived {
+		t.Fatalf("expected ErrCategoryArchived on continue, got %v", err)
+	}
+	listed, _ := categories.ListByProject(ctx, cat.ProjectID, false, repository.PageRequest{})
+	if len(listed) != 0 {
+		t.Fatalf("expected the archived category hidden, got %+v", listed)
+	}
+	entries, err := svc.ListByProject(ctx, cat.ProjectID, nil, nil, repository.TimeEntryFilter{})
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected the archived category's entry in listings, got %+v (%v)", entries, err)
+	}
+
+	// An archived project blocks its categories just the same
+	if _, err := categories.Unarchive(ctx, cat.ID); err != nil {
+		t.Fatalf("unarchive failed: %v", err)
+	}
+	if _, err := projects.Archive(ctx, cat.ProjectID); err != nil {
+		t.Fatalf("project archive failed: %v", err)
+	}
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{}); err != ErrCategoryArchived {
+		t.Fatalf("expected ErrCategoryArchived for an archived project, got %v", err)
+	}
+	if _, err := projects.Unarchive(ctx, cat.ProjectID); err != nil {
+		t.Fatalf("project unarchive failed: %v", err)
+	}
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{}); err != nil {
+		t.Fatalf("expected start to work again, got %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+)
+
+// systemActor is the actor of changes made outside of a request, such as by the timer reaper.
+const systemActor = "system"
+
+// actorKey is the context key under which WithActor stores the actor.
+type actorKey struct{}
+
+// WithActor returns a context attributing the changes made with it to actor in the audit log.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// actorOf returns the actor set on ctx, or systemActor when there is none.
+func actorOf(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return systemActor
+}
+
+type auditService struct {
+	repo repository.AuditRepository
+}
+
+func (s *auditService) List(ctx context.Context, filter repository.AuditFilter) ([]domain.AuditEvent, error) {
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, ErrInvalidTimeRange
+	}
+	return s.repo.List(ctx, filter)
+}
+
+var _ AuditService = (*auditService)(nil)
+
+// auditLog records the mutations of a service. Callers record inside the unit of work of the
+// mutation, so that the change and its event commit together. A nil repository records nothing.
+type auditLog struct {
+	repo repository.AuditRepository
+}
+
+func (a auditLog) project(ctx context.Context, action domain.AuditAction, before *domain.Project, after *domain.Project) error {
+	var b, f any
+	id := uuid.Nil
+	if before != nil {
+		b, id = projectSnapshotOf(*before), before.ID
+	}
+	if after != nil {
+		f, id = projectSnapshotOf(*after), after.ID
+	}
+	return a.record(ctx, action, domain.AuditProject, id, b, f)
+}
+
+func (a auditLog) category(ctx context.Context, action domain.AuditAction, before *domain.Category, after *domain.Category) error {
+	var b, f any
+	id := uuid.Nil
+	if before != nil {
+		b, id = categorySnapshotOf(*before), before.ID
+	}
+	if after != nil {
+		f, id = categorySnapshotOf(*after), after.ID
+	}
+	return a.record(ctx, action, domain.AuditCategory, id, b, f)
+}
+
+func (a auditLog) entry(ctx context.Context, action domain.AuditAction, before *domain.TimeEntry, after *domain.TimeEntry) error {
+	var b, f any
+	id := uuid.Nil
+	if before != nil {
+		b, id = entrySnapshotOf(*before), before.ID
+	}
+	if after != nil {
+		f, id = entrySnapshotOf(*after), after.ID
+	}
+	return a.record(ctx, action, domain.AuditTimeEntry, id, b, f)
+}
+
+func (a auditLog) record(ctx context.Context, action domain.AuditAction, entity domain.AuditEntity, id uuid.UUID, before any, after any) error {
+	if a.repo == nil {
+		return nil
+	}
+	event := domain.AuditEvent{
+		ID:         uuid.New(),
+		Actor:      actorOf(ctx),
+		RequestID:  middleware.GetReqID(ctx),
+		Action:     action,
+		EntityType: entity,
+		EntityID:   id,
+	}
+	var err error
+	if before != nil {
+		if event.Before, err = json.Marshal(before); err != nil {
+			return err
+		}
+	}
+	if after != nil {
+		if event.After, err = json.Marshal(after); err != nil {
+			return err
+		}
+	}
+	_, err = a.repo.Create(ctx, event)
+	return err
+}
+
+// The snapshots below are the JSON shapes stored in the audit log. They hold the stored fields of
+// an entity, named as in the API; derived values such as durations under rounding are left out.
+
+type rateSnapshot struct {
+	HourlyRateCents int64  `json:"hourlyRateCents"`
+	Currency        string `json:"currency"`
+}
+
+func rateSnapshotOf(r *domain.Rate) *rateSnapshot {
+	if r == nil {
+		return nil
+	}
+	return &rateSnapshot{HourlyRateCents: r.HourlyRateCents, Currency: r.Currency}
+}
+
+type roundingSnapshot struct {
+	IncrementSeconds int32  `json:"incrementSeconds"`
+	Mode             string `json:"mode"`
+	Scope            string `json:"scope"`
+}
+
+type projectSnapshot struct {
+	ID               uuid.UUID        `json:"id"`
+	Name             string           `json:"name"`
+	Description      *string          `json:"description"`
+	Rate             *rateSnapshot    `json:"rate"`
+	ConcurrentTimers bool             `json:"concurrentTimers"`
+	Rounding         roundingSnapshot `json:"rounding"`
+	ArchivedAt       *time.Time       `json:"archivedAt"`
+	Version          int64            `json:"version"`
+}
+
+func projectSnapshotOf(p domain.Project) projectSnapshot {
+	return projectSnapshot{
+		ID:               p.ID,
+		Name:             p.Name,
+		Description:      p.Description,
+		Rate:             rateSnapshotOf(p.Rate),
+		ConcurrentTimers: p.Settings.ConcurrentTimers,
+		Rounding: roundingSnapshot{
+			IncrementSeconds: p.Settings.Rounding.IncrementSeconds,
+			Mode:             string(p.Settings.Rounding.Mode),
+			Scope:            string(p.Settings.Rounding.Scope),
+		},
+		ArchivedAt: p.ArchivedAt,
+		Version:    p.Version,
+	}
+}
+
+type categorySnapshot struct {
+	ID               uuid.UUID     `json:"id"`
+	ProjectID        uuid.UUID     `json:"projectId"`
+	ParentCategoryID *uuid.UUID    `json:"parentCategoryId"`
+	Name             string        `json:"name"`
+	Description      *string       `json:"description"`
+	Rate             *rateSnapshot `json:"rate"`
+	Billable         bool          `json:"billable"`
+	ArchivedAt       *time.Time    `json:"archivedAt"`
+	Version          int64         `json:"version"`
+}
+
+func categorySnapshotOf(c domain.Category) categorySnapshot {
+	return categorySnapshot{
+		ID:               c.ID,
+		ProjectID:        c.ProjectID,
+		ParentCategoryID: c.ParentCategoryID,
+		Name:             c.Name,
+		Description:      c.Description,
+		Rate:             rateSnapshotOf(c.Rate),
+		Billable:         c.Billable,
+		ArchivedAt:       c.ArchivedAt,
+		Version:          c.Version,
+	}
+}
+
+// entrySnapshot carries tagIds only when the recording service had them at hand.
+type entrySnapshot struct {
+	ID              uuid.UUID   `json:"id"`
+	CategoryID      uuid.UUID   `json:"categoryId"`
+	StartedAt       time.Time   `json:"startedAt"`
+	StoppedAt       *time.Time  `json:"stoppedAt"`
+	DurationSeconds *int32      `json:"durationSeconds"`
+	Note            *string     `json:"note"`
+	TagIDs          []uuid.UUID `json:"tagIds,omitempty"`
+	Billable        bool        `json:"billable"`
+	AutoStopped     bool        `json:"autoStopped"`
+	TargetSeconds   *int32      `json:"targetSeconds"`
+	Break           bool        `json:"break"`
+	Version         int64       `json:"version"`
+}
+
+func entrySnapshotOf(e domain.TimeEntry) entrySnapshot {
+	return entrySnapshot{
+		ID:              e.ID,
+		CategoryID:      e.CategoryID,
+		StartedAt:       e.StartedAt,
+		StoppedAt:       e.StoppedAt,
+		DurationSeconds: e.DurationSeconds,
+		Note:            e.Note,
+		TagIDs:          e.TagIDs,
+		Billable:        e.Billable,
+		AutoStopped:     e.AutoStopped,
+		TargetSeconds:   e.TargetSeconds,
+		Break:           e.Break,
+		Version:         e.Version,
+	}
+}
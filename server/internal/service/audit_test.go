@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+)
+
+// snapshotField decodes raw and returns the value of its field key.
+func snapshotField(t *testing.T, raw json.RawMessage, key string) any {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		t.Fatalf("invalid snapshot %s: %v", raw, err)
+	}
+	return m[key]
+}
+
+func TestProjectServiceRecordsAuditEvents(t *testing.T) {
+	audit := &fakeAuditRepo{}
+	svc := NewProjectService(newFakeProjectRepo(), audit, fakeUnitOfWork{})
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-1")
+	ctx = WithActor(ctx, "alice")
+
+	p, err := svc.Create(ctx, "Old", nil)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if _, err := svc.Update(ctx, p.ID, "New", nil); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if err := svc.Delete(ctx, p.ID); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+
+	if len(audit.events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(audit.events))
+	}
+	for i, action := range []domain.AuditAction{domain.AuditCreate, domain.AuditUpdate, domain.AuditDelete} {
+		e := audit.events[i]
+		if e.Action != action || e.EntityType != domain.AuditProject || e.EntityID != p.ID {
+			t.Fatalf("event %d: unexpected %s %s %s", i, e.Action, e.EntityType, e.EntityID)
+		}
+		if e.Actor != "alice" || e.RequestID != "req-1" {
+			t.Fatalf("event %d: expected alice/req-1, got %q/%q", i, e.Actor, e.RequestID)
+		}
+	}
+	create, update, del := audit.events[0], audit.events[1], audit.events[2]
+	if create.Before != nil || create.After == nil || del.Before == nil || del.After != nil {
+		t.Fatalf("expected creations without before and deletions without after")
+	}
+	if snapshotField(t, update.Before, "name") != "Old" || snapshotField(t, update.After, "name") != "New" {
+		t.Fatalf("unexpected update snapshots: %s -> %s", update.Before, update.After)
+	}
+	if snapshotField(t, update.After, "version") != float64(2) {
+		t.Fatalf("expected the updated version in the snapshot, got %s", update.After)
+	}
+}
+
+func TestProjectServiceFailsWhenAuditFails(t *testing.T) {
+	recordErr := errors.New("audit down")
+	svc := NewProjectService(newFakeProjectRepo(), &fakeAuditRepo{failCreate: recordErr}, fakeUnitOfWork{})
+	if _, err := svc.Create(context.Background(), "P", nil); !errors.Is(err, recordErr) {
+		t.Fatalf("expected the audit error, got %v", err)
+	}
+}
+
+func TestTimeTrackingServiceRecordsAuditEvents(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
+	audit := &fakeAuditRepo{}
+	clk := newTestClock(time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC))
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, audit, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	first, err := svc.Start(ctx, cat.ID, StartOptions{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	clk.Advance(30 * time.Minute)
+	// Starting again stops the running entry first
+	second, err := svc.Start(ctx, cat.ID, StartOptions{})
+	if err != nil {
+		t.Fatalf("second start failed: %v", err)
+	}
+	clk.Advance(15 * time.Minute)
+	if _, err := svc.StopActive(ctx, StopOptions{}); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if _, err := svc.DeleteEntries(ctx, cat.ID, first.StartedAt, clk.Now(), true); err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if err := svc.DeleteEntry(ctx, first.ID); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+
+	want := []struct {
+		action domain.AuditAction
+		id     uuid.UUID
+	}{
+		{domain.AuditCreate, first.ID},
+		{domain.AuditStop, first.ID},
+		{domain.AuditCreate, second.ID},
+		{domain.AuditStop, second.ID},
+		{domain.AuditDelete, first.ID},
+	}
+	if len(audit.events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), audit.events)
+	}
+	for i, w := range want {
+		e := audit.events[i]
+		if e.Action != w.action || e.EntityID != w.id || e.EntityType != domain.AuditTimeEntry {
+			t.Fatalf("event %d: expected %s of %v, got %s of %s", i, w.action, w.id, e.Action, e.EntityID)
+		}
+		if e.Actor != "system" || e.RequestID != "" {
+			t.Fatalf("event %d: expected the system actor without request, got %q/%q", i, e.Actor, e.RequestID)
+		}
+	}
+	stop := audit.events[1]
+	if snapshotField(t, stop.Before, "stoppedAt") != nil || snapshotField(t, stop.After, "durationSeconds") != float64(1800) {
+		t.Fatalf("unexpected stop snapshots: %s -> %s", stop.Before, stop.After)
+	}
+
+	events, err := NewAuditService(audit).List(ctx, repository.AuditFilter{EntityID: &first.ID})
+	if err != nil || len(events) != 3 || events[0].Action != domain.AuditDelete {
+		t.Fatalf("expected the entry's 3 events newest first, got %+v (%v)", events, err)
+	}
+}
+
+func TestAuditServiceListRejectsInvertedRange(t *testing.T) {
+	from := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+	_, err := NewAuditService(&fakeAuditRepo{}).List(context.Background(), repository.AuditFilter{From: &from, To: &to})
+	if err != ErrInvalidTimeRange {
+		t.Fatalf("expected ErrInvalidTimeRange, got %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+type billingService struct {
+	projectRepo  repository.ProjectRepository
+	categoryRepo repository.CategoryRepository
+	timeRepo     repository.TimeEntryRepository
+	audit        auditLog
+	uow          repository.UnitOfWork
+	rates        rateResolver
+}
+
+func (s *billingService) SetProjectRate(ctx context.Context, projectID uuid.UUID, rate *domain.Rate) (domain.Project, error) {
+	normalized, err := normalizeRate(rate)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	var updated domain.Project
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		before, err := s.projectRepo.GetByID(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		if updated, err = s.projectRepo.UpdateRate(ctx, projectID, normalized); err != nil {
+			return err
+		}
+		return s.audit.project(ctx, domain.AuditUpdate, &before, &updated)
+	})
+	return updated, err
+}
+
+// SetCategoryBilling replaces the category's own rate (nil inherits) and, when billable is non-nil,
+// its default billable flag. The category must belong to projectID.
+func (s *billingService) SetCategoryBilling(ctx context.Context, projectID uuid.UUID, categoryID uuid.UUID, rate *domain.Rate, billable *bool) (domain.Category, error) {
+	normalized, err := normalizeRate(rate)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	current, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	if current.ProjectID != projectID {
+		return domain.Category{}, repository.ErrNotFound
+	}
+	flag := current.Billable
+	if billable != nil {
+		flag = *billable
+	}
+	var updated domain.Category
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if updated, err = s.categoryRepo.UpdateBilling(ctx, categoryID, normalized, flag); err != nil {
+			return err
+		}
+		return s.audit.category(ctx, domain.AuditUpdate, &current, &updated)
+	})
+	return updated, err
+}
+
+func (s *billingService) EffectiveRate(ctx context.Context, categoryID uuid.UUID) (*domain.Rate, error) {
+	return s.rates.resolve(ctx, categoryID, nil)
+}
+
+func (s *billingService) EntryAmount(ctx context.Context, entry domain.TimeEntry) (*domain.Money, error) {
+	return s.rates.entryAmount(ctx, entry, nil)
+}
+
+// Summary aggregates the stopped entries of a project started within [start, end], per category.
+// Amounts price the rounded billable time. With per-entry rounding they are summed from per-entry
+// amounts so that they match the entries shown individually; per-day rounding prices each day.
+func (s *billingService) Summary(ctx context.Context, projectID uuid.UUID, start time.Time, end time.Time) (domain.BillingSummary, error) {
+	if start.After(end) {
+		return domain.BillingSummary{}, ErrInvalidTimeRange
+	}
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return domain.BillingSummary{}, err
+	}
+	rounding := project.Settings.Rounding
+	// Archived categories still bill the time tracked in them
+	categories, err := s.categoryRepo.ListByProject(ctx, projectID, true, repository.PageRequest{})
+	if err != nil {
+		return domain.BillingSummary{}, err
+	}
+
+	summary := domain.BillingSummary{
+		ProjectID: projectID,
+		Start:     start,
+		End:       end,
+		Lines:     make([]domain.BillingLine, 0, len(categories)),
+		Totals:    []domain.Money{},
+	}
+	cache := map[uuid.UUID]*domain.Rate{}
+	totals := map[string]int64{}
+	for _, c := range categories {
+		rate, err := s.rates.resolve(ctx, c.ID, cache)
+		if err != nil {
+			return domain.BillingSummary{}, err
+		}
+		entries, err := s.timeRepo.ListByCategoryAndRange(ctx, c.ID, start, end, repository.TimeEntryFilter{})
+		if err != nil {
+			return domain.BillingSummary{}, err
+		}
+
+		line := domain.BillingLine{CategoryID: c.ID, CategoryName: c.Name, Rate: rate}
+		var billable, nonBillable []domain.TimeEntry
+		for _, e := range entries {
+			if e.DurationSeconds == nil {
+				continue
+			}
+			if e.Billable {
+				line.BillableSeconds += int64(*e.DurationSeconds)
+				billable = append(billable, e)
+			} else {
+				line.NonBillableSeconds += int64(*e.DurationSeconds)
+				nonBillable = append(nonBillable, e)
+			}
+		}
+		var cents int64
+		for _, seconds := range roundedUnits(billable, rounding) {
+			line.RoundedBillableSeconds += seconds
+			if rate != nil {
+				cents += billableCents(seconds, *rate)
+			}
+		}
+		for _, seconds := range roundedUnits(nonBillable, rounding) {
+			line.RoundedNonBillableSeconds += seconds
+		}
+		if rate != nil {
+			line.Amount = &domain.Money{Cents: cents, Currency: rate.Currency}
+			totals[rate.Currency] += cents
+		}
+		summary.Lines = append(summary.Lines, line)
+	}
+
+	for currency, cents := range totals {
+		summary.Totals = append(summary.Totals, domain.Money{Cents: cents, Currency: currency})
+	}
+	sort.Slice(summary.Totals, func(i, j int) bool { return summary.Totals[i].Currency < summary.Totals[j].Currency })
+	return summary, nil
+}
+
+// rateResolver finds the effective rate of a category: its own rate, else the nearest
+// ancestor's along ParentCategoryID, else the project's.
+type rateResolver struct {
+	projectRepo  repository.ProjectRepository
+	categoryRepo repository.CategoryRepository
+}
+
+// resolve returns the effective rate or nil when none is set. A non-nil cache memoizes
+// results per category across calls.
+func (r rateResolver) resolve(ctx context.Context, categoryID uuid.UUID, cache map[uuid.UUID]*domain.Rate) (*domain.Rate, error) {
+	if rate, ok := cache[categoryID]; ok {
+		return rate, nil
+	}
+	visited := map[uuid.UUID]bool{}
+	cur, err := r.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	var rate *domain.Rate
+	for {
+		visited[cur.ID] = true
+		if cur.Rate != nil {
+			rate = cur.Rate
+			break
+		}
+		if cur.ParentCategoryID == nil || visited[*cur.ParentCategoryID] {
+			project, err := r.projectRepo.GetByID(ctx, cur.ProjectID)
+			if err != nil {
+				return nil, err
+			}
+			rate = project.Rate
+			break
+		}
+		if cur, err = r.categoryRepo.GetByID(ctx, *cur.ParentCategoryID); err != nil {
+			return nil, err
+		}
+	}
+	if cache != nil {
+		cache[categoryID] = rate
+	}
+	return rate, nil
+}
+
+// entryAmount returns the billable amount of a stopped, billable entry with a known rate, else nil.
+// Entries carrying RoundedSeconds are priced by their rounded duration.
+func (r rateResolver) entryAmount(ctx context.Context, entry domain.TimeEntry, cache map[uuid.UUID]*domain.Rate) (*domain.Money, error) {
+	if !entry.Billable || entry.DurationSeconds == nil {
+		return nil, nil
+	}
+	rate, err := r.resolve(ctx, entry.CategoryID, cache)
+	if err != nil || rate == nil {
+		return nil, err
+	}
+	seconds := *entry.DurationSeconds
+	if entry.RoundedSeconds != nil {
+		seconds = *entry.RoundedSeconds
+	}
+	return &domain.Money{Cents: billableCents(int64(seconds), *rate), Currency: rate.Currency}, nil
+}
+
+// billableCents prices seconds at the hourly rate, rounding half up to the nearest minor unit.
+func billableCents(seconds int64, rate domain.Rate) int64 {
+	return (seconds*rate.HourlyRateCents + 1800) / 3600
+}
+
+// normalizeRate validates a rate and upper-cases its currency. A nil rate is valid and means "no rate".
+func normalizeRate(rate *domain.Rate) (*domain.Rate, error) {
+	if rate == nil {
+		return nil, nil
+	}
+	currency := strings.ToUpper(strings.TrimSpace(rate.Currency))
+	if rate.HourlyRateCents < 0 || len(currency) != 3 {
+		return nil, ErrInvalidRate
+	}
+	for _, ch := range currency {
+		if ch < 'A' || ch > 'Z' {
+			return nil, ErrInvalidRate
+		}
+	}
+	return &domain.Rate{HourlyRateCents: rate.HourlyRateCents, Currency: currency}, nil
+}
+
+var _ BillingService = (*billingService)(nil)
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+type billingFixture struct {
+	projects   *fakeProjectRepo
+	categories *fakeCategoryRepo
+	entries    *fakeTimeEntryRepo
+	project    domain.Project
+	root       domain.Category
+	child      domain.Category
+	grandchild domain.Category
+}
+
+// newBillingFixture seeds a project with a root > child > grandchild category chain.
+func newBillingFixture(t *testing.T) billingFixture {
+	t.Helper()
+	ctx := context.Background()
+	f := billingFixture{
+		projects:   newFakeProjectRepo(),
+		categories: newFakeCategoryRepo(),
+		entries:    newFakeTimeEntryRepo(),
+	}
+	f.project, _ = f.projects.Create(ctx, domain.Project{ID: uuid.New(), Name: "Client"})
+	f.root, _ = f.categories.Create(ctx, domain.Category{ID: uuid.New(), ProjectID: f.project.ID, Name: "Root", Billable: true})
+	f.child, _ = f.categories.Create(ctx, domain.Category{ID: uuid.New(), ProjectID: f.project.ID, ParentCategoryID: &f.root.ID, Name: "Child", Billable: true})
+	f.grandchild, _ = f.categories.Create(ctx, domain.Category{ID: uuid.New(), ProjectID: f.project.ID, ParentCategoryID: &f.child.ID, Name: "Grandchild", Billable: true})
+	return f
+}
+
+func (f billingFixture) service() BillingService {
+	return NewBillingService(f.projects, f.categories, f.entries, nil, fakeUnitOfWork{})
+}
+
+func (f billingFixture) seedStopped(t *testing.T, categoryID uuid.UUID, start time.Time, seconds int32, billable bool) {
+	t.Helper()
+	stop := start.Add(time.Duration(seconds) * time.Second)
+	e := domain.TimeEntry{ID: uuid.New(), CategoryID: categoryID, StartedAt: start, StoppedAt: &stop, DurationSeconds: &seconds, Billable: billable}
+	if _, err := f.entries.Create(context.Background(), e); err != nil {
+		t.Fatalf("seed entry failed: %v", err)
+	}
+}
+
+func TestBillingServiceEffectiveRateInheritsAlongParents(t *testing.T) {
+	ctx := context.Background()
+	f := newBillingFixture(t)
+	svc := f.service()
+
+	rate, err := svc.EffectiveRate(ctx, f.grandchild.ID)
+	if err != nil || rate != nil {
+		t.Fatalf("expected no rate, got %v %v", rate, err)
+	}
+
+	if _, err := svc.SetProjectRate(ctx, f.project.ID, &domain.Rate{HourlyRateCents: 10000, Currency: "eur"}); err != nil {
+		t.Fatalf("set project rate failed: %v", err)
+	}
+	rate, _ = svc.EffectiveRate(ctx, f.grandchild.ID)
+	if rate == nil || rate.HourlyRateCents != 10000 || rate.Currency != "EUR" {
+		t.Fatalf("expected project rate, got %+v", rate)
+	}
+
+	if _, err := svc.SetCategoryBilling(ctx, f.project.ID, f.root.ID, &domain.Rate{HourlyRateCents: 12000, Currency: "EUR"}, nil); err != nil {
+		t.Fatalf("set root rate failed: %v", err)
+	}
+	if _, err := svc.SetCategoryBilling(ctx, f.project.ID, f.child.ID, &domain.Rate{HourlyRateCents: 15000, Currency: "USD"}, nil); err != nil {
+		t.Fatalf("set child rate failed: %v", err)
+	}
+	rate, _ = svc.EffectiveRate(ctx, f.grandchild.ID)
+	if rate == nil || rate.HourlyRateCents != 15000 || rate.Currency != "USD" {
+		t.Fatalf("expected nearest ancestor rate, got %+v", rate)
+	}
+
+	// Clearing the child's rate falls back to the root
+	if _, err := svc.SetCategoryBilling(ctx, f.project.ID, f.child.ID, nil, nil); err != nil {
+		t.Fatalf("clear child rate failed: %v", err)
+	}
+	rate, _ = svc.EffectiveRate(ctx, f.grandchild.ID)
+	if rate == nil || rate.HourlyRateCents != 12000 {
+		t.Fatalf("expected root rate, got %+v", rate)
+	}
+}
+
+func TestBillingServiceSetRateValidation(t *testing.T) {
+	ctx := context.Background()
+	f := newBillingFixture(t)
+	svc := f.service()
+
+	invalid := []domain.Rate{
+		{HourlyRateCents: -1, Currency: "EUR"},
+		{HourlyRateCents: 100, Currency: "EURO"},
+		{HourlyRateCents: 100, Currency: "E1R"},
+	}
+	for _, r := range invalid {
+		rate := r
+		if _, err := svc.SetProjectRate(ctx, f.project.ID, &rate); err != ErrInvalidRate {
+			t.Fatalf("expected ErrInvalidRate for %+v, got %v", r, err)
+		}
+	}
+	if _, err := svc.SetCategoryBilling(ctx, uuid.New(), f.root.ID, nil, nil); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound for category of another project, got %v", err)
+	}
+
+	off := false
+	c, err := svc.SetCategoryBilling(ctx, f.project.ID, f.root.ID, nil, &off)
+	if err != nil || c.Billable {
+		t.Fatalf("expected non-billable category, got %+v %v", c, err)
+	}
+	// Omitting billable keeps the current flag
+	c, err = svc.SetCategoryBilling(ctx, f.project.ID, f.root.ID, &domain.Rate{HourlyRateCents: 1, Currency: "EUR"}, nil)
+	if err != nil || c.Billable {
+		t.Fatalf("expected billable flag kept, got %+v %v", c, err)
+	}
+}
+
+func TestBillingServiceEntryAmountRoundsHalfUp(t *testing.T) {
+	ctx := context.Background()
+	f := newBillingFixture(t)
+	svc := f.service()
+	if _, err := svc.SetProjectRate(ctx, f.project.ID, &domain.Rate{HourlyRateCents: 100, Currency: "EUR"}); err != nil {
+		t.Fatalf("set project rate failed: %v", err)
+	}
+
+	seconds := int32(90) // 90s at 1.00/h = 2.5 cents
+	entry := domain.TimeEntry{CategoryID: f.child.ID, DurationSeconds: &seconds, Billable: true}
+	amount, err := svc.EntryAmount(ctx, entry)
+	if err != nil {
+		t.Fatalf("entry amount failed: %v", err)
+	}
+	if amount == nil || amount.Cents != 3 || amount.Currency != "EUR" {
+		t.Fatalf("expected 3 EUR cents, got %+v", amount)
+	}
+
+	entry.Billable = false
+	if amount, _ := svc.EntryAmount(ctx, entry); amount != nil {
+		t.Fatalf("expected no amount for non-billable entry, got %+v", amount)
+	}
+	entry.Billable = true
+	entry.DurationSeconds = nil
+	if amount, _ := svc.EntryAmount(ctx, entry); amount != nil {
+		t.Fatalf("expected no amount for running entry, got %+v", amount)
+	}
+}
+
+func TestBillingServiceSummaryPerCategoryAndCurrency(t *testing.T) {
+	ctx := context.Background()
+	f := newBillingFixture(t)
+	svc := f.service()
+	if _, err := svc.SetProjectRate(ctx, f.project.ID, &domain.Rate{HourlyRateCents: 6000, Currency: "EUR"}); err != nil {
+		t.Fatalf("set project rate failed: %v", err)
+	}
+	if _, err := svc.SetCategoryBilling(ctx, f.project.ID, f.grandchild.ID, &domain.Rate{HourlyRateCents: 3600, Currency: "USD"}, nil); err != nil {
+		t.Fatalf("set grandchild rate failed: %v", err)
+	}
+
+	day := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	f.seedStopped(t, f.root.ID, day, 3600, true)
+	f.seedStopped(t, f.root.ID, day.Add(2*time.Hour), 1800, false)
+	f.seedStopped(t, f.child.ID, day.Add(3*time.Hour), 1800, true)
+	f.seedStopped(t, f.grandchild.ID, day.Add(4*time.Hour), 600, true)
+	f.seedStopped(t, f.root.ID, day.Add(48*time.Hour), 3600, true) // outside range
+	if _, err := f.entries.Create(ctx, domain.TimeEntry{ID: uuid.New(), CategoryID: f.root.ID, StartedAt: day.Add(5 * time.Hour), Billable: true}); err != nil {
+		t.Fatalf("seed running failed: %v", err)
+	}
+
+	summary, err := svc.Summary(ctx, f.project.ID, day, day.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("summary failed: %v", err)
+	}
+	lines := map[uuid.UUID]domain.BillingLine{}
+	for _, l := range summary.Lines {
+		lines[l.CategoryID] = l
+	}
+	root := lines[f.root.ID]
+	if root.BillableSeconds != 3600 || root.NonBillableSeconds != 1800 || root.Amount == nil || root.Amount.Cents != 6000 {
+		t.Fatalf("unexpected root line: %+v", root)
+	}
+	if child := lines[f.child.ID]; child.Amount == nil || child.Amount.Cents != 3000 || child.Amount.Currency != "EUR" {
+		t.Fatalf("unexpected child line: %+v", child)
+	}
+	if gc := lines[f.grandchild.ID]; gc.Amount == nil || gc.Amount.Cents != 600 || gc.Amount.Currency != "USD" {
+		t.Fatalf("unexpected grandchild line: %+v", gc)
+	}
+	if len(summary.Totals) != 2 || summary.Totals[0] != (domain.Money{Cents: 9000, Currency: "EUR"}) || summary.Totals[1] != (domain.Money{Cents: 600, Currency: "USD"}) {
+		t.Fatalf("unexpected totals: %+v", summary.Totals)
+	}
+
+	if _, err := svc.Summary(ctx, f.project.ID, day, day.Add(-time.Hour)); err != ErrInvalidTimeRange {
+		t.Fatalf("expected ErrInvalidTimeRange, got %v", err)
+	}
+	if _, err := svc.Summary(ctx, uuid.New(), day, day); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestBillingServiceSummaryAppliesRounding(t *testing.T) {
+	ctx := context.Background()
+	f := newBillingFixture(t)
+	svc := f.service()
+	if _, err := svc.SetProjectRate(ctx, f.project.ID, &domain.Rate{HourlyRateCents: 6000, Currency: "EUR"}); err != nil {
+		t.Fatalf("set project rate failed: %v", err)
+	}
+	day := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	f.seedStopped(t, f.root.ID, day, 600, true)
+	f.seedStopped(t, f.root.ID, day.Add(time.Hour), 600, true)
+	f.seedStopped(t, f.root.ID, day.Add(24*time.Hour), 60, true)
+	f.seedStopped(t, f.root.ID, day.Add(2*time.Hour), 100, false)
+
+	summaryOf := func(rounding domain.Rounding) domain.BillingLine {
+		t.Helper()
+		if _, err := f.projects.UpdateSettings(ctx, f.project.ID, domain.ProjectSettings{Rounding: rounding}); err != nil {
+			t.Fatalf("update settings failed: %v", err)
+		}
+		summary, err := svc.Summary(ctx, f.project.ID, day, day.Add(48*time.Hour))
+		if err != nil {
+			t.Fatalf("summary failed: %v", err)
+		}
+		for _, l := range summary.Lines {
+			if l.CategoryID == f.root.ID {
+				return l
+			}
+		}
+		t.Fatalf("root line missing")
+		return domain.BillingLine{}
+	}
+
+	// Each entry rounds up to 15 minutes; raw seconds stay exact
+	line := summaryOf(domain.Rounding{IncrementSeconds: 900, Mode: domain.RoundUp, Scope: domain.RoundPerEntry})
+	if line.BillableSeconds != 1260 || line.RoundedBillableSeconds != 2700 || line.RoundedNonBillableSeconds != 900 || line.Amount.Cents != 4500 {
+		t.Fatalf("unexpected per-entry line: %+v", line)
+	}
+	// Per day: 1200s on the first day and 60s on the second
+	line = summaryOf(domain.Rounding{IncrementSeconds: 900, Mode: domain.RoundUp, Scope: domain.RoundPerDay})
+	if line.RoundedBillableSeconds != 2700 || line.Amount.Cents != 2700*6000/3600 {
+		t.Fatalf("unexpected per-day line: %+v", line)
+	}
+	line = summaryOf(domain.Rounding{IncrementSeconds: 900, Mode: domain.RoundDown, Scope: domain.RoundPerDay})
+	if line.RoundedBillableSeconds != 900 || line.Amount.Cents != 1500 || line.RoundedNonBillableSeconds != 0 {
+		t.Fatalf("unexpected rounded-down line: %+v", line)
+	}
+}
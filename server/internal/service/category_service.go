@@ -2,14 +2,19 @@ package service
 
 import (
 	"context"
+	"time"
 
+	"github.com/Gargair/clockwork/server/internal/clock"
 	"github.com/Gargair/clockwork/server/internal/domain"
 	"github.com/Gargair/clockwork/server/internal/repository"
 	"github.com/google/uuid"
 )
 
 type categoryService struct {
-	repo repository.CategoryRepository
+	repo  repository.CategoryRepository
+	audit auditLog
+	uow   repository.UnitOfWork
+	clk   clock.Clock
 }
 
 func (s *categoryService) Create(ctx context.Context, projectID uuid.UUID, name string, description *string, parentCategoryID *uuid.UUID) (domain.Category, error) {
@@ -32,8 +37,17 @@ func (s *categoryService) Create(ctx context.Context, projectID uuid.UUID, name
 		ParentCategoryID: parentCategoryID,
 		Name:             name,
 		Description:      description,
+		Billable:         true,
 	}
-	return s.repo.Create(ctx, c)
+	var created domain.Category
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.Create(ctx, c); err != nil {
+			return err
+		}
+		return s.audit.category(ctx, domain.AuditCreate, nil, &created)
+	})
+	return created, err
 }
 
 func (s *categoryService) Update(ctx context.Context, id uuid.UUID, name string, description *string, parentCategoryID *uuid.UUID) (domain.Category, error) {
@@ -67,25 +81,118 @@ func (s *categoryService) Update(ctx context.Context, id uuid.UUID, name string,
 		}
 	}
 
-	return s.repo.Update(ctx, id, name, description, parentCategoryID)
+	var updated domain.Category
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if updated, err = s.repo.Update(ctx, id, name, description, parentCategoryID); err != nil {
+			return err
+		}
+		return s.audit.category(ctx, domain.AuditUpdate, &current, &updated)
+	})
+	return updated, err
 }
 
 func (s *categoryService) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.repo.Delete(ctx, id)
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.category(ctx, domain.AuditDelete, &before, nil)
+	})
+}
+
+func (s *categoryService) Restore(ctx context.Context, id uuid.UUID) (domain.Category, error) {
+	var restored domain.Category
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if restored, err = s.repo.Restore(ctx, id); err != nil {
+			// The name may have been given to another category while this one was in the trash
+			if err == repository.ErrDuplicate {
+				return ErrDuplicateCategoryName
+			}
+			return err
+		}
+		return s.audit.category(ctx, domain.AuditRestore, nil, &restored)
+	})
+	return restored, err
 }
 
 func (s *categoryService) GetByID(ctx context.Context, id uuid.UUID) (domain.Category, error) {
 	return s.repo.GetByID(ctx, id)
 }
 
-func (s *categoryService) ListByProject(ctx context.Context, projectID uuid.UUID) ([]domain.Category, error) {
-	return s.repo.ListByProject(ctx, projectID)
+func (s *categoryService) ListByProject(ctx context.Context, projectID uuid.UUID, includeArchived bool, page repository.PageRequest) ([]domain.Category, error) {
+	return s.repo.ListByProject(ctx, projectID, includeArchived, page)
+}
+
+func (s *categoryService) Archive(ctx context.Context, id uuid.UUID) (domain.Category, error) {
+	return s.setArchived(ctx, id, true)
+}
+
+func (s *categoryService) Unarchive(ctx context.Context, id uuid.UUID) (domain.Category, error) {
+	return s.setArchived(ctx, id, false)
+}
+
+// setArchived archives or unarchives a category, recording the change as an update.
+func (s *categoryService) setArchived(ctx context.Context, id uuid.UUID, archived bool) (domain.Category, error) {
+	var updated domain.Category
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if updated, err = s.repo.SetArchived(ctx, id, archived); err != nil {
+			return err
+		}
+		return s.audit.category(ctx, domain.AuditUpdate, &before, &updated)
+	})
+	return updated, err
 }
 
 func (s *categoryService) ListChildren(ctx context.Context, parentID uuid.UUID) ([]domain.Category, error) {
 	return s.repo.ListChildren(ctx, parentID)
 }
 
+// Tree returns the root categories of the project; an unknown project has none. A category whose
+// parent is missing from the listing is treated as a root.
+func (s *categoryService) Tree(ctx context.Context, projectID uuid.UUID, start *time.Time, end *time.Time) ([]domain.CategoryNode, error) {
+	if start != nil && end != nil && start.After(*end) {
+		return nil, ErrInvalidTimeRange
+	}
+	totals, err := s.repo.TotalsByProject(ctx, projectID, start, end, s.clk.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[uuid.UUID]bool, len(totals))
+	for _, t := range totals {
+		known[t.Category.ID] = true
+	}
+	children := map[uuid.UUID][]domain.CategoryTotal{}
+	var roots []domain.CategoryTotal
+	for _, t := range totals {
+		if p := t.Category.ParentCategoryID; p != nil && known[*p] {
+			children[*p] = append(children[*p], t)
+		} else {
+			roots = append(roots, t)
+		}
+	}
+
+	var build func(ts []domain.CategoryTotal) []domain.CategoryNode
+	build = func(ts []domain.CategoryTotal) []domain.CategoryNode {
+		nodes := make([]domain.CategoryNode, 0, len(ts))
+		for _, t := range ts {
+			nodes = append(nodes, domain.CategoryNode{CategoryTotal: t, Children: build(children[t.Category.ID])})
+		}
+		return nodes
+	}
+	return build(roots), nil
+}
+
 // isDescendant checks whether candidateID is a descendant of rootID using BFS over ListChildren.
 func (s *categoryService) isDescendant(ctx context.Context, rootID uuid.UUID, candidateID uuid.UUID) (bool, error) {
 	visited := map[uuid.UUID]struct{}{}
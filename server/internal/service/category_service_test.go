@@ -3,6 +3,7 @@ package service
 import (
     "context"
     "testing"
+    "time"
 
     "github.com/Gargair/clockwork/server/internal/domain"
     "github.com/Gargair/clockwork/server/internal/repository"
@@ -11,7 +12,7 @@ import (
 
 func TestCategoryServiceCreateWithParentSameProjectSucceeds(t *testing.T) {
 	repo := newFakeCategoryRepo()
-	svc := NewCategoryService(repo)
+	svc := NewCategoryService(repo, nil, fakeUnitOfWork{}, nil)
 	ctx := context.Background()
 
 	projectID := uuid.New()
@@ -32,7 +33,7 @@ func TestCategoryServiceCreateWithParentSameProjectSucceeds(t *testing.T) {
 
 func TestCategoryServiceCreateCrossProjectParentErr(t *testing.T) {
 	repo := newFakeCategoryRepo()
-	svc := NewCategoryService(repo)
+	svc := NewCategoryService(repo, nil, fakeUnitOfWork{}, nil)
 	ctx := context.Background()
 
 	projectA := uuid.New()
@@ -52,7 +53,7 @@ func TestCategoryServiceCreateCrossProjectParentErr(t *testing.T) {
 
 func TestCategoryServiceUpdateParentToDescendantErrCycle(t *testing.T) {
 	repo := newFakeCategoryRepo()
-	svc := NewCategoryService(repo)
+	svc := NewCategoryService(repo, nil, fakeUnitOfWork{}, nil)
 	ctx := context.Background()
 
 	proj := uuid.New()
@@ -78,7 +79,7 @@ func TestCategoryServiceUpdateParentToDescendantErrCycle(t *testing.T) {
 
 func TestCategoryServiceUpdateNameDescriptionOnlySucceeds(t *testing.T) {
 	repo := newFakeCategoryRepo()
-	svc := NewCategoryService(repo)
+	svc := NewCategoryService(repo, nil, fakeUnitOfWork{}, nil)
 	ctx := context.Background()
 	proj := uuid.New()
 
@@ -96,6 +97,53 @@ func TestCategoryServiceUpdateNameDescriptionOnlySucceeds(t *testing.T) {
 	}
 }
 
+func TestCategoryServiceTreeNestsCategoriesWithTotals(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeCategoryRepo()
+	svc := NewCategoryService(repo, nil, fakeUnitOfWork{}, newTestClock(time.Now()))
+	projectID := uuid.New()
+	base := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	add := func(name string, parent *uuid.UUID, age int) domain.Category {
+		c := domain.Category{ID: uuid.New(), ProjectID: projectID, ParentCategoryID: parent, Name: name, CreatedAt: base.Add(time.Duration(age) * time.Minute)}
+		repo.items[c.ID] = c
+		return c
+	}
+	dev := add("Dev", nil, 0)
+	backend := add("Backend", &dev.ID, 1)
+	api := add("API", &backend.ID, 2)
+	ops := add("Ops", nil, 3)
+	orphan := add("Orphan", &uuid.Nil, 4)
+	repo.seconds = map[uuid.UUID]int64{dev.ID: 60, backend.ID: 600, api.ID: 3600, ops.ID: 30}
+
+	roots, err := svc.Tree(ctx, projectID, nil, nil)
+	if err != nil {
+		t.Fatalf("tree failed: %v", err)
+	}
+	if len(roots) != 3 || roots[0].Category.ID != dev.ID || roots[1].Category.ID != ops.ID || roots[2].Category.ID != orphan.ID {
+		t.Fatalf("unexpected roots: %+v", roots)
+	}
+	if roots[0].OwnSeconds != 60 || roots[0].SubtreeSeconds != 4260 || len(roots[0].Children) != 1 {
+		t.Fatalf("unexpected root node: %+v", roots[0])
+	}
+	mid := roots[0].Children[0]
+	if mid.Category.ID != backend.ID || mid.SubtreeSeconds != 4200 || len(mid.Children) != 1 || mid.Children[0].Category.ID != api.ID {
+		t.Fatalf("unexpected nested node: %+v", mid)
+	}
+	if leaf := mid.Children[0]; leaf.Children == nil || len(leaf.Children) != 0 || leaf.SubtreeSeconds != 3600 {
+		t.Fatalf("expected a leaf with an empty child list, got %+v", leaf)
+	}
+
+	empty, err := svc.Tree(ctx, uuid.New(), nil, nil)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("expected no nodes for an unknown project, got %+v (%v)", empty, err)
+	}
+	from := base
+	to := from.Add(-time.Hour)
+	if _, err := svc.Tree(ctx, projectID, &from, &to); err != ErrInvalidTimeRange {
+		t.Fatalf("expected ErrInvalidTimeRange, got %v", err)
+	}
+}
+
 // --- Error propagation and edge cases ---
 
 type stubCategoryRepo struct {
@@ -124,7 +172,7 @@ func (r stubCategoryRepo) GetByID(_ context.Context, id uuid.UUID) (domain.Categ
     }
     return domain.Category{}, repository.ErrNotFound
 }
-func (r stubCategoryRepo) ListByProject(context.Context, uuid.UUID) ([]domain.Category, error) {
+func (r stubCategoryRepo) ListByProject(context.Context, uuid.UUID, bool, repository.PageRequest) ([]domain.Category, error) {
     if r.listByProjectErr != nil {
         return nil, r.listByProjectErr
     }
@@ -139,11 +187,25 @@ func (r stubCategoryRepo) ListChildren(context.Context, uuid.UUID) ([]domain.Cat
 func (r stubCategoryRepo) Update(context.Context, uuid.UUID, string, *string, *uuid.UUID) (domain.Category, error) {
     return domain.Category{}, r.updateErr
 }
+func (r stubCategoryRepo) UpdateBilling(context.Context, uuid.UUID, *domain.Rate, bool) (domain.Category, error) {
+    return domain.Category{}, r.updateErr
+}
+func (r stubCategoryRepo) SetArchived(context.Context, uuid.UUID, bool) (domain.Category, error) {
+    return domain.Category{}, r.updateErr
+}
 func (r stubCategoryRepo) Delete(context.Context, uuid.UUID) error { return r.deleteErr }
+func (r stubCategoryRepo) ListDeleted(context.Context) ([]domain.Category, error) { return nil, nil }
+func (r stubCategoryRepo) Restore(context.Context, uuid.UUID) (domain.Category, error) {
+    return domain.Category{}, nil
+}
+func (r stubCategoryRepo) PurgeDeleted(context.Context, time.Time) (int64, error) { return 0, nil }
+func (r stubCategoryRepo) TotalsByProject(context.Context, uuid.UUID, *time.Time, *time.Time, time.Time) ([]domain.CategoryTotal, error) {
+    return nil, nil
+}
 
 func TestCategoryServiceCreateInvalidParentWhenMissing(t *testing.T) {
     missingParentID := uuid.New()
-    svc := NewCategoryService(stubCategoryRepo{errGetByID: map[uuid.UUID]error{missingParentID: repository.ErrNotFound}})
+    svc := NewCategoryService(stubCategoryRepo{errGetByID: map[uuid.UUID]error{missingParentID: repository.ErrNotFound}}, nil, fakeUnitOfWork{}, nil)
     if _, err := svc.Create(context.Background(), uuid.New(), "Child", nil, &missingParentID); err == nil || err != ErrInvalidParent {
         t.Fatalf("expected ErrInvalidParent, got %v", err)
     }
@@ -151,14 +213,14 @@ func TestCategoryServiceCreateInvalidParentWhenMissing(t *testing.T) {
 
 func TestCategoryServiceCreatePropagatesParentLookupError(t *testing.T) {
     parentID := uuid.New()
-    svc := NewCategoryService(stubCategoryRepo{errGetByID: map[uuid.UUID]error{parentID: repository.ErrDuplicate}})
+    svc := NewCategoryService(stubCategoryRepo{errGetByID: map[uuid.UUID]error{parentID: repository.ErrDuplicate}}, nil, fakeUnitOfWork{}, nil)
     if _, err := svc.Create(context.Background(), uuid.New(), "Child", nil, &parentID); err == nil || err != repository.ErrDuplicate {
         t.Fatalf("expected parent lookup error, got %v", err)
     }
 }
 
 func TestCategoryServiceCreatePropagatesCreateError(t *testing.T) {
-    svc := NewCategoryService(stubCategoryRepo{createErr: repository.ErrDuplicate})
+    svc := NewCategoryService(stubCategoryRepo{createErr: repository.ErrDuplicate}, nil, fakeUnitOfWork{}, nil)
     if _, err := svc.Create(context.Background(), uuid.New(), "Child", nil, nil); err == nil || err != repository.ErrDuplicate {
         t.Fatalf("expected createErr, got %v", err)
     }
@@ -166,7 +228,7 @@ func TestCategoryServiceCreatePropagatesCreateError(t *testing.T) {
 
 func TestCategoryServiceUpdatePropagatesGetCurrentError(t *testing.T) {
     id := uuid.New()
-    svc := NewCategoryService(stubCategoryRepo{errGetByID: map[uuid.UUID]error{id: repository.ErrNotFound}})
+    svc := NewCategoryService(stubCategoryRepo{errGetByID: map[uuid.UUID]error{id: repository.ErrNotFound}}, nil, fakeUnitOfWork{}, nil)
     if _, err := svc.Update(context.Background(), id, "X", nil, nil); err == nil || err != repository.ErrNotFound {
         t.Fatalf("expected ErrNotFound, got %v", err)
     }
@@ -176,7 +238,7 @@ func TestCategoryServiceUpdateInvalidParentWhenMissing(t *testing.T) {
     id := uuid.New()
     parentID := uuid.New()
     items := map[uuid.UUID]domain.Category{id: {ID: id, ProjectID: uuid.New(), Name: "cur"}}
-    svc := NewCategoryService(stubCategoryRepo{items: items, errGetByID: map[uuid.UUID]error{parentID: repository.ErrNotFound}})
+    svc := NewCategoryService(stubCategoryRepo{items: items, errGetByID: map[uuid.UUID]error{parentID: repository.ErrNotFound}}, nil, fakeUnitOfWork{}, nil)
     if _, err := svc.Update(context.Background(), id, "X", nil, &parentID); err == nil || err != ErrInvalidParent {
         t.Fatalf("expected ErrInvalidParent, got %v", err)
     }
@@ -187,7 +249,7 @@ func TestCategoryServiceUpdatePropagatesParentLookupError(t *testing.T) {
     parentID := uuid.New()
     proj := uuid.New()
     items := map[uuid.UUID]domain.Category{id: {ID: id, ProjectID: proj, Name: "cur"}}
-    svc := NewCategoryService(stubCategoryRepo{items: items, errGetByID: map[uuid.UUID]error{parentID: repository.ErrDuplicate}})
+    svc := NewCategoryService(stubCategoryRepo{items: items, errGetByID: map[uuid.UUID]error{parentID: repository.ErrDuplicate}}, nil, fakeUnitOfWork{}, nil)
     if _, err := svc.Update(context.Background(), id, "X", nil, &parentID); err == nil || err != repository.ErrDuplicate {
         t.Fatalf("expected parent lookup error, got %v", err)
     }
@@ -199,7 +261,7 @@ func TestCategoryServiceUpdatePropagatesListChildrenError(t *testing.T) {
     proj := uuid.New()
     // Set parent to same project, not self
     items := map[uuid.UUID]domain.Category{id: {ID: id, ProjectID: proj, Name: "cur"}, parentID: {ID: parentID, ProjectID: proj, Name: "par"}}
-    svc := NewCategoryService(stubCategoryRepo{items: items, listChildrenErr: repository.ErrDuplicate})
+    svc := NewCategoryService(stubCategoryRepo{items: items, listChildrenErr: repository.ErrDuplicate}, nil, fakeUnitOfWork{}, nil)
     if _, err := svc.Update(context.Background(), id, "X", nil, &parentID); err == nil || err != repository.ErrDuplicate {
         t.Fatalf("expected listChildren error, got %v", err)
     }
@@ -209,35 +271,35 @@ func TestCategoryServiceUpdatePropagatesUpdateError(t *testing.T) {
     id := uuid.New()
     proj := uuid.New()
     items := map[uuid.UUID]domain.Category{id: {ID: id, ProjectID: proj, Name: "cur"}}
-    svc := NewCategoryService(stubCategoryRepo{items: items, updateErr: repository.ErrDuplicate})
+    svc := NewCategoryService(stubCategoryRepo{items: items, updateErr: repository.ErrDuplicate}, nil, fakeUnitOfWork{}, nil)
     if _, err := svc.Update(context.Background(), id, "X", nil, nil); err == nil || err != repository.ErrDuplicate {
         t.Fatalf("expected updateErr, got %v", err)
     }
 }
 
 func TestCategoryServiceDeletePropagatesError(t *testing.T) {
-    svc := NewCategoryService(stubCategoryRepo{deleteErr: repository.ErrNotFound})
+    svc := NewCategoryService(stubCategoryRepo{deleteErr: repository.ErrNotFound}, nil, fakeUnitOfWork{}, nil)
     if err := svc.Delete(context.Background(), uuid.New()); err == nil || err != repository.ErrNotFound {
         t.Fatalf("expected deleteErr, got %v", err)
     }
 }
 
 func TestCategoryServiceGetByIDPropagatesError(t *testing.T) {
-    svc := NewCategoryService(stubCategoryRepo{errGetByID: map[uuid.UUID]error{uuid.Nil: repository.ErrNotFound}})
+    svc := NewCategoryService(stubCategoryRepo{errGetByID: map[uuid.UUID]error{uuid.Nil: repository.ErrNotFound}}, nil, fakeUnitOfWork{}, nil)
     if _, err := svc.GetByID(context.Background(), uuid.Nil); err == nil || err != repository.ErrNotFound {
         t.Fatalf("expected GetByID error, got %v", err)
     }
 }
 
 func TestCategoryServiceListByProjectPropagatesError(t *testing.T) {
-    svc := NewCategoryService(stubCategoryRepo{listByProjectErr: repository.ErrDuplicate})
-    if _, err := svc.ListByProject(context.Background(), uuid.New()); err == nil || err != repository.ErrDuplicate {
+    svc := NewCategoryService(stubCategoryRepo{listByProjectErr: repository.ErrDuplicate}, nil, fakeUnitOfWork{}, nil)
+    if _, err := svc.ListByProject(context.Background(), uuid.New(), false, repository.PageRequest{}); err == nil || err != repository.ErrDuplicate {
         t.Fatalf("expected ListByProject error, got %v", err)
     }
 }
 
 func TestCategoryServiceListChildrenPropagatesError(t *testing.T) {
-    svc := NewCategoryService(stubCategoryRepo{listChildrenErr: repository.ErrDuplicate})
+    svc := NewCategoryService(stubCategoryRepo{listChildrenErr: repository.ErrDuplicate}, nil, fakeUnitOfWork{}, nil)
     if _, err := svc.ListChildren(context.Background(), uuid.New()); err == nil || err != repository.ErrDuplicate {
         t.Fatalf("expected ListChildren error, got %v", err)
     }
@@ -7,3 +7,25 @@ var ErrCategoryCycle = errors.New("service: category cycle detected")
 var ErrCrossProjectParent = errors.New("service: parent category belongs to a different project")
 var ErrInvalidParent = errors.New("service: invalid parent category")
 var ErrInvalidProjectName = errors.New("service: project name cannot be empty")
+var ErrInvalidTimeRange = errors.New("service: start must be before stop")
+var ErrTimeEntryOverlap = errors.New("service: time entry overlaps an existing entry")
+var ErrActiveTimerExists = errors.New("service: another timer is already running")
+var ErrInvalidTagName = errors.New("service: tag name cannot be empty")
+var ErrDuplicateTagName = errors.New("service: tag name already exists in project")
+var ErrDuplicateCategoryName = errors.New("service: category name already exists in project")
+var ErrInvalidTag = errors.New("service: invalid tag")
+var ErrCrossProjectTag = errors.New("service: tag belongs to a different project")
+var ErrInvalidRate = errors.New("service: hourly rate must be non-negative with a 3-letter currency code")
+var ErrTimerPaused = errors.New("service: active timer is already paused")
+var ErrTimerNotPaused = errors.New("service: active timer is not paused")
+var ErrInvalidSplitTime = errors.New("service: split time must lie strictly within the entry")
+var ErrInvalidMerge = errors.New("service: merge needs at least two distinct entries and a non-negative gap tolerance")
+var ErrCrossCategoryMerge = errors.New("service: merged entries must belong to one category")
+var ErrEntriesNotContiguous = errors.New("service: entries are separated by more than the gap tolerance")
+var ErrInvalidRounding = errors.New("service: rounding needs an increment of 0 to 86400 seconds, a mode of up, down or nearest and a scope of entry or day")
+var ErrInvalidTarget = errors.New("service: target must be between 1 second and 24 hours")
+var ErrInvalidTimerTime = errors.New("service: timer time must not lie in the future or before the running entry's last start, pause or resume")
+var ErrNothingToContinue = errors.New("service: no stopped entry to continue")
+var ErrCategoryArchived = errors.New("service: category or its project is archived")
+var ErrInvalidGroupBy = errors.New("service: groupBy takes distinct groups of project, category, tag, day, week or month, at most one of them a period")
+var ErrInvalidTimezone = errors.New("service: unknown time zone")
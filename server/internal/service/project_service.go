@@ -10,7 +10,9 @@ import (
 )
 
 type projectService struct {
-	repo repository.ProjectRepository
+	repo  repository.ProjectRepository
+	audit auditLog
+	uow   repository.UnitOfWork
 }
 
 func (s *projectService) Create(ctx context.Context, name string, description *string) (domain.Project, error) {
@@ -23,7 +25,15 @@ func (s *projectService) Create(ctx context.Context, name string, description *s
 		Name:        trimmed,
 		Description: description,
 	}
-	return s.repo.Create(ctx, p)
+	var created domain.Project
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.Create(ctx, p); err != nil {
+			return err
+		}
+		return s.audit.project(ctx, domain.AuditCreate, nil, &created)
+	})
+	return created, err
 }
 
 func (s *projectService) Update(ctx context.Context, id uuid.UUID, name string, description *string) (domain.Project, error) {
@@ -31,19 +41,101 @@ func (s *projectService) Update(ctx context.Context, id uuid.UUID, name string,
 	if trimmed == "" {
 		return domain.Project{}, ErrInvalidProjectName
 	}
-	return s.repo.Update(ctx, id, trimmed, description)
+	var updated domain.Project
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if updated, err = s.repo.Update(ctx, id, trimmed, description); err != nil {
+			return err
+		}
+		return s.audit.project(ctx, domain.AuditUpdate, &before, &updated)
+	})
+	return updated, err
 }
 
 func (s *projectService) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.repo.Delete(ctx, id)
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.project(ctx, domain.AuditDelete, &before, nil)
+	})
+}
+
+func (s *projectService) Restore(ctx context.Context, id uuid.UUID) (domain.Project, error) {
+	var restored domain.Project
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if restored, err = s.repo.Restore(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.project(ctx, domain.AuditRestore, nil, &restored)
+	})
+	return restored, err
 }
 
 func (s *projectService) GetByID(ctx context.Context, id uuid.UUID) (domain.Project, error) {
 	return s.repo.GetByID(ctx, id)
 }
 
-func (s *projectService) List(ctx context.Context) ([]domain.Project, error) {
-	return s.repo.List(ctx)
+func (s *projectService) List(ctx context.Context, includeArchived bool, page repository.PageRequest) ([]domain.Project, error) {
+	return s.repo.List(ctx, includeArchived, page)
+}
+
+func (s *projectService) Archive(ctx context.Context, id uuid.UUID) (domain.Project, error) {
+	return s.setArchived(ctx, id, true)
+}
+
+func (s *projectService) Unarchive(ctx context.Context, id uuid.UUID) (domain.Project, error) {
+	return s.setArchived(ctx, id, false)
+}
+
+// setArchived archives or unarchives a project, recording the change as an update.
+func (s *projectService) setArchived(ctx context.Context, id uuid.UUID, archived bool) (domain.Project, error) {
+	var updated domain.Project
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if updated, err = s.repo.SetArchived(ctx, id, archived); err != nil {
+			return err
+		}
+		return s.audit.project(ctx, domain.AuditUpdate, &before, &updated)
+	})
+	return updated, err
+}
+
+func (s *projectService) UpdateSettings(ctx context.Context, id uuid.UUID, update ProjectSettingsUpdate) (domain.Project, error) {
+	var updated domain.Project
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		p, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		settings := p.Settings
+		if update.ConcurrentTimers != nil {
+			settings.ConcurrentTimers = *update.ConcurrentTimers
+		}
+		if update.Rounding != nil {
+			rounding, err := normalizeRounding(*update.Rounding)
+			if err != nil {
+				return err
+			}
+			settings.Rounding = rounding
+		}
+		if updated, err = s.repo.UpdateSettings(ctx, id, settings); err != nil {
+			return err
+		}
+		return s.audit.project(ctx, domain.AuditUpdate, &p, &updated)
+	})
+	return updated, err
 }
 
 var _ ProjectService = (*projectService)(nil)
@@ -3,6 +3,7 @@ package service
 import (
     "context"
     "testing"
+    "time"
 
     "github.com/Gargair/clockwork/server/internal/domain"
     "github.com/Gargair/clockwork/server/internal/repository"
@@ -10,7 +11,7 @@ import (
 )
 
 func TestProjectServiceRejectsEmptyNameOnCreate(t *testing.T) {
-	svc := NewProjectService(newFakeProjectRepo())
+	svc := NewProjectService(newFakeProjectRepo(), nil, fakeUnitOfWork{})
 	if _, err := svc.Create(context.Background(), "   ", nil); err == nil {
 		t.Fatalf("expected error for empty name, got nil")
 	}
@@ -18,7 +19,7 @@ func TestProjectServiceRejectsEmptyNameOnCreate(t *testing.T) {
 
 func TestProjectServiceCreateAndListGetByID(t *testing.T) {
 	repo := newFakeProjectRepo()
-	svc := NewProjectService(repo)
+	svc := NewProjectService(repo, nil, fakeUnitOfWork{})
 
 	desc := "test"
 	created, err := svc.Create(context.Background(), " Project A ", &desc)
@@ -43,7 +44,7 @@ func TestProjectServiceCreateAndListGetByID(t *testing.T) {
 		t.Fatalf("roundtrip mismatch: got %+v want %+v", got, created)
 	}
 
-	list, err := svc.List(context.Background())
+	list, err := svc.List(context.Background(), false, repository.PageRequest{})
 	if err != nil {
 		t.Fatalf("list failed: %v", err)
 	}
@@ -68,21 +69,35 @@ func (r stubProjectRepo) Create(context.Context, domain.Project) (domain.Project
 func (r stubProjectRepo) GetByID(context.Context, uuid.UUID) (domain.Project, error) {
     return domain.Project{}, r.getErr
 }
-func (r stubProjectRepo) List(context.Context) ([]domain.Project, error) { return nil, r.listErr }
+func (r stubProjectRepo) List(context.Context, bool, repository.PageRequest) ([]domain.Project, error) { return nil, r.listErr }
 func (r stubProjectRepo) Update(context.Context, uuid.UUID, string, *string) (domain.Project, error) {
     return domain.Project{}, r.updateErr
 }
+func (r stubProjectRepo) UpdateRate(context.Context, uuid.UUID, *domain.Rate) (domain.Project, error) {
+    return domain.Project{}, r.updateErr
+}
+func (r stubProjectRepo) UpdateSettings(context.Context, uuid.UUID, domain.ProjectSettings) (domain.Project, error) {
+    return domain.Project{}, r.updateErr
+}
+func (r stubProjectRepo) SetArchived(context.Context, uuid.UUID, bool) (domain.Project, error) {
+    return domain.Project{}, r.updateErr
+}
 func (r stubProjectRepo) Delete(context.Context, uuid.UUID) error { return r.deleteErr }
+func (r stubProjectRepo) ListDeleted(context.Context) ([]domain.Project, error) { return nil, nil }
+func (r stubProjectRepo) Restore(context.Context, uuid.UUID) (domain.Project, error) {
+    return domain.Project{}, nil
+}
+func (r stubProjectRepo) PurgeDeleted(context.Context, time.Time) (int64, error) { return 0, nil }
 
 func TestProjectServiceUpdateRejectsEmptyName(t *testing.T) {
-    svc := NewProjectService(newFakeProjectRepo())
+    svc := NewProjectService(newFakeProjectRepo(), nil, fakeUnitOfWork{})
     if _, err := svc.Update(context.Background(), uuid.New(), "   ", nil); err == nil || err != ErrInvalidProjectName {
         t.Fatalf("expected ErrInvalidProjectName, got %v", err)
     }
 }
 
 func TestProjectServiceCreatePropagatesRepoError(t *testing.T) {
-    svc := NewProjectService(stubProjectRepo{createErr: repository.ErrDuplicate})
+    svc := NewProjectService(stubProjectRepo{createErr: repository.ErrDuplicate}, nil, fakeUnitOfWork{})
     _, err := svc.Create(context.Background(), "Valid Name", nil)
     if err == nil || err != repository.ErrDuplicate {
         t.Fatalf("expected ErrDuplicate, got %v", err)
@@ -90,7 +105,7 @@ func TestProjectServiceCreatePropagatesRepoError(t *testing.T) {
 }
 
 func TestProjectServiceUpdatePropagatesRepoError(t *testing.T) {
-    svc := NewProjectService(stubProjectRepo{updateErr: repository.ErrNotFound})
+    svc := NewProjectService(stubProjectRepo{updateErr: repository.ErrNotFound}, nil, fakeUnitOfWork{})
     _, err := svc.Update(context.Background(), uuid.New(), "Valid Name", nil)
     if err == nil || err != repository.ErrNotFound {
         t.Fatalf("expected ErrNotFound, got %v", err)
@@ -98,22 +113,82 @@ func TestProjectServiceUpdatePropagatesRepoError(t *testing.T) {
 }
 
 func TestProjectServiceDeletePropagatesRepoError(t *testing.T) {
-    svc := NewProjectService(stubProjectRepo{deleteErr: repository.ErrNotFound})
+    svc := NewProjectService(stubProjectRepo{deleteErr: repository.ErrNotFound}, nil, fakeUnitOfWork{})
     if err := svc.Delete(context.Background(), uuid.New()); err == nil || err != repository.ErrNotFound {
         t.Fatalf("expected ErrNotFound, got %v", err)
     }
 }
 
 func TestProjectServiceGetByIDPropagatesRepoError(t *testing.T) {
-    svc := NewProjectService(stubProjectRepo{getErr: repository.ErrNotFound})
+    svc := NewProjectService(stubProjectRepo{getErr: repository.ErrNotFound}, nil, fakeUnitOfWork{})
     if _, err := svc.GetByID(context.Background(), uuid.New()); err == nil || err != repository.ErrNotFound {
         t.Fatalf("expected ErrNotFound, got %v", err)
     }
 }
 
 func TestProjectServiceListPropagatesRepoError(t *testing.T) {
-    svc := NewProjectService(stubProjectRepo{listErr: repository.ErrDuplicate})
-    if _, err := svc.List(context.Background()); err == nil || err != repository.ErrDuplicate {
+    svc := NewProjectService(stubProjectRepo{listErr: repository.ErrDuplicate}, nil, fakeUnitOfWork{})
+    if _, err := svc.List(context.Background(), false, repository.PageRequest{}); err == nil || err != repository.ErrDuplicate {
         t.Fatalf("expected listErr, got %v", err)
     }
 }
+
+func TestProjectServiceUpdateSettingsAppliesPartialUpdate(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeProjectRepo()
+	svc := NewProjectService(repo, nil, fakeUnitOfWork{})
+	p, _ := svc.Create(ctx, "Proj", nil)
+
+	on := true
+	updated, err := svc.UpdateSettings(ctx, p.ID, ProjectSettingsUpdate{ConcurrentTimers: &on})
+	if err != nil || !updated.Settings.ConcurrentTimers {
+		t.Fatalf("expected concurrent timers enabled, got %+v err=%v", updated.Settings, err)
+	}
+	updated, err = svc.UpdateSettings(ctx, p.ID, ProjectSettingsUpdate{})
+	if err != nil || !updated.Settings.ConcurrentTimers {
+		t.Fatalf("expected empty update to keep settings, got %+v err=%v", updated.Settings, err)
+	}
+	if _, err := svc.UpdateSettings(ctx, uuid.New(), ProjectSettingsUpdate{ConcurrentTimers: &on}); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestProjectServiceUpdateSettingsRounding(t *testing.T) {
+	ctx := context.Background()
+	svc := NewProjectService(newFakeProjectRepo(), nil, fakeUnitOfWork{})
+	p, _ := svc.Create(ctx, "Proj", nil)
+
+	updated, err := svc.UpdateSettings(ctx, p.ID, ProjectSettingsUpdate{Rounding: &domain.Rounding{IncrementSeconds: 900, Mode: domain.RoundUp}})
+	want := domain.Rounding{IncrementSeconds: 900, Mode: domain.RoundUp, Scope: domain.RoundPerEntry}
+	if err != nil || updated.Settings.Rounding != want {
+		t.Fatalf("expected %+v, got %+v err=%v", want, updated.Settings.Rounding, err)
+	}
+	on := true
+	updated, _ = svc.UpdateSettings(ctx, p.ID, ProjectSettingsUpdate{ConcurrentTimers: &on})
+	if updated.Settings.Rounding != want {
+		t.Fatalf("expected rounding kept, got %+v", updated.Settings.Rounding)
+	}
+	if _, err := svc.UpdateSettings(ctx, p.ID, ProjectSettingsUpdate{Rounding: &domain.Rounding{IncrementSeconds: 60, Scope: "month"}}); err != ErrInvalidRounding {
+		t.Fatalf("expected ErrInvalidRounding, got %v", err)
+	}
+}
+
+func TestProjectServiceListPages(t *testing.T) {
+	ctx := context.Background()
+	svc := NewProjectService(newFakeProjectRepo(), nil, fakeUnitOfWork{})
+	for _, name := range []string{"A", "B", "C"} {
+		if _, err := svc.Create(ctx, name, nil); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+	}
+	all, _ := svc.List(ctx, false, repository.PageRequest{})
+	first, err := svc.List(ctx, false, repository.PageRequest{Limit: 2})
+	if err != nil || len(first) != 2 || first[0].ID != all[0].ID {
+		t.Fatalf("unexpected first page: %+v %v", first, err)
+	}
+	last := first[len(first)-1]
+	rest, err := svc.List(ctx, false, repository.PageRequest{After: &repository.Cursor{At: last.CreatedAt, ID: last.ID}, Limit: 2})
+	if err != nil || len(rest) != 1 || rest[0].ID != all[2].ID {
+		t.Fatalf("expected the remaining project, got %+v %v", rest, err)
+	}
+}
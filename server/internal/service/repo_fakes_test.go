@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/Gargair/clockwork/server/internal/domain"
@@ -12,15 +14,17 @@ import (
 
 // In-memory ProjectRepository fake
 type fakeProjectRepo struct {
-	items map[uuid.UUID]domain.Project
+	items   map[uuid.UUID]domain.Project
+	deleted map[uuid.UUID]domain.Project
 }
 
 func newFakeProjectRepo() *fakeProjectRepo {
-	return &fakeProjectRepo{items: make(map[uuid.UUID]domain.Project)}
+	return &fakeProjectRepo{items: make(map[uuid.UUID]domain.Project), deleted: make(map[uuid.UUID]domain.Project)}
 }
 
 func (r *fakeProjectRepo) Create(ctx context.Context, project domain.Project) (domain.Project, error) {
 	now := time.Now().UTC()
+	project.Version = 1
 	project.CreatedAt = now
 	project.UpdatedAt = now
 	r.items[project.ID] = project
@@ -35,13 +39,36 @@ func (r *fakeProjectRepo) GetByID(ctx context.Context, id uuid.UUID) (domain.Pro
 	return p, nil
 }
 
-func (r *fakeProjectRepo) List(ctx context.Context) ([]domain.Project, error) {
+func (r *fakeProjectRepo) List(ctx context.Context, includeArchived bool, page repository.PageRequest) ([]domain.Project, error) {
 	out := make([]domain.Project, 0, len(r.items))
 	for _, p := range r.items {
-		out = append(out, p)
+		if includeArchived || p.ArchivedAt == nil {
+			out = append(out, p)
+		}
 	}
-	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
-	return out, nil
+	return pageByCreatedAt(out, func(p domain.Project) (time.Time, uuid.UUID) { return p.CreatedAt, p.ID }, page), nil
+}
+
+// pageByCreatedAt orders items by creation time and ID and applies page, mirroring the Postgres keyset listings.
+func pageByCreatedAt[T any](items []T, key func(T) (time.Time, uuid.UUID), page repository.PageRequest) []T {
+	before := func(at time.Time, id uuid.UUID, at2 time.Time, id2 uuid.UUID) bool {
+		return at.Before(at2) || (at.Equal(at2) && id.String() < id2.String())
+	}
+	sort.Slice(items, func(i, j int) bool {
+		ai, ii := key(items[i])
+		aj, ij := key(items[j])
+		return before(ai, ii, aj, ij)
+	})
+	if c := page.After; c != nil {
+		items = slices.DeleteFunc(items, func(it T) bool {
+			at, id := key(it)
+			return !before(c.At, c.ID, at, id)
+		})
+	}
+	if page.Limit > 0 && len(items) > page.Limit {
+		items = items[:page.Limit]
+	}
+	return items
 }
 
 func (r *fakeProjectRepo) Update(ctx context.Context, id uuid.UUID, name string, description *string) (domain.Project, error) {
@@ -49,32 +76,158 @@ func (r *fakeProjectRepo) Update(ctx context.Context, id uuid.UUID, name string,
 	if !ok {
 		return domain.Project{}, repository.ErrNotFound
 	}
+	if err := checkVersion(ctx, id, p.Version); err != nil {
+		return domain.Project{}, err
+	}
 	p.Name = name
 	p.Description = description
+	p.Version++
+	p.UpdatedAt = time.Now().UTC()
+	r.items[id] = p
+	return p, nil
+}
+
+func (r *fakeProjectRepo) UpdateRate(ctx context.Context, id uuid.UUID, rate *domain.Rate) (domain.Project, error) {
+	p, ok := r.items[id]
+	if !ok {
+		return domain.Project{}, repository.ErrNotFound
+	}
+	p.Rate = rate
+	p.Version++
+	p.UpdatedAt = time.Now().UTC()
+	r.items[id] = p
+	return p, nil
+}
+
+func (r *fakeProjectRepo) UpdateSettings(ctx context.Context, id uuid.UUID, settings domain.ProjectSettings) (domain.Project, error) {
+	p, ok := r.items[id]
+	if !ok {
+		return domain.Project{}, repository.ErrNotFound
+	}
+	if err := checkVersion(ctx, id, p.Version); err != nil {
+		return domain.Project{}, err
+	}
+	p.Settings = settings
+	p.Version++
+	p.UpdatedAt = time.Now().UTC()
+	r.items[id] = p
+	return p, nil
+}
+
+func (r *fakeProjectRepo) SetArchived(ctx context.Context, id uuid.UUID, archived bool) (domain.Project, error) {
+	p, ok := r.items[id]
+	if !ok {
+		return domain.Project{}, repository.ErrNotFound
+	}
+	if err := checkVersion(ctx, id, p.Version); err != nil {
+		return domain.Project{}, err
+	}
+	p.ArchivedAt = archivedAt(p.ArchivedAt, archived)
+	p.Version++
 	p.UpdatedAt = time.Now().UTC()
 	r.items[id] = p
 	return p, nil
 }
 
 func (r *fakeProjectRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	if _, ok := r.items[id]; !ok {
+	p, ok := r.items[id]
+	if !ok {
 		return repository.ErrNotFound
 	}
+	if err := checkVersion(ctx, id, p.Version); err != nil {
+		return err
+	}
 	delete(r.items, id)
+	p.DeletedAt = deletedNow()
+	p.Version++
+	r.deleted[id] = p
+	return nil
+}
+
+func (r *fakeProjectRepo) ListDeleted(ctx context.Context) ([]domain.Project, error) {
+	return listTrash(r.deleted, func(p domain.Project) time.Time { return *p.DeletedAt }), nil
+}
+
+func (r *fakeProjectRepo) Restore(ctx context.Context, id uuid.UUID) (domain.Project, error) {
+	p, ok := r.deleted[id]
+	if !ok {
+		return domain.Project{}, repository.ErrNotFound
+	}
+	delete(r.deleted, id)
+	p.DeletedAt = nil
+	p.Version++
+	r.items[id] = p
+	return p, nil
+}
+
+func (r *fakeProjectRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return purgeTrash(r.deleted, func(p domain.Project) time.Time { return *p.DeletedAt }, before), nil
+}
+
+// deletedNow stamps a row the fakes move to the trash. Unlike Postgres, the fakes trash and
+// restore single rows without their dependents.
+// archivedAt returns the archive time after archiving or unarchiving; archiving keeps an earlier time.
+func archivedAt(current *time.Time, archived bool) *time.Time {
+	switch {
+	case !archived:
+		return nil
+	case current != nil:
+		return current
+	}
+	now := time.Now().UTC()
+	return &now
+}
+
+func deletedNow() *time.Time {
+	now := time.Now().UTC()
+	return &now
+}
+
+// listTrash orders trashed rows most recently deleted first.
+func listTrash[T any](deleted map[uuid.UUID]T, deletedAt func(T) time.Time) []T {
+	out := make([]T, 0, len(deleted))
+	for _, row := range deleted {
+		out = append(out, row)
+	}
+	sort.Slice(out, func(i, j int) bool { return deletedAt(out[i]).After(deletedAt(out[j])) })
+	return out
+}
+
+// purgeTrash drops the rows deleted before the given time.
+func purgeTrash[T any](deleted map[uuid.UUID]T, deletedAt func(T) time.Time, before time.Time) int64 {
+	var n int64
+	for id, row := range deleted {
+		if deletedAt(row).Before(before) {
+			delete(deleted, id)
+			n++
+		}
+	}
+	return n
+}
+
+// checkVersion mirrors the conditional writes of the Postgres repositories.
+func checkVersion(ctx context.Context, id uuid.UUID, current int64) error {
+	if v, ok := repository.ExpectedVersion(ctx, id); ok && v != current {
+		return repository.ErrVersionMismatch
+	}
 	return nil
 }
 
 // In-memory CategoryRepository fake
 type fakeCategoryRepo struct {
-	items map[uuid.UUID]domain.Category
+	items   map[uuid.UUID]domain.Category
+	deleted map[uuid.UUID]domain.Category
+	// seconds is the worked time TotalsByProject reports in each category itself
+	seconds map[uuid.UUID]int64
 }
 
 func newFakeCategoryRepo() *fakeCategoryRepo {
-	return &fakeCategoryRepo{items: make(map[uuid.UUID]domain.Category)}
+	return &fakeCategoryRepo{items: make(map[uuid.UUID]domain.Category), deleted: make(map[uuid.UUID]domain.Category)}
 }
 
 func (r *fakeCategoryRepo) Create(ctx context.Context, category domain.Category) (domain.Category, error) {
 	now := time.Now().UTC()
+	category.Version = 1
 	category.CreatedAt = now
 	category.UpdatedAt = now
 	r.items[category.ID] = category
@@ -89,14 +242,14 @@ func (r *fakeCategoryRepo) GetByID(ctx context.Context, id uuid.UUID) (domain.Ca
 	return c, nil
 }
 
-func (r *fakeCategoryRepo) ListByProject(ctx context.Context, projectID uuid.UUID) ([]domain.Category, error) {
+func (r *fakeCategoryRepo) ListByProject(ctx context.Context, projectID uuid.UUID, includeArchived bool, page repository.PageRequest) ([]domain.Category, error) {
 	var out []domain.Category
 	for _, c := range r.items {
-		if c.ProjectID == projectID {
+		if c.ProjectID == projectID && (includeArchived || c.ArchivedAt == nil) {
 			out = append(out, c)
 		}
 	}
-	return out, nil
+	return pageByCreatedAt(out, func(c domain.Category) (time.Time, uuid.UUID) { return c.CreatedAt, c.ID }, page), nil
 }
 
 func (r *fakeCategoryRepo) ListChildren(ctx context.Context, parentID uuid.UUID) ([]domain.Category, error) {
@@ -114,33 +267,134 @@ func (r *fakeCategoryRepo) Update(ctx context.Context, id uuid.UUID, name string
 	if !ok {
 		return domain.Category{}, repository.ErrNotFound
 	}
+	if err := checkVersion(ctx, id, c.Version); err != nil {
+		return domain.Category{}, err
+	}
 	c.Name = name
 	c.Description = description
 	c.ParentCategoryID = parentCategoryID
+	c.Version++
+	c.UpdatedAt = time.Now().UTC()
+	r.items[id] = c
+	return c, nil
+}
+
+func (r *fakeCategoryRepo) UpdateBilling(ctx context.Context, id uuid.UUID, rate *domain.Rate, billable bool) (domain.Category, error) {
+	c, ok := r.items[id]
+	if !ok {
+		return domain.Category{}, repository.ErrNotFound
+	}
+	c.Rate = rate
+	c.Billable = billable
+	c.Version++
+	c.UpdatedAt = time.Now().UTC()
+	r.items[id] = c
+	return c, nil
+}
+
+func (r *fakeCategoryRepo) SetArchived(ctx context.Context, id uuid.UUID, archived bool) (domain.Category, error) {
+	c, ok := r.items[id]
+	if !ok {
+		return domain.Category{}, repository.ErrNotFound
+	}
+	if err := checkVersion(ctx, id, c.Version); err != nil {
+		return domain.Category{}, err
+	}
+	c.ArchivedAt = archivedAt(c.ArchivedAt, archived)
+	c.Version++
 	c.UpdatedAt = time.Now().UTC()
 	r.items[id] = c
 	return c, nil
 }
 
 func (r *fakeCategoryRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	if _, ok := r.items[id]; !ok {
+	c, ok := r.items[id]
+	if !ok {
 		return repository.ErrNotFound
 	}
+	if err := checkVersion(ctx, id, c.Version); err != nil {
+		return err
+	}
 	delete(r.items, id)
+	c.DeletedAt = deletedNow()
+	c.Version++
+	r.deleted[id] = c
 	return nil
 }
 
+func (r *fakeCategoryRepo) ListDeleted(ctx context.Context) ([]domain.Category, error) {
+	return listTrash(r.deleted, func(c domain.Category) time.Time { return *c.DeletedAt }), nil
+}
+
+func (r *fakeCategoryRepo) Restore(ctx context.Context, id uuid.UUID) (domain.Category, error) {
+	c, ok := r.deleted[id]
+	if !ok {
+		return domain.Category{}, repository.ErrNotFound
+	}
+	if c.ParentCategoryID != nil {
+		if _, ok := r.deleted[*c.ParentCategoryID]; ok {
+			return domain.Category{}, repository.ErrParentDeleted
+		}
+	}
+	for _, other := range r.items {
+		if other.ProjectID == c.ProjectID && other.Name == c.Name {
+			return domain.Category{}, repository.ErrDuplicate
+		}
+	}
+	delete(r.deleted, id)
+	c.DeletedAt = nil
+	c.Version++
+	r.items[id] = c
+	return c, nil
+}
+
+func (r *fakeCategoryRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return purgeTrash(r.deleted, func(c domain.Category) time.Time { return *c.DeletedAt }, before), nil
+}
+
+func (r *fakeCategoryRepo) TotalsByProject(ctx context.Context, projectID uuid.UUID, start *time.Time, end *time.Time, now time.Time) ([]domain.CategoryTotal, error) {
+	categories, _ := r.ListByProject(ctx, projectID, true, repository.PageRequest{})
+	var subtree func(id uuid.UUID) int64
+	subtree = func(id uuid.UUID) int64 {
+		sum := r.seconds[id]
+		for _, c := range categories {
+			if c.ParentCategoryID != nil && *c.ParentCategoryID == id {
+				sum += subtree(c.ID)
+			}
+		}
+		return sum
+	}
+	out := make([]domain.CategoryTotal, 0, len(categories))
+	for _, c := range categories {
+		out = append(out, domain.CategoryTotal{Category: c, OwnSeconds: r.seconds[c.ID], SubtreeSeconds: subtree(c.ID)})
+	}
+	return out, nil
+}
+
 // In-memory TimeEntryRepository fake
 type fakeTimeEntryRepo struct {
-	items map[uuid.UUID]domain.TimeEntry
+	items    map[uuid.UUID]domain.TimeEntry
+	deleted  map[uuid.UUID]domain.TimeEntry
+	tags     map[uuid.UUID][]uuid.UUID
+	segments map[uuid.UUID][]domain.TimeEntrySegment
+	// categories backs the project- and subtree-wide listings
+	categories *fakeCategoryRepo
+	// slotLocks records the categories whose timer slot was locked
+	slotLocks []uuid.UUID
 }
 
 func newFakeTimeEntryRepo() *fakeTimeEntryRepo {
-	return &fakeTimeEntryRepo{items: make(map[uuid.UUID]domain.TimeEntry)}
+	return &fakeTimeEntryRepo{
+		items:    make(map[uuid.UUID]domain.TimeEntry),
+		deleted:  make(map[uuid.UUID]domain.TimeEntry),
+		tags:     make(map[uuid.UUID][]uuid.UUID),
+		segments: make(map[uuid.UUID][]domain.TimeEntrySegment),
+	}
 }
 
 func (r *fakeTimeEntryRepo) Create(ctx context.Context, entry domain.TimeEntry) (domain.TimeEntry, error) {
 	now := time.Now().UTC()
+	entry.Version = 1
 	entry.CreatedAt = now
 	entry.UpdatedAt = now
 	r.items[entry.ID] = entry
@@ -155,10 +409,14 @@ func (r *fakeTimeEntryRepo) GetByID(ctx context.Context, id uuid.UUID) (domain.T
 	return e, nil
 }
 
-func (r *fakeTimeEntryRepo) ListByCategory(ctx context.Context, categoryID uuid.UUID) ([]domain.TimeEntry, error) {
+func (r *fakeTimeEntryRepo) GetForUpdate(ctx context.Context, id uuid.UUID) (domain.TimeEntry, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeTimeEntryRepo) ListByCategory(ctx context.Context, categoryID uuid.UUID, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
 	var out []domain.TimeEntry
 	for _, e := range r.items {
-		if e.CategoryID == categoryID {
+		if e.CategoryID == categoryID && r.matchesFilter(e, filter) {
 			out = append(out, e)
 		}
 	}
@@ -166,10 +424,10 @@ func (r *fakeTimeEntryRepo) ListByCategory(ctx context.Context, categoryID uuid.
 	return out, nil
 }
 
-func (r *fakeTimeEntryRepo) ListByCategoryAndRange(ctx context.Context, categoryID uuid.UUID, start time.Time, end time.Time) ([]domain.TimeEntry, error) {
+func (r *fakeTimeEntryRepo) ListByCategoryAndRange(ctx context.Context, categoryID uuid.UUID, start time.Time, end time.Time, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
 	var out []domain.TimeEntry
 	for _, e := range r.items {
-		if e.CategoryID == categoryID && !e.StartedAt.Before(start) && !e.StartedAt.After(end) {
+		if e.CategoryID == categoryID && inRange(e, &start, &end, filter.Range) && r.matchesFilter(e, filter) {
 			out = append(out, e)
 		}
 	}
@@ -177,6 +435,22 @@ func (r *fakeTimeEntryRepo) ListByCategoryAndRange(ctx context.Context, category
 	return out, nil
 }
 
+func (r *fakeTimeEntryRepo) ListOverlapping(ctx context.Context, start time.Time, end time.Time) ([]domain.TimeEntry, error) {
+	var out []domain.TimeEntry
+	for _, e := range r.items {
+		if e.StartedAt.Before(end) && (e.StoppedAt == nil || e.StoppedAt.After(start)) {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.Before(out[j].StartedAt) })
+	return out, nil
+}
+
+func (r *fakeTimeEntryRepo) LockTimerSlot(ctx context.Context, categoryIDs ...uuid.UUID) error {
+	r.slotLocks = append(r.slotLocks, categoryIDs...)
+	return nil
+}
+
 func (r *fakeTimeEntryRepo) FindActive(ctx context.Context) (*domain.TimeEntry, error) {
 	var candidates []domain.TimeEntry
 	for _, e := range r.items {
@@ -192,14 +466,354 @@ func (r *fakeTimeEntryRepo) FindActive(ctx context.Context) (*domain.TimeEntry,
 	return &e, nil
 }
 
-func (r *fakeTimeEntryRepo) Stop(ctx context.Context, id uuid.UUID, stoppedAt time.Time, durationSeconds *int32) (domain.TimeEntry, error) {
+func (r *fakeTimeEntryRepo) FindActiveByCategory(ctx context.Context, categoryID uuid.UUID) (*domain.TimeEntry, error) {
+	for _, e := range r.items {
+		if e.StoppedAt == nil && e.CategoryID == categoryID {
+			return &e, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeTimeEntryRepo) FindLastStopped(ctx context.Context) (*domain.TimeEntry, error) {
+	var last *domain.TimeEntry
+	for _, e := range r.items {
+		if e.StoppedAt != nil && !e.Break && (last == nil || e.StoppedAt.After(*last.StoppedAt)) {
+			e := e
+			last = &e
+		}
+	}
+	return last, nil
+}
+
+func (r *fakeTimeEntryRepo) ListActive(ctx context.Context) ([]domain.TimeEntry, error) {
+	var out []domain.TimeEntry
+	for _, e := range r.items {
+		if e.StoppedAt == nil {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.After(out[j].StartedAt) })
+	return out, nil
+}
+
+func (r *fakeTimeEntryRepo) Stop(ctx context.Context, id uuid.UUID, stoppedAt time.Time, durationSeconds *int32, note *string, autoStopped bool) (domain.TimeEntry, error) {
 	e, ok := r.items[id]
-	if !ok {
+	if !ok || e.StoppedAt != nil {
 		return domain.TimeEntry{}, repository.ErrNotFound
 	}
 	e.StoppedAt = &stoppedAt
 	e.DurationSeconds = durationSeconds
+	e.AutoStopped = autoStopped
+	if note != nil {
+		e.Note = note
+	}
+	e.Version++
 	e.UpdatedAt = time.Now().UTC()
 	r.items[id] = e
 	return e, nil
 }
+
+func (r *fakeTimeEntryRepo) Update(ctx context.Context, entry domain.TimeEntry) (domain.TimeEntry, error) {
+	e, ok := r.items[entry.ID]
+	if !ok {
+		return domain.TimeEntry{}, repository.ErrNotFound
+	}
+	if err := checkVersion(ctx, entry.ID, e.Version); err != nil {
+		return domain.TimeEntry{}, err
+	}
+	e.CategoryID = entry.CategoryID
+	e.StartedAt = entry.StartedAt
+	e.StoppedAt = entry.StoppedAt
+	e.DurationSeconds = entry.DurationSeconds
+	e.Note = entry.Note
+	e.Billable = entry.Billable
+	e.AutoStopped = entry.AutoStopped
+	e.Version++
+	e.UpdatedAt = time.Now().UTC()
+	r.items[entry.ID] = e
+	return e, nil
+}
+
+func (r *fakeTimeEntryRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	e, ok := r.items[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if err := checkVersion(ctx, id, e.Version); err != nil {
+		return err
+	}
+	r.trash(e)
+	return nil
+}
+
+func (r *fakeTimeEntryRepo) DeleteByCategoryAndRange(ctx context.Context, categoryID uuid.UUID, start time.Time, end time.Time) ([]domain.TimeEntry, error) {
+	out, _ := r.ListByCategoryAndRange(ctx, categoryID, start, end, repository.TimeEntryFilter{})
+	for i := range out {
+		out[i] = r.trash(out[i])
+	}
+	return out, nil
+}
+
+// trash moves e to the trash; like the Postgres rows, it keeps its tags and segments there.
+func (r *fakeTimeEntryRepo) trash(e domain.TimeEntry) domain.TimeEntry {
+	delete(r.items, e.ID)
+	e.DeletedAt = deletedNow()
+	e.Version++
+	r.deleted[e.ID] = e
+	return e
+}
+
+func (r *fakeTimeEntryRepo) ListDeleted(ctx context.Context) ([]domain.TimeEntry, error) {
+	return listTrash(r.deleted, func(e domain.TimeEntry) time.Time { return *e.DeletedAt }), nil
+}
+
+func (r *fakeTimeEntryRepo) Restore(ctx context.Context, id uuid.UUID) (domain.TimeEntry, error) {
+	e, ok := r.deleted[id]
+	if !ok {
+		return domain.TimeEntry{}, repository.ErrNotFound
+	}
+	if r.categories != nil {
+		if _, ok := r.categories.deleted[e.CategoryID]; ok {
+			return domain.TimeEntry{}, repository.ErrParentDeleted
+		}
+	}
+	delete(r.deleted, id)
+	e.DeletedAt = nil
+	e.Version++
+	r.items[id] = e
+	return e, nil
+}
+
+func (r *fakeTimeEntryRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return purgeTrash(r.deleted, func(e domain.TimeEntry) time.Time { return *e.DeletedAt }, before), nil
+}
+
+func (r *fakeTimeEntryRepo) SetTags(ctx context.Context, entryID uuid.UUID, tagIDs []uuid.UUID) error {
+	r.tags[entryID] = append([]uuid.UUID(nil), tagIDs...)
+	return nil
+}
+
+func (r *fakeTimeEntryRepo) ListTagIDs(ctx context.Context, entryIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	out := make(map[uuid.UUID][]uuid.UUID)
+	for _, id := range entryIDs {
+		if tags := r.tags[id]; len(tags) > 0 {
+			out[id] = append([]uuid.UUID(nil), tags...)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeTimeEntryRepo) CreateSegment(ctx context.Context, segment domain.TimeEntrySegment) (domain.TimeEntrySegment, error) {
+	r.segments[segment.TimeEntryID] = append(r.segments[segment.TimeEntryID], segment)
+	return segment, nil
+}
+
+func (r *fakeTimeEntryRepo) StopOpenSegment(ctx context.Context, entryID uuid.UUID, stoppedAt time.Time) error {
+	for i, seg := range r.segments[entryID] {
+		if seg.StoppedAt == nil {
+			r.segments[entryID][i].StoppedAt = &stoppedAt
+		}
+	}
+	return nil
+}
+
+func (r *fakeTimeEntryRepo) ListSegments(ctx context.Context, entryID uuid.UUID) ([]domain.TimeEntrySegment, error) {
+	return append([]domain.TimeEntrySegment(nil), r.segments[entryID]...), nil
+}
+
+func (r *fakeTimeEntryRepo) DeleteSegments(ctx context.Context, entryID uuid.UUID) error {
+	delete(r.segments, entryID)
+	return nil
+}
+
+func (r *fakeTimeEntryRepo) ListByProject(ctx context.Context, projectID uuid.UUID, start *time.Time, end *time.Time, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+	return r.listWithPath(func(c domain.Category, _ []uuid.UUID) bool { return c.ProjectID == projectID }, start, end, filter), nil
+}
+
+func (r *fakeTimeEntryRepo) ListByCategoryTree(ctx context.Context, categoryID uuid.UUID, start *time.Time, end *time.Time, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+	return r.listWithPath(func(_ domain.Category, ancestry []uuid.UUID) bool { return slices.Contains(ancestry, categoryID) }, start, end, filter), nil
+}
+
+// listWithPath returns the entries whose category matches, with category name and path filled.
+// ancestry lists the category's own ID followed by its ancestors.
+func (r *fakeTimeEntryRepo) listWithPath(match func(c domain.Category, ancestry []uuid.UUID) bool, start *time.Time, end *time.Time, filter repository.TimeEntryFilter) []domain.TimeEntry {
+	var out []domain.TimeEntry
+	for _, e := range r.items {
+		c, ok := r.categories.items[e.CategoryID]
+		if !ok {
+			continue
+		}
+		ancestry := []uuid.UUID{c.ID}
+		path := []string{c.Name}
+		for cur := c; cur.ParentCategoryID != nil; {
+			cur = r.categories.items[*cur.ParentCategoryID]
+			ancestry = append(ancestry, cur.ID)
+			path = append([]string{cur.Name}, path...)
+		}
+		if !match(c, ancestry) || !r.matchesFilter(e, filter) {
+			continue
+		}
+		if !inRange(e, start, end, filter.Range) {
+			continue
+		}
+		e.CategoryName = c.Name
+		e.CategoryPath = path
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.After(out[j].StartedAt) })
+	return out
+}
+
+// inRange mirrors the Postgres range conditions of both range modes; nil bounds are open.
+func inRange(e domain.TimeEntry, start *time.Time, end *time.Time, mode repository.RangeMode) bool {
+	if mode == repository.RangeOverlap {
+		return (end == nil || e.StartedAt.Before(*end)) && (start == nil || e.StoppedAt == nil || e.StoppedAt.After(*start))
+	}
+	return (start == nil || !e.StartedAt.Before(*start)) && (end == nil || !e.StartedAt.After(*end))
+}
+
+// matchesFilter mirrors the Postgres filter semantics of TimeEntryFilter.
+func (r *fakeTimeEntryRepo) matchesFilter(e domain.TimeEntry, filter repository.TimeEntryFilter) bool {
+	if filter.NoteContains != "" {
+		if e.Note == nil || !strings.Contains(strings.ToLower(*e.Note), strings.ToLower(filter.NoteContains)) {
+			return false
+		}
+	}
+	if filter.TagID != nil {
+		found := false
+		for _, t := range r.tags[e.ID] {
+			if t == *filter.TagID {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// In-memory TagRepository fake
+type fakeTagRepo struct {
+	items map[uuid.UUID]domain.Tag
+}
+
+func newFakeTagRepo() *fakeTagRepo {
+	return &fakeTagRepo{items: make(map[uuid.UUID]domain.Tag)}
+}
+
+func (r *fakeTagRepo) Create(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+	for _, t := range r.items {
+		if t.ProjectID == tag.ProjectID && t.Name == tag.Name {
+			return domain.Tag{}, repository.ErrDuplicate
+		}
+	}
+	now := time.Now().UTC()
+	tag.CreatedAt = now
+	tag.UpdatedAt = now
+	r.items[tag.ID] = tag
+	return tag, nil
+}
+
+func (r *fakeTagRepo) GetByID(ctx context.Context, id uuid.UUID) (domain.Tag, error) {
+	t, ok := r.items[id]
+	if !ok {
+		return domain.Tag{}, repository.ErrNotFound
+	}
+	return t, nil
+}
+
+func (r *fakeTagRepo) ListByProject(ctx context.Context, projectID uuid.UUID) ([]domain.Tag, error) {
+	var out []domain.Tag
+	for _, t := range r.items {
+		if t.ProjectID == projectID {
+			out = append(out, t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (r *fakeTagRepo) Rename(ctx context.Context, id uuid.UUID, name string) (domain.Tag, error) {
+	t, ok := r.items[id]
+	if !ok {
+		return domain.Tag{}, repository.ErrNotFound
+	}
+	for _, other := range r.items {
+		if other.ID != id && other.ProjectID == t.ProjectID && other.Name == name {
+			return domain.Tag{}, repository.ErrDuplicate
+		}
+	}
+	t.Name = name
+	t.UpdatedAt = time.Now().UTC()
+	r.items[id] = t
+	return t, nil
+}
+
+func (r *fakeTagRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, ok := r.items[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.items, id)
+	return nil
+}
+
+func (r *fakeTagRepo) TotalsByProject(ctx context.Context, projectID uuid.UUID, start *time.Time, end *time.Time, now time.Time) ([]domain.TagTotal, error) {
+	tags, _ := r.ListByProject(ctx, projectID)
+	out := make([]domain.TagTotal, 0, len(tags))
+	for _, t := range tags {
+		out = append(out, domain.TagTotal{TagID: t.ID, Name: t.Name})
+	}
+	return out, nil
+}
+
+// fakeUnitOfWork runs the work directly; the in-memory fakes have no transactions to join.
+type fakeUnitOfWork struct{}
+
+func (fakeUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+var _ repository.UnitOfWork = fakeUnitOfWork{}
+
+// In-memory AuditRepository fake keeping events in recording order
+type fakeAuditRepo struct {
+	events []domain.AuditEvent
+	// failCreate makes Create fail, to check that failing to record fails the mutation.
+	failCreate error
+}
+
+func (r *fakeAuditRepo) Create(ctx context.Context, event domain.AuditEvent) (domain.AuditEvent, error) {
+	if r.failCreate != nil {
+		return domain.AuditEvent{}, r.failCreate
+	}
+	event.CreatedAt = time.Now().UTC()
+	r.events = append(r.events, event)
+	return event, nil
+}
+
+func (r *fakeAuditRepo) List(ctx context.Context, filter repository.AuditFilter) ([]domain.AuditEvent, error) {
+	var out []domain.AuditEvent
+	for i := len(r.events) - 1; i >= 0; i-- {
+		e := r.events[i]
+		if (filter.EntityType == "" || e.EntityType == filter.EntityType) &&
+			(filter.EntityID == nil || e.EntityID == *filter.EntityID) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// ReportRepository fake recording the last query and answering with canned rows
+type fakeReportRepo struct {
+	last         repository.SummaryQuery
+	rows         []domain.ReportRow
+	total        int64
+	roundedTotal int64
+}
+
+func (r *fakeReportRepo) Summary(ctx context.Context, q repository.SummaryQuery) ([]domain.ReportRow, int64, int64, error) {
+	r.last = q
+	return r.rows, r.total, r.roundedTotal, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gargair/clockwork/server/internal/clock"
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+)
+
+type reportService struct {
+	repo         repository.ReportRepository
+	projectRepo  repository.ProjectRepository
+	categoryRepo repository.CategoryRepository
+	clk          clock.Clock
+}
+
+// Summary reports archived projects and categories like any other; only deleted entries are left out.
+func (s *reportService) Summary(ctx context.Context, start time.Time, end time.Time, opts SummaryOptions) (domain.SummaryReport, error) {
+	if start.After(end) {
+		return domain.SummaryReport{}, ErrInvalidTimeRange
+	}
+	if err := validateGroupBy(opts.GroupBy); err != nil {
+		return domain.SummaryReport{}, err
+	}
+	loc, err := loadTimezone(opts.Timezone)
+	if err != nil {
+		return domain.SummaryReport{}, err
+	}
+	if opts.ProjectID != nil {
+		if _, err := s.projectRepo.GetByID(ctx, *opts.ProjectID); err != nil {
+			return domain.SummaryReport{}, err
+		}
+	}
+	if opts.CategoryID != nil {
+		if _, err := s.categoryRepo.GetByID(ctx, *opts.CategoryID); err != nil {
+			return domain.SummaryReport{}, err
+		}
+	}
+
+	rows, total, roundedTotal, err := s.repo.Summary(ctx, repository.SummaryQuery{
+		Start:      start,
+		End:        end,
+		Now:        s.clk.Now(),
+		Timezone:   loc.String(),
+		GroupBy:    opts.GroupBy,
+		ProjectID:  opts.ProjectID,
+		CategoryID: opts.CategoryID,
+	})
+	if err != nil {
+		return domain.SummaryReport{}, err
+	}
+	for i := range rows {
+		if rows[i].Period != nil {
+			local := rows[i].Period.In(loc)
+			rows[i].Period = &local
+		}
+	}
+	if rows == nil {
+		rows = []domain.ReportRow{}
+	}
+	return domain.SummaryReport{
+		Start:               start,
+		End:                 end,
+		Location:            loc,
+		GroupBy:             opts.GroupBy,
+		Rows:                rows,
+		TotalSeconds:        total,
+		RoundedTotalSeconds: roundedTotal,
+	}, nil
+}
+
+// validateGroupBy accepts distinct known groups with at most one period among them.
+func validateGroupBy(groups []domain.ReportGroup) error {
+	seen := map[domain.ReportGroup]bool{}
+	periods := 0
+	for _, g := range groups {
+		switch g {
+		case domain.ReportByProject, domain.ReportByCategory, domain.ReportByTag,
+			domain.ReportByDay, domain.ReportByWeek, domain.ReportByMonth:
+		default:
+			return ErrInvalidGroupBy
+		}
+		if seen[g] {
+			return ErrInvalidGroupBy
+		}
+		seen[g] = true
+		if g.IsPeriod() {
+			periods++
+		}
+	}
+	if periods > 1 {
+		return ErrInvalidGroupBy
+	}
+	return nil
+}
+
+// loadTimezone resolves an IANA time zone name; an empty name means UTC. The process-dependent
+// "Local" zone is refused since the database could not resolve it the same way.
+func loadTimezone(name string) (*time.Location, error) {
+	if name == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
+
+var _ ReportService = (*reportService)(nil)
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+)
+
+func TestReportServiceSummaryValidatesOptions(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2025, 3, 24, 0, 0, 0, 0, time.UTC)
+	svc := NewReportService(&fakeReportRepo{}, newFakeProjectRepo(), newFakeCategoryRepo(), newTestClock(base))
+	missing := uuid.New()
+
+	cases := []struct {
+		name string
+		from time.Time
+		opts SummaryOptions
+		want error
+	}{
+		{"reversed range", base.Add(48 * time.Hour), SummaryOptions{}, ErrInvalidTimeRange},
+		{"unknown group", base, SummaryOptions{GroupBy: []domain.ReportGroup{"year"}}, ErrInvalidGroupBy},
+		{"duplicate group", base, SummaryOptions{GroupBy: []domain.ReportGroup{domain.ReportByTag, domain.ReportByTag}}, ErrInvalidGroupBy},
+		{"two periods", base, SummaryOptions{GroupBy: []domain.ReportGroup{domain.ReportByDay, domain.ReportByMonth}}, ErrInvalidGroupBy},
+		{"unknown timezone", base, SummaryOptions{Timezone: "Mars/Olympus"}, ErrInvalidTimezone},
+		{"local timezone", base, SummaryOptions{Timezone: "Local"}, ErrInvalidTimezone},
+		{"unknown project", base, SummaryOptions{ProjectID: &missing}, repository.ErrNotFound},
+		{"unknown category", base, SummaryOptions{CategoryID: &missing}, repository.ErrNotFound},
+	}
+	for _, tc := range cases {
+		if _, err := svc.Summary(ctx, tc.from, base.Add(24*time.Hour), tc.opts); err != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestReportServiceSummaryQueriesInTimezone(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2025, 3, 29, 23, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 31, 22, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 3, 30, 12, 0, 0, 0, time.UTC)
+	projectID := uuid.New()
+	day := time.Date(2025, 3, 29, 23, 0, 0, 0, time.UTC)
+	repo := &fakeReportRepo{
+		rows:         []domain.ReportRow{{ProjectID: &projectID, ProjectName: "P", Period: &day, Seconds: 3600, RoundedSeconds: 4500}},
+		total:        3600,
+		roundedTotal: 4500,
+	}
+	projRepo := newFakeProjectRepo()
+	seedProject(t, projRepo, projectID, false)
+	svc := NewReportService(repo, projRepo, newFakeCategoryRepo(), newTestClock(now))
+
+	groups := []domain.ReportGroup{domain.ReportByProject, domain.ReportByDay}
+	report, err := svc.Summary(ctx, from, to, SummaryOptions{Timezone: "Europe/Berlin", GroupBy: groups, ProjectID: &projectID})
+	if err != nil {
+		t.Fatalf("summary failed: %v", err)
+	}
+	q := repo.last
+	if q.Timezone != "Europe/Berlin" || !q.Now.Equal(now) || !q.Start.Equal(from) || !q.End.Equal(to) ||
+		len(q.GroupBy) != 2 || q.ProjectID == nil || *q.ProjectID != projectID || q.CategoryID != nil {
+		t.Fatalf("unexpected query: %+v", q)
+	}
+	if report.Location.String() != "Europe/Berlin" || report.TotalSeconds != 3600 || report.RoundedTotalSeconds != 4500 ||
+		len(report.Rows) != 1 || report.Rows[0].RoundedSeconds != 4500 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	period := report.Rows[0].Period
+	if period == nil || !period.Equal(day) || period.Location() != report.Location || period.Hour() != 0 {
+		t.Fatalf("expected the period at local midnight, got %v", period)
+	}
+
+	// No rows yield an empty list; an empty timezone means UTC
+	repo.rows, repo.total, repo.roundedTotal = nil, 0, 0
+	report, err = svc.Summary(ctx, from, to, SummaryOptions{})
+	if err != nil || report.Rows == nil || len(report.Rows) != 0 || report.Location != time.UTC || repo.last.Timezone != "UTC" {
+		t.Fatalf("unexpected empty report: %+v (%v)", report, err)
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+// maxRoundingIncrement caps the rounding increment at one day.
+const maxRoundingIncrement = 24 * 60 * 60
+
+// roundingResolver finds the rounding rules of a category's entries, which are its project's.
+type roundingResolver struct {
+	projectRepo  repository.ProjectRepository
+	categoryRepo repository.CategoryRepository
+}
+
+// resolve returns the rounding of the category's project. A non-nil cache memoizes results
+// per category across calls.
+func (r roundingResolver) resolve(ctx context.Context, categoryID uuid.UUID, cache map[uuid.UUID]domain.Rounding) (domain.Rounding, error) {
+	if rounding, ok := cache[categoryID]; ok {
+		return rounding, nil
+	}
+	category, err := r.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return domain.Rounding{}, err
+	}
+	project, err := r.projectRepo.GetByID(ctx, category.ProjectID)
+	if err != nil {
+		return domain.Rounding{}, err
+	}
+	if cache != nil {
+		cache[categoryID] = project.Settings.Rounding
+	}
+	return project.Settings.Rounding, nil
+}
+
+// roundedDuration returns the duration of a stopped entry as reported under rounding, or nil
+// for running entries. Per-day rounding leaves single entries exact.
+func roundedDuration(entry domain.TimeEntry, rounding domain.Rounding) *int32 {
+	if entry.DurationSeconds == nil {
+		return nil
+	}
+	seconds := *entry.DurationSeconds
+	if rounding.Scope != domain.RoundPerDay {
+		seconds = int32(rounding.Round(int64(seconds)))
+	}
+	return &seconds
+}
+
+// roundingUnit identifies the per-day rounding unit of an entry, as defined on domain.Rounding.
+type roundingUnit struct {
+	categoryID uuid.UUID
+	billable   bool
+	day        time.Time
+}
+
+// roundedUnits returns the rounded durations of the units rounding applies to among the stopped
+// entries (see domain.Rounding). Days are UTC days regardless of any time zone a caller reports
+// in, so that rounded totals do not depend on it.
+func roundedUnits(entries []domain.TimeEntry, rounding domain.Rounding) []int64 {
+	var units []int64
+	if rounding.Scope != domain.RoundPerDay {
+		for _, e := range entries {
+			if e.DurationSeconds != nil {
+				units = append(units, rounding.Round(int64(*e.DurationSeconds)))
+			}
+		}
+		return units
+	}
+	days := map[roundingUnit]int64{}
+	for _, e := range entries {
+		if e.DurationSeconds != nil {
+			days[roundingUnit{e.CategoryID, e.Billable, e.StartedAt.UTC().Truncate(24 * time.Hour)}] += int64(*e.DurationSeconds)
+		}
+	}
+	for _, seconds := range days {
+		units = append(units, rounding.Round(seconds))
+	}
+	return units
+}
+
+// normalizeRounding validates rounding rules and fills in the default mode and scope.
+func normalizeRounding(rounding domain.Rounding) (domain.Rounding, error) {
+	if rounding.IncrementSeconds < 0 || rounding.IncrementSeconds > maxRoundingIncrement {
+		return domain.Rounding{}, ErrInvalidRounding
+	}
+	switch rounding.Mode {
+	case "":
+		rounding.Mode = domain.RoundNearest
+	case domain.RoundUp, domain.RoundDown, domain.RoundNearest:
+	default:
+		return domain.Rounding{}, ErrInvalidRounding
+	}
+	switch rounding.Scope {
+	case "":
+		rounding.Scope = domain.RoundPerEntry
+	case domain.RoundPerEntry, domain.RoundPerDay:
+	default:
+		return domain.Rounding{}, ErrInvalidRounding
+	}
+	return rounding, nil
+}
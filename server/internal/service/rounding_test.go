@@ -0,0 +1,109 @@
+package service
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestRoundingRound(t *testing.T) {
+	cases := []struct {
+		rounding domain.Rounding
+		seconds  int64
+		want     int64
+	}{
+		{domain.Rounding{}, 1234, 1234},
+		{domain.Rounding{IncrementSeconds: 360, Mode: domain.RoundUp}, 361, 720},
+		{domain.Rounding{IncrementSeconds: 360, Mode: domain.RoundUp}, 360, 360},
+		{domain.Rounding{IncrementSeconds: 360, Mode: domain.RoundDown}, 719, 360},
+		{domain.Rounding{IncrementSeconds: 900, Mode: domain.RoundNearest}, 449, 0},
+		{domain.Rounding{IncrementSeconds: 900, Mode: domain.RoundNearest}, 450, 900},
+		{domain.Rounding{IncrementSeconds: 900, Mode: domain.RoundUp}, 0, 0},
+	}
+	for _, tc := range cases {
+		if got := tc.rounding.Round(tc.seconds); got != tc.want {
+			t.Fatalf("%+v.Round(%d) = %d, want %d", tc.rounding, tc.seconds, got, tc.want)
+		}
+	}
+}
+
+func TestRoundedUnitsPerEntryAndPerDay(t *testing.T) {
+	day := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	entry := func(start time.Time, seconds int32) domain.TimeEntry {
+		return domain.TimeEntry{StartedAt: start, DurationSeconds: &seconds}
+	}
+	entries := []domain.TimeEntry{
+		entry(day, 600),
+		entry(day.Add(2*time.Hour), 600),
+		entry(day.Add(24*time.Hour), 60),
+		{StartedAt: day.Add(26 * time.Hour)}, // running entries are skipped
+	}
+	sum := func(units []int64) (total int64) {
+		for _, u := range units {
+			total += u
+		}
+		return total
+	}
+
+	perEntry := domain.Rounding{IncrementSeconds: 900, Mode: domain.RoundUp, Scope: domain.RoundPerEntry}
+	if units := roundedUnits(entries, perEntry); len(units) != 3 || sum(units) != 2700 {
+		t.Fatalf("expected three entries rounded up to 15 minutes, got %v", units)
+	}
+	perDay := domain.Rounding{IncrementSeconds: 900, Mode: domain.RoundUp, Scope: domain.RoundPerDay}
+	if units := roundedUnits(entries, perDay); len(units) != 2 || sum(units) != 2700 {
+		t.Fatalf("expected two days of 1800s and 900s, got %v", units)
+	}
+	perDay.Mode = domain.RoundNearest
+	if units := roundedUnits(entries, perDay); sum(units) != 900 {
+		t.Fatalf("expected 1200s to round to 900s and 60s to 0s, got %v", units)
+	}
+	// Each category's billable and non-billable time of a day are units of their own
+	entries[1].CategoryID = uuid.New()
+	entries[2].StartedAt, entries[2].Billable = day, true
+	if units := roundedUnits(entries, perDay); len(units) != 3 || sum(units) != 1800 {
+		t.Fatalf("expected three units of 600s, 600s and 60s on one day, got %v", units)
+	}
+}
+
+func TestRoundedUnitsPerDayCutsUTCDays(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	entry := func(start time.Time, seconds int32) domain.TimeEntry {
+		return domain.TimeEntry{StartedAt: start, DurationSeconds: &seconds}
+	}
+	// 23:30 and 00:30 Berlin time fall on different local days but on the same UTC day,
+	// while 01:30 Berlin time is the next UTC day
+	entries := []domain.TimeEntry{
+		entry(time.Date(2025, 11, 3, 23, 30, 0, 0, berlin), 600),
+		entry(time.Date(2025, 11, 4, 0, 30, 0, 0, berlin), 600),
+		entry(time.Date(2025, 11, 4, 1, 30, 0, 0, berlin), 600),
+	}
+	perDay := domain.Rounding{IncrementSeconds: 900, Mode: domain.RoundUp, Scope: domain.RoundPerDay}
+	units := roundedUnits(entries, perDay)
+	sort.Slice(units, func(i, j int) bool { return units[i] < units[j] })
+	if len(units) != 2 || units[0] != 900 || units[1] != 1800 {
+		t.Fatalf("expected UTC days of 600s and 1200s rounded up to 900s and 1800s, got %v", units)
+	}
+}
+
+func TestNormalizeRounding(t *testing.T) {
+	got, err := normalizeRounding(domain.Rounding{IncrementSeconds: 360})
+	if err != nil || got.Mode != domain.RoundNearest || got.Scope != domain.RoundPerEntry {
+		t.Fatalf("expected defaults for mode and scope, got %+v err=%v", got, err)
+	}
+	for _, r := range []domain.Rounding{
+		{IncrementSeconds: -1},
+		{IncrementSeconds: maxRoundingIncrement + 1},
+		{IncrementSeconds: 360, Mode: "sideways"},
+		{IncrementSeconds: 360, Scope: "week"},
+	} {
+		if _, err := normalizeRounding(r); err != ErrInvalidRounding {
+			t.Fatalf("expected ErrInvalidRounding for %+v, got %v", r, err)
+		}
+	}
+}
@@ -14,41 +14,242 @@ import (
 type ProjectService interface {
 	Create(ctx context.Context, name string, description *string) (domain.Project, error)
 	Update(ctx context.Context, id uuid.UUID, name string, description *string) (domain.Project, error)
+	// Delete moves the project to the trash together with its categories and their entries.
 	Delete(ctx context.Context, id uuid.UUID) error
+	// Restore takes the project out of the trash together with everything deleted with it.
+	Restore(ctx context.Context, id uuid.UUID) (domain.Project, error)
 	GetByID(ctx context.Context, id uuid.UUID) (domain.Project, error)
-	List(ctx context.Context) ([]domain.Project, error)
+	// List returns projects oldest first, archived ones only with includeArchived; page selects a slice of them.
+	List(ctx context.Context, includeArchived bool, page repository.PageRequest) ([]domain.Project, error)
+	UpdateSettings(ctx context.Context, id uuid.UUID, update ProjectSettingsUpdate) (domain.Project, error)
+	// Archive hides the project from listings and stops timers from starting in its categories.
+	// Its entries keep counting in reports.
+	Archive(ctx context.Context, id uuid.UUID) (domain.Project, error)
+	Unarchive(ctx context.Context, id uuid.UUID) (domain.Project, error)
+}
+
+// ProjectSettingsUpdate describes a partial edit of project settings. Nil fields are left unchanged.
+type ProjectSettingsUpdate struct {
+	ConcurrentTimers *bool
+	// Rounding replaces the rounding rules as a whole; an empty mode or scope takes its default.
+	Rounding *domain.Rounding
 }
 
 // CategoryService defines category-related operations and invariants.
 type CategoryService interface {
 	Create(ctx context.Context, projectID uuid.UUID, name string, description *string, parentCategoryID *uuid.UUID) (domain.Category, error)
 	Update(ctx context.Context, id uuid.UUID, name string, description *string, parentCategoryID *uuid.UUID) (domain.Category, error)
+	// Delete moves the category to the trash together with its descendants and their entries.
 	Delete(ctx context.Context, id uuid.UUID) error
+	// Restore takes the category out of the trash together with everything deleted with it.
+	// The project and parent category must not be in the trash.
+	Restore(ctx context.Context, id uuid.UUID) (domain.Category, error)
 	GetByID(ctx context.Context, id uuid.UUID) (domain.Category, error)
-	ListByProject(ctx context.Context, projectID uuid.UUID) ([]domain.Category, error)
+	// ListByProject returns the project's categories oldest first, archived ones only with
+	// includeArchived; page selects a slice of them.
+	ListByProject(ctx context.Context, projectID uuid.UUID, includeArchived bool, page repository.PageRequest) ([]domain.Category, error)
 	ListChildren(ctx context.Context, parentID uuid.UUID) ([]domain.Category, error)
+	// Archive hides the category from listings and stops timers from starting in it.
+	// Its entries keep counting in reports.
+	Archive(ctx context.Context, id uuid.UUID) (domain.Category, error)
+	Unarchive(ctx context.Context, id uuid.UUID) (domain.Category, error)
+	// Tree nests the project's categories, archived ones included, under their parents with the
+	// time worked inside the optional bounds [start, end) in each node and in its subtree.
+	Tree(ctx context.Context, projectID uuid.UUID, start *time.Time, end *time.Time) ([]domain.CategoryNode, error)
+}
+
+// TagService defines operations on project-scoped tags.
+type TagService interface {
+	Create(ctx context.Context, projectID uuid.UUID, name string) (domain.Tag, error)
+	Rename(ctx context.Context, id uuid.UUID, name string) (domain.Tag, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (domain.Tag, error)
+	ListByProject(ctx context.Context, projectID uuid.UUID) ([]domain.Tag, error)
+	Totals(ctx context.Context, projectID uuid.UUID, start *time.Time, end *time.Time) ([]domain.TagTotal, error)
+}
+
+// BillingService manages hourly rates and computes billable amounts.
+type BillingService interface {
+	SetProjectRate(ctx context.Context, projectID uuid.UUID, rate *domain.Rate) (domain.Project, error)
+	SetCategoryBilling(ctx context.Context, projectID uuid.UUID, categoryID uuid.UUID, rate *domain.Rate, billable *bool) (domain.Category, error)
+	EffectiveRate(ctx context.Context, categoryID uuid.UUID) (*domain.Rate, error)
+	EntryAmount(ctx context.Context, entry domain.TimeEntry) (*domain.Money, error)
+	Summary(ctx context.Context, projectID uuid.UUID, start time.Time, end time.Time) (domain.BillingSummary, error)
 }
 
 // TimeTrackingService defines timer operations and invariants.
+// Running entries of projects with concurrent timers exclude only entries of the same category;
+// all other running entries share a single timer.
 type TimeTrackingService interface {
-	Start(ctx context.Context, categoryID uuid.UUID) (domain.TimeEntry, error)
-	StopActive(ctx context.Context) (domain.TimeEntry, error)
+	Start(ctx context.Context, categoryID uuid.UUID, opts StartOptions) (domain.TimeEntry, error)
+	// Continue starts a new entry in the category of entryID, or of the most recently stopped entry
+	// when entryID is nil, carrying over its note and tags.
+	Continue(ctx context.Context, entryID *uuid.UUID) (domain.TimeEntry, error)
+	StopActive(ctx context.Context, opts StopOptions) (domain.TimeEntry, error)
+	StopActiveInCategory(ctx context.Context, categoryID uuid.UUID, opts StopOptions) (domain.TimeEntry, error)
+	// Pause suspends a running entry and Resume continues it. Both lock the entry, so concurrent
+	// calls fail with ErrTimerPaused or ErrTimerNotPaused instead of recording the pause twice.
+	Pause(ctx context.Context, target TimerTarget) (domain.TimeEntry, error)
+	Resume(ctx context.Context, target TimerTarget) (domain.TimeEntry, error)
+	CreateManual(ctx context.Context, categoryID uuid.UUID, startedAt time.Time, stoppedAt time.Time) (domain.TimeEntry, error)
+	UpdateEntry(ctx context.Context, id uuid.UUID, update TimeEntryUpdate) (domain.TimeEntry, error)
+	Split(ctx context.Context, id uuid.UUID, at time.Time, categoryID *uuid.UUID) (domain.TimeEntry, domain.TimeEntry, error)
+	Merge(ctx context.Context, ids []uuid.UUID, gapTolerance time.Duration) (domain.TimeEntry, error)
+	DeleteEntry(ctx context.Context, id uuid.UUID) error
+	DeleteEntries(ctx context.Context, categoryID uuid.UUID, start time.Time, end time.Time, dryRun bool) ([]domain.TimeEntry, error)
+	// RestoreEntry takes an entry out of the trash; its category must not be in the trash.
+	RestoreEntry(ctx context.Context, id uuid.UUID) (domain.TimeEntry, error)
+	GetEntry(ctx context.Context, id uuid.UUID) (domain.TimeEntry, error)
 	GetActive(ctx context.Context) (*domain.TimeEntry, error)
-	ListByCategory(ctx context.Context, categoryID uuid.UUID) ([]domain.TimeEntry, error)
-	ListByCategoryAndRange(ctx context.Context, categoryID uuid.UUID, start time.Time, end time.Time) ([]domain.TimeEntry, error)
+	GetActiveInCategory(ctx context.Context, categoryID uuid.UUID) (*domain.TimeEntry, error)
+	ListActive(ctx context.Context) ([]domain.TimeEntry, error)
+	ReapRunaway(ctx context.Context, policy ReaperPolicy) ([]domain.TimeEntry, error)
+	StopReachedTargets(ctx context.Context) ([]domain.TimeEntry, error)
+	ListByCategory(ctx context.Context, categoryID uuid.UUID, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error)
+	ListByCategoryAndRange(ctx context.Context, categoryID uuid.UUID, start time.Time, end time.Time, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error)
+	ListByProject(ctx context.Context, projectID uuid.UUID, start *time.Time, end *time.Time, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error)
+	ListByCategoryTree(ctx context.Context, categoryID uuid.UUID, start *time.Time, end *time.Time, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error)
+}
+
+// AuditService reads the audit log of project, category and time entry mutations.
+type AuditService interface {
+	// List returns matching events, most recent first.
+	List(ctx context.Context, filter repository.AuditFilter) ([]domain.AuditEvent, error)
+}
+
+// TrashService lists and purges deleted projects, categories and time entries.
+type TrashService interface {
+	// List returns everything in the trash, most recently deleted first.
+	List(ctx context.Context) (domain.Trash, error)
+	// Purge removes what was deleted more than retention ago for good.
+	Purge(ctx context.Context, retention time.Duration) (domain.PurgeResult, error)
+}
+
+// ReportService computes reports over tracked time.
+type ReportService interface {
+	// Summary totals the worked time inside [start, end) per combination of opts.GroupBy.
+	// Running entries count up to now.
+	Summary(ctx context.Context, start time.Time, end time.Time, opts SummaryOptions) (domain.SummaryReport, error)
+}
+
+// SummaryOptions narrows and groups a summary report. An empty Timezone means UTC and nil
+// filters apply no restriction. GroupBy may combine distinct groups, at most one of them a period.
+type SummaryOptions struct {
+	Timezone   string
+	GroupBy    []domain.ReportGroup
+	ProjectID  *uuid.UUID
+	CategoryID *uuid.UUID
+}
+
+// StartOptions carries optional attributes for a newly started entry.
+// A nil Billable defaults to the category's billable flag; breaks are never billable.
+// A non-nil TargetSeconds has the entry stopped once it has worked that long, pauses excluded.
+// A non-nil StartedAt backdates the entry; whatever runs in its timer slot is stopped at that instant.
+type StartOptions struct {
+	Note          *string
+	TagIDs        []uuid.UUID
+	Billable      *bool
+	TargetSeconds *int32
+	Break         bool
+	StartedAt     *time.Time
+}
+
+// StopOptions carries optional attributes applied when stopping the active entry.
+// A nil Note keeps the note set at start; a nil StoppedAt stops the entry now.
+type StopOptions struct {
+	Note      *string
+	StoppedAt *time.Time
+}
+
+// TimerTarget selects the running entry a pause or resume acts on: EntryID when set, else the
+// running entry of CategoryID when set, else the most recently started running entry.
+type TimerTarget struct {
+	EntryID    *uuid.UUID
+	CategoryID *uuid.UUID
+}
+
+// ReaperPolicy configures the automatic stopping of forgotten timers.
+// Entries running for at least MaxDuration are stopped at startedAt+MaxDuration or, when
+// WorkdayEnd is set, at the first end of workday after startedAt if that comes earlier.
+type ReaperPolicy struct {
+	MaxDuration time.Duration
+	// WorkdayEnd is the local time of day at which the workday ends, as an offset from midnight.
+	// Nil disables the end-of-workday cap; zero ends the workday at midnight.
+	WorkdayEnd *time.Duration
+	// Location is the time zone of WorkdayEnd; nil means UTC.
+	Location *time.Location
 }
 
+// TimeEntryUpdate describes a partial edit of a time entry. Nil fields are left unchanged.
+// ClearStoppedAt turns a stopped entry back into a running one and takes precedence over StoppedAt.
+// Changing any timestamp discards the entry's pauses and clears its auto-stopped mark.
+// An empty Note removes the note. A nil TagIDs keeps the tags; a non-nil (possibly empty) slice replaces them.
+type TimeEntryUpdate struct {
+	CategoryID     *uuid.UUID
+	StartedAt      *time.Time
+	StoppedAt      *time.Time
+	ClearStoppedAt bool
+	Note           *string
+	TagIDs         []uuid.UUID
+	Billable       *bool
+}
+
+// The constructors of services that mutate projects, categories or time entries take the audit
+// repository their changes are recorded in; a nil audit repository records nothing.
+
 // NewProjectService constructs a ProjectService.
-func NewProjectService(repo repository.ProjectRepository) ProjectService {
-	return &projectService{repo: repo}
+func NewProjectService(repo repository.ProjectRepository, audit repository.AuditRepository, uow repository.UnitOfWork) ProjectService {
+	return &projectService{repo: repo, audit: auditLog{repo: audit}, uow: uow}
 }
 
 // NewCategoryService constructs a CategoryService.
-func NewCategoryService(repo repository.CategoryRepository) CategoryService {
-	return &categoryService{repo: repo}
+func NewCategoryService(repo repository.CategoryRepository, audit repository.AuditRepository, uow repository.UnitOfWork, clk clock.Clock) CategoryService {
+	return &categoryService{repo: repo, audit: auditLog{repo: audit}, uow: uow, clk: clk}
+}
+
+// NewTagService constructs a TagService.
+func NewTagService(repo repository.TagRepository, clk clock.Clock) TagService {
+	return &tagService{repo: repo, clk: clk}
+}
+
+// NewBillingService constructs a BillingService.
+func NewBillingService(projectRepo repository.ProjectRepository, categoryRepo repository.CategoryRepository, timeRepo repository.TimeEntryRepository, audit repository.AuditRepository, uow repository.UnitOfWork) BillingService {
+	return &billingService{
+		projectRepo:  projectRepo,
+		categoryRepo: categoryRepo,
+		timeRepo:     timeRepo,
+		audit:        auditLog{repo: audit},
+		uow:          uow,
+		rates:        rateResolver{projectRepo: projectRepo, categoryRepo: categoryRepo},
+	}
 }
 
 // NewTimeTrackingService constructs a TimeTrackingService.
-func NewTimeTrackingService(repo repository.TimeEntryRepository, categoryRepo repository.CategoryRepository, clk clock.Clock) TimeTrackingService {
-	return &timeTrackingService{repo: repo, categoryRepo: categoryRepo, clk: clk}
+func NewTimeTrackingService(repo repository.TimeEntryRepository, categoryRepo repository.CategoryRepository, tagRepo repository.TagRepository, projectRepo repository.ProjectRepository, audit repository.AuditRepository, uow repository.UnitOfWork, clk clock.Clock) TimeTrackingService {
+	return &timeTrackingService{
+		repo:         repo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		projectRepo:  projectRepo,
+		audit:        auditLog{repo: audit},
+		uow:          uow,
+		rates:        rateResolver{projectRepo: projectRepo, categoryRepo: categoryRepo},
+		rounding:     roundingResolver{projectRepo: projectRepo, categoryRepo: categoryRepo},
+		clk:          clk,
+	}
+}
+
+// NewTrashService constructs a TrashService.
+func NewTrashService(projectRepo repository.ProjectRepository, categoryRepo repository.CategoryRepository, timeRepo repository.TimeEntryRepository, uow repository.UnitOfWork, clk clock.Clock) TrashService {
+	return &trashService{projectRepo: projectRepo, categoryRepo: categoryRepo, timeRepo: timeRepo, uow: uow, clk: clk}
+}
+
+// NewReportService constructs a ReportService.
+func NewReportService(repo repository.ReportRepository, projectRepo repository.ProjectRepository, categoryRepo repository.CategoryRepository, clk clock.Clock) ReportService {
+	return &reportService{repo: repo, projectRepo: projectRepo, categoryRepo: categoryRepo, clk: clk}
+}
+
+// NewAuditService constructs an AuditService.
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return &auditService{repo: repo}
 }
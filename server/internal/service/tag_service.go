@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Gargair/clockwork/server/internal/clock"
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+type tagService struct {
+	repo repository.TagRepository
+	clk  clock.Clock
+}
+
+func (s *tagService) Create(ctx context.Context, projectID uuid.UUID, name string) (domain.Tag, error) {
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return domain.Tag{}, ErrInvalidTagName
+	}
+	t := domain.Tag{
+		ID:        uuid.New(),
+		ProjectID: projectID,
+		Name:      trimmed,
+	}
+	created, err := s.repo.Create(ctx, t)
+	if err == repository.ErrDuplicate {
+		return domain.Tag{}, ErrDuplicateTagName
+	}
+	return created, err
+}
+
+func (s *tagService) Rename(ctx context.Context, id uuid.UUID, name string) (domain.Tag, error) {
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return domain.Tag{}, ErrInvalidTagName
+	}
+	renamed, err := s.repo.Rename(ctx, id, trimmed)
+	if err == repository.ErrDuplicate {
+		return domain.Tag{}, ErrDuplicateTagName
+	}
+	return renamed, err
+}
+
+// Delete removes a tag; entries keep existing but lose the tag.
+func (s *tagService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *tagService) GetByID(ctx context.Context, id uuid.UUID) (domain.Tag, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *tagService) ListByProject(ctx context.Context, projectID uuid.UUID) ([]domain.Tag, error) {
+	return s.repo.ListByProject(ctx, projectID)
+}
+
+func (s *tagService) Totals(ctx context.Context, projectID uuid.UUID, start *time.Time, end *time.Time) ([]domain.TagTotal, error) {
+	if start != nil && end != nil && start.After(*end) {
+		return nil, ErrInvalidTimeRange
+	}
+	return s.repo.TotalsByProject(ctx, projectID, start, end, s.clk.Now())
+}
+
+var _ TagService = (*tagService)(nil)
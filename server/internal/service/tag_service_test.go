@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Gargair/clockwork/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+func TestTagServiceCreateTrimsAndRejectsInvalidNames(t *testing.T) {
+	ctx := context.Background()
+	svc := NewTagService(newFakeTagRepo(), newTestClock(time.Now().UTC()))
+	projectID := uuid.New()
+
+	tag, err := svc.Create(ctx, projectID, "  billing  ")
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if tag.Name != "billing" || tag.ProjectID != projectID {
+		t.Fatalf("unexpected tag: %+v", tag)
+	}
+
+	if _, err := svc.Create(ctx, projectID, "   "); err != ErrInvalidTagName {
+		t.Fatalf("expected ErrInvalidTagName, got %v", err)
+	}
+	if _, err := svc.Create(ctx, projectID, "billing"); err != ErrDuplicateTagName {
+		t.Fatalf("expected ErrDuplicateTagName, got %v", err)
+	}
+	// The same name is allowed in another project
+	if _, err := svc.Create(ctx, uuid.New(), "billing"); err != nil {
+		t.Fatalf("create in other project failed: %v", err)
+	}
+}
+
+func TestTagServiceRenameAndDelete(t *testing.T) {
+	ctx := context.Background()
+	svc := NewTagService(newFakeTagRepo(), newTestClock(time.Now().UTC()))
+	projectID := uuid.New()
+
+	a, _ := svc.Create(ctx, projectID, "a")
+	if _, err := svc.Create(ctx, projectID, "b"); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	renamed, err := svc.Rename(ctx, a.ID, " c ")
+	if err != nil {
+		t.Fatalf("rename failed: %v", err)
+	}
+	if renamed.Name != "c" {
+		t.Fatalf("expected renamed to c, got %q", renamed.Name)
+	}
+	if _, err := svc.Rename(ctx, a.ID, "b"); err != ErrDuplicateTagName {
+		t.Fatalf("expected ErrDuplicateTagName, got %v", err)
+	}
+	if _, err := svc.Rename(ctx, a.ID, ""); err != ErrInvalidTagName {
+		t.Fatalf("expected ErrInvalidTagName, got %v", err)
+	}
+	if _, err := svc.Rename(ctx, uuid.New(), "x"); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := svc.Delete(ctx, a.ID); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	tags, err := svc.ListByProject(ctx, projectID)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(tags) != 1 || tags[0].Name != "b" {
+		t.Fatalf("expected only b left, got %+v", tags)
+	}
+}
+
+func TestTagServiceTotalsRejectsInvertedRange(t *testing.T) {
+	ctx := context.Background()
+	svc := NewTagService(newFakeTagRepo(), newTestClock(time.Now().UTC()))
+	from := time.Date(2025, 11, 2, 10, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+	if _, err := svc.Totals(ctx, uuid.New(), &from, &to); err != ErrInvalidTimeRange {
+		t.Fatalf("expected ErrInvalidTimeRange, got %v", err)
+	}
+}
@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/Gargair/clockwork/server/internal/clock"
@@ -13,69 +16,1497 @@ import (
 type timeTrackingService struct {
 	repo         repository.TimeEntryRepository
 	categoryRepo repository.CategoryRepository
+	tagRepo      repository.TagRepository
+	projectRepo  repository.ProjectRepository
+	audit        auditLog
+	uow          repository.UnitOfWork
+	rates        rateResolver
+	rounding     roundingResolver
 	clk          clock.Clock
 }
 
-func (s *timeTrackingService) Start(ctx context.Context, categoryID uuid.UUID) (domain.TimeEntry, error) {
-	// Ensure category exists
-	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+// Start runs inside a unit of work so that concurrent starts cannot leave two running
+// entries in one timer slot; the loser fails with repository.ErrRunningEntryConflict.
+func (s *timeTrackingService) Start(ctx context.Context, categoryID uuid.UUID, opts StartOptions) (domain.TimeEntry, error) {
+	var created domain.TimeEntry
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.start(ctx, categoryID, opts)
+		return err
+	})
+	return created, err
+}
+
+// Continue starts a new entry like the given or the last stopped work entry in one unit of work,
+// auto-stopping whatever runs in the timer slot just like Start.
+func (s *timeTrackingService) Continue(ctx context.Context, entryID *uuid.UUID) (domain.TimeEntry, error) {
+	var created domain.TimeEntry
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var source domain.TimeEntry
+		if entryID != nil {
+			e, err := s.repo.GetByID(ctx, *entryID)
+			if err != nil {
+				return err
+			}
+			source = e
+		} else {
+			last, err := s.repo.FindLastStopped(ctx)
+			if err != nil {
+				return err
+			}
+			if last == nil {
+				return ErrNothingToContinue
+			}
+			source = *last
+		}
+		tags, err := s.repo.ListTagIDs(ctx, []uuid.UUID{source.ID})
+		if err != nil {
+			return err
+		}
+		created, err = s.start(ctx, source.CategoryID, StartOptions{Note: source.Note, TagIDs: tags[source.ID]})
+		return err
+	})
+	return created, err
+}
+
+func (s *timeTrackingService) start(ctx context.Context, categoryID uuid.UUID, opts StartOptions) (domain.TimeEntry, error) {
+	// Ensure category exists and takes new timers
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	if category.ArchivedAt != nil {
+		return domain.TimeEntry{}, ErrCategoryArchived
+	}
+	project, err := s.projectRepo.GetByID(ctx, category.ProjectID)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	if project.ArchivedAt != nil {
+		return domain.TimeEntry{}, ErrCategoryArchived
+	}
+	tagIDs, err := s.validateTags(ctx, category.ProjectID, opts.TagIDs)
+	if err != nil {
 		return domain.TimeEntry{}, err
 	}
+	if t := opts.TargetSeconds; t != nil && (*t <= 0 || *t > maxTargetSeconds) {
+		return domain.TimeEntry{}, ErrInvalidTarget
+	}
 
 	now := s.clk.Now()
+	startedAt := now
+	if opts.StartedAt != nil {
+		startedAt = opts.StartedAt.UTC()
+		if startedAt.After(now) {
+			return domain.TimeEntry{}, ErrInvalidTimerTime
+		}
+		// A backdated entry must not overlap finished entries of its timer slot; the slot lock
+		// keeps a concurrent create or edit from passing the same check
+		if err := s.repo.LockTimerSlot(ctx, categoryID); err != nil {
+			return domain.TimeEntry{}, err
+		}
+		overlapping, err := s.repo.ListOverlapping(ctx, startedAt, now)
+		if err != nil {
+			return domain.TimeEntry{}, err
+		}
+		var finished []domain.TimeEntry
+		for _, e := range overlapping {
+			if e.StoppedAt != nil {
+				finished = append(finished, e)
+			}
+		}
+		conflicts, err := s.inSlot(ctx, finished, categoryID, uuid.Nil)
+		if err != nil {
+			return domain.TimeEntry{}, err
+		}
+		if len(conflicts) > 0 {
+			return domain.TimeEntry{}, ErrTimeEntryOverlap
+		}
+	}
 
-	// If an active entry exists, stop it using the same timestamp and computed duration
-	active, err := s.repo.FindActive(ctx)
+	// Stop whatever runs in the new entry's timer slot at the new entry's start
+	running, err := s.runningInSlot(ctx, categoryID, uuid.Nil)
 	if err != nil {
 		return domain.TimeEntry{}, err
 	}
-	if active != nil {
-		durationSeconds := int32(now.Sub(active.StartedAt).Seconds())
-		if durationSeconds < 0 {
-			durationSeconds = 0
+	for _, candidate := range running {
+		active, err := s.lockRunning(ctx, candidate.ID)
+		if err != nil {
+			return domain.TimeEntry{}, err
+		}
+		if active == nil {
+			continue
+		}
+		// Only a backdated start can fall before the running entry's last start, pause or resume
+		if opts.StartedAt != nil {
+			if err := s.checkStopTime(ctx, *active, startedAt, now); err != nil {
+				return domain.TimeEntry{}, err
+			}
 		}
-		if _, err := s.repo.Stop(ctx, active.ID, now, &durationSeconds); err != nil {
+		if _, err := s.stopEntry(ctx, *active, startedAt, nil, false); err != nil {
 			return domain.TimeEntry{}, err
 		}
 	}
 
+	billable := category.Billable
+	if opts.Billable != nil {
+		billable = *opts.Billable
+	}
+	if opts.Break {
+		billable = false
+	}
+
 	// Create new active entry
 	entry := domain.TimeEntry{
 		ID:              uuid.New(),
 		CategoryID:      categoryID,
-		StartedAt:       now,
+		StartedAt:       startedAt,
 		StoppedAt:       nil,
 		DurationSeconds: nil,
+		Note:            normalizeNote(opts.Note),
+		Billable:        billable,
+		TargetSeconds:   opts.TargetSeconds,
+		Break:           opts.Break,
+	}
+	created, err := s.repo.Create(ctx, entry)
+	if err != nil {
+		return domain.TimeEntry{}, err
 	}
-	return s.repo.Create(ctx, entry)
+	if len(tagIDs) > 0 {
+		if err := s.repo.SetTags(ctx, created.ID, tagIDs); err != nil {
+			return domain.TimeEntry{}, err
+		}
+	}
+	created.TagIDs = tagIDs
+	if err := s.audit.entry(ctx, domain.AuditCreate, nil, &created); err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return created, nil
 }
 
-func (s *timeTrackingService) StopActive(ctx context.Context) (domain.TimeEntry, error) {
-	active, err := s.repo.FindActive(ctx)
+func (s *timeTrackingService) StopActive(ctx context.Context, opts StopOptions) (domain.TimeEntry, error) {
+	var stopped domain.TimeEntry
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		active, err := s.repo.FindActive(ctx)
+		if err != nil {
+			return err
+		}
+		if active == nil {
+			return ErrNoActiveTimer
+		}
+		if active, err = s.lockRunning(ctx, active.ID); err != nil {
+			return err
+		}
+		if active == nil {
+			return ErrNoActiveTimer
+		}
+		stoppedAt, err := s.stopTime(ctx, *active, opts)
+		if err != nil {
+			return err
+		}
+		stopped, err = s.stopEntry(ctx, *active, stoppedAt, normalizeNote(opts.Note), false)
+		return err
+	})
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return s.annotateOne(ctx, stopped)
+}
+
+// StopActiveInCategory stops the running entry of a category.
+func (s *timeTrackingService) StopActiveInCategory(ctx context.Context, categoryID uuid.UUID, opts StopOptions) (domain.TimeEntry, error) {
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return domain.TimeEntry{}, err
+	}
+	var stopped domain.TimeEntry
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		active, err := s.repo.FindActiveByCategory(ctx, categoryID)
+		if err != nil {
+			return err
+		}
+		if active == nil {
+			return ErrNoActiveTimer
+		}
+		if active, err = s.lockRunning(ctx, active.ID); err != nil {
+			return err
+		}
+		if active == nil {
+			return ErrNoActiveTimer
+		}
+		stoppedAt, err := s.stopTime(ctx, *active, opts)
+		if err != nil {
+			return err
+		}
+		stopped, err = s.stopEntry(ctx, *active, stoppedAt, normalizeNote(opts.Note), false)
+		return err
+	})
 	if err != nil {
 		return domain.TimeEntry{}, err
 	}
-	if active == nil {
+	return s.annotateOne(ctx, stopped)
+}
+
+// stopTime returns the instant at which a user stop ends entry: opts.StoppedAt when given, now otherwise.
+func (s *timeTrackingService) stopTime(ctx context.Context, entry domain.TimeEntry, opts StopOptions) (time.Time, error) {
+	now := s.clk.Now()
+	if opts.StoppedAt == nil {
+		return now, nil
+	}
+	at := opts.StoppedAt.UTC()
+	if err := s.checkStopTime(ctx, entry, at, now); err != nil {
+		return time.Time{}, err
+	}
+	return at, nil
+}
+
+// checkStopTime fails with ErrInvalidTimerTime unless the running entry can end at at: after its
+// start, not before its last pause or resume and not after now.
+func (s *timeTrackingService) checkStopTime(ctx context.Context, entry domain.TimeEntry, at time.Time, now time.Time) error {
+	if !at.After(entry.StartedAt) || at.After(now) {
+		return ErrInvalidTimerTime
+	}
+	segments, err := s.repo.ListSegments(ctx, entry.ID)
+	if err != nil {
+		return err
+	}
+	for _, seg := range segments {
+		if at.Before(seg.StartedAt) || (seg.StoppedAt != nil && at.Before(*seg.StoppedAt)) {
+			return ErrInvalidTimerTime
+		}
+	}
+	return nil
+}
+
+// Pause suspends a running entry. The entry stays active; paused time is excluded from its duration.
+func (s *timeTrackingService) Pause(ctx context.Context, target TimerTarget) (domain.TimeEntry, error) {
+	var paused domain.TimeEntry
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		active, err := s.lockTarget(ctx, target)
+		if err != nil {
+			return err
+		}
+		segments, err := s.repo.ListSegments(ctx, active.ID)
+		if err != nil {
+			return err
+		}
+		if isPaused(segments) {
+			return ErrTimerPaused
+		}
+		now := s.clk.Now()
+		if len(segments) == 0 {
+			// First pause: the time since start becomes the entry's first segment
+			if _, err := s.repo.CreateSegment(ctx, domain.TimeEntrySegment{
+				ID:          uuid.New(),
+				TimeEntryID: active.ID,
+				StartedAt:   active.StartedAt,
+				StoppedAt:   &now,
+			}); err != nil {
+				return err
+			}
+		} else if err := s.repo.StopOpenSegment(ctx, active.ID, now); err != nil {
+			return err
+		}
+		paused = active
+		return s.audit.entry(ctx, domain.AuditPause, &active, &paused)
+	})
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return s.annotateOne(ctx, paused)
+}
+
+// Resume continues a paused running entry by opening a new segment.
+func (s *timeTrackingService) Resume(ctx context.Context, target TimerTarget) (domain.TimeEntry, error) {
+	var resumed domain.TimeEntry
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		active, err := s.lockTarget(ctx, target)
+		if err != nil {
+			return err
+		}
+		segments, err := s.repo.ListSegments(ctx, active.ID)
+		if err != nil {
+			return err
+		}
+		if !isPaused(segments) {
+			return ErrTimerNotPaused
+		}
+		if _, err := s.repo.CreateSegment(ctx, domain.TimeEntrySegment{
+			ID:          uuid.New(),
+			TimeEntryID: active.ID,
+			StartedAt:   s.clk.Now(),
+		}); err != nil {
+			// The unique index on open segments caught a resume that slipped past the lock
+			if err == repository.ErrDuplicate {
+				return ErrTimerNotPaused
+			}
+			return err
+		}
+		resumed = active
+		return s.audit.entry(ctx, domain.AuditResume, &active, &resumed)
+	})
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return s.annotateOne(ctx, resumed)
+}
+
+// lockTarget resolves target to a running entry and locks it until the unit of work ends. It fails
+// with ErrNoActiveTimer when nothing runs there, also when the entry was stopped in the meantime,
+// and with repository.ErrNotFound for an unknown entry or category.
+func (s *timeTrackingService) lockTarget(ctx context.Context, target TimerTarget) (domain.TimeEntry, error) {
+	var id uuid.UUID
+	switch {
+	case target.EntryID != nil:
+		id = *target.EntryID
+	case target.CategoryID != nil:
+		if _, err := s.categoryRepo.GetByID(ctx, *target.CategoryID); err != nil {
+			return domain.TimeEntry{}, err
+		}
+		active, err := s.repo.FindActiveByCategory(ctx, *target.CategoryID)
+		if err != nil {
+			return domain.TimeEntry{}, err
+		}
+		if active == nil {
+			return domain.TimeEntry{}, ErrNoActiveTimer
+		}
+		id = active.ID
+	default:
+		active, err := s.repo.FindActive(ctx)
+		if err != nil {
+			return domain.TimeEntry{}, err
+		}
+		if active == nil {
+			return domain.TimeEntry{}, ErrNoActiveTimer
+		}
+		id = active.ID
+	}
+	if target.EntryID != nil {
+		// An unknown entry is not found rather than idle
+		if _, err := s.repo.GetByID(ctx, id); err != nil {
+			return domain.TimeEntry{}, err
+		}
+	}
+	entry, err := s.lockRunning(ctx, id)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	if entry == nil {
 		return domain.TimeEntry{}, ErrNoActiveTimer
 	}
+	return *entry, nil
+}
+
+// lockRunning re-reads a running entry and locks it until the unit of work ends, so that it is
+// stopped, paused or resumed by one caller at a time. It returns nil when the entry was stopped
+// or deleted in the meantime.
+func (s *timeTrackingService) lockRunning(ctx context.Context, id uuid.UUID) (*domain.TimeEntry, error) {
+	entry, err := s.repo.GetForUpdate(ctx, id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if entry.StoppedAt != nil {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+// ReapRunaway auto-stops running entries that have been running for at least policy.MaxDuration
+// and returns them. Paused time counts towards the age. A zero MaxDuration disables reaping.
+func (s *timeTrackingService) ReapRunaway(ctx context.Context, policy ReaperPolicy) ([]domain.TimeEntry, error) {
+	if policy.MaxDuration <= 0 {
+		return nil, nil
+	}
+	running, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := s.clk.Now()
+	var stopped []domain.TimeEntry
+	for _, entry := range running {
+		if now.Sub(entry.StartedAt) < policy.MaxDuration {
+			continue
+		}
+		out, ok, err := s.stopInUnitOfWork(ctx, entry.ID, true, func(entry domain.TimeEntry, segments []domain.TimeEntrySegment) (time.Time, bool) {
+			stopAt := policy.stopTime(entry.StartedAt)
+			// Never end an entry inside or before a segment, as checkStopTime requires of user stops
+			for _, seg := range segments {
+				if seg.StartedAt.After(stopAt) {
+					stopAt = seg.StartedAt
+				}
+				if seg.StoppedAt != nil && seg.StoppedAt.After(stopAt) {
+					stopAt = *seg.StoppedAt
+				}
+			}
+			return stopAt, true
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			stopped = append(stopped, out)
+		}
+	}
+	return s.annotate(ctx, stopped)
+}
+
+// StopReachedTargets stops the running entries that have worked for their target, at the instant
+// the target was reached, and returns them marked as auto-stopped.
+func (s *timeTrackingService) StopReachedTargets(ctx context.Context) ([]domain.TimeEntry, error) {
+	running, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := s.clk.Now()
+	var stopped []domain.TimeEntry
+	for _, entry := range running {
+		if entry.TargetSeconds == nil {
+			continue
+		}
+		// Stopped at the target instant itself, which may equal now, so the mark is set explicitly
+		out, ok, err := s.stopInUnitOfWork(ctx, entry.ID, true, func(entry domain.TimeEntry, segments []domain.TimeEntrySegment) (time.Time, bool) {
+			at, ok := targetReachedAt(entry, segments)
+			if !ok || at.After(now) {
+				return time.Time{}, false
+			}
+			return at, true
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			stopped = append(stopped, out)
+		}
+	}
+	return s.annotate(ctx, stopped)
+}
+
+// stopTime returns when an entry started at startedAt is cut off under the policy.
+func (p ReaperPolicy) stopTime(startedAt time.Time) time.Time {
+	stop := startedAt.Add(p.MaxDuration)
+	if p.WorkdayEnd != nil {
+		loc := p.Location
+		if loc == nil {
+			loc = time.UTC
+		}
+		// Build the wall-clock time per day so DST transitions do not shift it
+		hour, minute := int(*p.WorkdayEnd/time.Hour), int(*p.WorkdayEnd%time.Hour/time.Minute)
+		y, m, d := startedAt.In(loc).Date()
+		end := time.Date(y, m, d, hour, minute, 0, 0, loc)
+		if !end.After(startedAt) {
+			end = time.Date(y, m, d+1, hour, minute, 0, 0, loc)
+		}
+		if end.Before(stop) {
+			stop = end
+		}
+	}
+	return stop.UTC()
+}
+
+// stopInUnitOfWork stops a running entry on behalf of the server, together with its audit event.
+// The entry is locked and re-read first, and stopAt picks the stop from its current state or
+// declines. ok is false when the entry was stopped in the meantime or stopAt declined.
+func (s *timeTrackingService) stopInUnitOfWork(ctx context.Context, id uuid.UUID, autoStopped bool, stopAt func(entry domain.TimeEntry, segments []domain.TimeEntrySegment) (time.Time, bool)) (domain.TimeEntry, bool, error) {
+	var (
+		stopped domain.TimeEntry
+		ok      bool
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		entry, err := s.lockRunning(ctx, id)
+		if err != nil || entry == nil {
+			return err
+		}
+		segments, err := s.repo.ListSegments(ctx, id)
+		if err != nil {
+			return err
+		}
+		at, stop := stopAt(*entry, segments)
+		if !stop {
+			return nil
+		}
+		if stopped, err = s.stopEntry(ctx, *entry, at, nil, autoStopped); err != nil {
+			return err
+		}
+		ok = true
+		return nil
+	})
+	return stopped, ok, err
+}
+
+// stopEntry ends a running entry at stoppedAt, closing its open segment, and records the stop.
+// The stored duration excludes paused time. Entries that reached their target earlier end at
+// the target instant and are marked as auto-stopped.
+func (s *timeTrackingService) stopEntry(ctx context.Context, entry domain.TimeEntry, stoppedAt time.Time, note *string, autoStopped bool) (domain.TimeEntry, error) {
+	segments, err := s.repo.ListSegments(ctx, entry.ID)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	if at, ok := targetReachedAt(entry, segments); ok && at.Before(stoppedAt) {
+		stoppedAt, autoStopped = at, true
+	}
+	if len(segments) > 0 {
+		if err := s.repo.StopOpenSegment(ctx, entry.ID, stoppedAt); err != nil {
+			return domain.TimeEntry{}, err
+		}
+	}
+	durationSeconds := workedSeconds(entry.StartedAt, segments, stoppedAt)
+	stopped, err := s.repo.Stop(ctx, entry.ID, stoppedAt, &durationSeconds, note, autoStopped)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	if err := s.audit.entry(ctx, domain.AuditStop, &entry, &stopped); err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return stopped, nil
+}
+
+func (s *timeTrackingService) CreateManual(ctx context.Context, categoryID uuid.UUID, startedAt time.Time, stoppedAt time.Time) (domain.TimeEntry, error) {
+	if !startedAt.Before(stoppedAt) || stoppedAt.After(s.clk.Now()) {
+		return domain.TimeEntry{}, ErrInvalidTimeRange
+	}
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+
+	stopped := stoppedAt.UTC()
+	durationSeconds := durationBetween(startedAt, stopped)
+	entry := domain.TimeEntry{
+		ID:              uuid.New(),
+		CategoryID:      categoryID,
+		StartedAt:       startedAt.UTC(),
+		StoppedAt:       &stopped,
+		DurationSeconds: &durationSeconds,
+		Billable:        category.Billable,
+	}
+	var created domain.TimeEntry
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// The slot lock keeps a concurrent create from passing the same overlap check
+		if err := s.repo.LockTimerSlot(ctx, categoryID); err != nil {
+			return err
+		}
+		overlapping, err := s.repo.ListOverlapping(ctx, startedAt, stoppedAt)
+		if err != nil {
+			return err
+		}
+		conflicts, err := s.inSlot(ctx, overlapping, categoryID, uuid.Nil)
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return ErrTimeEntryOverlap
+		}
+		if created, err = s.repo.Create(ctx, entry); err != nil {
+			return err
+		}
+		return s.audit.entry(ctx, domain.AuditCreate, nil, &created)
+	})
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return s.annotateOne(ctx, created)
+}
+
+func (s *timeTrackingService) UpdateEntry(ctx context.Context, id uuid.UUID, update TimeEntryUpdate) (domain.TimeEntry, error) {
+	var updated domain.TimeEntry
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = s.updateEntry(ctx, id, update)
+		return err
+	})
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return s.annotateOne(ctx, updated)
+}
+
+func (s *timeTrackingService) updateEntry(ctx context.Context, id uuid.UUID, update TimeEntryUpdate) (domain.TimeEntry, error) {
+	entry, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	before := entry
+	wasRunning := entry.StoppedAt == nil
+
+	categoryChanged := update.CategoryID != nil && *update.CategoryID != entry.CategoryID
+	if categoryChanged {
+		entry.CategoryID = *update.CategoryID
+	}
+	// Tags are re-validated whenever they or the owning category change
+	var tagIDs []uuid.UUID
+	if update.TagIDs != nil || categoryChanged {
+		category, err := s.categoryRepo.GetByID(ctx, entry.CategoryID)
+		if err != nil {
+			return domain.TimeEntry{}, err
+		}
+		current, err := s.repo.ListTagIDs(ctx, []uuid.UUID{id})
+		if err != nil {
+			return domain.TimeEntry{}, err
+		}
+		before.TagIDs = current[id]
+		tagIDs = update.TagIDs
+		if tagIDs == nil {
+			tagIDs = current[id]
+		}
+		if tagIDs, err = s.validateTags(ctx, category.ProjectID, tagIDs); err != nil {
+			return domain.TimeEntry{}, err
+		}
+	}
+	if update.StartedAt != nil {
+		entry.StartedAt = update.StartedAt.UTC()
+	}
+	if update.Note != nil {
+		entry.Note = normalizeNote(update.Note)
+	}
+	if update.Billable != nil {
+		entry.Billable = *update.Billable
+	}
+	if update.ClearStoppedAt {
+		entry.StoppedAt = nil
+	} else if update.StoppedAt != nil {
+		stopped := update.StoppedAt.UTC()
+		entry.StoppedAt = &stopped
+	}
+
+	now := s.clk.Now()
+	end := now
+	if entry.StoppedAt != nil {
+		end = *entry.StoppedAt
+		if !entry.StartedAt.Before(end) || end.After(now) {
+			return domain.TimeEntry{}, ErrInvalidTimeRange
+		}
+	} else if entry.StartedAt.After(now) {
+		return domain.TimeEntry{}, ErrInvalidTimeRange
+	}
+
+	// The slot lock keeps a concurrent write from passing the same overlap check; a moved entry
+	// locks the slot it leaves as well
+	slots := []uuid.UUID{entry.CategoryID}
+	if categoryChanged {
+		slots = append(slots, before.CategoryID)
+	}
+	if err := s.repo.LockTimerSlot(ctx, slots...); err != nil {
+		return domain.TimeEntry{}, err
+	}
+
+	// Reopening or moving a running entry must not put a second running entry into its timer slot
+	if entry.StoppedAt == nil && (!wasRunning || categoryChanged) {
+		running, err := s.runningInSlot(ctx, entry.CategoryID, id)
+		if err != nil {
+			return domain.TimeEntry{}, err
+		}
+		if len(running) > 0 {
+			return domain.TimeEntry{}, ErrActiveTimerExists
+		}
+	}
+
+	overlapping, err := s.repo.ListOverlapping(ctx, entry.StartedAt, end)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	conflicts, err := s.inSlot(ctx, overlapping, entry.CategoryID, id)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	if len(conflicts) > 0 {
+		return domain.TimeEntry{}, ErrTimeEntryOverlap
+	}
+
+	// Durations are only recomputed when the timing changes so that edits of other
+	// fields keep the paused time excluded.
+	timingChanged := update.StartedAt != nil || update.StoppedAt != nil || update.ClearStoppedAt
+	if entry.StoppedAt == nil {
+		entry.DurationSeconds = nil
+	} else if timingChanged {
+		durationSeconds := durationBetween(entry.StartedAt, *entry.StoppedAt)
+		entry.DurationSeconds = &durationSeconds
+	}
+	if timingChanged {
+		entry.AutoStopped = false
+	}
+	updated, err := s.repo.Update(ctx, entry)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	if timingChanged {
+		if err := s.repo.DeleteSegments(ctx, id); err != nil {
+			return domain.TimeEntry{}, err
+		}
+	}
+	if update.TagIDs != nil {
+		if err := s.repo.SetTags(ctx, id, tagIDs); err != nil {
+			return domain.TimeEntry{}, err
+		}
+	}
+	after := updated
+	after.TagIDs = tagIDs
+	if err := s.audit.entry(ctx, domain.AuditUpdate, &before, &after); err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return updated, nil
+}
+
+// Split ends the entry at at and records the rest as a new entry, optionally in another category.
+// Both halves keep the note, billable flag and pauses of their part; tags are kept when the second
+// half stays in the same project. Splitting the running entry leaves the second half running.
+func (s *timeTrackingService) Split(ctx context.Context, id uuid.UUID, at time.Time, categoryID *uuid.UUID) (domain.TimeEntry, domain.TimeEntry, error) {
+	var first, second domain.TimeEntry
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		first, second, err = s.split(ctx, id, at.UTC(), categoryID)
+		return err
+	})
+	if err != nil {
+		return domain.TimeEntry{}, domain.TimeEntry{}, err
+	}
+	entries, err := s.annotate(ctx, []domain.TimeEntry{first, second})
+	if err != nil {
+		return domain.TimeEntry{}, domain.TimeEntry{}, err
+	}
+	return entries[0], entries[1], nil
+}
+
+func (s *timeTrackingService) split(ctx context.Context, id uuid.UUID, at time.Time, categoryID *uuid.UUID) (domain.TimeEntry, domain.TimeEntry, error) {
+	entry, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain.TimeEntry{}, domain.TimeEntry{}, err
+	}
+	end := s.clk.Now()
+	if entry.StoppedAt != nil {
+		end = *entry.StoppedAt
+	}
+	if !entry.StartedAt.Before(at) || !at.Before(end) {
+		return domain.TimeEntry{}, domain.TimeEntry{}, ErrInvalidSplitTime
+	}
+
+	category, err := s.categoryRepo.GetByID(ctx, entry.CategoryID)
+	if err != nil {
+		return domain.TimeEntry{}, domain.TimeEntry{}, err
+	}
+	target := category
+	if categoryID != nil && *categoryID != entry.CategoryID {
+		if target, err = s.categoryRepo.GetByID(ctx, *categoryID); err != nil {
+			return domain.TimeEntry{}, domain.TimeEntry{}, err
+		}
+		// The second half must fit into the timer slot of its new category, checked under its lock
+		if err := s.repo.LockTimerSlot(ctx, target.ID); err != nil {
+			return domain.TimeEntry{}, domain.TimeEntry{}, err
+		}
+		if entry.StoppedAt == nil {
+			running, err := s.runningInSlot(ctx, target.ID, id)
+			if err != nil {
+				return domain.TimeEntry{}, domain.TimeEntry{}, err
+			}
+			if len(running) > 0 {
+				return domain.TimeEntry{}, domain.TimeEntry{}, ErrActiveTimerExists
+			}
+		}
+		overlapping, err := s.repo.ListOverlapping(ctx, at, end)
+		if err != nil {
+			return domain.TimeEntry{}, domain.TimeEntry{}, err
+		}
+		conflicts, err := s.inSlot(ctx, overlapping, target.ID, id)
+		if err != nil {
+			return domain.TimeEntry{}, domain.TimeEntry{}, err
+		}
+		if len(conflicts) > 0 {
+			return domain.TimeEntry{}, domain.TimeEntry{}, ErrTimeEntryOverlap
+		}
+	}
+
+	segments, err := s.repo.ListSegments(ctx, id)
+	if err != nil {
+		return domain.TimeEntry{}, domain.TimeEntry{}, err
+	}
+	firstSegments, secondSegments := splitSegments(entry.StartedAt, segments, at)
+	before := entry
+
+	second := domain.TimeEntry{
+		ID:          uuid.New(),
+		CategoryID:  target.ID,
+		StartedAt:   at,
+		StoppedAt:   entry.StoppedAt,
+		Note:        entry.Note,
+		Billable:    entry.Billable,
+		AutoStopped: entry.AutoStopped,
+		Break:       entry.Break,
+	}
+	firstDuration := workedSeconds(entry.StartedAt, firstSegments, at)
+	if second.StoppedAt != nil {
+		durationSeconds := workedSeconds(at, secondSegments, *second.StoppedAt)
+		second.DurationSeconds = &durationSeconds
+	} else if entry.TargetSeconds != nil && *entry.TargetSeconds > firstDuration {
+		// A running second half keeps what is left of the target
+		remaining := *entry.TargetSeconds - firstDuration
+		second.TargetSeconds = &remaining
+	}
+	entry.StoppedAt = &at
+	entry.DurationSeconds = &firstDuration
+	entry.AutoStopped = false
+
+	// Stop the first half before inserting the second so that a running second half finds its slot free
+	first, err := s.repo.Update(ctx, entry)
+	if err != nil {
+		return domain.TimeEntry{}, domain.TimeEntry{}, err
+	}
+	if err := s.audit.entry(ctx, domain.AuditUpdate, &before, &first); err != nil {
+		return domain.TimeEntry{}, domain.TimeEntry{}, err
+	}
+	created, err := s.repo.Create(ctx, second)
+	if err != nil {
+		return domain.TimeEntry{}, domain.TimeEntry{}, err
+	}
+	if err := s.audit.entry(ctx, domain.AuditCreate, nil, &created); err != nil {
+		return domain.TimeEntry{}, domain.TimeEntry{}, err
+	}
+	if len(segments) > 0 {
+		if err := s.repo.DeleteSegments(ctx, id); err != nil {
+			return domain.TimeEntry{}, domain.TimeEntry{}, err
+		}
+		for _, seg := range firstSegments {
+			seg.TimeEntryID = first.ID
+			if _, err := s.repo.CreateSegment(ctx, seg); err != nil {
+				return domain.TimeEntry{}, domain.TimeEntry{}, err
+			}
+		}
+		for _, seg := range secondSegments {
+			seg.TimeEntryID = created.ID
+			if _, err := s.repo.CreateSegment(ctx, seg); err != nil {
+				return domain.TimeEntry{}, domain.TimeEntry{}, err
+			}
+		}
+	}
+	if target.ProjectID == category.ProjectID {
+		tags, err := s.repo.ListTagIDs(ctx, []uuid.UUID{id})
+		if err != nil {
+			return domain.TimeEntry{}, domain.TimeEntry{}, err
+		}
+		if len(tags[id]) > 0 {
+			if err := s.repo.SetTags(ctx, created.ID, tags[id]); err != nil {
+				return domain.TimeEntry{}, domain.TimeEntry{}, err
+			}
+		}
+	}
+	return first, created, nil
+}
+
+// Merge replaces entries of one category with a single entry spanning the earliest start to the
+// latest stop. Consecutive entries may be separated by at most gapTolerance; gaps and pauses count
+// as tracked time. The earliest entry is kept and receives the distinct notes and all tags;
+// the merged entry is running when one of the entries was.
+func (s *timeTrackingService) Merge(ctx context.Context, ids []uuid.UUID, gapTolerance time.Duration) (domain.TimeEntry, error) {
+	var merged domain.TimeEntry
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		merged, err = s.merge(ctx, ids, gapTolerance)
+		return err
+	})
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return s.annotateOne(ctx, merged)
+}
+
+func (s *timeTrackingService) merge(ctx context.Context, ids []uuid.UUID, gapTolerance time.Duration) (domain.TimeEntry, error) {
+	if gapTolerance < 0 {
+		return domain.TimeEntry{}, ErrInvalidMerge
+	}
+	var entries []domain.TimeEntry
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		entry, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return domain.TimeEntry{}, err
+		}
+		if len(entries) > 0 && entry.CategoryID != entries[0].CategoryID {
+			return domain.TimeEntry{}, ErrCrossCategoryMerge
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) < 2 {
+		return domain.TimeEntry{}, ErrInvalidMerge
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].StartedAt.Before(entries[j].StartedAt) })
+
+	// Walk the entries in start order; a running entry is open-ended and must come last
+	now := s.clk.Now()
+	end := entries[0].StartedAt
+	var stoppedAt *time.Time
+	var notes []string
+	for i, e := range entries {
+		if i > 0 && e.StartedAt.Sub(end) > gapTolerance {
+			return domain.TimeEntry{}, ErrEntriesNotContiguous
+		}
+		stop := now
+		if e.StoppedAt != nil {
+			stop = *e.StoppedAt
+		} else if i != len(entries)-1 {
+			return domain.TimeEntry{}, ErrEntriesNotContiguous
+		}
+		if stop.After(end) {
+			end = stop
+			stoppedAt = e.StoppedAt
+		}
+		if e.Note != nil && !slices.Contains(notes, *e.Note) {
+			notes = append(notes, *e.Note)
+		}
+	}
+	if entries[len(entries)-1].StoppedAt == nil {
+		stoppedAt = nil
+	}
+
+	kept := entries[0]
+	kept.StoppedAt = stoppedAt
+	kept.DurationSeconds = nil
+	if stoppedAt != nil {
+		durationSeconds := durationBetween(kept.StartedAt, *stoppedAt)
+		kept.DurationSeconds = &durationSeconds
+	}
+	kept.AutoStopped = false
+	if len(notes) > 0 {
+		joined := strings.Join(notes, "; ")
+		kept.Note = &joined
+	}
+
+	// The merged span is checked under the slot lock so that no entry can be written into it meanwhile
+	if err := s.repo.LockTimerSlot(ctx, kept.CategoryID); err != nil {
+		return domain.TimeEntry{}, err
+	}
+	overlapping, err := s.repo.ListOverlapping(ctx, kept.StartedAt, end)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	var others []domain.TimeEntry
+	for _, e := range overlapping {
+		if !seen[e.ID] {
+			others = append(others, e)
+		}
+	}
+	conflicts, err := s.inSlot(ctx, others, kept.CategoryID, kept.ID)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	if len(conflicts) > 0 {
+		return domain.TimeEntry{}, ErrTimeEntryOverlap
+	}
+
+	tagsByEntry, err := s.repo.ListTagIDs(ctx, ids)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	tagIDs := []uuid.UUID{}
+	seenTags := map[uuid.UUID]bool{}
+	for _, e := range entries {
+		for _, tagID := range tagsByEntry[e.ID] {
+			if !seenTags[tagID] {
+				seenTags[tagID] = true
+				tagIDs = append(tagIDs, tagID)
+			}
+		}
+	}
+
+	// Remove the other entries first so that a running merged entry finds its slot free
+	for _, e := range entries[1:] {
+		if err := s.repo.Delete(ctx, e.ID); err != nil {
+			return domain.TimeEntry{}, err
+		}
+		e.TagIDs = tagsByEntry[e.ID]
+		if err := s.audit.entry(ctx, domain.AuditDelete, &e, nil); err != nil {
+			return domain.TimeEntry{}, err
+		}
+	}
+	merged, err := s.repo.Update(ctx, kept)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	before, after := entries[0], merged
+	before.TagIDs, after.TagIDs = tagsByEntry[before.ID], tagIDs
+	if err := s.audit.entry(ctx, domain.AuditUpdate, &before, &after); err != nil {
+		return domain.TimeEntry{}, err
+	}
+	if err := s.repo.DeleteSegments(ctx, kept.ID); err != nil {
+		return domain.TimeEntry{}, err
+	}
+	if err := s.repo.SetTags(ctx, kept.ID, tagIDs); err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return merged, nil
+}
+
+// DeleteEntry moves a single entry to the trash. Deleting the running entry cancels the timer:
+// no entry is active afterwards.
+func (s *timeTrackingService) DeleteEntry(ctx context.Context, id uuid.UUID) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		entry, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.entry(ctx, domain.AuditDelete, &entry, nil)
+	})
+}
+
+// DeleteEntries moves all entries of a category started within [start, end] to the trash.
+// With dryRun set, nothing is deleted and the matching entries are returned instead.
+func (s *timeTrackingService) DeleteEntries(ctx context.Context, categoryID uuid.UUID, start time.Time, end time.Time, dryRun bool) ([]domain.TimeEntry, error) {
+	if start.After(end) {
+		return nil, ErrInvalidTimeRange
+	}
+	if dryRun {
+		entries, err := s.repo.ListByCategoryAndRange(ctx, categoryID, start, end, repository.TimeEntryFilter{})
+		if err != nil {
+			return nil, err
+		}
+		return s.annotate(ctx, entries)
+	}
+	var deleted []domain.TimeEntry
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if deleted, err = s.repo.DeleteByCategoryAndRange(ctx, categoryID, start, end); err != nil {
+			return err
+		}
+		for i := range deleted {
+			if err := s.audit.entry(ctx, domain.AuditDelete, &deleted[i], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// RestoreEntry takes an entry out of the trash. A restored running entry runs on; the restore
+// fails with ErrTimeEntryOverlap when the entry now overlaps another entry of its timer slot.
+func (s *timeTrackingService) RestoreEntry(ctx context.Context, id uuid.UUID) (domain.TimeEntry, error) {
+	var restored domain.TimeEntry
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if restored, err = s.repo.Restore(ctx, id); err != nil {
+			return err
+		}
+		end := s.clk.Now()
+		if restored.StoppedAt != nil {
+			end = *restored.StoppedAt
+		}
+		if err := s.repo.LockTimerSlot(ctx, restored.CategoryID); err != nil {
+			return err
+		}
+		overlapping, err := s.repo.ListOverlapping(ctx, restored.StartedAt, end)
+		if err != nil {
+			return err
+		}
+		conflicts, err := s.inSlot(ctx, overlapping, restored.CategoryID, restored.ID)
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return ErrTimeEntryOverlap
+		}
+		return s.audit.entry(ctx, domain.AuditRestore, nil, &restored)
+	})
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return s.annotateOne(ctx, restored)
+}
+
+// GetEntry returns a single entry with its tags, amount and timer state.
+func (s *timeTrackingService) GetEntry(ctx context.Context, id uuid.UUID) (domain.TimeEntry, error) {
+	entry, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return s.annotateOne(ctx, entry)
+}
+
+func (s *timeTrackingService) GetActive(ctx context.Context) (*domain.TimeEntry, error) {
+	active, err := s.repo.FindActive(ctx)
+	if err != nil || active == nil {
+		return active, err
+	}
+	annotated, err := s.annotateOne(ctx, *active)
+	if err != nil {
+		return nil, err
+	}
+	return &annotated, nil
+}
+
+// GetActiveInCategory returns the running entry of a category, or nil when none is running.
+func (s *timeTrackingService) GetActiveInCategory(ctx context.Context, categoryID uuid.UUID) (*domain.TimeEntry, error) {
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return nil, err
+	}
+	active, err := s.repo.FindActiveByCategory(ctx, categoryID)
+	if err != nil || active == nil {
+		return active, err
+	}
+	annotated, err := s.annotateOne(ctx, *active)
+	if err != nil {
+		return nil, err
+	}
+	return &annotated, nil
+}
+
+// ListActive returns all running entries, most recently started first.
+func (s *timeTrackingService) ListActive(ctx context.Context) ([]domain.TimeEntry, error) {
+	entries, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.annotate(ctx, entries)
+}
+
+func (s *timeTrackingService) ListByCategory(ctx context.Context, categoryID uuid.UUID, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+	entries, err := s.repo.ListByCategory(ctx, categoryID, filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.annotateListing(ctx, entries, nil, nil, filter)
+}
+
+func (s *timeTrackingService) ListByCategoryAndRange(ctx context.Context, categoryID uuid.UUID, start time.Time, end time.Time, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+	entries, err := s.repo.ListByCategoryAndRange(ctx, categoryID, start, end, filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.annotateListing(ctx, entries, &start, &end, filter)
+}
+
+// ListByProject returns the entries of all categories of a project with their category paths.
+func (s *timeTrackingService) ListByProject(ctx context.Context, projectID uuid.UUID, start *time.Time, end *time.Time, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+	if _, err := s.projectRepo.GetByID(ctx, projectID); err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.ListByProject(ctx, projectID, start, end, filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.annotateListing(ctx, entries, start, end, filter)
+}
+
+// ListByCategoryTree returns the entries of a category and its descendants with their category paths.
+func (s *timeTrackingService) ListByCategoryTree(ctx context.Context, categoryID uuid.UUID, start *time.Time, end *time.Time, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.ListByCategoryTree(ctx, categoryID, start, end, filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.annotateListing(ctx, entries, start, end, filter)
+}
+
+// globalSlot is the timer slot shared by entries of projects without concurrent timers.
+var globalSlot = uuid.Nil
+
+// maxTargetSeconds caps the planned length of a timer at one day.
+const maxTargetSeconds = 24 * 60 * 60
+
+// slotOf returns the timer slot of entries in a category: the category itself when its
+// project allows concurrent timers, else globalSlot. Results are memoized in cache.
+func (s *timeTrackingService) slotOf(ctx context.Context, categoryID uuid.UUID, cache map[uuid.UUID]uuid.UUID) (uuid.UUID, error) {
+	if slot, ok := cache[categoryID]; ok {
+		return slot, nil
+	}
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	project, err := s.projectRepo.GetByID(ctx, category.ProjectID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	slot := globalSlot
+	if project.Settings.ConcurrentTimers {
+		slot = categoryID
+	}
+	cache[categoryID] = slot
+	return slot, nil
+}
+
+// inSlot keeps the entries sharing the timer slot of categoryID, skipping the entry exclude.
+func (s *timeTrackingService) inSlot(ctx context.Context, entries []domain.TimeEntry, categoryID uuid.UUID, exclude uuid.UUID) ([]domain.TimeEntry, error) {
+	var out []domain.TimeEntry
+	cache := map[uuid.UUID]uuid.UUID{}
+	for _, e := range entries {
+		if e.ID == exclude {
+			continue
+		}
+		// Entries of the same category always share a slot
+		if e.CategoryID == categoryID {
+			out = append(out, e)
+			continue
+		}
+		slot, err := s.slotOf(ctx, categoryID, cache)
+		if err != nil {
+			return nil, err
+		}
+		other, err := s.slotOf(ctx, e.CategoryID, cache)
+		if err != nil {
+			return nil, err
+		}
+		if other == slot {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// runningInSlot returns the running entries sharing the timer slot of categoryID, skipping the entry exclude.
+func (s *timeTrackingService) runningInSlot(ctx context.Context, categoryID uuid.UUID, exclude uuid.UUID) ([]domain.TimeEntry, error) {
+	running, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.inSlot(ctx, running, categoryID, exclude)
+}
+
+// validateTags de-duplicates tagIDs and ensures each tag exists within projectID.
+func (s *timeTrackingService) validateTags(ctx context.Context, projectID uuid.UUID, tagIDs []uuid.UUID) ([]uuid.UUID, error) {
+	out := make([]uuid.UUID, 0, len(tagIDs))
+	seen := make(map[uuid.UUID]bool, len(tagIDs))
+	for _, tagID := range tagIDs {
+		if seen[tagID] {
+			continue
+		}
+		seen[tagID] = true
+		tag, err := s.tagRepo.GetByID(ctx, tagID)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				return nil, ErrInvalidTag
+			}
+			return nil, err
+		}
+		if tag.ProjectID != projectID {
+			return nil, ErrCrossProjectTag
+		}
+		out = append(out, tagID)
+	}
+	return out, nil
+}
+
+// annotate fills the derived TagIDs and BillableAmount of each entry, and the
+// pause state and elapsed time of running entries.
+func (s *timeTrackingService) annotate(ctx context.Context, entries []domain.TimeEntry) ([]domain.TimeEntry, error) {
+	if len(entries) == 0 {
+		return entries, nil
+	}
+	ids := make([]uuid.UUID, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+	tagsByEntry, err := s.repo.ListTagIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	rates := map[uuid.UUID]*domain.Rate{}
+	roundings := map[uuid.UUID]domain.Rounding{}
+	now := s.clk.Now()
+	for i := range entries {
+		entries[i].TagIDs = tagsByEntry[entries[i].ID]
+		if entries[i].TagIDs == nil {
+			entries[i].TagIDs = []uuid.UUID{}
+		}
+		if entries[i].StoppedAt == nil {
+			segments, err := s.repo.ListSegments(ctx, entries[i].ID)
+			if err != nil {
+				return nil, err
+			}
+			elapsed := workedSeconds(entries[i].StartedAt, segments, now)
+			entries[i].Paused = isPaused(segments)
+			entries[i].ElapsedSeconds = &elapsed
+			if target := entries[i].TargetSeconds; target != nil {
+				remaining := max(*target-elapsed, 0)
+				entries[i].RemainingSeconds = &remaining
+			}
+		} else {
+			rounding, err := s.rounding.resolve(ctx, entries[i].CategoryID, roundings)
+			if err != nil {
+				return nil, err
+			}
+			entries[i].RoundedSeconds = roundedDuration(entries[i], rounding)
+		}
+		amount, err := s.rates.entryAmount(ctx, entries[i], rates)
+		if err != nil {
+			return nil, err
+		}
+		entries[i].BillableAmount = amount
+	}
+	return entries, nil
+}
+
+// annotateListing annotates the entries of a listing bounded by start and end (nil bounds are open)
+// and, when filter.Clip is set, fills their ClippedSeconds with the worked time inside [start, end).
+func (s *timeTrackingService) annotateListing(ctx context.Context, entries []domain.TimeEntry, start *time.Time, end *time.Time, filter repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+	entries, err := s.annotate(ctx, entries)
+	if err != nil || !filter.Clip {
+		return entries, err
+	}
 	now := s.clk.Now()
-	durationSeconds := int32(now.Sub(active.StartedAt).Seconds())
+	for i := range entries {
+		e := &entries[i]
+		stop := now
+		if e.StoppedAt != nil {
+			stop = *e.StoppedAt
+		}
+		var seconds int32
+		if (start == nil || !e.StartedAt.Before(*start)) && (end == nil || !stop.After(*end)) {
+			// Entirely inside the window; only entries reaching past a bound need their segments.
+			if e.DurationSeconds != nil {
+				seconds = *e.DurationSeconds
+			} else if e.ElapsedSeconds != nil {
+				seconds = *e.ElapsedSeconds
+			}
+		} else {
+			segments, err := s.repo.ListSegments(ctx, e.ID)
+			if err != nil {
+				return nil, err
+			}
+			seconds = workedSecondsWithin(e.StartedAt, stop, segments, start, end)
+		}
+		e.ClippedSeconds = &seconds
+	}
+	return entries, nil
+}
+
+func (s *timeTrackingService) annotateOne(ctx context.Context, entry domain.TimeEntry) (domain.TimeEntry, error) {
+	entries, err := s.annotate(ctx, []domain.TimeEntry{entry})
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return entries[0], nil
+}
+
+// normalizeNote trims surrounding whitespace and maps blank notes to nil.
+func normalizeNote(note *string) *string {
+	if note == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*note)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// durationBetween returns the whole seconds between start and stop, clamped at zero.
+func durationBetween(start time.Time, stop time.Time) int32 {
+	durationSeconds := int32(stop.Sub(start).Seconds())
 	if durationSeconds < 0 {
 		durationSeconds = 0
 	}
-	return s.repo.Stop(ctx, active.ID, now, &durationSeconds)
+	return durationSeconds
 }
 
-func (s *timeTrackingService) GetActive(ctx context.Context) (*domain.TimeEntry, error) {
-	return s.repo.FindActive(ctx)
+// isPaused reports whether an entry with the given segments is currently paused.
+func isPaused(segments []domain.TimeEntrySegment) bool {
+	return len(segments) > 0 && segments[len(segments)-1].StoppedAt != nil
+}
+
+// workedSeconds returns the running time of an entry up to end, excluding pauses.
+// Entries without segments ran continuously since startedAt.
+func workedSeconds(startedAt time.Time, segments []domain.TimeEntrySegment, end time.Time) int32 {
+	if len(segments) == 0 {
+		return durationBetween(startedAt, end)
+	}
+	var total time.Duration
+	for _, seg := range segments {
+		stop := end
+		if seg.StoppedAt != nil {
+			stop = *seg.StoppedAt
+		}
+		if stop.After(seg.StartedAt) {
+			total += stop.Sub(seg.StartedAt)
+		}
+	}
+	return int32(total.Seconds())
+}
+
+// targetReachedAt returns the instant at which entry has worked for its TargetSeconds, pauses
+// excluded. It reports false for entries without a target and for entries paused short of it.
+func targetReachedAt(entry domain.TimeEntry, segments []domain.TimeEntrySegment) (time.Time, bool) {
+	if entry.TargetSeconds == nil {
+		return time.Time{}, false
+	}
+	remaining := time.Duration(*entry.TargetSeconds) * time.Second
+	if len(segments) == 0 {
+		return entry.StartedAt.Add(remaining), true
+	}
+	for _, seg := range segments {
+		if seg.StoppedAt == nil || seg.StoppedAt.Sub(seg.StartedAt) >= remaining {
+			return seg.StartedAt.Add(remaining), true
+		}
+		remaining -= seg.StoppedAt.Sub(seg.StartedAt)
+	}
+	return time.Time{}, false
 }
 
-func (s *timeTrackingService) ListByCategory(ctx context.Context, categoryID uuid.UUID) ([]domain.TimeEntry, error) {
-	return s.repo.ListByCategory(ctx, categoryID)
+// workedSecondsWithin returns the running time of an entry from startedAt to stop that falls inside
+// [from, to), excluding pauses. Nil bounds are open; open segments run until stop.
+func workedSecondsWithin(startedAt time.Time, stop time.Time, segments []domain.TimeEntrySegment, from *time.Time, to *time.Time) int32 {
+	if len(segments) == 0 {
+		segments = []domain.TimeEntrySegment{{StartedAt: startedAt, StoppedAt: &stop}}
+	}
+	var total time.Duration
+	for _, seg := range segments {
+		lo, hi := seg.StartedAt, stop
+		if seg.StoppedAt != nil {
+			hi = *seg.StoppedAt
+		}
+		if from != nil && lo.Before(*from) {
+			lo = *from
+		}
+		if to != nil && hi.After(*to) {
+			hi = *to
+		}
+		if hi.After(lo) {
+			total += hi.Sub(lo)
+		}
+	}
+	return int32(total.Seconds())
 }
 
-func (s *timeTrackingService) ListByCategoryAndRange(ctx context.Context, categoryID uuid.UUID, start time.Time, end time.Time) ([]domain.TimeEntry, error) {
-	return s.repo.ListByCategoryAndRange(ctx, categoryID, start, end)
+// splitSegments divides the segments of an entry started at startedAt into those before and after at,
+// cutting a segment that spans at. Entries without segments need none. A half without any running
+// time gets a zero-length segment so that it does not count as having run continuously.
+func splitSegments(startedAt time.Time, segments []domain.TimeEntrySegment, at time.Time) (first, second []domain.TimeEntrySegment) {
+	if len(segments) == 0 {
+		return nil, nil
+	}
+	for _, seg := range segments {
+		if seg.StartedAt.Before(at) {
+			stop := at
+			if seg.StoppedAt != nil && seg.StoppedAt.Before(at) {
+				stop = *seg.StoppedAt
+			}
+			first = append(first, domain.TimeEntrySegment{ID: uuid.New(), StartedAt: seg.StartedAt, StoppedAt: &stop})
+		}
+		if seg.StoppedAt == nil || seg.StoppedAt.After(at) {
+			start := seg.StartedAt
+			if start.Before(at) {
+				start = at
+			}
+			second = append(second, domain.TimeEntrySegment{ID: uuid.New(), StartedAt: start, StoppedAt: seg.StoppedAt})
+		}
+	}
+	if len(first) == 0 {
+		first = []domain.TimeEntrySegment{{ID: uuid.New(), StartedAt: startedAt, StoppedAt: &startedAt}}
+	}
+	if len(second) == 0 {
+		second = []domain.TimeEntrySegment{{ID: uuid.New(), StartedAt: at, StoppedAt: &at}}
+	}
+	return first, second
 }
 
 var _ TimeTrackingService = (*timeTrackingService)(nil)
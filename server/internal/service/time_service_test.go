@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"slices"
 	"testing"
 	"time"
 
@@ -24,16 +25,29 @@ func seedCategory(t *testing.T, repo *fakeCategoryRepo) domain.Category {
 	return out
 }
 
+// seedProject registers a project with the given ID, e.g. the project of a seeded category.
+func seedProject(t *testing.T, repo *fakeProjectRepo, id uuid.UUID, concurrentTimers bool) domain.Project {
+	t.Helper()
+	p := domain.Project{ID: id, Name: "Proj", Settings: domain.ProjectSettings{ConcurrentTimers: concurrentTimers}}
+	out, err := repo.Create(context.Background(), p)
+	if err != nil {
+		t.Fatalf("seed project failed: %v", err)
+	}
+	return out
+}
+
 func TestTimeTrackingServiceStartNoActiveCreatesActive(t *testing.T) {
 	ctx := context.Background()
 	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
 	timeRepo := newFakeTimeEntryRepo()
 	start := time.Date(2025, 11, 2, 10, 0, 0, 0, time.UTC)
 	clk := newTestClock(start)
-	svc := NewTimeTrackingService(timeRepo, catRepo, clk)
+	svc := NewTimeTrackingService(timeRepo, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
 
 	cat := seedCategory(t, catRepo)
-	entry, err := svc.Start(ctx, cat.ID)
+	seedProject(t, projRepo, cat.ProjectID, false)
+	entry, err := svc.Start(ctx, cat.ID, StartOptions{})
 	if err != nil {
 		t.Fatalf("start failed: %v", err)
 	}
@@ -55,12 +69,15 @@ func TestTimeTrackingServiceStartStopsPreviousAndStartsNewAtSameNow(t *testing.T
 	timeRepo := newFakeTimeEntryRepo()
 	t0 := time.Date(2025, 11, 2, 10, 0, 0, 0, time.UTC)
 	clk := newTestClock(t0)
-	svc := NewTimeTrackingService(timeRepo, catRepo, clk)
+	projRepo := newFakeProjectRepo()
+	svc := NewTimeTrackingService(timeRepo, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
 
 	cat1 := seedCategory(t, catRepo)
 	cat2 := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat1.ProjectID, false)
+	seedProject(t, projRepo, cat2.ProjectID, false)
 
-	first, err := svc.Start(ctx, cat1.ID)
+	first, err := svc.Start(ctx, cat1.ID, StartOptions{})
 	if err != nil {
 		t.Fatalf("first start failed: %v", err)
 	}
@@ -68,7 +85,7 @@ func TestTimeTrackingServiceStartStopsPreviousAndStartsNewAtSameNow(t *testing.T
 	t1 := t0.Add(5 * time.Minute)
 	clk.Set(t1)
 
-	second, err := svc.Start(ctx, cat2.ID)
+	second, err := svc.Start(ctx, cat2.ID, StartOptions{})
 	if err != nil {
 		t.Fatalf("second start failed: %v", err)
 	}
@@ -92,16 +109,18 @@ func TestTimeTrackingServiceStopActiveComputesDurationAndClearsActive(t *testing
 	timeRepo := newFakeTimeEntryRepo()
 	t0 := time.Date(2025, 11, 2, 10, 0, 0, 0, time.UTC)
 	clk := newTestClock(t0)
-	svc := NewTimeTrackingService(timeRepo, catRepo, clk)
+	projRepo := newFakeProjectRepo()
+	svc := NewTimeTrackingService(timeRepo, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
 
 	cat := seedCategory(t, catRepo)
-	_, err := svc.Start(ctx, cat.ID)
+	seedProject(t, projRepo, cat.ProjectID, false)
+	_, err := svc.Start(ctx, cat.ID, StartOptions{})
 	if err != nil {
 		t.Fatalf("start failed: %v", err)
 	}
 
 	clk.Advance(1*time.Hour + 30*time.Second)
-	stopped, err := svc.StopActive(ctx)
+	stopped, err := svc.StopActive(ctx, StopOptions{})
 	if err != nil {
 		t.Fatalf("stop failed: %v", err)
 	}
@@ -124,14 +143,28 @@ type errCategoryRepo struct{ err error }
 
 func (r errCategoryRepo) GetByID(context.Context, uuid.UUID) (domain.Category, error) { return domain.Category{}, r.err }
 func (r errCategoryRepo) Create(context.Context, domain.Category) (domain.Category, error) { return domain.Category{}, nil }
-func (r errCategoryRepo) ListByProject(context.Context, uuid.UUID) ([]domain.Category, error) {
+func (r errCategoryRepo) ListByProject(context.Context, uuid.UUID, bool, repository.PageRequest) ([]domain.Category, error) {
     return nil, nil
 }
 func (r errCategoryRepo) ListChildren(context.Context, uuid.UUID) ([]domain.Category, error) { return nil, nil }
 func (r errCategoryRepo) Update(context.Context, uuid.UUID, string, *string, *uuid.UUID) (domain.Category, error) {
     return domain.Category{}, nil
 }
+func (r errCategoryRepo) UpdateBilling(context.Context, uuid.UUID, *domain.Rate, bool) (domain.Category, error) {
+    return domain.Category{}, nil
+}
+func (r errCategoryRepo) SetArchived(context.Context, uuid.UUID, bool) (domain.Category, error) {
+    return domain.Category{}, nil
+}
 func (r errCategoryRepo) Delete(context.Context, uuid.UUID) error { return nil }
+func (r errCategoryRepo) ListDeleted(context.Context) ([]domain.Category, error) { return nil, nil }
+func (r errCategoryRepo) Restore(context.Context, uuid.UUID) (domain.Category, error) {
+    return domain.Category{}, r.err
+}
+func (r errCategoryRepo) PurgeDeleted(context.Context, time.Time) (int64, error) { return 0, nil }
+func (r errCategoryRepo) TotalsByProject(context.Context, uuid.UUID, *time.Time, *time.Time, time.Time) ([]domain.CategoryTotal, error) {
+    return nil, r.err
+}
 
 type stubTimeRepo struct{
     active *domain.TimeEntry
@@ -144,20 +177,68 @@ func (r stubTimeRepo) Create(context.Context, domain.TimeEntry) (domain.TimeEntr
     return domain.TimeEntry{}, r.createErr
 }
 func (r stubTimeRepo) GetByID(context.Context, uuid.UUID) (domain.TimeEntry, error) { return domain.TimeEntry{}, nil }
-func (r stubTimeRepo) ListByCategory(context.Context, uuid.UUID) ([]domain.TimeEntry, error) { return nil, nil }
-func (r stubTimeRepo) ListByCategoryAndRange(context.Context, uuid.UUID, time.Time, time.Time) ([]domain.TimeEntry, error) {
+func (r stubTimeRepo) GetForUpdate(context.Context, uuid.UUID) (domain.TimeEntry, error) {
+    if r.active == nil {
+        return domain.TimeEntry{}, repository.ErrNotFound
+    }
+    return *r.active, nil
+}
+func (r stubTimeRepo) ListByCategory(context.Context, uuid.UUID, repository.TimeEntryFilter) ([]domain.TimeEntry, error) { return nil, nil }
+func (r stubTimeRepo) ListByCategoryAndRange(context.Context, uuid.UUID, time.Time, time.Time, repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+    return nil, nil
+}
+func (r stubTimeRepo) ListByProject(context.Context, uuid.UUID, *time.Time, *time.Time, repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
     return nil, nil
 }
+func (r stubTimeRepo) ListByCategoryTree(context.Context, uuid.UUID, *time.Time, *time.Time, repository.TimeEntryFilter) ([]domain.TimeEntry, error) {
+    return nil, nil
+}
+func (r stubTimeRepo) Update(context.Context, domain.TimeEntry) (domain.TimeEntry, error) {
+    return domain.TimeEntry{}, nil
+}
+func (r stubTimeRepo) Delete(context.Context, uuid.UUID) error { return nil }
+func (r stubTimeRepo) DeleteByCategoryAndRange(context.Context, uuid.UUID, time.Time, time.Time) ([]domain.TimeEntry, error) {
+    return nil, nil
+}
+func (r stubTimeRepo) ListDeleted(context.Context) ([]domain.TimeEntry, error) { return nil, nil }
+func (r stubTimeRepo) Restore(context.Context, uuid.UUID) (domain.TimeEntry, error) {
+    return domain.TimeEntry{}, nil
+}
+func (r stubTimeRepo) PurgeDeleted(context.Context, time.Time) (int64, error) { return 0, nil }
+func (r stubTimeRepo) ListOverlapping(context.Context, time.Time, time.Time) ([]domain.TimeEntry, error) {
+    return nil, nil
+}
+func (r stubTimeRepo) LockTimerSlot(context.Context, ...uuid.UUID) error { return nil }
 func (r stubTimeRepo) FindActive(context.Context) (*domain.TimeEntry, error) { return r.active, r.findErr }
-func (r stubTimeRepo) Stop(context.Context, uuid.UUID, time.Time, *int32) (domain.TimeEntry, error) {
+func (r stubTimeRepo) FindActiveByCategory(context.Context, uuid.UUID) (*domain.TimeEntry, error) {
+    return r.active, r.findErr
+}
+func (r stubTimeRepo) FindLastStopped(context.Context) (*domain.TimeEntry, error) { return nil, nil }
+func (r stubTimeRepo) ListActive(context.Context) ([]domain.TimeEntry, error) {
+    if r.active == nil {
+        return nil, r.findErr
+    }
+    return []domain.TimeEntry{*r.active}, r.findErr
+}
+func (r stubTimeRepo) Stop(context.Context, uuid.UUID, time.Time, *int32, *string, bool) (domain.TimeEntry, error) {
     return domain.TimeEntry{}, r.stopErr
 }
+func (r stubTimeRepo) SetTags(context.Context, uuid.UUID, []uuid.UUID) error { return nil }
+func (r stubTimeRepo) ListTagIDs(context.Context, []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+    return map[uuid.UUID][]uuid.UUID{}, nil
+}
+func (r stubTimeRepo) CreateSegment(_ context.Context, seg domain.TimeEntrySegment) (domain.TimeEntrySegment, error) {
+    return seg, nil
+}
+func (r stubTimeRepo) StopOpenSegment(context.Context, uuid.UUID, time.Time) error { return nil }
+func (r stubTimeRepo) ListSegments(context.Context, uuid.UUID) ([]domain.TimeEntrySegment, error) { return nil, nil }
+func (r stubTimeRepo) DeleteSegments(context.Context, uuid.UUID) error { return nil }
 
 func TestTimeTrackingServiceStartReturnsCategoryError(t *testing.T) {
     ctx := context.Background()
     clk := newTestClock(time.Now().UTC())
-    svc := NewTimeTrackingService(stubTimeRepo{}, errCategoryRepo{err: repository.ErrNotFound}, clk)
-    _, err := svc.Start(ctx, uuid.New())
+    svc := NewTimeTrackingService(stubTimeRepo{}, errCategoryRepo{err: repository.ErrNotFound}, newFakeTagRepo(), newFakeProjectRepo(), nil, fakeUnitOfWork{}, clk)
+    _, err := svc.Start(ctx, uuid.New(), StartOptions{})
     if err == nil || err != repository.ErrNotFound {
         t.Fatalf("expected ErrNotFound, got %v", err)
     }
@@ -166,10 +247,12 @@ func TestTimeTrackingServiceStartReturnsCategoryError(t *testing.T) {
 func TestTimeTrackingServiceStartPropagatesFindActiveError(t *testing.T) {
     ctx := context.Background()
     catRepo := newFakeCategoryRepo()
-    seedCategory(t, catRepo)
+    projRepo := newFakeProjectRepo()
+    cat := seedCategory(t, catRepo)
+    seedProject(t, projRepo, cat.ProjectID, false)
     findErr := repository.ErrDuplicate
-    svc := NewTimeTrackingService(stubTimeRepo{findErr: findErr}, catRepo, newTestClock(time.Now().UTC()))
-    _, err := svc.Start(ctx, seedCategory(t, catRepo).ID)
+    svc := NewTimeTrackingService(stubTimeRepo{findErr: findErr}, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, newTestClock(time.Now().UTC()))
+    _, err := svc.Start(ctx, cat.ID, StartOptions{})
     if err == nil || err != findErr {
         t.Fatalf("expected findErr, got %v", err)
     }
@@ -178,12 +261,14 @@ func TestTimeTrackingServiceStartPropagatesFindActiveError(t *testing.T) {
 func TestTimeTrackingServiceStartPropagatesStopError(t *testing.T) {
     ctx := context.Background()
     catRepo := newFakeCategoryRepo()
+    projRepo := newFakeProjectRepo()
     cat := seedCategory(t, catRepo)
+    seedProject(t, projRepo, cat.ProjectID, false)
     now := time.Now().UTC()
     active := domain.TimeEntry{ID: uuid.New(), CategoryID: cat.ID, StartedAt: now.Add(-time.Minute)}
     stopErr := repository.ErrForeignKeyViolation
-    svc := NewTimeTrackingService(stubTimeRepo{active: &active, stopErr: stopErr}, catRepo, newTestClock(now))
-    _, err := svc.Start(ctx, cat.ID)
+    svc := NewTimeTrackingService(stubTimeRepo{active: &active, stopErr: stopErr}, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, newTestClock(now))
+    _, err := svc.Start(ctx, cat.ID, StartOptions{})
     if err == nil || err != stopErr {
         t.Fatalf("expected stopErr, got %v", err)
     }
@@ -191,8 +276,8 @@ func TestTimeTrackingServiceStartPropagatesStopError(t *testing.T) {
 
 func TestTimeTrackingServiceStopActiveNoActiveReturnsErr(t *testing.T) {
     ctx := context.Background()
-    svc := NewTimeTrackingService(stubTimeRepo{}, newFakeCategoryRepo(), newTestClock(time.Now().UTC()))
-    _, err := svc.StopActive(ctx)
+    svc := NewTimeTrackingService(stubTimeRepo{}, newFakeCategoryRepo(), newFakeTagRepo(), newFakeProjectRepo(), nil, fakeUnitOfWork{}, newTestClock(time.Now().UTC()))
+    _, err := svc.StopActive(ctx, StopOptions{})
     if err == nil || err != ErrNoActiveTimer {
         t.Fatalf("expected ErrNoActiveTimer, got %v", err)
     }
@@ -201,8 +286,8 @@ func TestTimeTrackingServiceStopActiveNoActiveReturnsErr(t *testing.T) {
 func TestTimeTrackingServiceStopActivePropagatesFindError(t *testing.T) {
     ctx := context.Background()
     findErr := repository.ErrDuplicate
-    svc := NewTimeTrackingService(stubTimeRepo{findErr: findErr}, newFakeCategoryRepo(), newTestClock(time.Now().UTC()))
-    _, err := svc.StopActive(ctx)
+    svc := NewTimeTrackingService(stubTimeRepo{findErr: findErr}, newFakeCategoryRepo(), newFakeTagRepo(), newFakeProjectRepo(), nil, fakeUnitOfWork{}, newTestClock(time.Now().UTC()))
+    _, err := svc.StopActive(ctx, StopOptions{})
     if err == nil || err != findErr {
         t.Fatalf("expected findErr, got %v", err)
     }
@@ -213,8 +298,8 @@ func TestTimeTrackingServiceStopActivePropagatesStopError(t *testing.T) {
     now := time.Now().UTC()
     active := &domain.TimeEntry{ID: uuid.New(), StartedAt: now.Add(-time.Minute)}
     stopErr := repository.ErrForeignKeyViolation
-    svc := NewTimeTrackingService(stubTimeRepo{active: active, stopErr: stopErr}, newFakeCategoryRepo(), newTestClock(now))
-    _, err := svc.StopActive(ctx)
+    svc := NewTimeTrackingService(stubTimeRepo{active: active, stopErr: stopErr}, newFakeCategoryRepo(), newFakeTagRepo(), newFakeProjectRepo(), nil, fakeUnitOfWork{}, newTestClock(now))
+    _, err := svc.StopActive(ctx, StopOptions{})
     if err == nil || err != stopErr {
         t.Fatalf("expected stopErr, got %v", err)
     }
@@ -237,8 +322,8 @@ func TestTimeTrackingServiceListByCategoryPropagatesResults(t *testing.T) {
     if err != nil { t.Fatalf("create: %v", err) }
     if _, err := repo.Create(ctx, domain.TimeEntry{ID: uuid.New(), CategoryID: catB, StartedAt: t1}); err != nil { t.Fatalf("create: %v", err) }
 
-    svc := NewTimeTrackingService(repo, newFakeCategoryRepo(), newTestClock(t0))
-    got, err := svc.ListByCategory(ctx, catA)
+    svc := NewTimeTrackingService(repo, newFakeCategoryRepo(), newFakeTagRepo(), newFakeProjectRepo(), nil, fakeUnitOfWork{}, newTestClock(t0))
+    got, err := svc.ListByCategory(ctx, catA, repository.TimeEntryFilter{})
     if err != nil { t.Fatalf("ListByCategory: %v", err) }
     if len(got) != 2 { t.Fatalf("expected 2 entries, got %d", len(got)) }
     // Repo sorts by StartedAt desc → e2 first
@@ -272,8 +357,8 @@ func TestTimeTrackingServiceListByCategoryAndRangePropagatesResults(t *testing.T
     // Distractor in another category within range
     if _, err := repo.Create(ctx, domain.TimeEntry{ID: uuid.New(), CategoryID: uuid.New(), StartedAt: mid}); err != nil { t.Fatalf("create: %v", err) }
 
-    svc := NewTimeTrackingService(repo, newFakeCategoryRepo(), newTestClock(start))
-    got, err := svc.ListByCategoryAndRange(ctx, cat, start, end)
+    svc := NewTimeTrackingService(repo, newFakeCategoryRepo(), newFakeTagRepo(), newFakeProjectRepo(), nil, fakeUnitOfWork{}, newTestClock(start))
+    got, err := svc.ListByCategoryAndRange(ctx, cat, start, end, repository.TimeEntryFilter{})
     if err != nil { t.Fatalf("ListByCategoryAndRange: %v", err) }
     if len(got) != 3 { t.Fatalf("expected 3 entries in range, got %d", len(got)) }
     // Repo sorts desc by StartedAt: end, mid, start
@@ -281,3 +366,1540 @@ func TestTimeTrackingServiceListByCategoryAndRangePropagatesResults(t *testing.T
         t.Fatalf("unexpected order: got [%s,%s,%s]", got[0].ID, got[1].ID, got[2].ID)
     }
 }
+
+// --- Manual entry tests ---
+
+func TestTimeTrackingServiceCreateManualComputesDuration(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	timeRepo := newFakeTimeEntryRepo()
+	now := time.Date(2025, 11, 2, 18, 0, 0, 0, time.UTC)
+	projRepo := newFakeProjectRepo()
+	svc := NewTimeTrackingService(timeRepo, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, newTestClock(now))
+
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+	start := now.Add(-3 * time.Hour)
+	stop := start.Add(90 * time.Minute)
+	entry, err := svc.CreateManual(ctx, cat.ID, start, stop)
+	if err != nil {
+		t.Fatalf("create manual failed: %v", err)
+	}
+	if entry.StoppedAt == nil || !entry.StoppedAt.Equal(stop) {
+		t.Fatalf("expected stoppedAt %v, got %v", stop, entry.StoppedAt)
+	}
+	if entry.DurationSeconds == nil || *entry.DurationSeconds != int32(90*60) {
+		t.Fatalf("expected duration 5400, got %v", entry.DurationSeconds)
+	}
+	active, err := svc.GetActive(ctx)
+	if err != nil {
+		t.Fatalf("get active failed: %v", err)
+	}
+	if active != nil {
+		t.Fatalf("manual entry must not become active")
+	}
+}
+
+func TestTimeTrackingServiceCreateManualRejectsInvalidRange(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	now := time.Date(2025, 11, 2, 18, 0, 0, 0, time.UTC)
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), newFakeProjectRepo(), nil, fakeUnitOfWork{}, newTestClock(now))
+	cat := seedCategory(t, catRepo)
+
+	start := now.Add(-1 * time.Hour)
+	if _, err := svc.CreateManual(ctx, cat.ID, start, start); err != ErrInvalidTimeRange {
+		t.Fatalf("expected ErrInvalidTimeRange for empty range, got %v", err)
+	}
+	if _, err := svc.CreateManual(ctx, cat.ID, start, start.Add(-time.Minute)); err != ErrInvalidTimeRange {
+		t.Fatalf("expected ErrInvalidTimeRange for reversed range, got %v", err)
+	}
+	if _, err := svc.CreateManual(ctx, cat.ID, start, now.Add(time.Minute)); err != ErrInvalidTimeRange {
+		t.Fatalf("expected ErrInvalidTimeRange for future stop, got %v", err)
+	}
+}
+
+func TestTimeTrackingServiceCreateManualRejectsOverlap(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	timeRepo := newFakeTimeEntryRepo()
+	now := time.Date(2025, 11, 2, 18, 0, 0, 0, time.UTC)
+	projRepo := newFakeProjectRepo()
+	svc := NewTimeTrackingService(timeRepo, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, newTestClock(now))
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	start := now.Add(-4 * time.Hour)
+	if _, err := svc.CreateManual(ctx, cat.ID, start, start.Add(time.Hour)); err != nil {
+		t.Fatalf("first create failed: %v", err)
+	}
+	// Overlaps the tail of the first entry
+	if _, err := svc.CreateManual(ctx, cat.ID, start.Add(30*time.Minute), start.Add(2*time.Hour)); err != ErrTimeEntryOverlap {
+		t.Fatalf("expected ErrTimeEntryOverlap, got %v", err)
+	}
+	if len(timeRepo.slotLocks) != 2 || timeRepo.slotLocks[1] != cat.ID {
+		t.Fatalf("expected the overlap check under the slot lock of the category, got %v", timeRepo.slotLocks)
+	}
+	// Touching boundaries is allowed
+	if _, err := svc.CreateManual(ctx, cat.ID, start.Add(time.Hour), start.Add(2*time.Hour)); err != nil {
+		t.Fatalf("adjacent create failed: %v", err)
+	}
+}
+
+func TestTimeTrackingServiceCreateManualRejectsOverlapWithRunningEntry(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
+	now := time.Date(2025, 11, 2, 18, 0, 0, 0, time.UTC)
+	clk := newTestClock(now.Add(-2 * time.Hour))
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{}); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	clk.Set(now)
+	if _, err := svc.CreateManual(ctx, cat.ID, now.Add(-time.Hour), now.Add(-30*time.Minute)); err != ErrTimeEntryOverlap {
+		t.Fatalf("expected ErrTimeEntryOverlap, got %v", err)
+	}
+}
+
+// --- Update tests ---
+
+func TestTimeTrackingServiceUpdateEntryRecomputesDuration(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	now := time.Date(2025, 11, 2, 18, 0, 0, 0, time.UTC)
+	projRepo := newFakeProjectRepo()
+	timeRepo := newFakeTimeEntryRepo()
+	svc := NewTimeTrackingService(timeRepo, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, newTestClock(now))
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+	other := seedCategory(t, catRepo)
+	seedProject(t, projRepo, other.ProjectID, false)
+
+	start := now.Add(-4 * time.Hour)
+	created, err := svc.CreateManual(ctx, cat.ID, start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	timeRepo.slotLocks = nil
+	newStart := start.Add(-30 * time.Minute)
+	updated, err := svc.UpdateEntry(ctx, created.ID, TimeEntryUpdate{CategoryID: &other.ID, StartedAt: &newStart})
+	if err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	// The overlap check runs under the locks of the new and the old timer slot
+	if len(timeRepo.slotLocks) != 2 || timeRepo.slotLocks[0] != other.ID || timeRepo.slotLocks[1] != cat.ID {
+		t.Fatalf("expected the slot locks of both categories, got %v", timeRepo.slotLocks)
+	}
+	if updated.CategoryID != other.ID || !updated.StartedAt.Equal(newStart) {
+		t.Fatalf("unexpected updated entry: %+v", updated)
+	}
+	if updated.DurationSeconds == nil || *updated.DurationSeconds != int32(90*60) {
+		t.Fatalf("expected duration 5400, got %v", updated.DurationSeconds)
+	}
+}
+
+func TestTimeTrackingServiceUpdateEntryValidation(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	now := time.Date(2025, 11, 2, 18, 0, 0, 0, time.UTC)
+	projRepo := newFakeProjectRepo()
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, newTestClock(now))
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	start := now.Add(-4 * time.Hour)
+	first, err := svc.CreateManual(ctx, cat.ID, start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("create first failed: %v", err)
+	}
+	second, err := svc.CreateManual(ctx, cat.ID, start.Add(2*time.Hour), start.Add(3*time.Hour))
+	if err != nil {
+		t.Fatalf("create second failed: %v", err)
+	}
+
+	// Start after stop
+	badStart := start.Add(2 * time.Hour)
+	if _, err := svc.UpdateEntry(ctx, first.ID, TimeEntryUpdate{StartedAt: &badStart}); err != ErrInvalidTimeRange {
+		t.Fatalf("expected ErrInvalidTimeRange, got %v", err)
+	}
+	// Extending into the second entry
+	overlapStop := start.Add(2*time.Hour + time.Minute)
+	if _, err := svc.UpdateEntry(ctx, first.ID, TimeEntryUpdate{StoppedAt: &overlapStop}); err != ErrTimeEntryOverlap {
+		t.Fatalf("expected ErrTimeEntryOverlap, got %v", err)
+	}
+	// Unknown category
+	missing := uuid.New()
+	if _, err := svc.UpdateEntry(ctx, first.ID, TimeEntryUpdate{CategoryID: &missing}); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	// Shrinking the entry never conflicts with itself
+	shorter := start.Add(30 * time.Minute)
+	if _, err := svc.UpdateEntry(ctx, first.ID, TimeEntryUpdate{StoppedAt: &shorter}); err != nil {
+		t.Fatalf("shrink failed: %v", err)
+	}
+	// Reopening the latest entry is allowed while nothing else runs
+	if _, err := svc.UpdateEntry(ctx, second.ID, TimeEntryUpdate{ClearStoppedAt: true}); err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+}
+
+func TestTimeTrackingServiceUpdateEntryRejectsSecondRunningEntry(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	now := time.Date(2025, 11, 2, 18, 0, 0, 0, time.UTC)
+	projRepo := newFakeProjectRepo()
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, newTestClock(now))
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	past, err := svc.CreateManual(ctx, cat.ID, now.Add(-3*time.Hour), now.Add(-2*time.Hour))
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{}); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if _, err := svc.UpdateEntry(ctx, past.ID, TimeEntryUpdate{ClearStoppedAt: true}); err != ErrActiveTimerExists {
+		t.Fatalf("expected ErrActiveTimerExists, got %v", err)
+	}
+}
+
+// --- Delete tests ---
+
+func TestTimeTrackingServiceDeleteRunningEntryCancelsTimer(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, newTestClock(time.Date(2025, 11, 2, 10, 0, 0, 0, time.UTC)))
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	running, err := svc.Start(ctx, cat.ID, StartOptions{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := svc.DeleteEntry(ctx, running.ID); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	active, err := svc.GetActive(ctx)
+	if err != nil {
+		t.Fatalf("get active failed: %v", err)
+	}
+	if active != nil {
+		t.Fatalf("expected no active entry after deleting the running one")
+	}
+	if _, err := svc.StopActive(ctx, StopOptions{}); err != ErrNoActiveTimer {
+		t.Fatalf("expected ErrNoActiveTimer, got %v", err)
+	}
+	if err := svc.DeleteEntry(ctx, running.ID); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound on second delete, got %v", err)
+	}
+}
+
+func TestTimeTrackingServiceDeleteEntriesDryRunKeepsRows(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	timeRepo := newFakeTimeEntryRepo()
+	now := time.Date(2025, 11, 2, 18, 0, 0, 0, time.UTC)
+	projRepo := newFakeProjectRepo()
+	svc := NewTimeTrackingService(timeRepo, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, newTestClock(now))
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	base := now.Add(-6 * time.Hour)
+	for i := 0; i < 3; i++ {
+		start := base.Add(time.Duration(i) * time.Hour)
+		if _, err := svc.CreateManual(ctx, cat.ID, start, start.Add(30*time.Minute)); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+	}
+
+	from, to := base, base.Add(time.Hour)
+	preview, err := svc.DeleteEntries(ctx, cat.ID, from, to, true)
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if len(preview) != 2 || len(timeRepo.items) != 3 {
+		t.Fatalf("expected 2 previewed and 3 kept, got %d and %d", len(preview), len(timeRepo.items))
+	}
+
+	deleted, err := svc.DeleteEntries(ctx, cat.ID, from, to, false)
+	if err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if len(deleted) != 2 || len(timeRepo.items) != 1 {
+		t.Fatalf("expected 2 deleted and 1 kept, got %d and %d", len(deleted), len(timeRepo.items))
+	}
+
+	if _, err := svc.DeleteEntries(ctx, cat.ID, to, from, false); err != ErrInvalidTimeRange {
+		t.Fatalf("expected ErrInvalidTimeRange, got %v", err)
+	}
+}
+
+// --- Note tests ---
+
+func TestTimeTrackingServiceNotesOnStartStopAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	clk := newTestClock(time.Date(2025, 11, 2, 10, 0, 0, 0, time.UTC))
+	projRepo := newFakeProjectRepo()
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	note := "  planning  "
+	started, err := svc.Start(ctx, cat.ID, StartOptions{Note: &note})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if started.Note == nil || *started.Note != "planning" {
+		t.Fatalf("expected trimmed note, got %v", started.Note)
+	}
+
+	// Stopping without a note keeps the one set at start
+	clk.Advance(time.Minute)
+	stopped, err := svc.StopActive(ctx, StopOptions{})
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if stopped.Note == nil || *stopped.Note != "planning" {
+		t.Fatalf("expected note kept on stop, got %v", stopped.Note)
+	}
+
+	blank := " "
+	updated, err := svc.UpdateEntry(ctx, stopped.ID, TimeEntryUpdate{Note: &blank})
+	if err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if updated.Note != nil {
+		t.Fatalf("expected note cleared, got %v", *updated.Note)
+	}
+
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{}); err != nil {
+		t.Fatalf("second start failed: %v", err)
+	}
+	final := "wrapped up"
+	stopped, err = svc.StopActive(ctx, StopOptions{Note: &final})
+	if err != nil {
+		t.Fatalf("second stop failed: %v", err)
+	}
+	if stopped.Note == nil || *stopped.Note != final {
+		t.Fatalf("expected note set on stop, got %v", stopped.Note)
+	}
+
+	list, err := svc.ListByCategory(ctx, cat.ID, repository.TimeEntryFilter{NoteContains: "WRAPPED"})
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != stopped.ID {
+		t.Fatalf("expected one note match, got %d", len(list))
+	}
+}
+
+// --- Tag tests ---
+
+func TestTimeTrackingServiceTagsOnStartUpdateAndFilter(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	tagRepo := newFakeTagRepo()
+	timeRepo := newFakeTimeEntryRepo()
+	clk := newTestClock(time.Date(2025, 11, 2, 10, 0, 0, 0, time.UTC))
+	projRepo := newFakeProjectRepo()
+	svc := NewTimeTrackingService(timeRepo, catRepo, tagRepo, projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	tagSvc := NewTagService(tagRepo, clk)
+	deep, err := tagSvc.Create(ctx, cat.ProjectID, "deep-work")
+	if err != nil {
+		t.Fatalf("create tag failed: %v", err)
+	}
+	meeting, err := tagSvc.Create(ctx, cat.ProjectID, "meeting")
+	if err != nil {
+		t.Fatalf("create tag failed: %v", err)
+	}
+
+	started, err := svc.Start(ctx, cat.ID, StartOptions{TagIDs: []uuid.UUID{deep.ID, deep.ID}})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if len(started.TagIDs) != 1 || started.TagIDs[0] != deep.ID {
+		t.Fatalf("expected deduplicated tag, got %v", started.TagIDs)
+	}
+
+	clk.Advance(time.Minute)
+	stopped, err := svc.StopActive(ctx, StopOptions{})
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if len(stopped.TagIDs) != 1 {
+		t.Fatalf("expected tags kept on stop, got %v", stopped.TagIDs)
+	}
+
+	// A nil TagIDs keeps the tags, a non-nil slice replaces them
+	note := "sync"
+	updated, err := svc.UpdateEntry(ctx, stopped.ID, TimeEntryUpdate{Note: &note})
+	if err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if len(updated.TagIDs) != 1 || updated.TagIDs[0] != deep.ID {
+		t.Fatalf("expected tags unchanged, got %v", updated.TagIDs)
+	}
+	updated, err = svc.UpdateEntry(ctx, stopped.ID, TimeEntryUpdate{TagIDs: []uuid.UUID{meeting.ID}})
+	if err != nil {
+		t.Fatalf("update tags failed: %v", err)
+	}
+	if len(updated.TagIDs) != 1 || updated.TagIDs[0] != meeting.ID {
+		t.Fatalf("expected tags replaced, got %v", updated.TagIDs)
+	}
+
+	list, err := svc.ListByCategory(ctx, cat.ID, repository.TimeEntryFilter{TagID: &deep.ID})
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("expected no entries for removed tag, got %d", len(list))
+	}
+	list, err = svc.ListByCategory(ctx, cat.ID, repository.TimeEntryFilter{TagID: &meeting.ID})
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != stopped.ID {
+		t.Fatalf("expected one tagged entry, got %d", len(list))
+	}
+
+	updated, err = svc.UpdateEntry(ctx, stopped.ID, TimeEntryUpdate{TagIDs: []uuid.UUID{}})
+	if err != nil {
+		t.Fatalf("clear tags failed: %v", err)
+	}
+	if updated.TagIDs == nil || len(updated.TagIDs) != 0 {
+		t.Fatalf("expected empty tags, got %v", updated.TagIDs)
+	}
+}
+
+func TestTimeTrackingServiceRejectsUnknownAndCrossProjectTags(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
+	tagRepo := newFakeTagRepo()
+	now := time.Date(2025, 11, 2, 10, 0, 0, 0, time.UTC)
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, tagRepo, projRepo, nil, fakeUnitOfWork{}, newTestClock(now))
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+	other := seedCategory(t, catRepo)
+	seedProject(t, projRepo, other.ProjectID, false)
+
+	foreign, err := NewTagService(tagRepo, newTestClock(now)).Create(ctx, other.ProjectID, "foreign")
+	if err != nil {
+		t.Fatalf("create tag failed: %v", err)
+	}
+
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{TagIDs: []uuid.UUID{uuid.New()}}); err != ErrInvalidTag {
+		t.Fatalf("expected ErrInvalidTag, got %v", err)
+	}
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{TagIDs: []uuid.UUID{foreign.ID}}); err != ErrCrossProjectTag {
+		t.Fatalf("expected ErrCrossProjectTag, got %v", err)
+	}
+
+	// Moving a tagged entry into another project's category must revalidate its tags
+	entry, err := svc.Start(ctx, other.ID, StartOptions{TagIDs: []uuid.UUID{foreign.ID}})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if _, err := svc.UpdateEntry(ctx, entry.ID, TimeEntryUpdate{CategoryID: &cat.ID}); err != ErrCrossProjectTag {
+		t.Fatalf("expected ErrCrossProjectTag on move, got %v", err)
+	}
+}
+
+// --- Billing tests ---
+
+func TestTimeTrackingServiceBillableDefaultsAndAmounts(t *testing.T) {
+	ctx := context.Background()
+	projRepo := newFakeProjectRepo()
+	catRepo := newFakeCategoryRepo()
+	clk := newTestClock(time.Date(2025, 11, 2, 10, 0, 0, 0, time.UTC))
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+
+	rate := domain.Rate{HourlyRateCents: 12000, Currency: "EUR"}
+	p, _ := projRepo.Create(ctx, domain.Project{ID: uuid.New(), Name: "Client", Rate: &rate})
+	billable, _ := catRepo.Create(ctx, domain.Category{ID: uuid.New(), ProjectID: p.ID, Name: "Dev", Billable: true})
+	internal, _ := catRepo.Create(ctx, domain.Category{ID: uuid.New(), ProjectID: p.ID, Name: "Admin"})
+
+	started, err := svc.Start(ctx, billable.ID, StartOptions{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if !started.Billable || started.BillableAmount != nil {
+		t.Fatalf("expected billable running entry without amount, got %+v", started)
+	}
+	clk.Advance(30 * time.Minute)
+	stopped, err := svc.StopActive(ctx, StopOptions{})
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if stopped.BillableAmount == nil || stopped.BillableAmount.Cents != 6000 || stopped.BillableAmount.Currency != "EUR" {
+		t.Fatalf("expected 60.00 EUR, got %+v", stopped.BillableAmount)
+	}
+
+	manual, err := svc.CreateManual(ctx, internal.ID, clk.Now().Add(-4*time.Hour), clk.Now().Add(-3*time.Hour))
+	if err != nil {
+		t.Fatalf("create manual failed: %v", err)
+	}
+	if manual.Billable || manual.BillableAmount != nil {
+		t.Fatalf("expected non-billable entry from category default, got %+v", manual)
+	}
+	on := true
+	manual, err = svc.UpdateEntry(ctx, manual.ID, TimeEntryUpdate{Billable: &on})
+	if err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if !manual.Billable || manual.BillableAmount == nil || manual.BillableAmount.Cents != 12000 {
+		t.Fatalf("expected billed hour after update, got %+v", manual)
+	}
+
+	off := false
+	overridden, err := svc.Start(ctx, billable.ID, StartOptions{Billable: &off})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if overridden.Billable {
+		t.Fatalf("expected explicit billable=false to override the category default")
+	}
+}
+
+func TestTimeTrackingServicePauseResumeExcludesPausedTime(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	clk := newTestClock(time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC))
+	projRepo := newFakeProjectRepo()
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	if _, err := svc.Pause(ctx, TimerTarget{}); err != ErrNoActiveTimer {
+		t.Fatalf("expected ErrNoActiveTimer, got %v", err)
+	}
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{}); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if _, err := svc.Resume(ctx, TimerTarget{}); err != ErrTimerNotPaused {
+		t.Fatalf("expected ErrTimerNotPaused, got %v", err)
+	}
+
+	clk.Advance(10 * time.Minute)
+	paused, err := svc.Pause(ctx, TimerTarget{})
+	if err != nil {
+		t.Fatalf("pause failed: %v", err)
+	}
+	if !paused.Paused || paused.ElapsedSeconds == nil || *paused.ElapsedSeconds != 600 {
+		t.Fatalf("expected paused entry with 600s elapsed, got %+v", paused)
+	}
+	if _, err := svc.Pause(ctx, TimerTarget{}); err != ErrTimerPaused {
+		t.Fatalf("expected ErrTimerPaused, got %v", err)
+	}
+
+	clk.Advance(15 * time.Minute)
+	active, err := svc.GetActive(ctx)
+	if err != nil || active == nil {
+		t.Fatalf("get active failed: %v", err)
+	}
+	if !active.Paused || *active.ElapsedSeconds != 600 {
+		t.Fatalf("expected elapsed time frozen while paused, got %+v", active)
+	}
+
+	resumed, err := svc.Resume(ctx, TimerTarget{})
+	if err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if resumed.Paused {
+		t.Fatalf("expected running entry after resume")
+	}
+	clk.Advance(5 * time.Minute)
+	active, _ = svc.GetActive(ctx)
+	if active.Paused || *active.ElapsedSeconds != 900 {
+		t.Fatalf("expected 900s elapsed after resume, got %+v", active)
+	}
+
+	stopped, err := svc.StopActive(ctx, StopOptions{})
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if stopped.DurationSeconds == nil || *stopped.DurationSeconds != 900 {
+		t.Fatalf("expected duration 900 excluding the pause, got %v", stopped.DurationSeconds)
+	}
+	if !stopped.StoppedAt.Equal(clk.Now()) {
+		t.Fatalf("expected stoppedAt at now, got %v", stopped.StoppedAt)
+	}
+
+	// Editing other fields keeps the paused time excluded; editing timestamps discards the pauses
+	note := "standup"
+	edited, err := svc.UpdateEntry(ctx, stopped.ID, TimeEntryUpdate{Note: &note})
+	if err != nil {
+		t.Fatalf("update note failed: %v", err)
+	}
+	if *edited.DurationSeconds != 900 {
+		t.Fatalf("expected duration to stay 900, got %d", *edited.DurationSeconds)
+	}
+	newStop := stopped.StoppedAt.Add(-time.Minute)
+	edited, err = svc.UpdateEntry(ctx, stopped.ID, TimeEntryUpdate{StoppedAt: &newStop})
+	if err != nil {
+		t.Fatalf("update stop failed: %v", err)
+	}
+	if *edited.DurationSeconds != 1740 {
+		t.Fatalf("expected continuous duration 1740 after timestamp edit, got %d", *edited.DurationSeconds)
+	}
+}
+
+func TestTimeTrackingServiceStartAutoStopsPausedEntry(t *testing.T) {
+	ctx := context.Background()
+	timeRepo := newFakeTimeEntryRepo()
+	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
+	clk := newTestClock(time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC))
+	svc := NewTimeTrackingService(timeRepo, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	first, _ := svc.Start(ctx, cat.ID, StartOptions{})
+	clk.Advance(20 * time.Minute)
+	if _, err := svc.Pause(ctx, TimerTarget{}); err != nil {
+		t.Fatalf("pause failed: %v", err)
+	}
+	clk.Advance(40 * time.Minute)
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{}); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	prev, _ := timeRepo.GetByID(ctx, first.ID)
+	if prev.DurationSeconds == nil || *prev.DurationSeconds != 1200 {
+		t.Fatalf("expected auto-stopped duration 1200, got %v", prev.DurationSeconds)
+	}
+}
+
+func TestTimeTrackingServiceConcurrentTimersPerCategory(t *testing.T) {
+	ctx := context.Background()
+	timeRepo := newFakeTimeEntryRepo()
+	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
+	clk := newTestClock(time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC))
+	svc := NewTimeTrackingService(timeRepo, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+
+	parallel := seedProject(t, projRepo, uuid.New(), true)
+	classic := seedProject(t, projRepo, uuid.New(), false)
+	dev, _ := catRepo.Create(ctx, domain.Category{ID: uuid.New(), ProjectID: parallel.ID, Name: "Dev"})
+	build, _ := catRepo.Create(ctx, domain.Category{ID: uuid.New(), ProjectID: parallel.ID, Name: "Build"})
+	mail, _ := catRepo.Create(ctx, domain.Category{ID: uuid.New(), ProjectID: classic.ID, Name: "Mail"})
+	meet, _ := catRepo.Create(ctx, domain.Category{ID: uuid.New(), ProjectID: classic.ID, Name: "Meetings"})
+
+	firstDev, _ := svc.Start(ctx, dev.ID, StartOptions{})
+	clk.Advance(time.Minute)
+	buildEntry, _ := svc.Start(ctx, build.ID, StartOptions{})
+	clk.Advance(time.Minute)
+	if running, _ := svc.ListActive(ctx); len(running) != 2 {
+		t.Fatalf("expected two parallel timers, got %d", len(running))
+	}
+
+	// Restarting a category only stops that category's timer
+	if _, err := svc.Start(ctx, dev.ID, StartOptions{}); err != nil {
+		t.Fatalf("restart failed: %v", err)
+	}
+	if prev, _ := timeRepo.GetByID(ctx, firstDev.ID); prev.StoppedAt == nil {
+		t.Fatalf("expected previous dev entry to be stopped")
+	}
+	if active, _ := svc.GetActiveInCategory(ctx, build.ID); active == nil || active.ID != buildEntry.ID {
+		t.Fatalf("expected build timer to keep running, got %+v", active)
+	}
+
+	stopped, err := svc.StopActiveInCategory(ctx, build.ID, StopOptions{})
+	if err != nil || stopped.ID != buildEntry.ID || *stopped.DurationSeconds != 60 {
+		t.Fatalf("expected build entry stopped after 60s, got %+v err=%v", stopped, err)
+	}
+	if _, err := svc.StopActiveInCategory(ctx, build.ID, StopOptions{}); err != ErrNoActiveTimer {
+		t.Fatalf("expected ErrNoActiveTimer, got %v", err)
+	}
+	if _, err := svc.GetActiveInCategory(ctx, uuid.New()); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound for unknown category, got %v", err)
+	}
+
+	// Classic projects share one timer that leaves parallel timers alone
+	mailEntry, _ := svc.Start(ctx, mail.ID, StartOptions{})
+	clk.Advance(time.Minute)
+	if _, err := svc.Start(ctx, meet.ID, StartOptions{}); err != nil {
+		t.Fatalf("start meetings failed: %v", err)
+	}
+	running, _ := svc.ListActive(ctx)
+	if len(running) != 2 || running[0].CategoryID != meet.ID || running[1].CategoryID != dev.ID {
+		t.Fatalf("expected meetings and dev running, got %+v", running)
+	}
+	reopened, _ := timeRepo.GetByID(ctx, mailEntry.ID)
+	if reopened.StoppedAt == nil {
+		t.Fatalf("expected mail entry to be stopped")
+	}
+	if _, err := svc.UpdateEntry(ctx, mailEntry.ID, TimeEntryUpdate{ClearStoppedAt: true}); err != ErrActiveTimerExists {
+		t.Fatalf("expected ErrActiveTimerExists, got %v", err)
+	}
+
+	// Parallel entries may overlap entries of other categories
+	if _, err := svc.CreateManual(ctx, build.ID, clk.Now().Add(-45*time.Second), clk.Now().Add(-15*time.Second)); err != nil {
+		t.Fatalf("expected overlapping entry in another slot to be accepted, got %v", err)
+	}
+}
+
+func TestTimeTrackingServiceReapRunawayStopsAtCap(t *testing.T) {
+	ctx := context.Background()
+	timeRepo := newFakeTimeEntryRepo()
+	catRepo := newFakeCategoryRepo()
+	clk := newTestClock(time.Date(2025, 11, 3, 8, 0, 0, 0, time.UTC))
+	projRepo := newFakeProjectRepo()
+	svc := NewTimeTrackingService(timeRepo, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	// Workday ends at 18:00 UTC+2, i.e. 16:00 UTC
+	workdayEnd := 18 * time.Hour
+	policy := ReaperPolicy{MaxDuration: 10 * time.Hour, WorkdayEnd: &workdayEnd, Location: time.FixedZone("UTC+2", 2*60*60)}
+	entry, _ := svc.Start(ctx, cat.ID, StartOptions{})
+	if stopped, err := svc.ReapRunaway(ctx, ReaperPolicy{}); err != nil || stopped != nil {
+		t.Fatalf("expected disabled policy to stop nothing, got %+v err=%v", stopped, err)
+	}
+	clk.Advance(4 * time.Hour)
+	if stopped, _ := svc.ReapRunaway(ctx, policy); len(stopped) != 0 {
+		t.Fatalf("expected young timer to keep running, got %+v", stopped)
+	}
+
+	clk.Advance(6 * time.Hour)
+	stopped, err := svc.ReapRunaway(ctx, policy)
+	if err != nil {
+		t.Fatalf("reap failed: %v", err)
+	}
+	if len(stopped) != 1 || stopped[0].ID != entry.ID {
+		t.Fatalf("expected entry to be reaped, got %+v", stopped)
+	}
+	want := time.Date(2025, 11, 3, 16, 0, 0, 0, time.UTC)
+	if !stopped[0].StoppedAt.Equal(want) || *stopped[0].DurationSeconds != 8*60*60 {
+		t.Fatalf("expected stop at end of workday %v, got %v (%v)", want, stopped[0].StoppedAt, stopped[0].DurationSeconds)
+	}
+	if !stopped[0].AutoStopped {
+		t.Fatalf("expected entry to be marked auto-stopped")
+	}
+	if active, _ := svc.GetActive(ctx); active != nil {
+		t.Fatalf("expected no running timer, got %+v", active)
+	}
+
+	// Manual stops are not marked; editing the times clears the mark
+	clk.Advance(time.Hour)
+	svc.Start(ctx, cat.ID, StartOptions{})
+	clk.Advance(time.Hour)
+	manual, _ := svc.StopActive(ctx, StopOptions{})
+	if manual.AutoStopped {
+		t.Fatalf("expected manual stop not to be marked auto-stopped")
+	}
+	fixed := want.Add(time.Hour)
+	edited, err := svc.UpdateEntry(ctx, entry.ID, TimeEntryUpdate{StoppedAt: &fixed})
+	if err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if edited.AutoStopped {
+		t.Fatalf("expected corrected entry to clear the auto-stopped mark")
+	}
+}
+
+func TestTimeTrackingServiceReapRunawayExcludesPausedTime(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	clk := newTestClock(time.Date(2025, 11, 3, 20, 0, 0, 0, time.UTC))
+	projRepo := newFakeProjectRepo()
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	svc.Start(ctx, cat.ID, StartOptions{})
+	clk.Advance(30 * time.Minute)
+	if _, err := svc.Pause(ctx, TimerTarget{}); err != nil {
+		t.Fatalf("pause failed: %v", err)
+	}
+	clk.Advance(3 * time.Hour)
+	stopped, err := svc.ReapRunaway(ctx, ReaperPolicy{MaxDuration: 2 * time.Hour})
+	if err != nil || len(stopped) != 1 {
+		t.Fatalf("expected paused entry to be reaped, got %+v err=%v", stopped, err)
+	}
+	if !stopped[0].StoppedAt.Equal(time.Date(2025, 11, 3, 22, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected stop at start+2h, got %v", stopped[0].StoppedAt)
+	}
+	if *stopped[0].DurationSeconds != 1800 || stopped[0].Paused {
+		t.Fatalf("expected 1800s worked and not paused, got %+v", stopped[0])
+	}
+}
+
+func TestTimeTrackingServiceReapRunawayHonoursMidnightAndSegments(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	clk := newTestClock(time.Date(2025, 11, 3, 20, 0, 0, 0, time.UTC))
+	projRepo := newFakeProjectRepo()
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	// A workday ending at 00:00 caps the timer at midnight
+	midnight := time.Duration(0)
+	svc.Start(ctx, cat.ID, StartOptions{})
+	clk.Advance(12 * time.Hour)
+	stopped, err := svc.ReapRunaway(ctx, ReaperPolicy{MaxDuration: 10 * time.Hour, WorkdayEnd: &midnight})
+	if err != nil || len(stopped) != 1 {
+		t.Fatalf("expected entry to be reaped, got %+v err=%v", stopped, err)
+	}
+	if want := time.Date(2025, 11, 4, 0, 0, 0, 0, time.UTC); !stopped[0].StoppedAt.Equal(want) {
+		t.Fatalf("expected stop at midnight %v, got %v", want, stopped[0].StoppedAt)
+	}
+
+	// A cap that falls inside a worked segment moves to the segment's end
+	svc.Start(ctx, cat.ID, StartOptions{})
+	clk.Advance(3 * time.Hour)
+	if _, err := svc.Pause(ctx, TimerTarget{}); err != nil {
+		t.Fatalf("pause failed: %v", err)
+	}
+	pausedAt := clk.Now()
+	clk.Advance(3 * time.Hour)
+	stopped, err = svc.ReapRunaway(ctx, ReaperPolicy{MaxDuration: 2 * time.Hour})
+	if err != nil || len(stopped) != 1 {
+		t.Fatalf("expected paused entry to be reaped, got %+v err=%v", stopped, err)
+	}
+	if !stopped[0].StoppedAt.Equal(pausedAt) || *stopped[0].DurationSeconds != 3*60*60 {
+		t.Fatalf("expected stop at the pause %v with 3h worked, got %v (%v)", pausedAt, stopped[0].StoppedAt, *stopped[0].DurationSeconds)
+	}
+}
+
+// staleActiveRepo lists the running entries as they were when stale was captured, like a server
+// job whose listing raced with a user's stop.
+type staleActiveRepo struct {
+	*fakeTimeEntryRepo
+	stale []domain.TimeEntry
+}
+
+func (r staleActiveRepo) ListActive(context.Context) ([]domain.TimeEntry, error) {
+	return r.stale, nil
+}
+
+func TestTimeTrackingServiceServerStopsSkipEntriesStoppedMeanwhile(t *testing.T) {
+	ctx := context.Background()
+	timeRepo := newFakeTimeEntryRepo()
+	catRepo := newFakeCategoryRepo()
+	clk := newTestClock(time.Date(2025, 11, 3, 8, 0, 0, 0, time.UTC))
+	projRepo := newFakeProjectRepo()
+	audit := &fakeAuditRepo{}
+	svc := NewTimeTrackingService(timeRepo, catRepo, newFakeTagRepo(), projRepo, audit, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	target := int32(1800)
+	entry, err := svc.Start(ctx, cat.ID, StartOptions{TargetSeconds: &target})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	stale, _ := timeRepo.ListActive(ctx)
+	clk.Advance(20 * time.Minute)
+	stoppedByUser, err := svc.StopActive(ctx, StopOptions{})
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+
+	clk.Advance(3 * time.Hour)
+	jobs := NewTimeTrackingService(staleActiveRepo{timeRepo, stale}, catRepo, newFakeTagRepo(), projRepo, audit, fakeUnitOfWork{}, clk)
+	if stopped, err := jobs.StopReachedTargets(ctx); err != nil || len(stopped) != 0 {
+		t.Fatalf("expected no target stop, got %+v err=%v", stopped, err)
+	}
+	if stopped, err := jobs.ReapRunaway(ctx, ReaperPolicy{MaxDuration: time.Hour}); err != nil || len(stopped) != 0 {
+		t.Fatalf("expected nothing reaped, got %+v err=%v", stopped, err)
+	}
+	got, _ := timeRepo.GetByID(ctx, entry.ID)
+	if !got.StoppedAt.Equal(*stoppedByUser.StoppedAt) || got.AutoStopped || got.Version != stoppedByUser.Version {
+		t.Fatalf("expected the user's stop to stand, got %+v", got)
+	}
+	stops := 0
+	for _, e := range audit.events {
+		if e.Action == domain.AuditStop {
+			stops++
+		}
+	}
+	if stops != 1 {
+		t.Fatalf("expected one stop event, got %d", stops)
+	}
+}
+
+// recordingUnitOfWork runs the work directly and records how each unit of work ended.
+type recordingUnitOfWork struct {
+	results []error
+}
+
+func (u *recordingUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := fn(ctx)
+	u.results = append(u.results, err)
+	return err
+}
+
+func TestTimeTrackingServiceStartAndStopRunInUnitOfWork(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+	now := time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC)
+
+	// A concurrent start that won the race surfaces as a conflict and rolls the work back
+	uow := &recordingUnitOfWork{}
+	svc := NewTimeTrackingService(stubTimeRepo{createErr: repository.ErrRunningEntryConflict}, catRepo, newFakeTagRepo(), projRepo, nil, uow, newTestClock(now))
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{}); err != repository.ErrRunningEntryConflict {
+		t.Fatalf("expected ErrRunningEntryConflict, got %v", err)
+	}
+	if len(uow.results) != 1 || uow.results[0] != repository.ErrRunningEntryConflict {
+		t.Fatalf("expected one failed unit of work, got %v", uow.results)
+	}
+
+	uow = &recordingUnitOfWork{}
+	svc = NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, nil, uow, newTestClock(now))
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{}); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if _, err := svc.StopActive(ctx, StopOptions{}); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if _, err := svc.StopActive(ctx, StopOptions{}); err != ErrNoActiveTimer {
+		t.Fatalf("expected ErrNoActiveTimer, got %v", err)
+	}
+	if len(uow.results) != 3 || uow.results[0] != nil || uow.results[1] != nil || uow.results[2] != ErrNoActiveTimer {
+		t.Fatalf("expected start and stops in their own units of work, got %v", uow.results)
+	}
+}
+
+func TestTimeTrackingServiceSplitStoppedEntry(t *testing.T) {
+	ctx := context.Background()
+	timeRepo := newFakeTimeEntryRepo()
+	catRepo := newFakeCategoryRepo()
+	tagRepo := newFakeTagRepo()
+	projRepo := newFakeProjectRepo()
+	clk := newTestClock(time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC))
+	svc := NewTimeTrackingService(timeRepo, catRepo, tagRepo, projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+	review, _ := catRepo.Create(ctx, domain.Category{ID: uuid.New(), ProjectID: cat.ProjectID, Name: "Review"})
+	tag, _ := NewTagService(tagRepo, clk).Create(ctx, cat.ProjectID, "focus")
+	note := "feature"
+
+	// 09:00-09:30 worked, 09:30-09:40 paused, 09:40-10:00 worked
+	entry, _ := svc.Start(ctx, cat.ID, StartOptions{Note: &note, TagIDs: []uuid.UUID{tag.ID}})
+	clk.Advance(30 * time.Minute)
+	svc.Pause(ctx, TimerTarget{})
+	clk.Advance(10 * time.Minute)
+	svc.Resume(ctx, TimerTarget{})
+	clk.Advance(20 * time.Minute)
+	svc.StopActive(ctx, StopOptions{})
+
+	if _, _, err := svc.Split(ctx, entry.ID, entry.StartedAt, nil); err != ErrInvalidSplitTime {
+		t.Fatalf("expected ErrInvalidSplitTime at the start, got %v", err)
+	}
+	if _, _, err := svc.Split(ctx, entry.ID, clk.Now(), nil); err != ErrInvalidSplitTime {
+		t.Fatalf("expected ErrInvalidSplitTime at the end, got %v", err)
+	}
+	if _, _, err := svc.Split(ctx, uuid.New(), clk.Now(), nil); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	at := entry.StartedAt.Add(20 * time.Minute)
+	timeRepo.slotLocks = nil
+	first, second, err := svc.Split(ctx, entry.ID, at, &review.ID)
+	if err != nil {
+		t.Fatalf("split failed: %v", err)
+	}
+	if len(timeRepo.slotLocks) != 1 || timeRepo.slotLocks[0] != review.ID {
+		t.Fatalf("expected the overlap check under the slot lock of the new category, got %v", timeRepo.slotLocks)
+	}
+	if first.ID != entry.ID || !first.StoppedAt.Equal(at) || *first.DurationSeconds != 1200 {
+		t.Fatalf("unexpected first half: %+v", first)
+	}
+	if second.CategoryID != review.ID || !second.StartedAt.Equal(at) || !second.StoppedAt.Equal(clk.Now()) {
+		t.Fatalf("unexpected second half: %+v", second)
+	}
+	if *second.DurationSeconds != 1800 {
+		t.Fatalf("expected 1800s worked in the second half excluding the pause, got %d", *second.DurationSeconds)
+	}
+	if second.Note == nil || *second.Note != note || len(second.TagIDs) != 1 || second.TagIDs[0] != tag.ID {
+		t.Fatalf("expected note and tags to be copied, got %+v", second)
+	}
+}
+
+func TestTimeTrackingServiceSplitRunningEntryKeepsSecondHalfRunning(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
+	clk := newTestClock(time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC))
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+	other, _ := catRepo.Create(ctx, domain.Category{ID: uuid.New(), ProjectID: cat.ProjectID, Name: "Other"})
+
+	entry, _ := svc.Start(ctx, cat.ID, StartOptions{})
+	clk.Advance(time.Hour)
+	svc.Pause(ctx, TimerTarget{})
+	clk.Advance(time.Hour)
+	if _, _, err := svc.Split(ctx, entry.ID, clk.Now(), nil); err != ErrInvalidSplitTime {
+		t.Fatalf("expected ErrInvalidSplitTime at now, got %v", err)
+	}
+
+	// Splitting inside the pause: the first half keeps the hour of work, the second stays paused
+	first, second, err := svc.Split(ctx, entry.ID, entry.StartedAt.Add(90*time.Minute), &other.ID)
+	if err != nil {
+		t.Fatalf("split failed: %v", err)
+	}
+	if *first.DurationSeconds != 3600 || first.Paused {
+		t.Fatalf("unexpected first half: %+v", first)
+	}
+	if second.StoppedAt != nil || !second.Paused || *second.ElapsedSeconds != 0 {
+		t.Fatalf("expected paused running second half, got %+v", second)
+	}
+	active, _ := svc.GetActive(ctx)
+	if active == nil || active.ID != second.ID || active.CategoryID != other.ID {
+		t.Fatalf("expected second half to be the active entry, got %+v", active)
+	}
+
+	svc.Resume(ctx, TimerTarget{})
+	clk.Advance(15 * time.Minute)
+	stopped, _ := svc.StopActive(ctx, StopOptions{})
+	if *stopped.DurationSeconds != 900 {
+		t.Fatalf("expected 900s after resuming, got %d", *stopped.DurationSeconds)
+	}
+}
+
+func TestTimeTrackingServiceMergeEntries(t *testing.T) {
+	ctx := context.Background()
+	timeRepo := newFakeTimeEntryRepo()
+	catRepo := newFakeCategoryRepo()
+	tagRepo := newFakeTagRepo()
+	projRepo := newFakeProjectRepo()
+	clk := newTestClock(time.Date(2025, 11, 2, 12, 0, 0, 0, time.UTC))
+	svc := NewTimeTrackingService(timeRepo, catRepo, tagRepo, projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+	other, _ := catRepo.Create(ctx, domain.Category{ID: uuid.New(), ProjectID: cat.ProjectID, Name: "Other"})
+	tag, _ := NewTagService(tagRepo, clk).Create(ctx, cat.ProjectID, "focus")
+
+	base := time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC)
+	a, _ := svc.CreateManual(ctx, cat.ID, base, base.Add(10*time.Minute))
+	b, _ := svc.CreateManual(ctx, cat.ID, base.Add(11*time.Minute), base.Add(20*time.Minute))
+	c, _ := svc.CreateManual(ctx, cat.ID, base.Add(20*time.Minute), base.Add(30*time.Minute))
+	foreign, _ := svc.CreateManual(ctx, other.ID, base.Add(40*time.Minute), base.Add(50*time.Minute))
+	noteA, noteC := "design", "review"
+	svc.UpdateEntry(ctx, a.ID, TimeEntryUpdate{Note: &noteA})
+	svc.UpdateEntry(ctx, c.ID, TimeEntryUpdate{Note: &noteC, TagIDs: []uuid.UUID{tag.ID}})
+
+	if _, err := svc.Merge(ctx, []uuid.UUID{a.ID, a.ID}, 0); err != ErrInvalidMerge {
+		t.Fatalf("expected ErrInvalidMerge for a single entry, got %v", err)
+	}
+	if _, err := svc.Merge(ctx, []uuid.UUID{a.ID, foreign.ID}, time.Hour); err != ErrCrossCategoryMerge {
+		t.Fatalf("expected ErrCrossCategoryMerge, got %v", err)
+	}
+	if _, err := svc.Merge(ctx, []uuid.UUID{c.ID, b.ID, a.ID}, 0); err != ErrEntriesNotContiguous {
+		t.Fatalf("expected ErrEntriesNotContiguous with a one minute gap, got %v", err)
+	}
+	if _, err := svc.Merge(ctx, []uuid.UUID{a.ID, uuid.New()}, 0); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	timeRepo.slotLocks = nil
+	merged, err := svc.Merge(ctx, []uuid.UUID{c.ID, b.ID, a.ID}, time.Minute)
+	if err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	if len(timeRepo.slotLocks) != 1 || timeRepo.slotLocks[0] != cat.ID {
+		t.Fatalf("expected the overlap check under the slot lock of the category, got %v", timeRepo.slotLocks)
+	}
+	if merged.ID != a.ID || !merged.StartedAt.Equal(base) || !merged.StoppedAt.Equal(base.Add(30*time.Minute)) || *merged.DurationSeconds != 1800 {
+		t.Fatalf("unexpected merged entry: %+v", merged)
+	}
+	if merged.Note == nil || *merged.Note != "design; review" || len(merged.TagIDs) != 1 || merged.TagIDs[0] != tag.ID {
+		t.Fatalf("expected combined notes and tags, got %+v", merged)
+	}
+	if _, err := timeRepo.GetByID(ctx, b.ID); err != repository.ErrNotFound {
+		t.Fatalf("expected merged-away entry to be deleted, got %v", err)
+	}
+
+	// Merging with the running entry keeps the result running; the span must not cover other entries of the slot
+	running, _ := svc.Start(ctx, cat.ID, StartOptions{})
+	if _, err := svc.Merge(ctx, []uuid.UUID{merged.ID, running.ID}, 3*time.Hour); err != ErrTimeEntryOverlap {
+		t.Fatalf("expected ErrTimeEntryOverlap across the other category's entry, got %v", err)
+	}
+	svc.DeleteEntry(ctx, foreign.ID)
+	result, err := svc.Merge(ctx, []uuid.UUID{merged.ID, running.ID}, 3*time.Hour)
+	if err != nil {
+		t.Fatalf("merge with running entry failed: %v", err)
+	}
+	if result.StoppedAt != nil || result.DurationSeconds != nil {
+		t.Fatalf("expected merged entry to keep running, got %+v", result)
+	}
+	if active, _ := svc.GetActive(ctx); active == nil || active.ID != merged.ID {
+		t.Fatalf("expected merged entry to be active, got %+v", active)
+	}
+}
+
+func TestTimeTrackingServiceListByProjectAndCategoryTree(t *testing.T) {
+	ctx := context.Background()
+	timeRepo := newFakeTimeEntryRepo()
+	catRepo := newFakeCategoryRepo()
+	timeRepo.categories = catRepo
+	projRepo := newFakeProjectRepo()
+	clk := newTestClock(time.Date(2025, 11, 3, 18, 0, 0, 0, time.UTC))
+	svc := NewTimeTrackingService(timeRepo, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+
+	root := seedCategory(t, catRepo)
+	seedProject(t, projRepo, root.ProjectID, false)
+	child, _ := catRepo.Create(ctx, domain.Category{ID: uuid.New(), ProjectID: root.ProjectID, ParentCategoryID: &root.ID, Name: "Child"})
+	grandchild, _ := catRepo.Create(ctx, domain.Category{ID: uuid.New(), ProjectID: root.ProjectID, ParentCategoryID: &child.ID, Name: "Grandchild"})
+	sibling, _ := catRepo.Create(ctx, domain.Category{ID: uuid.New(), ProjectID: root.ProjectID, Name: "Sibling"})
+	foreign := seedCategory(t, catRepo)
+	seedProject(t, projRepo, foreign.ProjectID, false)
+
+	base := time.Date(2025, 11, 3, 8, 0, 0, 0, time.UTC)
+	for i, catID := range []uuid.UUID{root.ID, child.ID, grandchild.ID, sibling.ID, foreign.ID} {
+		start := base.Add(time.Duration(i) * time.Hour)
+		if _, err := svc.CreateManual(ctx, catID, start, start.Add(30*time.Minute)); err != nil {
+			t.Fatalf("seed entry failed: %v", err)
+		}
+	}
+
+	all, err := svc.ListByProject(ctx, root.ProjectID, nil, nil, repository.TimeEntryFilter{})
+	if err != nil {
+		t.Fatalf("list by project failed: %v", err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("expected the project's 4 entries, got %d", len(all))
+	}
+	for _, e := range all {
+		if e.CategoryID == grandchild.ID && (e.CategoryName != "Grandchild" || !slices.Equal(e.CategoryPath, []string{"Cat", "Child", "Grandchild"})) {
+			t.Fatalf("unexpected category path: %q %v", e.CategoryName, e.CategoryPath)
+		}
+	}
+
+	from := base.Add(90 * time.Minute)
+	bounded, _ := svc.ListByProject(ctx, root.ProjectID, &from, nil, repository.TimeEntryFilter{})
+	if len(bounded) != 2 {
+		t.Fatalf("expected a lone from bound to keep 2 entries, got %d", len(bounded))
+	}
+
+	tree, err := svc.ListByCategoryTree(ctx, child.ID, nil, nil, repository.TimeEntryFilter{})
+	if err != nil {
+		t.Fatalf("list by category tree failed: %v", err)
+	}
+	if len(tree) != 2 || tree[0].CategoryID != grandchild.ID || tree[1].CategoryID != child.ID {
+		t.Fatalf("expected child and grandchild entries newest first, got %+v", tree)
+	}
+
+	if _, err := svc.ListByProject(ctx, uuid.New(), nil, nil, repository.TimeEntryFilter{}); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound for unknown project, got %v", err)
+	}
+	if _, err := svc.ListByCategoryTree(ctx, uuid.New(), nil, nil, repository.TimeEntryFilter{}); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound for unknown category, got %v", err)
+	}
+}
+
+func TestTimeTrackingServiceListOverlapModeAndClip(t *testing.T) {
+	ctx := context.Background()
+	timeRepo := newFakeTimeEntryRepo()
+	catRepo := newFakeCategoryRepo()
+	timeRepo.categories = catRepo
+	projRepo := newFakeProjectRepo()
+	day := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	clk := newTestClock(day.Add(10 * time.Hour))
+	svc := NewTimeTrackingService(timeRepo, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	overnight, _ := svc.CreateManual(ctx, cat.ID, day.Add(-time.Hour), day.Add(time.Hour))
+	morning, _ := svc.CreateManual(ctx, cat.ID, day.Add(2*time.Hour), day.Add(3*time.Hour))
+	if _, err := svc.CreateManual(ctx, cat.ID, day.Add(-4*time.Hour), day.Add(-3*time.Hour)); err != nil {
+		t.Fatalf("seed entry failed: %v", err)
+	}
+	// Running since 10:00 with a pause from 10:30 to 11:00; now is 12:00
+	running, _ := svc.Start(ctx, cat.ID, StartOptions{})
+	clk.Advance(30 * time.Minute)
+	svc.Pause(ctx, TimerTarget{})
+	clk.Advance(30 * time.Minute)
+	svc.Resume(ctx, TimerTarget{})
+	clk.Advance(time.Hour)
+
+	started, _ := svc.ListByCategoryAndRange(ctx, cat.ID, day, day.Add(24*time.Hour), repository.TimeEntryFilter{})
+	if len(started) != 2 {
+		t.Fatalf("expected the started-in-range mode to miss the overnight entry, got %d entries", len(started))
+	}
+
+	filter := repository.TimeEntryFilter{Range: repository.RangeOverlap, Clip: true}
+	overlap, err := svc.ListByCategoryAndRange(ctx, cat.ID, day, day.Add(24*time.Hour), filter)
+	if err != nil {
+		t.Fatalf("overlap listing failed: %v", err)
+	}
+	clipped := map[uuid.UUID]int32{}
+	for _, e := range overlap {
+		if e.ClippedSeconds == nil {
+			t.Fatalf("expected clipped seconds on %+v", e)
+		}
+		clipped[e.ID] = *e.ClippedSeconds
+	}
+	if len(overlap) != 3 || clipped[overnight.ID] != 3600 || clipped[morning.ID] != 3600 || clipped[running.ID] != 5400 {
+		t.Fatalf("unexpected overlap listing: %v", clipped)
+	}
+
+	// Clipping a paused entry excludes the pause: 10:15-10:30 plus 11:00-12:00
+	from := day.Add(10*time.Hour + 15*time.Minute)
+	tail, _ := svc.ListByProject(ctx, cat.ProjectID, &from, nil, filter)
+	if len(tail) != 1 || tail[0].ID != running.ID || *tail[0].ClippedSeconds != 4500 {
+		t.Fatalf("expected the running entry clipped to 4500s, got %+v", tail)
+	}
+
+	if plain, _ := svc.ListByCategory(ctx, cat.ID, repository.TimeEntryFilter{}); plain[0].ClippedSeconds != nil {
+		t.Fatalf("expected no clipped seconds without clip, got %v", *plain[0].ClippedSeconds)
+	}
+}
+
+func TestTimeTrackingServiceRoundsEntriesPerProjectSettings(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
+	now := time.Date(2025, 11, 3, 18, 0, 0, 0, time.UTC)
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, newTestClock(now))
+	cat := seedCategory(t, catRepo)
+	project := seedProject(t, projRepo, cat.ProjectID, false)
+	project.Settings.Rounding = domain.Rounding{IncrementSeconds: 360, Mode: domain.RoundUp, Scope: domain.RoundPerEntry}
+	if _, err := projRepo.UpdateSettings(ctx, project.ID, project.Settings); err != nil {
+		t.Fatalf("update settings failed: %v", err)
+	}
+
+	start := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	entry, err := svc.CreateManual(ctx, cat.ID, start, start.Add(7*time.Minute))
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if *entry.DurationSeconds != 420 || entry.RoundedSeconds == nil || *entry.RoundedSeconds != 720 {
+		t.Fatalf("expected 420s rounded up to 720s, got %v / %v", *entry.DurationSeconds, entry.RoundedSeconds)
+	}
+
+	// Per-day rounding leaves single entries exact
+	project.Settings.Rounding.Scope = domain.RoundPerDay
+	projRepo.UpdateSettings(ctx, project.ID, project.Settings)
+	entries, _ := svc.ListByCategory(ctx, cat.ID, repository.TimeEntryFilter{})
+	if len(entries) != 1 || *entries[0].RoundedSeconds != 420 {
+		t.Fatalf("expected the exact duration under per-day rounding, got %+v", entries)
+	}
+
+	running, _ := svc.Start(ctx, cat.ID, StartOptions{})
+	if running.RoundedSeconds != nil {
+		t.Fatalf("expected no rounded duration on a running entry, got %v", *running.RoundedSeconds)
+	}
+}
+
+func TestTimeTrackingServiceTargetTimersStopAtTarget(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
+	start := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	clk := newTestClock(start)
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	zero := int32(0)
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{TargetSeconds: &zero}); err != ErrInvalidTarget {
+		t.Fatalf("expected ErrInvalidTarget, got %v", err)
+	}
+
+	target := int32(1500)
+	entry, err := svc.Start(ctx, cat.ID, StartOptions{TargetSeconds: &target})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	clk.Advance(10 * time.Minute)
+	active, _ := svc.GetActive(ctx)
+	if active == nil || active.RemainingSeconds == nil || *active.RemainingSeconds != 900 {
+		t.Fatalf("expected 900s remaining, got %+v", active)
+	}
+
+	// A five minute pause pushes the target instant from 09:25 to 09:30
+	svc.Pause(ctx, TimerTarget{})
+	clk.Advance(5 * time.Minute)
+	svc.Resume(ctx, TimerTarget{})
+	if stopped, err := svc.StopReachedTargets(ctx); err != nil || len(stopped) != 0 {
+		t.Fatalf("expected nothing to stop yet, got %v err=%v", stopped, err)
+	}
+	clk.Advance(20 * time.Minute)
+	stopped, err := svc.StopReachedTargets(ctx)
+	if err != nil || len(stopped) != 1 {
+		t.Fatalf("expected the target timer to be stopped, got %v err=%v", stopped, err)
+	}
+	got := stopped[0]
+	if got.ID != entry.ID || !got.StoppedAt.Equal(start.Add(30*time.Minute)) || *got.DurationSeconds != target || !got.AutoStopped || got.RemainingSeconds != nil {
+		t.Fatalf("expected the entry stopped at 09:30 after 1500s, got %+v", got)
+	}
+
+	// A job running exactly at the target instant still marks the entry auto-stopped
+	onTime, _ := svc.Start(ctx, cat.ID, StartOptions{TargetSeconds: &target})
+	clk.Set(onTime.StartedAt.Add(time.Duration(target) * time.Second))
+	stopped, err = svc.StopReachedTargets(ctx)
+	if err != nil || len(stopped) != 1 || !stopped[0].StoppedAt.Equal(clk.Now()) || !stopped[0].AutoStopped {
+		t.Fatalf("expected the entry auto-stopped at its target instant, got %+v err=%v", stopped, err)
+	}
+
+	// Stopping by hand after the target still ends the entry at the target instant
+	breakTarget := int32(300)
+	billable := true
+	brk, err := svc.Start(ctx, cat.ID, StartOptions{TargetSeconds: &breakTarget, Break: true, Billable: &billable})
+	if err != nil || !brk.Break || brk.Billable {
+		t.Fatalf("expected a non-billable break, got %+v err=%v", brk, err)
+	}
+	clk.Advance(10 * time.Minute)
+	out, err := svc.StopActive(ctx, StopOptions{})
+	if err != nil || !out.StoppedAt.Equal(brk.StartedAt.Add(5*time.Minute)) || !out.AutoStopped {
+		t.Fatalf("expected the break to end at its target, got %+v err=%v", out, err)
+	}
+}
+
+func TestTimeTrackingServiceStartAtSameInstantStopsRunningEntry(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
+	clk := newTestClock(time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC))
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	first, err := svc.Start(ctx, cat.ID, StartOptions{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	// Both starts read the same clock value: the first entry ends with zero seconds
+	second, err := svc.Start(ctx, cat.ID, StartOptions{})
+	if err != nil || second.ID == first.ID {
+		t.Fatalf("expected a second start at the same instant, got %+v err=%v", second, err)
+	}
+	entries, _ := svc.ListByCategory(ctx, cat.ID, repository.TimeEntryFilter{})
+	for _, e := range entries {
+		if e.ID == first.ID && (e.StoppedAt == nil || !e.StoppedAt.Equal(first.StartedAt) || *e.DurationSeconds != 0) {
+			t.Fatalf("expected the first entry stopped with zero seconds, got %+v", e)
+		}
+	}
+}
+
+func TestTimeTrackingServiceBackdatedStartAndExplicitStop(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
+	nine := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	clk := newTestClock(nine)
+	timeRepo := newFakeTimeEntryRepo()
+	svc := NewTimeTrackingService(timeRepo, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+	at := func(minutes int) *time.Time {
+		ts := nine.Add(time.Duration(minutes) * time.Minute)
+		return &ts
+	}
+
+	first, err := svc.Start(ctx, cat.ID, StartOptions{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	clk.Advance(30 * time.Minute)
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{StartedAt: at(31)}); err != ErrInvalidTimerTime {
+		t.Fatalf("expected ErrInvalidTimerTime for a future start, got %v", err)
+	}
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{StartedAt: at(-5)}); err != ErrInvalidTimerTime {
+		t.Fatalf("expected ErrInvalidTimerTime for a start before the running entry, got %v", err)
+	}
+
+	// The running entry is stopped at the backdated start
+	timeRepo.slotLocks = nil
+	second, err := svc.Start(ctx, cat.ID, StartOptions{StartedAt: at(20)})
+	if err != nil || !second.StartedAt.Equal(*at(20)) {
+		t.Fatalf("expected an entry started at 09:20, got %+v err=%v", second, err)
+	}
+	if len(timeRepo.slotLocks) != 1 || timeRepo.slotLocks[0] != cat.ID {
+		t.Fatalf("expected the overlap check under the slot lock of the category, got %v", timeRepo.slotLocks)
+	}
+	stopped, _ := svc.ListByCategory(ctx, cat.ID, repository.TimeEntryFilter{})
+	for _, e := range stopped {
+		if e.ID == first.ID && (e.StoppedAt == nil || !e.StoppedAt.Equal(*at(20)) || *e.DurationSeconds != 1200) {
+			t.Fatalf("expected the first entry stopped at 09:20 after 1200s, got %+v", e)
+		}
+	}
+
+	if _, err := svc.StopActive(ctx, StopOptions{StoppedAt: at(15)}); err != ErrInvalidTimerTime {
+		t.Fatalf("expected ErrInvalidTimerTime for a stop before the start, got %v", err)
+	}
+	out, err := svc.StopActive(ctx, StopOptions{StoppedAt: at(25)})
+	if err != nil || !out.StoppedAt.Equal(*at(25)) || *out.DurationSeconds != 300 {
+		t.Fatalf("expected a stop at 09:25 after 300s, got %+v err=%v", out, err)
+	}
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{StartedAt: at(22)}); err != ErrTimeEntryOverlap {
+		t.Fatalf("expected ErrTimeEntryOverlap for a start inside a finished entry, got %v", err)
+	}
+
+	// Stops cannot precede the last pause
+	if _, err := svc.Start(ctx, cat.ID, StartOptions{StartedAt: at(25)}); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	clk.Advance(10 * time.Minute)
+	svc.Pause(ctx, TimerTarget{})
+	clk.Advance(10 * time.Minute)
+	if _, err := svc.StopActive(ctx, StopOptions{StoppedAt: at(35)}); err != ErrInvalidTimerTime {
+		t.Fatalf("expected ErrInvalidTimerTime for a stop before the pause, got %v", err)
+	}
+	out, err = svc.StopActive(ctx, StopOptions{StoppedAt: at(45)})
+	if err != nil || *out.DurationSeconds != 900 {
+		t.Fatalf("expected 900s worked before the pause, got %+v err=%v", out, err)
+	}
+}
+
+func TestTimeTrackingServiceContinueCopiesNoteAndTags(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	tagRepo := newFakeTagRepo()
+	projRepo := newFakeProjectRepo()
+	clk := newTestClock(time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC))
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, tagRepo, projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+	review, _ := catRepo.Create(ctx, domain.Category{ID: uuid.New(), ProjectID: cat.ProjectID, Name: "Review"})
+	tag, _ := NewTagService(tagRepo, clk).Create(ctx, cat.ProjectID, "focus")
+
+	if _, err := svc.Continue(ctx, nil); err != ErrNothingToContinue {
+		t.Fatalf("expected ErrNothingToContinue, got %v", err)
+	}
+
+	note := "feature"
+	feature, _ := svc.Start(ctx, cat.ID, StartOptions{Note: &note, TagIDs: []uuid.UUID{tag.ID}})
+	clk.Advance(time.Hour)
+	svc.StopActive(ctx, StopOptions{})
+	clk.Advance(time.Hour)
+	svc.Start(ctx, review.ID, StartOptions{})
+	clk.Advance(15 * time.Minute)
+
+	// The running review entry is not the last stopped one, so the feature work continues
+	continued, err := svc.Continue(ctx, nil)
+	if err != nil {
+		t.Fatalf("continue failed: %v", err)
+	}
+	if continued.CategoryID != cat.ID || continued.Note == nil || *continued.Note != note || len(continued.TagIDs) != 1 || continued.TagIDs[0] != tag.ID {
+		t.Fatalf("expected the feature entry continued, got %+v", continued)
+	}
+	running, _ := svc.ListActive(ctx)
+	if len(running) != 1 || running[0].ID != continued.ID {
+		t.Fatalf("expected the review entry auto-stopped, got %+v", running)
+	}
+
+	// Breaks are skipped: after lunch the feature work continues
+	clk.Advance(time.Minute)
+	svc.Start(ctx, review.ID, StartOptions{Break: true})
+	clk.Advance(30 * time.Minute)
+	svc.StopActive(ctx, StopOptions{})
+	afterLunch, err := svc.Continue(ctx, nil)
+	if err != nil || afterLunch.CategoryID != cat.ID || afterLunch.Break {
+		t.Fatalf("expected the work before the break continued, got %+v err=%v", afterLunch, err)
+	}
+
+	// An explicit entry wins over the last stopped one
+	clk.Advance(time.Minute)
+	again, err := svc.Continue(ctx, &feature.ID)
+	if err != nil || again.CategoryID != cat.ID || again.ID == continued.ID {
+		t.Fatalf("expected a new entry continuing the given one, got %+v err=%v", again, err)
+	}
+	missing := uuid.New()
+	if _, err := svc.Continue(ctx, &missing); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestTimeTrackingServiceUpdateEntryHonorsExpectedVersion(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
+	clk := newTestClock(time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC))
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+	clk.Advance(time.Hour)
+	entry, _ := svc.CreateManual(ctx, cat.ID, clk.Now().Add(-time.Hour), clk.Now())
+
+	billable := true
+	updated, err := svc.UpdateEntry(repository.WithExpectedVersion(ctx, entry.ID, entry.Version), entry.ID, TimeEntryUpdate{Billable: &billable})
+	if err != nil || updated.Version != entry.Version+1 {
+		t.Fatalf("expected the version to advance, got %+v err=%v", updated, err)
+	}
+	// The first version is stale now
+	if _, err := svc.UpdateEntry(repository.WithExpectedVersion(ctx, entry.ID, entry.Version), entry.ID, TimeEntryUpdate{Billable: &billable}); err != repository.ErrVersionMismatch {
+		t.Fatalf("expected ErrVersionMismatch, got %v", err)
+	}
+	if err := svc.DeleteEntry(repository.WithExpectedVersion(ctx, entry.ID, entry.Version), entry.ID); err != repository.ErrVersionMismatch {
+		t.Fatalf("expected ErrVersionMismatch on delete, got %v", err)
+	}
+	if err := svc.DeleteEntry(repository.WithExpectedVersion(ctx, entry.ID, updated.Version), entry.ID); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+}
+
+func TestTimeTrackingServicePauseResumeTargetsEntryOrCategory(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
+	audit := &fakeAuditRepo{}
+	clk := newTestClock(time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC))
+	svc := NewTimeTrackingService(newFakeTimeEntryRepo(), catRepo, newFakeTagRepo(), projRepo, audit, fakeUnitOfWork{}, clk)
+	a := seedCategory(t, catRepo)
+	seedProject(t, projRepo, a.ProjectID, true)
+	b := seedCategory(t, catRepo)
+	seedProject(t, projRepo, b.ProjectID, true)
+
+	older, err := svc.Start(ctx, a.ID, StartOptions{})
+	if err != nil {
+		t.Fatalf("start a failed: %v", err)
+	}
+	clk.Advance(time.Minute)
+	newer, err := svc.Start(ctx, b.ID, StartOptions{})
+	if err != nil {
+		t.Fatalf("start b failed: %v", err)
+	}
+
+	// Concurrent timers: the older entry can be paused by category or by ID
+	clk.Advance(time.Minute)
+	paused, err := svc.Pause(ctx, TimerTarget{CategoryID: &a.ID})
+	if err != nil || paused.ID != older.ID || !paused.Paused {
+		t.Fatalf("expected the entry of a paused, got %+v (%v)", paused, err)
+	}
+	if _, err := svc.Pause(ctx, TimerTarget{EntryID: &older.ID}); err != ErrTimerPaused {
+		t.Fatalf("expected ErrTimerPaused, got %v", err)
+	}
+	if active, _ := svc.GetActive(ctx); active == nil || active.ID != newer.ID || active.Paused {
+		t.Fatalf("expected the newer entry to keep running, got %+v", active)
+	}
+	resumed, err := svc.Resume(ctx, TimerTarget{EntryID: &older.ID})
+	if err != nil || resumed.ID != older.ID || resumed.Paused {
+		t.Fatalf("expected the entry of a resumed, got %+v (%v)", resumed, err)
+	}
+
+	last := audit.events[len(audit.events)-2:]
+	if last[0].Action != domain.AuditPause || last[1].Action != domain.AuditResume || last[1].EntityID != older.ID {
+		t.Fatalf("expected pause and resume events, got %+v", last)
+	}
+
+	missing := uuid.New()
+	if _, err := svc.Pause(ctx, TimerTarget{EntryID: &missing}); err != repository.ErrNotFound {
+		t.Fatalf("expected ErrNotFound for an unknown entry, got %v", err)
+	}
+	if _, err := svc.StopActiveInCategory(ctx, a.ID, StopOptions{}); err != nil {
+		t.Fatalf("stop a failed: %v", err)
+	}
+	if _, err := svc.Pause(ctx, TimerTarget{EntryID: &older.ID}); err != ErrNoActiveTimer {
+		t.Fatalf("expected ErrNoActiveTimer for a stopped entry, got %v", err)
+	}
+	if _, err := svc.Resume(ctx, TimerTarget{CategoryID: &a.ID}); err != ErrNoActiveTimer {
+		t.Fatalf("expected ErrNoActiveTimer for an idle category, got %v", err)
+	}
+}
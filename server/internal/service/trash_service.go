@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gargair/clockwork/server/internal/clock"
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+)
+
+type trashService struct {
+	projectRepo  repository.ProjectRepository
+	categoryRepo repository.CategoryRepository
+	timeRepo     repository.TimeEntryRepository
+	uow          repository.UnitOfWork
+	clk          clock.Clock
+}
+
+func (s *trashService) List(ctx context.Context) (domain.Trash, error) {
+	var (
+		trash domain.Trash
+		err   error
+	)
+	if trash.Projects, err = s.projectRepo.ListDeleted(ctx); err != nil {
+		return domain.Trash{}, err
+	}
+	if trash.Categories, err = s.categoryRepo.ListDeleted(ctx); err != nil {
+		return domain.Trash{}, err
+	}
+	if trash.Entries, err = s.timeRepo.ListDeleted(ctx); err != nil {
+		return domain.Trash{}, err
+	}
+	return trash, nil
+}
+
+// Purge removes entries before categories and categories before projects, so that no remaining
+// row references a purged one. Purges are not recorded in the audit log.
+func (s *trashService) Purge(ctx context.Context, retention time.Duration) (domain.PurgeResult, error) {
+	before := s.clk.Now().Add(-retention)
+	var result domain.PurgeResult
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if result.Entries, err = s.timeRepo.PurgeDeleted(ctx, before); err != nil {
+			return err
+		}
+		if result.Categories, err = s.categoryRepo.PurgeDeleted(ctx, before); err != nil {
+			return err
+		}
+		result.Projects, err = s.projectRepo.PurgeDeleted(ctx, before)
+		return err
+	})
+	if err != nil {
+		return domain.PurgeResult{}, err
+	}
+	return result, nil
+}
+
+var _ TrashService = (*trashService)(nil)
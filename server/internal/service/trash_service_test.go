@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Gargair/clockwork/server/internal/domain"
+	"github.com/Gargair/clockwork/server/internal/repository"
+)
+
+func TestTrashServiceListsAndPurgesDeletedRows(t *testing.T) {
+	ctx := context.Background()
+	projRepo, catRepo, timeRepo := newFakeProjectRepo(), newFakeCategoryRepo(), newFakeTimeEntryRepo()
+	projects := NewProjectService(projRepo, nil, fakeUnitOfWork{})
+	p, err := projects.Create(ctx, "P", nil)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	cat := seedCategory(t, catRepo)
+	entry, _ := timeRepo.Create(ctx, domain.TimeEntry{ID: uuid.New(), CategoryID: cat.ID, StartedAt: time.Now().UTC()})
+	if err := projects.Delete(ctx, p.ID); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if err := catRepo.Delete(ctx, cat.ID); err != nil {
+		t.Fatalf("category delete failed: %v", err)
+	}
+	if err := timeRepo.Delete(ctx, entry.ID); err != nil {
+		t.Fatalf("entry delete failed: %v", err)
+	}
+
+	clk := newTestClock(time.Now().Add(48 * time.Hour))
+	svc := NewTrashService(projRepo, catRepo, timeRepo, fakeUnitOfWork{}, clk)
+	trash, err := svc.List(ctx)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(trash.Projects) != 1 || trash.Projects[0].DeletedAt == nil || len(trash.Categories) != 1 || len(trash.Entries) != 1 {
+		t.Fatalf("expected one deleted row of each kind, got %+v", trash)
+	}
+	if _, err := projects.GetByID(ctx, p.ID); err != repository.ErrNotFound {
+		t.Fatalf("expected a deleted project to be hidden, got %v", err)
+	}
+
+	// Nothing is old enough under a longer retention
+	result, err := svc.Purge(ctx, 72*time.Hour)
+	if err != nil || result != (domain.PurgeResult{}) {
+		t.Fatalf("expected nothing purged, got %+v (%v)", result, err)
+	}
+	result, err = svc.Purge(ctx, 24*time.Hour)
+	if err != nil || result != (domain.PurgeResult{Projects: 1, Categories: 1, Entries: 1}) {
+		t.Fatalf("expected everything purged, got %+v (%v)", result, err)
+	}
+	if _, err := projects.Restore(ctx, p.ID); err != repository.ErrNotFound {
+		t.Fatalf("expected purged projects to be gone, got %v", err)
+	}
+}
+
+func TestCategoryServiceRestoreNeedsParentOutOfTrash(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeCategoryRepo()
+	audit := &fakeAuditRepo{}
+	svc := NewCategoryService(repo, audit, fakeUnitOfWork{}, nil)
+	projectID := uuid.New()
+	parent, _ := svc.Create(ctx, projectID, "Parent", nil, nil)
+	child, _ := svc.Create(ctx, projectID, "Child", nil, &parent.ID)
+	if err := svc.Delete(ctx, child.ID); err != nil {
+		t.Fatalf("delete child failed: %v", err)
+	}
+	if err := svc.Delete(ctx, parent.ID); err != nil {
+		t.Fatalf("delete parent failed: %v", err)
+	}
+
+	if _, err := svc.Restore(ctx, child.ID); !errors.Is(err, repository.ErrParentDeleted) {
+		t.Fatalf("expected ErrParentDeleted, got %v", err)
+	}
+	if _, err := svc.Restore(ctx, parent.ID); err != nil {
+		t.Fatalf("restore parent failed: %v", err)
+	}
+	restored, err := svc.Restore(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("restore child failed: %v", err)
+	}
+	if restored.DeletedAt != nil || restored.ParentCategoryID == nil || *restored.ParentCategoryID != parent.ID {
+		t.Fatalf("expected the child back under its parent, got %+v", restored)
+	}
+	last := audit.events[len(audit.events)-1]
+	if last.Action != domain.AuditRestore || last.EntityID != child.ID || last.Before != nil || last.After == nil {
+		t.Fatalf("unexpected restore event: %+v", last)
+	}
+}
+
+func TestCategoryServiceRestoreRejectsReusedName(t *testing.T) {
+	ctx := context.Background()
+	svc := NewCategoryService(newFakeCategoryRepo(), &fakeAuditRepo{}, fakeUnitOfWork{}, nil)
+	projectID := uuid.New()
+	old, _ := svc.Create(ctx, projectID, "Frontend", nil, nil)
+	if err := svc.Delete(ctx, old.ID); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, err := svc.Create(ctx, projectID, "Frontend", nil, nil); err != nil {
+		t.Fatalf("create with the freed name failed: %v", err)
+	}
+	if _, err := svc.Restore(ctx, old.ID); err != ErrDuplicateCategoryName {
+		t.Fatalf("expected ErrDuplicateCategoryName, got %v", err)
+	}
+}
+
+func TestTimeTrackingServiceRestoreEntryRejectsOverlap(t *testing.T) {
+	ctx := context.Background()
+	catRepo := newFakeCategoryRepo()
+	projRepo := newFakeProjectRepo()
+	base := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	clk := newTestClock(base.Add(2 * time.Hour))
+	timeRepo := newFakeTimeEntryRepo()
+	svc := NewTimeTrackingService(timeRepo, catRepo, newFakeTagRepo(), projRepo, nil, fakeUnitOfWork{}, clk)
+	cat := seedCategory(t, catRepo)
+	seedProject(t, projRepo, cat.ProjectID, false)
+
+	entry, err := svc.CreateManual(ctx, cat.ID, base, base.Add(30*time.Minute))
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if err := svc.DeleteEntry(ctx, entry.ID); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, err := svc.GetEntry(ctx, entry.ID); err != repository.ErrNotFound {
+		t.Fatalf("expected a deleted entry to be hidden, got %v", err)
+	}
+	timeRepo.slotLocks = nil
+	restored, err := svc.RestoreEntry(ctx, entry.ID)
+	if err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if len(timeRepo.slotLocks) != 1 || timeRepo.slotLocks[0] != cat.ID {
+		t.Fatalf("expected the overlap check under the slot lock of the category, got %v", timeRepo.slotLocks)
+	}
+	if restored.DeletedAt != nil || !restored.StartedAt.Equal(entry.StartedAt) || restored.Version != entry.Version+2 {
+		t.Fatalf("unexpected restored entry: %+v", restored)
+	}
+
+	// Time tracked in the meantime wins over the deleted entry
+	if err := svc.DeleteEntry(ctx, entry.ID); err != nil {
+		t.Fatalf("second delete failed: %v", err)
+	}
+	if _, err := svc.CreateManual(ctx, cat.ID, base.Add(15*time.Minute), base.Add(45*time.Minute)); err != nil {
+		t.Fatalf("create replacement failed: %v", err)
+	}
+	if _, err := svc.RestoreEntry(ctx, entry.ID); err != ErrTimeEntryOverlap {
+		t.Fatalf("expected ErrTimeEntryOverlap, got %v", err)
+	}
+}
@@ -10,6 +10,11 @@ type Services struct {
 	Projects   ProjectService
 	Categories CategoryService
 	Time       TimeTrackingService
+	Tags       TagService
+	Billing    BillingService
+	Audit      AuditService
+	Trash      TrashService
+	Reports    ReportService
 }
 
 // NewServices constructs all services from repositories and a clock.
@@ -17,10 +22,19 @@ func NewServices(repos struct {
 	Projects    repository.ProjectRepository
 	Categories  repository.CategoryRepository
 	TimeEntries repository.TimeEntryRepository
+	Tags        repository.TagRepository
+	Audit       repository.AuditRepository
+	Reports     repository.ReportRepository
+	UnitOfWork  repository.UnitOfWork
 }, clk clock.Clock) Services {
 	return Services{
-		Projects:   NewProjectService(repos.Projects),
-		Categories: NewCategoryService(repos.Categories),
-		Time:       NewTimeTrackingService(repos.TimeEntries, repos.Categories, clk),
+		Projects:   NewProjectService(repos.Projects, repos.Audit, repos.UnitOfWork),
+		Categories: NewCategoryService(repos.Categories, repos.Audit, repos.UnitOfWork, clk),
+		Time:       NewTimeTrackingService(repos.TimeEntries, repos.Categories, repos.Tags, repos.Projects, repos.Audit, repos.UnitOfWork, clk),
+		Tags:       NewTagService(repos.Tags, clk),
+		Billing:    NewBillingService(repos.Projects, repos.Categories, repos.TimeEntries, repos.Audit, repos.UnitOfWork),
+		Audit:      NewAuditService(repos.Audit),
+		Trash:      NewTrashService(repos.Projects, repos.Categories, repos.TimeEntries, repos.UnitOfWork, clk),
+		Reports:    NewReportService(repos.Reports, repos.Projects, repos.Categories, clk),
 	}
 }
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Free-text note describing the work done during a time entry
+
+ALTER TABLE time_entry
+  ADD COLUMN IF NOT EXISTS note text NULL;
+
+-- +goose Down
+ALTER TABLE time_entry DROP COLUMN IF EXISTS note;
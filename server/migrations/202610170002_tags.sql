@@ -0,0 +1,36 @@
+-- +goose Up
+-- Project-scoped tags and their many-to-many link to time entries
+
+CREATE TABLE IF NOT EXISTS tag (
+  id uuid PRIMARY KEY,
+  project_id uuid NOT NULL,
+  name text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT fk_tag_project
+    FOREIGN KEY (project_id)
+    REFERENCES project (id)
+    ON DELETE CASCADE,
+  CONSTRAINT tag_project_name_unique UNIQUE (project_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS time_entry_tag (
+  time_entry_id uuid NOT NULL,
+  tag_id uuid NOT NULL,
+  PRIMARY KEY (time_entry_id, tag_id),
+  CONSTRAINT fk_time_entry_tag_entry
+    FOREIGN KEY (time_entry_id)
+    REFERENCES time_entry (id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_time_entry_tag_tag
+    FOREIGN KEY (tag_id)
+    REFERENCES tag (id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS time_entry_tag_tag_id_idx ON time_entry_tag (tag_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS time_entry_tag_tag_id_idx;
+DROP TABLE IF EXISTS time_entry_tag;
+DROP TABLE IF EXISTS tag;
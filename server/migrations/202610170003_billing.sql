@@ -0,0 +1,31 @@
+-- +goose Up
+-- Hourly rates on projects and categories, and a billable flag on categories and time entries.
+-- Rates are stored in minor currency units together with an ISO 4217 currency code.
+
+ALTER TABLE project
+  ADD COLUMN IF NOT EXISTS hourly_rate_cents bigint NULL,
+  ADD COLUMN IF NOT EXISTS currency text NULL,
+  ADD CONSTRAINT project_rate_check
+    CHECK ((hourly_rate_cents IS NULL) = (currency IS NULL) AND (hourly_rate_cents IS NULL OR hourly_rate_cents >= 0));
+
+ALTER TABLE category
+  ADD COLUMN IF NOT EXISTS hourly_rate_cents bigint NULL,
+  ADD COLUMN IF NOT EXISTS currency text NULL,
+  ADD COLUMN IF NOT EXISTS billable boolean NOT NULL DEFAULT true,
+  ADD CONSTRAINT category_rate_check
+    CHECK ((hourly_rate_cents IS NULL) = (currency IS NULL) AND (hourly_rate_cents IS NULL OR hourly_rate_cents >= 0));
+
+ALTER TABLE time_entry
+  ADD COLUMN IF NOT EXISTS billable boolean NOT NULL DEFAULT true;
+
+-- +goose Down
+ALTER TABLE time_entry DROP COLUMN IF EXISTS billable;
+ALTER TABLE category
+  DROP CONSTRAINT IF EXISTS category_rate_check,
+  DROP COLUMN IF EXISTS billable,
+  DROP COLUMN IF EXISTS currency,
+  DROP COLUMN IF EXISTS hourly_rate_cents;
+ALTER TABLE project
+  DROP CONSTRAINT IF EXISTS project_rate_check,
+  DROP COLUMN IF EXISTS currency,
+  DROP COLUMN IF EXISTS hourly_rate_cents;
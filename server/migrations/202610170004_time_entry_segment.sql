@@ -0,0 +1,26 @@
+-- +goose Up
+-- Running intervals of paused/resumed time entries. Entries that were never paused have no segments.
+
+CREATE TABLE IF NOT EXISTS time_entry_segment (
+  id uuid PRIMARY KEY,
+  time_entry_id uuid NOT NULL,
+  started_at timestamptz NOT NULL,
+  stopped_at timestamptz NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT fk_time_entry_segment_entry
+    FOREIGN KEY (time_entry_id)
+    REFERENCES time_entry (id)
+    ON DELETE CASCADE,
+  CONSTRAINT time_entry_segment_range_check
+    CHECK (stopped_at IS NULL OR stopped_at >= started_at)
+);
+
+CREATE INDEX IF NOT EXISTS time_entry_segment_entry_started_idx ON time_entry_segment (time_entry_id, started_at);
+
+-- At most one open segment per entry
+CREATE UNIQUE INDEX IF NOT EXISTS time_entry_segment_open_unique ON time_entry_segment (time_entry_id) WHERE stopped_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS time_entry_segment_open_unique;
+DROP INDEX IF EXISTS time_entry_segment_entry_started_idx;
+DROP TABLE IF EXISTS time_entry_segment;
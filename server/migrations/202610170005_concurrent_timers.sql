@@ -0,0 +1,9 @@
+-- +goose Up
+-- Per-project setting allowing one running time entry per category instead of one overall
+
+ALTER TABLE project
+  ADD COLUMN IF NOT EXISTS concurrent_timers boolean NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE project
+  DROP COLUMN IF EXISTS concurrent_timers;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- Marks entries that were stopped by the server rather than by the user
+
+ALTER TABLE time_entry
+  ADD COLUMN IF NOT EXISTS auto_stopped boolean NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE time_entry
+  DROP COLUMN IF EXISTS auto_stopped;
@@ -0,0 +1,44 @@
+-- +goose Up
+-- Database-enforced single running entry per timer slot. The slot is the category for projects
+-- with concurrent timers and the nil UUID for the shared slot of all other entries.
+
+ALTER TABLE time_entry
+  ADD COLUMN IF NOT EXISTS timer_slot uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000';
+
+UPDATE time_entry te
+SET timer_slot = te.category_id
+FROM category c
+JOIN project p ON p.id = c.project_id
+WHERE c.id = te.category_id
+  AND p.concurrent_timers
+  AND te.stopped_at IS NULL;
+
+-- Stop duplicates left behind by earlier races at the start of the newest entry in their slot
+WITH ranked AS (
+  SELECT id, started_at,
+         max(started_at) OVER (PARTITION BY timer_slot) AS latest_start,
+         row_number() OVER (PARTITION BY timer_slot ORDER BY started_at DESC, id DESC) AS rn
+  FROM time_entry
+  WHERE stopped_at IS NULL
+)
+UPDATE time_entry te
+SET stopped_at = ranked.latest_start,
+    duration_seconds = EXTRACT(EPOCH FROM (ranked.latest_start - ranked.started_at))::integer,
+    updated_at = now()
+FROM ranked
+WHERE ranked.id = te.id
+  AND ranked.rn > 1;
+
+UPDATE time_entry_segment s
+SET stopped_at = te.stopped_at
+FROM time_entry te
+WHERE te.id = s.time_entry_id
+  AND s.stopped_at IS NULL
+  AND te.stopped_at IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS time_entry_running_slot_unique ON time_entry (timer_slot) WHERE stopped_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS time_entry_running_slot_unique;
+ALTER TABLE time_entry
+  DROP COLUMN IF EXISTS timer_slot;
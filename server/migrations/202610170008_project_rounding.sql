@@ -0,0 +1,19 @@
+-- +goose Up
+-- Per-project rounding of reported durations. Stored durations stay exact; an increment of 0
+-- disables rounding. The scope rounds either each entry or each day's total.
+
+ALTER TABLE project
+  ADD COLUMN IF NOT EXISTS rounding_increment_seconds integer NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS rounding_mode text NOT NULL DEFAULT 'nearest',
+  ADD COLUMN IF NOT EXISTS rounding_scope text NOT NULL DEFAULT 'entry',
+  ADD CONSTRAINT project_rounding_check
+    CHECK (rounding_increment_seconds BETWEEN 0 AND 86400
+       AND rounding_mode IN ('up', 'down', 'nearest')
+       AND rounding_scope IN ('entry', 'day'));
+
+-- +goose Down
+ALTER TABLE project
+  DROP CONSTRAINT IF EXISTS project_rounding_check,
+  DROP COLUMN IF EXISTS rounding_scope,
+  DROP COLUMN IF EXISTS rounding_mode,
+  DROP COLUMN IF EXISTS rounding_increment_seconds;
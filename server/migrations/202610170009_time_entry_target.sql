@@ -0,0 +1,13 @@
+-- +goose Up
+-- Planned length of a timer, after which the server stops it, and a flag marking break timers
+
+ALTER TABLE time_entry
+  ADD COLUMN IF NOT EXISTS target_seconds integer NULL,
+  ADD COLUMN IF NOT EXISTS is_break boolean NOT NULL DEFAULT false,
+  ADD CONSTRAINT time_entry_target_check CHECK (target_seconds IS NULL OR target_seconds > 0);
+
+-- +goose Down
+ALTER TABLE time_entry
+  DROP CONSTRAINT IF EXISTS time_entry_target_check,
+  DROP COLUMN IF EXISTS is_break,
+  DROP COLUMN IF EXISTS target_seconds;
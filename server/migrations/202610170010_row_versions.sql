@@ -0,0 +1,17 @@
+-- +goose Up
+-- Row versions backing ETags and conditional writes; every update increments them
+
+ALTER TABLE project
+  ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+ALTER TABLE category
+  ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+ALTER TABLE time_entry
+  ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE time_entry
+  DROP COLUMN IF EXISTS version;
+ALTER TABLE category
+  DROP COLUMN IF EXISTS version;
+ALTER TABLE project
+  DROP COLUMN IF EXISTS version;
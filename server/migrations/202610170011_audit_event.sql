@@ -0,0 +1,27 @@
+-- +goose Up
+-- Append-only log of project, category and time entry mutations.
+-- entity_id has no foreign key so that events outlive the rows they describe.
+
+CREATE TABLE IF NOT EXISTS audit_event (
+  id uuid PRIMARY KEY,
+  actor text NOT NULL,
+  request_id text NULL,
+  action text NOT NULL,
+  entity_type text NOT NULL,
+  entity_id uuid NOT NULL,
+  before_data jsonb NULL,
+  after_data jsonb NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT audit_event_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'stop')),
+  CONSTRAINT audit_event_entity_type_check
+    CHECK (entity_type IN ('project', 'category', 'time_entry'))
+);
+
+CREATE INDEX IF NOT EXISTS audit_event_created_at_idx ON audit_event (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS audit_event_entity_idx ON audit_event (entity_type, entity_id, created_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS audit_event_entity_idx;
+DROP INDEX IF EXISTS audit_event_created_at_idx;
+DROP TABLE IF EXISTS audit_event;
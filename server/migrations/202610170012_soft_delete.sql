@@ -0,0 +1,60 @@
+-- +goose Up
+-- Soft deletes: deleted rows stay in place with deleted_at set until they are purged.
+-- Rows trashed together (a project with its categories, a category with its subtree, and their
+-- entries) share the same deleted_at, which is how a restore finds them again.
+
+ALTER TABLE project
+  ADD COLUMN IF NOT EXISTS deleted_at timestamptz NULL;
+ALTER TABLE category
+  ADD COLUMN IF NOT EXISTS deleted_at timestamptz NULL;
+ALTER TABLE time_entry
+  ADD COLUMN IF NOT EXISTS deleted_at timestamptz NULL;
+
+CREATE INDEX IF NOT EXISTS project_deleted_at_idx ON project (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS category_deleted_at_idx ON category (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS time_entry_deleted_at_idx ON time_entry (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Category names are only unique among the categories outside the trash
+ALTER TABLE category DROP CONSTRAINT IF EXISTS category_project_name_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS category_project_name_unique ON category (project_id, name)
+  WHERE deleted_at IS NULL;
+
+-- A running entry in the trash no longer holds its timer slot
+DROP INDEX IF EXISTS time_entry_running_slot_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS time_entry_running_slot_unique ON time_entry (timer_slot)
+  WHERE stopped_at IS NULL AND deleted_at IS NULL;
+
+ALTER TABLE audit_event
+  DROP CONSTRAINT IF EXISTS audit_event_action_check,
+  ADD CONSTRAINT audit_event_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'stop', 'restore'));
+
+-- +goose Down
+-- The trash is emptied: without deleted_at its rows would come back to life.
+DELETE FROM audit_event WHERE action = 'restore';
+ALTER TABLE audit_event
+  DROP CONSTRAINT IF EXISTS audit_event_action_check,
+  ADD CONSTRAINT audit_event_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'stop'));
+
+DELETE FROM time_entry WHERE deleted_at IS NOT NULL;
+DELETE FROM category WHERE deleted_at IS NOT NULL;
+DELETE FROM project WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS time_entry_running_slot_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS time_entry_running_slot_unique ON time_entry (timer_slot) WHERE stopped_at IS NULL;
+
+DROP INDEX IF EXISTS category_project_name_unique;
+ALTER TABLE category
+  ADD CONSTRAINT category_project_name_unique UNIQUE (project_id, name);
+
+DROP INDEX IF EXISTS time_entry_deleted_at_idx;
+DROP INDEX IF EXISTS category_deleted_at_idx;
+DROP INDEX IF EXISTS project_deleted_at_idx;
+
+ALTER TABLE time_entry
+  DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE category
+  DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE project
+  DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- Archived projects and categories drop out of listings but keep their entries in reports.
+
+ALTER TABLE project
+  ADD COLUMN IF NOT EXISTS archived_at timestamptz NULL;
+ALTER TABLE category
+  ADD COLUMN IF NOT EXISTS archived_at timestamptz NULL;
+
+-- +goose Down
+ALTER TABLE category
+  DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE project
+  DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Pauses and resumes of time entries are audited.
+
+ALTER TABLE audit_event
+  DROP CONSTRAINT IF EXISTS audit_event_action_check,
+  ADD CONSTRAINT audit_event_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'stop', 'restore', 'pause', 'resume'));
+
+-- +goose Down
+DELETE FROM audit_event WHERE action IN ('pause', 'resume');
+ALTER TABLE audit_event
+  DROP CONSTRAINT IF EXISTS audit_event_action_check,
+  ADD CONSTRAINT audit_event_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'stop', 'restore'));